@@ -0,0 +1,294 @@
+package fxjson
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// StringAt 按路径（同 GetPath）取字符串值，错误信息包含路径
+// 路径不存在时返回 ErrorTypeNotFound，类型不符或无法转换时返回 ErrorTypeTypeMismatch
+func (n Node) StringAt(path string) (string, error) {
+	v, err := n.at(path, 's')
+	if err != nil {
+		return "", err
+	}
+	s, err := v.String()
+	return s, pathConversionError(path, err)
+}
+
+// IntAt 按路径取 int64 值，非整数或超出 int64 范围时返回错误
+func (n Node) IntAt(path string) (int64, error) {
+	v, err := n.at(path, 'n')
+	if err != nil {
+		return 0, err
+	}
+	i, err := v.Int()
+	return i, pathConversionError(path, err)
+}
+
+// FloatAt 按路径取 float64 值
+func (n Node) FloatAt(path string) (float64, error) {
+	v, err := n.at(path, 'n')
+	if err != nil {
+		return 0, err
+	}
+	f, err := v.Float()
+	return f, pathConversionError(path, err)
+}
+
+// BoolAt 按路径取布尔值
+func (n Node) BoolAt(path string) (bool, error) {
+	v, err := n.at(path, 'b')
+	if err != nil {
+		return false, err
+	}
+	b, err := v.Bool()
+	return b, pathConversionError(path, err)
+}
+
+// at 按路径取节点并检查类型
+func (n Node) at(path string, typ byte) (Node, error) {
+	v := n.GetPath(path)
+	if path == "" {
+		v = n
+	}
+	if !v.Exists() {
+		return Node{}, &FxJSONError{Type: ErrorTypeNotFound, Message: fmt.Sprintf("path %q not found", path)}
+	}
+	if v.typ != typ {
+		return Node{}, &FxJSONError{
+			Type:    ErrorTypeTypeMismatch,
+			Message: fmt.Sprintf("path %q: expected %s, got %s", path, NodeType(typ), v.Kind()),
+			Context: string(v.Raw()),
+		}
+	}
+	return v, nil
+}
+
+// pathConversionError 为转换错误补充路径
+func pathConversionError(path string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &FxJSONError{Type: ErrorTypeTypeMismatch, Message: fmt.Sprintf("path %q: %v", path, err), Cause: err}
+}
+
+// MustString 返回字符串值，失败时 panic
+// 仅用于测试与固定数据（fixtures），业务代码应使用 String 或 StringAt
+func (n Node) MustString() string {
+	s, err := n.String()
+	if err != nil {
+		panic(mustMessage("MustString", n, err))
+	}
+	return s
+}
+
+// MustInt 返回 int64 值，失败时 panic
+// 仅用于测试与固定数据（fixtures），业务代码应使用 Int 或 IntAt
+func (n Node) MustInt() int64 {
+	i, err := n.Int()
+	if err != nil {
+		panic(mustMessage("MustInt", n, err))
+	}
+	return i
+}
+
+// MustNode 按路径（同 GetPath）取节点，路径不存在时 panic，信息中包含路径与最后一个存在的前缀
+// 仅用于测试与固定数据（fixtures），便于链式断言深层数据：
+//
+//	node.MustNode("data.items[0]").MustString()
+func (n Node) MustNode(path string) Node {
+	v := n.GetPath(path)
+	if path == "" {
+		v = n
+	}
+	if !v.Exists() {
+		panic(fmt.Sprintf("fxjson: MustNode: path %q not found (%s)", path, deepestPrefix(n, path)))
+	}
+	return v
+}
+
+// mustMessage 生成 Must 系列 panic 信息，附带值的片段
+func mustMessage(op string, n Node, err error) string {
+	if !n.Exists() {
+		return fmt.Sprintf("fxjson: %s: node does not exist", op)
+	}
+	raw := string(n.Raw())
+	if len(raw) > 64 {
+		raw = raw[:64] + "..."
+	}
+	return fmt.Sprintf("fxjson: %s: %v (value %s)", op, err, raw)
+}
+
+// deepestPrefix 描述路径中最后一个存在的前缀
+func deepestPrefix(n Node, path string) string {
+	last := ""
+	for i := 0; i < len(path); i++ {
+		if path[i] != '.' && path[i] != '[' {
+			continue
+		}
+		if i == 0 || !n.GetPath(path[:i]).Exists() {
+			break
+		}
+		last = path[:i]
+	}
+	if last == "" {
+		return "no prefix exists"
+	}
+	return fmt.Sprintf("%q exists as %s", last, n.GetPath(last).Kind())
+}
+
+// Access 取值的类型转换策略，供 Or 系列取值统一使用
+//
+// 零值为严格策略，行为与 Node.StringOr/IntOr 等方法一致：类型不符即返回默认值。
+// 策略是普通值，可放在全局配置中，也可通过 Document.SetAccess 附加到文档，
+// 从而在整个代码库中统一转换规则，而不是在每个调用处各自决定：
+//
+//	var access = fxjson.Access{CoerceNumbersFromStrings: true, TreatNullAsZero: true}
+//	port := access.IntOr(node.Get("port"), 8080)
+type Access struct {
+	CoerceNumbersFromStrings bool // 内容为数字的字符串（允许首尾空白）可按数字读取，如 "42"
+	CoerceBoolsFromStrings   bool // "true"/"false"/"1"/"0" 等字符串（同 strconv.ParseBool）可按布尔读取
+	StringifyScalars         bool // 数字与布尔可按字符串读取，数字保持原始文本
+	TreatNullAsZero          bool // null 读取为零值（""、0、false）而不是默认值
+	TruncateFloats           bool // 带小数的数字按整数读取时向零截断，超出范围仍返回默认值
+}
+
+// StringOr 按策略读取字符串，失败返回默认值
+func (a Access) StringOr(n Node, defaultValue string) string {
+	switch n.typ {
+	case 's':
+		if s, err := n.String(); err == nil {
+			return s
+		}
+	case 'n', 'b':
+		if a.StringifyScalars {
+			return string(n.Raw())
+		}
+	case 'l':
+		if a.TreatNullAsZero {
+			return ""
+		}
+	}
+	return defaultValue
+}
+
+// IntOr 按策略读取 int64，失败返回默认值
+func (a Access) IntOr(n Node, defaultValue int64) int64 {
+	switch n.typ {
+	case 'n':
+		if v, err := n.Int(); err == nil {
+			return v
+		}
+		if a.TruncateFloats {
+			if f, err := n.Float(); err == nil && f >= -(1<<63) && f < 1<<63 {
+				return int64(f)
+			}
+		}
+	case 's':
+		if !a.CoerceNumbersFromStrings {
+			break
+		}
+		s := trimmedString(n)
+		if v, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return v
+		}
+		if a.TruncateFloats && isJSONNumberLiteral(s) {
+			if f, err := strconv.ParseFloat(s, 64); err == nil && f >= -(1<<63) && f < 1<<63 {
+				return int64(f)
+			}
+		}
+	case 'l':
+		if a.TreatNullAsZero {
+			return 0
+		}
+	}
+	return defaultValue
+}
+
+// UintOr 按策略读取 uint64，失败返回默认值
+func (a Access) UintOr(n Node, defaultValue uint64) uint64 {
+	switch n.typ {
+	case 'n':
+		if v, err := n.Uint(); err == nil {
+			return v
+		}
+		if a.TruncateFloats {
+			if f, err := n.Float(); err == nil && f >= 0 && f < 1<<64 {
+				return uint64(f)
+			}
+		}
+	case 's':
+		if !a.CoerceNumbersFromStrings {
+			break
+		}
+		s := trimmedString(n)
+		if v, err := strconv.ParseUint(s, 10, 64); err == nil {
+			return v
+		}
+		if a.TruncateFloats && isJSONNumberLiteral(s) {
+			if f, err := strconv.ParseFloat(s, 64); err == nil && f >= 0 && f < 1<<64 {
+				return uint64(f)
+			}
+		}
+	case 'l':
+		if a.TreatNullAsZero {
+			return 0
+		}
+	}
+	return defaultValue
+}
+
+// FloatOr 按策略读取 float64，失败返回默认值
+func (a Access) FloatOr(n Node, defaultValue float64) float64 {
+	switch n.typ {
+	case 'n':
+		if v, err := n.Float(); err == nil {
+			return v
+		}
+	case 's':
+		if !a.CoerceNumbersFromStrings {
+			break
+		}
+		if s := trimmedString(n); isJSONNumberLiteral(s) {
+			if v, err := strconv.ParseFloat(s, 64); err == nil {
+				return v
+			}
+		}
+	case 'l':
+		if a.TreatNullAsZero {
+			return 0
+		}
+	}
+	return defaultValue
+}
+
+// BoolOr 按策略读取布尔值，失败返回默认值
+func (a Access) BoolOr(n Node, defaultValue bool) bool {
+	switch n.typ {
+	case 'b':
+		if v, err := n.Bool(); err == nil {
+			return v
+		}
+	case 's':
+		if !a.CoerceBoolsFromStrings {
+			break
+		}
+		s, _ := n.String()
+		if v, err := strconv.ParseBool(strings.TrimSpace(s)); err == nil {
+			return v
+		}
+	case 'l':
+		if a.TreatNullAsZero {
+			return false
+		}
+	}
+	return defaultValue
+}
+
+// trimmedString 返回去除首尾空白的字符串内容
+func trimmedString(n Node) string {
+	s, _ := n.String()
+	return strings.TrimSpace(s)
+}
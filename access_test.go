@@ -0,0 +1,152 @@
+package fxjson
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestTypedAccessorsAt 测试按路径取值并转换
+func TestTypedAccessorsAt(t *testing.T) {
+	node := FromBytes([]byte(`{"user":{"name":"Alice","age":30,"score":9.5,"active":true,"tags":["a","b"]}}`))
+
+	if s, err := node.StringAt("user.name"); err != nil || s != "Alice" {
+		t.Errorf("StringAt = %q, %v", s, err)
+	}
+	if i, err := node.IntAt("user.age"); err != nil || i != 30 {
+		t.Errorf("IntAt = %d, %v", i, err)
+	}
+	if f, err := node.FloatAt("user.score"); err != nil || f != 9.5 {
+		t.Errorf("FloatAt = %v, %v", f, err)
+	}
+	if b, err := node.BoolAt("user.active"); err != nil || !b {
+		t.Errorf("BoolAt = %v, %v", b, err)
+	}
+	if s, err := node.StringAt("user.tags[1]"); err != nil || s != "b" {
+		t.Errorf("StringAt(tags[1]) = %q, %v", s, err)
+	}
+
+	cases := []struct {
+		name string
+		err  error
+		typ  ErrorType
+		want string
+	}{
+		{"missing", errOf(node.StringAt("user.email")), ErrorTypeNotFound, `path "user.email" not found`},
+		{"mismatch", errOf(node.IntAt("user.name")), ErrorTypeTypeMismatch, `path "user.name": expected number, got string`},
+		{"not integer", errOf(node.IntAt("user.score")), ErrorTypeTypeMismatch, `path "user.score": `},
+		{"bool mismatch", errOf(node.BoolAt("user.tags")), ErrorTypeTypeMismatch, `expected bool, got array`},
+	}
+	for _, c := range cases {
+		var fxErr *FxJSONError
+		if !errors.As(c.err, &fxErr) {
+			t.Errorf("%s: error = %v, want *FxJSONError", c.name, c.err)
+			continue
+		}
+		if fxErr.Type != c.typ || !strings.Contains(c.err.Error(), c.want) {
+			t.Errorf("%s: error = %v (type %v), want %q", c.name, c.err, fxErr.Type, c.want)
+		}
+	}
+}
+
+// errOf 丢弃取值结果，只返回错误
+func errOf[T any](_ T, err error) error {
+	return err
+}
+
+// TestMustAccessors 测试 Must 系列取值与 panic 信息
+func TestMustAccessors(t *testing.T) {
+	node := FromBytes([]byte(`{"data":{"items":[{"id":7,"name":"x"}]}}`))
+
+	if got := node.MustNode("data.items[0]").MustNode("name").MustString(); got != "x" {
+		t.Errorf("MustString = %q", got)
+	}
+	if got := node.MustNode("data.items[0].id").MustInt(); got != 7 {
+		t.Errorf("MustInt = %d", got)
+	}
+
+	cases := map[string]func(){
+		`path "data.items[3].id" not found ("data.items" exists as array)`: func() { node.MustNode("data.items[3].id") },
+		`path "meta.x" not found (no prefix exists)`:                       func() { node.MustNode("meta.x") },
+		`fxjson: MustString: `:                                             func() { node.MustNode("data.items[0].id").MustString() },
+		`(value {"id":7,"name":"x"})`:                                      func() { node.MustNode("data.items[0]").MustInt() },
+	}
+	for want, fn := range cases {
+		if msg := capturePanic(fn); !strings.Contains(msg, want) {
+			t.Errorf("panic = %q, want %q", msg, want)
+		}
+	}
+}
+
+// capturePanic 执行 fn 并返回 panic 信息
+func capturePanic(fn func()) (msg string) {
+	defer func() {
+		if r := recover(); r != nil {
+			msg = r.(string)
+		}
+	}()
+	fn()
+	return ""
+}
+
+// TestAccessPolicy 测试取值的类型转换策略
+func TestAccessPolicy(t *testing.T) {
+	node := FromBytes([]byte(`{"port":" 8080 ","ratio":"0.5","count":2.9,"on":"1","nil":null,"n":42,"b":true,"neg":"-3.5"}`))
+
+	// 零值策略与 Node 的 Or 方法一致
+	strict := Access{}
+	if strict.IntOr(node.Get("port"), -1) != node.Get("port").IntOr(-1) || strict.IntOr(node.Get("port"), -1) != -1 {
+		t.Error("strict access should not coerce strings")
+	}
+	if strict.StringOr(node.Get("nil"), "d") != "d" || strict.IntOr(node.Get("count"), -1) != -1 {
+		t.Error("strict access should return defaults for null and floats")
+	}
+
+	lenient := Access{CoerceNumbersFromStrings: true, CoerceBoolsFromStrings: true, StringifyScalars: true, TreatNullAsZero: true, TruncateFloats: true}
+	if v := lenient.IntOr(node.Get("port"), -1); v != 8080 {
+		t.Errorf("IntOr(port) = %d", v)
+	}
+	if v := lenient.FloatOr(node.Get("ratio"), -1); v != 0.5 {
+		t.Errorf("FloatOr(ratio) = %v", v)
+	}
+	if v := lenient.IntOr(node.Get("count"), -1); v != 2 {
+		t.Errorf("IntOr(count) = %d", v)
+	}
+	if v := lenient.IntOr(node.Get("neg"), 0); v != -3 {
+		t.Errorf("IntOr(neg) = %d", v)
+	}
+	if v := lenient.UintOr(node.Get("neg"), 7); v != 7 {
+		t.Errorf("UintOr(neg) = %d", v)
+	}
+	if !lenient.BoolOr(node.Get("on"), false) {
+		t.Error("BoolOr(on) should be true")
+	}
+	if v := lenient.IntOr(node.Get("nil"), 9); v != 0 {
+		t.Errorf("IntOr(nil) = %d", v)
+	}
+	if v := lenient.StringOr(node.Get("n"), ""); v != "42" {
+		t.Errorf("StringOr(n) = %q", v)
+	}
+	if v := lenient.StringOr(node.Get("b"), ""); v != "true" {
+		t.Errorf("StringOr(b) = %q", v)
+	}
+	if v := lenient.IntOr(node.Get("missing"), 5); v != 5 {
+		t.Errorf("IntOr(missing) = %d", v)
+	}
+
+	// 附加到文档
+	doc, err := NewDocument(node.Raw())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.IntOr("port", -1) != -1 {
+		t.Error("document should default to strict access")
+	}
+	doc.SetAccess(Access{CoerceNumbersFromStrings: true})
+	if doc.IntOr("port", -1) != 8080 || doc.FloatOr("ratio", 0) != 0.5 || doc.BoolOr("on", false) {
+		t.Errorf("document access not applied: port=%d ratio=%v", doc.IntOr("port", -1), doc.FloatOr("ratio", 0))
+	}
+	if err := doc.Set("port", []byte(`"9090"`)); err != nil || doc.IntOr("port", -1) != 9090 {
+		t.Errorf("IntOr after Set = %d, %v", doc.IntOr("port", -1), err)
+	}
+}
@@ -0,0 +1,319 @@
+package fxjson
+
+import (
+	"bytes"
+	"container/heap"
+	"fmt"
+	"sort"
+)
+
+// aggAccumulator 单个聚合操作的增量累加器，只保存运行状态而不保留元素
+type aggAccumulator struct {
+	op AggOperation
+
+	count       int     // 参与的元素数量（count / count_if）
+	sum         float64 // 数值之和（sum / avg / sum_if）
+	valueCount  int     // 有效数值数量（avg）
+	extreme     float64 // 当前最大 / 最小值
+	hasValue    bool
+	weightedSum float64 // weighted_avg
+	weightSum   float64
+	buckets     []HistogramBucket
+	top         topHeap
+	seq         int // 已处理元素序号，用于 TopN 同值时保持原顺序
+	qb          QueryBuilder
+}
+
+// newAggAccumulator 创建累加器并校验操作参数，preserveInts 控制条件聚合是否按整数精确比较；
+// groupSize 为分组的元素数量，用于限制 TopN 预分配的容量，未知时为 0（按需增长）
+func newAggAccumulator(op AggOperation, preserveInts bool, groupSize int) (*aggAccumulator, error) {
+	acc := &aggAccumulator{op: op, qb: QueryBuilder{preserveInts: preserveInts}}
+
+	switch op.Type {
+	case "count", "sum", "avg", "max", "min", "weighted_avg":
+	case "sum_if", "count_if":
+		if op.Condition == nil {
+			return nil, fmt.Errorf("%s operation %s requires a condition", op.Type, op.Alias)
+		}
+	case "top_n":
+		if op.Limit > 0 && groupSize > 0 {
+			acc.top = make(topHeap, 0, min(op.Limit, groupSize))
+		}
+	case "histogram":
+		edges := op.Edges
+		if len(edges) < 2 {
+			return nil, fmt.Errorf("histogram %s requires at least 2 edges, got %d", op.Alias, len(edges))
+		}
+		for i := 1; i < len(edges); i++ {
+			if edges[i] <= edges[i-1] {
+				return nil, fmt.Errorf("histogram %s edges must be strictly increasing", op.Alias)
+			}
+		}
+		acc.buckets = make([]HistogramBucket, len(edges)-1)
+		for i := range acc.buckets {
+			acc.buckets[i].Lower = edges[i]
+			acc.buckets[i].Upper = edges[i+1]
+		}
+	default:
+		return nil, fmt.Errorf("unknown aggregation operation: %s", op.Type)
+	}
+
+	return acc, nil
+}
+
+// add 累加一个元素
+func (acc *aggAccumulator) add(item Node) {
+	op := acc.op
+	seq := acc.seq
+	acc.seq++
+
+	switch op.Type {
+	case "count":
+		acc.count++
+
+	case "sum":
+		if val, err := item.Get(op.Field).Float(); err == nil {
+			acc.sum += val
+		}
+
+	case "avg":
+		if val, err := item.Get(op.Field).Float(); err == nil {
+			acc.sum += val
+			acc.valueCount++
+		}
+
+	case "max", "min":
+		val, err := item.Get(op.Field).Float()
+		if err != nil {
+			return
+		}
+		if !acc.hasValue || (op.Type == "max" && val > acc.extreme) || (op.Type == "min" && val < acc.extreme) {
+			acc.extreme = val
+			acc.hasValue = true
+		}
+
+	case "weighted_avg":
+		val, err := item.Get(op.Field).Float()
+		if err != nil {
+			return
+		}
+		weight, err := item.Get(op.Weight).Float()
+		if err != nil {
+			return
+		}
+		acc.weightedSum += val * weight
+		acc.weightSum += weight
+
+	case "sum_if", "count_if":
+		if !acc.qb.evaluateCondition(item, *op.Condition) {
+			return
+		}
+		acc.count++
+		if val, err := item.Get(op.Field).Float(); err == nil {
+			acc.sum += val
+		}
+
+	case "histogram":
+		edges := op.Edges
+		val, err := item.Get(op.Field).Float()
+		if err != nil || val < edges[0] || val > edges[len(edges)-1] {
+			return
+		}
+		// 找到第一个大于 val 的边界，其前一个区间即所属分桶
+		idx := sort.Search(len(edges), func(i int) bool { return edges[i] > val }) - 1
+		if idx >= len(acc.buckets) {
+			idx = len(acc.buckets) - 1
+		}
+		acc.buckets[idx].Count++
+
+	case "top_n":
+		if op.Limit <= 0 {
+			return
+		}
+		val, err := item.Get(op.Field).Float()
+		if err != nil {
+			return
+		}
+		if acc.top.Len() < op.Limit {
+			heap.Push(&acc.top, topEntry{value: val, index: seq, node: detachNode(item)})
+		} else if val > acc.top[0].value {
+			acc.top[0] = topEntry{value: val, index: seq, node: detachNode(item)}
+			heap.Fix(&acc.top, 0)
+		}
+	}
+}
+
+// result 返回当前累加结果
+func (acc *aggAccumulator) result() interface{} {
+	switch acc.op.Type {
+	case "count", "count_if":
+		return acc.count
+	case "sum", "sum_if":
+		return acc.sum
+	case "avg":
+		if acc.valueCount == 0 {
+			return 0
+		}
+		return acc.sum / float64(acc.valueCount)
+	case "max", "min":
+		if !acc.hasValue {
+			return nil
+		}
+		return acc.extreme
+	case "weighted_avg":
+		if acc.weightSum == 0 {
+			return 0
+		}
+		return acc.weightedSum / acc.weightSum
+	case "histogram":
+		buckets := make([]HistogramBucket, len(acc.buckets))
+		copy(buckets, acc.buckets)
+		return buckets
+	case "top_n":
+		// 复制堆后依次弹出，保证多次调用 result 不破坏状态
+		h := make(topHeap, len(acc.top))
+		copy(h, acc.top)
+		nodes := make([]Node, h.Len())
+		for i := len(nodes) - 1; i >= 0; i-- {
+			nodes[i] = heap.Pop(&h).(topEntry).node
+		}
+		return nodes
+	}
+	return nil
+}
+
+// detachNode 复制节点的原始字节并重新解析（不展开），使保留的节点不引用调用方的输入缓冲区
+func detachNode(n Node) Node {
+	return parseRootNode(bytes.Clone(n.Raw()))
+}
+
+// topEntry TopN 堆中的元素
+type topEntry struct {
+	value float64
+	index int
+	node  Node
+}
+
+// topHeap 以“最差”元素为堆顶的有界小顶堆
+type topHeap []topEntry
+
+func (h topHeap) Len() int { return len(h) }
+func (h topHeap) Less(i, j int) bool {
+	// 值相同时，原顺序靠后的元素更差
+	if h[i].value != h[j].value {
+		return h[i].value < h[j].value
+	}
+	return h[i].index > h[j].index
+}
+func (h topHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *topHeap) Push(x interface{}) { *h = append(*h, x.(topEntry)) }
+func (h *topHeap) Pop() interface{} {
+	old := *h
+	entry := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return entry
+}
+
+// Feed 增量输入数据：数组逐元素累加，其他节点作为单个元素累加
+// 可多次调用（如逐行处理 NDJSON），只保留运行中的统计状态，内存占用与数据量无关；
+// TopN 保留的元素是进入结果时复制的副本（最多 n 个），调用方可以在 Feed 返回后复用输入缓冲区
+func (agg *Aggregator) Feed(node Node) error {
+	if !node.Exists() {
+		return fmt.Errorf("cannot feed a non-existent node")
+	}
+
+	if node.typ == 'a' {
+		var feedErr error
+		node.ArrayForEach(func(_ int, item Node) bool {
+			feedErr = agg.feedItem(item)
+			return feedErr == nil
+		})
+		return feedErr
+	}
+	return agg.feedItem(node)
+}
+
+// feedItem 将单个元素累加到所属分组
+func (agg *Aggregator) feedItem(item Node) error {
+	groupKey := ""
+	if len(agg.groupBy) > 0 {
+		groupKey = agg.buildGroupKey(item)
+	}
+
+	accs, err := agg.groupAccumulators(groupKey)
+	if err != nil {
+		return err
+	}
+	for _, acc := range accs {
+		acc.add(item)
+	}
+	return nil
+}
+
+// groupAccumulators 获取或创建分组的累加器
+func (agg *Aggregator) groupAccumulators(groupKey string) ([]*aggAccumulator, error) {
+	if accs, ok := agg.stream[groupKey]; ok {
+		return accs, nil
+	}
+
+	accs := make([]*aggAccumulator, len(agg.operations))
+	for i, op := range agg.operations {
+		acc, err := newAggAccumulator(op, agg.preserveInts, 0)
+		if err != nil {
+			return nil, err
+		}
+		accs[i] = acc
+	}
+
+	size := len(groupKey) + groupEntrySize + len(accs)*accumulatorSize
+	if err := agg.limits.checkGroups(len(agg.stream)+1, agg.streamBytes+size); err != nil {
+		return nil, err
+	}
+	if agg.stream == nil {
+		agg.stream = make(map[string][]*aggAccumulator)
+	}
+	agg.stream[groupKey] = accs
+	agg.streamBytes += size
+	return accs, nil
+}
+
+// Result 返回 Feed 累计的聚合结果，结构与 Execute 相同
+func (agg *Aggregator) Result() (map[string]interface{}, error) {
+	if len(agg.groupBy) == 0 {
+		accs, err := agg.groupAccumulators("")
+		if err != nil {
+			return nil, err
+		}
+		return accumulatorResults(accs), nil
+	}
+
+	result := make(map[string]interface{}, len(agg.stream))
+	for groupKey, accs := range agg.stream {
+		result[groupKey] = accumulatorResults(accs)
+	}
+	return result, nil
+}
+
+// ResultJSON 以 JSON 形式返回 Feed 累计的聚合结果
+func (agg *Aggregator) ResultJSON() ([]byte, error) {
+	result, err := agg.Result()
+	if err != nil {
+		return nil, err
+	}
+	return marshalAggResult(result)
+}
+
+// Reset 清空 Feed 累计的状态
+func (agg *Aggregator) Reset() {
+	agg.stream = nil
+	agg.streamBytes = 0
+}
+
+// accumulatorResults 汇总一组累加器的结果
+func accumulatorResults(accs []*aggAccumulator) map[string]interface{} {
+	result := make(map[string]interface{}, len(accs))
+	for _, acc := range accs {
+		result[acc.op.Alias] = acc.result()
+	}
+	return result
+}
@@ -0,0 +1,115 @@
+package fxjson
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// allocsMatrixDocs 零分配契约矩阵使用的文档：普通、含转义键、嵌套 JSON 字符串展开、超过批处理容量的对象
+func allocsMatrixDocs() map[string]Node {
+	var wide strings.Builder
+	wide.WriteString(`{"user":{"name":"Alice","tags":["x","y"]},"list":[1,2,3]`)
+	for i := 0; i < 40; i++ {
+		fmt.Fprintf(&wide, `,"ké%d":%d`, i, i)
+	}
+	wide.WriteString("}")
+
+	return map[string]Node{
+		"plain":    FromBytes([]byte(`{"user":{"name":"Alice","tags":["x","y"]},"list":[1,2,3]}`)),
+		"escaped":  FromBytes([]byte(`{"user":{"name":"Alice","tags":["x","y"],"k\"q":1},"list":[1,2,3]}`)),
+		"expanded": FromBytes([]byte(`{"user":"{\"name\":\"Alice\",\"tags\":[\"x\",\"y\"]}","list":"[1,2,3]"}`)),
+		"wide":     FromBytes([]byte(wide.String())),
+	}
+}
+
+// TestZeroAllocContract 核心访问 API 必须保持 0 allocs/op
+func TestZeroAllocContract(t *testing.T) {
+	if !zeroCopy {
+		t.Skip("zero-allocation contract does not hold with the fxjson_safe build tag")
+	}
+	ops := []struct {
+		name string
+		fn   func(Node)
+	}{
+		{"Get", func(n Node) { _ = n.Get("user") }},
+		{"GetPath", func(n Node) { _ = n.GetPath("user.tags[1]") }},
+		{"Index", func(n Node) { _ = n.Get("list").Index(2) }},
+		{"Len", func(n Node) { _ = n.Get("list").Len() }},
+		{"Exists", func(n Node) { _ = n.Get("user").Exists() }},
+		{"ForEach", func(n Node) { n.ForEach(func(string, Node) bool { return true }) }},
+		{"ForEachNested", func(n Node) { n.Get("user").ForEach(func(string, Node) bool { return true }) }},
+		{"ArrayForEach", func(n Node) { n.Get("list").ArrayForEach(func(int, Node) bool { return true }) }},
+	}
+
+	for docName, node := range allocsMatrixDocs() {
+		if !node.Get("user").Exists() || node.GetPath("user.tags[1]").Len() != 1 {
+			t.Fatalf("%s: unexpected document shape", docName)
+		}
+		for _, op := range ops {
+			// 预热：首次出现的含转义键会写入缓存
+			op.fn(node)
+			if allocs := testing.AllocsPerRun(100, func() { op.fn(node) }); allocs != 0 {
+				t.Errorf("%s/%s: expected 0 allocs/op, got %.1f", docName, op.name, allocs)
+			}
+		}
+	}
+}
+
+// TestEscapedKeyStringsStable 缓存的含转义键在后续遍历后仍保持正确
+func TestEscapedKeyStringsStable(t *testing.T) {
+	node := FromBytes([]byte(`{"ab":1,"c\"d":2,"e\\f":3}`))
+
+	var first []string
+	node.ForEach(func(key string, _ Node) bool {
+		first = append(first, key)
+		return true
+	})
+	node.ForEach(func(string, Node) bool { return true })
+
+	want := []string{"ab", `c"d`, `e\f`}
+	if strings.Join(first, "|") != strings.Join(want, "|") {
+		t.Errorf("got %q, want %q", first, want)
+	}
+}
+
+// TestEscapedKeyCacheChurn 大量不同的含转义键写满缓存后，新出现的键仍会被缓存
+func TestEscapedKeyCacheChurn(t *testing.T) {
+	var sb strings.Builder
+	sb.WriteByte('{')
+	for i := 0; i < maxEscapedKeyCache+10; i++ {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		fmt.Fprintf(&sb, `"churn\"%d":%d`, i, i)
+	}
+	sb.WriteByte('}')
+	FromBytes([]byte(sb.String())).ForEach(func(string, Node) bool { return true })
+
+	hot := FromBytes([]byte(`{"hot\"key":1}`))
+	hot.ForEach(func(string, Node) bool { return true })
+	escapedKeyCache.RLock()
+	size, cached := len(escapedKeyCache.m), escapedKeyCache.m[`hot\"key`]
+	escapedKeyCache.RUnlock()
+	if size > maxEscapedKeyCache || cached != `hot"key` {
+		t.Errorf("cache size = %d, hot key cached as %q", size, cached)
+	}
+}
+
+// BenchmarkZeroAllocContract 零分配契约矩阵的基准测试
+func BenchmarkZeroAllocContract(b *testing.B) {
+	for docName, node := range allocsMatrixDocs() {
+		b.Run(docName+"/GetPath", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = node.GetPath("user.tags[1]")
+			}
+		})
+		b.Run(docName+"/ForEach", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				node.ForEach(func(string, Node) bool { return true })
+			}
+		})
+	}
+}
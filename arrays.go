@@ -0,0 +1,707 @@
+package fxjson
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"strconv"
+)
+
+// arrayScan 直接遍历数组元素的原始字节范围，不构造 Node；fn 返回 false 时停止
+func (n Node) arrayScan(fn func(idx int, data []byte, start, end int) bool) {
+	if n.typ != 'a' {
+		return
+	}
+
+	data := n.getWorkingData()
+	pos := n.start + 1
+	end := n.end - 1
+
+	for idx := 0; pos < end; idx++ {
+		for pos < end && data[pos] <= ' ' {
+			pos++
+		}
+		if pos >= end {
+			return
+		}
+
+		valueEnd := skipValueFast(data, pos, end)
+		if valueEnd <= pos {
+			return
+		}
+		if !fn(idx, data, pos, valueEnd) {
+			return
+		}
+
+		pos = valueEnd
+		for pos < end && (data[pos] <= ' ' || data[pos] == ',') {
+			pos++
+		}
+	}
+}
+
+// valueMatcher 将 Go 标量值与数组元素的原始字节进行比较
+type valueMatcher struct {
+	kind   byte // 's' 字符串, 'i' 有符号整数, 'u' 无符号整数, 'f' 浮点数, 'b' 布尔, 'l' null, 'x' Node
+	str    string
+	i64    int64
+	u64    uint64
+	f64    float64
+	b      bool
+	raw    []byte
+	rawTyp byte
+}
+
+// newValueMatcher 根据 v 创建匹配器，不支持的类型返回 false
+func newValueMatcher(v any) (valueMatcher, bool) {
+	switch val := v.(type) {
+	case nil:
+		return valueMatcher{kind: 'l'}, true
+	case Node:
+		if !val.Exists() {
+			return valueMatcher{}, false
+		}
+		return valueMatcher{kind: 'x', raw: val.Raw(), rawTyp: val.typ}, true
+	case string:
+		return valueMatcher{kind: 's', str: val}, true
+	case bool:
+		return valueMatcher{kind: 'b', b: val}, true
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return valueMatcher{kind: 'i', i64: rv.Int()}, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return valueMatcher{kind: 'u', u64: rv.Uint()}, true
+	case reflect.Float32, reflect.Float64:
+		return valueMatcher{kind: 'f', f64: rv.Float()}, true
+	case reflect.String:
+		return valueMatcher{kind: 's', str: rv.String()}, true
+	case reflect.Bool:
+		return valueMatcher{kind: 'b', b: rv.Bool()}, true
+	}
+	return valueMatcher{}, false
+}
+
+// match 判断原始 JSON 值是否与匹配器相等，数字按数值比较（1 与 1.0 相等）
+func (m *valueMatcher) match(raw []byte) bool {
+	if len(raw) == 0 {
+		return false
+	}
+
+	switch m.kind {
+	case 's':
+		if raw[0] != '"' || len(raw) < 2 {
+			return false
+		}
+		inner := raw[1 : len(raw)-1]
+		if bytes.IndexByte(inner, '\\') < 0 {
+			return string(inner) == m.str
+		}
+		return keyEqualsEscaped(inner, m.str)
+	case 'b':
+		if m.b {
+			return string(raw) == "true"
+		}
+		return string(raw) == "false"
+	case 'l':
+		return string(raw) == "null"
+	case 'x':
+		return detectType(raw[0]) == m.rawTyp && bytes.Equal(raw, m.raw)
+	case 'i', 'u', 'f':
+		return m.matchNumber(raw)
+	}
+	return false
+}
+
+// matchNumber 数值比较：整数形式优先精确比较，其余按 float64 比较
+func (m *valueMatcher) matchNumber(raw []byte) bool {
+	if raw[0] != '-' && (raw[0] < '0' || raw[0] > '9') {
+		return false
+	}
+
+	s := bytesToString(raw)
+	isInt := bytes.IndexAny(raw, ".eE") < 0
+
+	switch m.kind {
+	case 'i':
+		if isInt {
+			v, err := strconv.ParseInt(s, 10, 64)
+			return err == nil && v == m.i64
+		}
+		f, err := strconv.ParseFloat(s, 64)
+		return err == nil && f == float64(m.i64)
+	case 'u':
+		if isInt {
+			v, err := strconv.ParseUint(s, 10, 64)
+			return err == nil && v == m.u64
+		}
+		f, err := strconv.ParseFloat(s, 64)
+		return err == nil && f == float64(m.u64)
+	default:
+		f, err := strconv.ParseFloat(s, 64)
+		return err == nil && f == m.f64
+	}
+}
+
+// IndexOf 返回数组中第一个等于 v 的元素下标，未找到或非数组返回 -1
+// v 支持字符串、数字、布尔、nil 与 Node（按原始字节比较）；数字按数值比较
+func (n Node) IndexOf(v any) int {
+	matcher, ok := newValueMatcher(v)
+	if !ok {
+		return -1
+	}
+
+	found := -1
+	n.arrayScan(func(idx int, data []byte, start, end int) bool {
+		if matcher.match(data[start:end]) {
+			found = idx
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+// ContainsValue 检查数组是否包含等于 v 的元素
+func (n Node) ContainsValue(v any) bool {
+	return n.IndexOf(v) >= 0
+}
+
+// ContainsWhere 检查数组中是否存在满足条件的元素
+func (n Node) ContainsWhere(predicate func(value Node) bool) bool {
+	if predicate == nil {
+		return false
+	}
+	_, _, found := n.FindInArray(func(_ int, value Node) bool {
+		return predicate(value)
+	})
+	return found
+}
+
+// setOp 数组集合运算类型
+type setOp int
+
+const (
+	setIntersect setOp = iota
+	setUnion
+	setDifference
+)
+
+// String 返回集合运算名称
+func (op setOp) String() string {
+	switch op {
+	case setIntersect:
+		return "Intersect"
+	case setUnion:
+		return "Union"
+	default:
+		return "Difference"
+	}
+}
+
+// canonicalValueKey 返回值的规范化比较键：字符串按解转义后比较，数字按数值比较，其余按原始字节比较
+func canonicalValueKey(n Node) (string, bool) {
+	key, ok := appendCanonicalValueKey(nil, n)
+	return string(key), ok
+}
+
+// appendCanonicalValueKey 将 canonicalValueKey 的结果追加到 dst：首字节为值的类型，其后为规范化的内容
+func appendCanonicalValueKey(dst []byte, n Node) ([]byte, bool) {
+	raw := n.Raw()
+	if len(raw) == 0 {
+		return dst, false
+	}
+
+	switch n.typ {
+	case 's':
+		if len(raw) < 2 {
+			return dst, false
+		}
+		return appendUnescaped(append(dst, 's'), raw[1:len(raw)-1]), true
+	case 'n':
+		s := bytesToString(raw)
+		if bytes.IndexAny(raw, ".eE") < 0 {
+			if v, err := strconv.ParseInt(s, 10, 64); err == nil {
+				return strconv.AppendInt(append(dst, 'n'), v, 10), true
+			}
+			if v, err := strconv.ParseUint(s, 10, 64); err == nil {
+				return strconv.AppendUint(append(dst, 'n'), v, 10), true
+			}
+		}
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return dst, false
+		}
+		if f == math.Trunc(f) && math.Abs(f) < 1<<53 {
+			return strconv.AppendInt(append(dst, 'n'), int64(f), 10), true
+		}
+		return append(append(dst, 'n'), FormatNumber(f)...), true
+	default:
+		return append(append(dst, n.typ), raw...), true
+	}
+}
+
+// keyedValueKey 返回对象元素在 keyPath 处值的规范化比较键
+func keyedValueKey(keyPath string) func(Node) (string, bool) {
+	return func(n Node) (string, bool) {
+		if n.typ != 'o' {
+			return "", false
+		}
+		v := n.GetPath(keyPath)
+		if !v.Exists() {
+			return "", false
+		}
+		return canonicalValueKey(v)
+	}
+}
+
+// arraySetOperation 执行数组集合运算并序列化结果，保持元素在原数组中的顺序与原始字节
+// 无法取得比较键的元素视为互不相等：并集与差集中保留，交集中忽略
+func arraySetOperation(op setOp, a, b Node, keyOf func(Node) (string, bool)) ([]byte, error) {
+	if a.typ != 'a' || b.typ != 'a' {
+		return nil, fmt.Errorf("%s requires two arrays, got %s and %s", op, a.Kind(), b.Kind())
+	}
+
+	inB := make(map[string]struct{})
+	if op != setUnion {
+		b.ArrayForEach(func(_ int, value Node) bool {
+			if key, ok := keyOf(value); ok {
+				inB[key] = struct{}{}
+			}
+			return true
+		})
+	}
+
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	buf.WriteByte('[')
+	written := false
+	emitted := make(map[string]struct{})
+
+	emit := func(value Node) {
+		if written {
+			buf.WriteByte(',')
+		}
+		buf.Write(value.Raw())
+		written = true
+	}
+
+	visit := func(value Node, keep func(key string) bool) bool {
+		key, ok := keyOf(value)
+		if !ok {
+			if op != setIntersect {
+				emit(value)
+			}
+			return true
+		}
+		if _, dup := emitted[key]; dup || !keep(key) {
+			return true
+		}
+		emitted[key] = struct{}{}
+		emit(value)
+		return true
+	}
+
+	a.ArrayForEach(func(_ int, value Node) bool {
+		return visit(value, func(key string) bool {
+			_, found := inB[key]
+			switch op {
+			case setIntersect:
+				return found
+			case setDifference:
+				return !found
+			default:
+				return true
+			}
+		})
+	})
+
+	if op == setUnion {
+		b.ArrayForEach(func(_ int, value Node) bool {
+			return visit(value, func(string) bool { return true })
+		})
+	}
+
+	buf.WriteByte(']')
+
+	result := make([]byte, len(buf.Bytes()))
+	copy(result, buf.Bytes())
+	return result, nil
+}
+
+// Intersect 返回同时出现在 a 与 b 中的元素（去重，按 a 中顺序）
+func Intersect(a, b Node) ([]byte, error) {
+	return arraySetOperation(setIntersect, a, b, canonicalValueKey)
+}
+
+// Union 返回 a 与 b 的并集（去重，a 中元素在前）
+func Union(a, b Node) ([]byte, error) {
+	return arraySetOperation(setUnion, a, b, canonicalValueKey)
+}
+
+// Difference 返回出现在 a 但不在 b 中的元素（去重，按 a 中顺序）
+func Difference(a, b Node) ([]byte, error) {
+	return arraySetOperation(setDifference, a, b, canonicalValueKey)
+}
+
+// IntersectBy 按对象元素 keyPath 处的值计算交集
+func IntersectBy(a, b Node, keyPath string) ([]byte, error) {
+	return arraySetOperation(setIntersect, a, b, keyedValueKey(keyPath))
+}
+
+// UnionBy 按对象元素 keyPath 处的值计算并集，键相同时保留 a 中的元素
+func UnionBy(a, b Node, keyPath string) ([]byte, error) {
+	return arraySetOperation(setUnion, a, b, keyedValueKey(keyPath))
+}
+
+// DifferenceBy 按对象元素 keyPath 处的值计算差集
+func DifferenceBy(a, b Node, keyPath string) ([]byte, error) {
+	return arraySetOperation(setDifference, a, b, keyedValueKey(keyPath))
+}
+
+// maxPreallocNodes 按调用方给出的数量预分配 Node 切片时的容量上限，更多的元素由 append 按需增长
+const maxPreallocNodes = 1024
+
+// Chunks 按 size 个元素一组遍历数组，fn 返回 false 时停止
+// chunk 切片在各次回调间复用，需要保留时请自行复制
+func (n Node) Chunks(size int, fn func(chunk []Node) bool) {
+	if size <= 0 || fn == nil {
+		return
+	}
+
+	chunk := make([]Node, 0, min(size, maxPreallocNodes))
+	stopped := false
+	n.arrayScan(func(_ int, data []byte, start, end int) bool {
+		chunk = append(chunk, n.nodeAt(data, start, end))
+		if len(chunk) == size {
+			if !fn(chunk) {
+				stopped = true
+				return false
+			}
+			chunk = chunk[:0]
+		}
+		return true
+	})
+
+	if !stopped && len(chunk) > 0 {
+		fn(chunk)
+	}
+}
+
+// ChunksRaw 按 size 个元素一组遍历数组，每组以 JSON 数组字节形式交给 fn，fn 返回 false 时停止
+// 各组直接截取原始字节，不构造 Node；raw 缓冲区在各次回调间复用，需要保留时请自行复制
+func (n Node) ChunksRaw(size int, fn func(raw []byte) bool) {
+	if size <= 0 || fn == nil {
+		return
+	}
+
+	var buf []byte
+	count := 0
+	chunkStart, chunkEnd := -1, -1
+	var src []byte
+
+	flush := func() bool {
+		buf = append(buf[:0], '[')
+		buf = append(buf, src[chunkStart:chunkEnd]...)
+		buf = append(buf, ']')
+		count = 0
+		chunkStart = -1
+		return fn(buf)
+	}
+
+	stopped := false
+	n.arrayScan(func(_ int, data []byte, start, end int) bool {
+		src = data
+		if chunkStart < 0 {
+			chunkStart = start
+		}
+		chunkEnd = end
+		count++
+		if count == size && !flush() {
+			stopped = true
+			return false
+		}
+		return true
+	})
+
+	if !stopped && count > 0 {
+		flush()
+	}
+}
+
+// resolveArrayPath 解析文档并定位 path 处的数组，path 为空时为根节点
+func resolveArrayPath(doc []byte, path string) (Node, error) {
+	root := parseRootNode(doc)
+	if !root.Exists() {
+		return Node{}, fmt.Errorf("invalid JSON data")
+	}
+	arr := root
+	if path != "" {
+		arr = root.GetPath(path)
+	}
+	if !arr.Exists() {
+		return Node{}, fmt.Errorf("path %q not found", path)
+	}
+	if arr.typ != 'a' {
+		return Node{}, fmt.Errorf("path %q must be an array, got %s", path, arr.Kind())
+	}
+	return arr, nil
+}
+
+// SplitArray 将 path 处的数组拆分为多份文档，每份最多包含 maxItems 个元素，数组以外的部分原样复制
+// 元素直接截取原始字节，不解码也不重新编码；空数组返回一份原文档的副本
+func SplitArray(doc []byte, path string, maxItems int) ([][]byte, error) {
+	if maxItems <= 0 {
+		return nil, fmt.Errorf("maxItems must be positive, got %d", maxItems)
+	}
+	arr, err := resolveArrayPath(doc, path)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix, suffix := doc[:arr.start], doc[arr.end:]
+	var docs [][]byte
+	arr.ChunksRaw(maxItems, func(raw []byte) bool {
+		part := make([]byte, 0, len(prefix)+len(raw)+len(suffix))
+		part = append(part, prefix...)
+		part = append(part, raw...)
+		docs = append(docs, append(part, suffix...))
+		return true
+	})
+	if len(docs) == 0 {
+		docs = append(docs, append([]byte(nil), doc...))
+	}
+	return docs, nil
+}
+
+// ConcatArrays 将各文档 path 处数组的元素依次合并，结果沿用第一份文档的其余部分
+// 适合拼接分页 API 的响应：元素直接复制原始字节，不解码也不重新编码
+func ConcatArrays(path string, docs ...[]byte) ([]byte, error) {
+	if len(docs) == 0 {
+		return nil, fmt.Errorf("no documents to concatenate")
+	}
+
+	type elemRange struct {
+		data       []byte
+		start, end int
+	}
+	ranges := make([]elemRange, 0, len(docs))
+	size := 0
+	var first Node
+	for i, doc := range docs {
+		arr, err := resolveArrayPath(doc, path)
+		if err != nil {
+			return nil, fmt.Errorf("document %d: %w", i, err)
+		}
+		if i == 0 {
+			first = arr
+		}
+		r := elemRange{start: -1}
+		arr.arrayScan(func(_ int, data []byte, start, end int) bool {
+			if r.start < 0 {
+				r.data, r.start = data, start
+			}
+			r.end = end
+			return true
+		})
+		if r.start >= 0 {
+			ranges = append(ranges, r)
+			size += r.end - r.start + 1
+		}
+	}
+
+	envelope := docs[0]
+	result := make([]byte, 0, len(envelope)-(first.end-first.start)+size+2)
+	result = append(result, envelope[:first.start]...)
+	result = append(result, '[')
+	for i, r := range ranges {
+		if i > 0 {
+			result = append(result, ',')
+		}
+		result = append(result, r.data[r.start:r.end]...)
+	}
+	result = append(result, ']')
+	return append(result, envelope[first.end:]...), nil
+}
+
+// PageMeta 分页元数据
+type PageMeta struct {
+	Page    int  `json:"page"`
+	PerPage int  `json:"per_page"`
+	Total   int  `json:"total"`
+	Pages   int  `json:"pages"`
+	HasNext bool `json:"has_next"`
+	HasPrev bool `json:"has_prev"`
+}
+
+// Page 对数组分页（page 从 1 开始），返回当前页元素组成的 JSON 数组与分页元数据
+// 页码超出范围时返回空数组
+func (n Node) Page(page, perPage int) ([]byte, PageMeta, error) {
+	if n.typ != 'a' {
+		return nil, PageMeta{}, fmt.Errorf("Page requires an array, got %s", n.Kind())
+	}
+	if page < 1 {
+		return nil, PageMeta{}, fmt.Errorf("page must be >= 1, got %d", page)
+	}
+	if perPage < 1 {
+		return nil, PageMeta{}, fmt.Errorf("perPage must be >= 1, got %d", perPage)
+	}
+
+	offset := (page - 1) * perPage
+	total := 0
+	spanStart, spanEnd := -1, -1
+	var src []byte
+
+	n.arrayScan(func(idx int, data []byte, start, end int) bool {
+		total++
+		if idx >= offset && idx < offset+perPage {
+			if spanStart < 0 {
+				spanStart = start
+				src = data
+			}
+			spanEnd = end
+		}
+		return true
+	})
+
+	var items []byte
+	if spanStart >= 0 {
+		items = make([]byte, 0, spanEnd-spanStart+2)
+		items = append(items, '[')
+		items = append(items, src[spanStart:spanEnd]...)
+		items = append(items, ']')
+	} else {
+		items = []byte("[]")
+	}
+
+	pages := (total + perPage - 1) / perPage
+	meta := PageMeta{
+		Page:    page,
+		PerPage: perPage,
+		Total:   total,
+		Pages:   pages,
+		HasNext: page < pages,
+		HasPrev: page > 1,
+	}
+	return items, meta, nil
+}
+
+// Sample 使用单次遍历的蓄水池抽样从数组中随机选取最多 k 个元素（rng 为 nil 时使用全局随机源）
+func (n Node) Sample(k int, rng *rand.Rand) []Node {
+	if n.typ != 'a' || k <= 0 {
+		return nil
+	}
+
+	intn := rand.Intn
+	if rng != nil {
+		intn = rng.Intn
+	}
+
+	reservoir := make([]Node, 0, min(k, maxPreallocNodes))
+	n.arrayScan(func(idx int, data []byte, start, end int) bool {
+		if idx < k {
+			reservoir = append(reservoir, n.nodeAt(data, start, end))
+			return true
+		}
+		// 以 k/(idx+1) 的概率替换已有元素，只有被选中时才构造 Node
+		if j := intn(idx + 1); j < k {
+			reservoir[j] = n.nodeAt(data, start, end)
+		}
+		return true
+	})
+	return reservoir
+}
+
+// RandomIndex 返回数组的一个随机下标，非数组或空数组返回 -1
+func (n Node) RandomIndex() int {
+	length := n.Len()
+	if n.typ != 'a' || length == 0 {
+		return -1
+	}
+	return rand.Intn(length)
+}
+
+// nodeAt 基于当前节点的数据构造子节点
+func (n Node) nodeAt(data []byte, start, end int) Node {
+	return Node{
+		raw:      n.raw,
+		start:    start,
+		end:      end,
+		typ:      detectType(data[start]),
+		expanded: n.expanded,
+		lazy:     n.lazy,
+	}
+}
+
+// DeleteWhere 删除 path 处数组中满足条件的元素（条件语义同 QueryBuilder.Where），返回新文档与删除数量
+// 通过字节拼接完成：保留的元素及其间的分隔与缩进原样复制，文档其余部分保持不变
+func DeleteWhere(doc []byte, arrayPath string, cond Condition) ([]byte, int, error) {
+	if !isQueryOperator(cond.Operator) {
+		return nil, 0, fmt.Errorf("unsupported operator %q", cond.Operator)
+	}
+	qb := &QueryBuilder{}
+	return deleteArrayElements(doc, arrayPath, func(item Node) bool {
+		return qb.evaluateCondition(item, cond)
+	})
+}
+
+// CompactNulls 删除 path 处数组中的 null 元素，返回新文档与删除数量
+func CompactNulls(doc []byte, arrayPath string) ([]byte, int, error) {
+	return deleteArrayElements(doc, arrayPath, func(item Node) bool {
+		return item.typ == 'l'
+	})
+}
+
+// deleteArrayElements 删除数组中 remove 返回 true 的元素
+func deleteArrayElements(doc []byte, arrayPath string, remove func(item Node) bool) ([]byte, int, error) {
+	arr, err := resolveArrayPath(doc, arrayPath)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	type elem struct {
+		start, end int
+		keep       bool
+	}
+	var elems []elem
+	removed := 0
+	arr.arrayScan(func(_ int, data []byte, start, end int) bool {
+		keep := !remove(parseValueAtWithData(data, start, end, arr))
+		if !keep {
+			removed++
+		}
+		elems = append(elems, elem{start: start, end: end, keep: keep})
+		return true
+	})
+	if removed == 0 {
+		return append([]byte(nil), doc...), 0, nil
+	}
+
+	result := make([]byte, 0, len(doc))
+	if removed == len(elems) {
+		result = append(result, doc[:arr.start]...)
+		result = append(result, "[]"...)
+		return append(result, doc[arr.end:]...), removed, nil
+	}
+
+	// 每个保留的元素沿用原本位于它之前的分隔符，首尾的空白与括号保持不变
+	result = append(result, doc[:elems[0].start]...)
+	first := true
+	for i, e := range elems {
+		if !e.keep {
+			continue
+		}
+		if !first {
+			result = append(result, doc[elems[i-1].end:e.start]...)
+		}
+		first = false
+		result = append(result, doc[e.start:e.end]...)
+	}
+	result = append(result, doc[elems[len(elems)-1].end:]...)
+	return result, removed, nil
+}
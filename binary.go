@@ -0,0 +1,72 @@
+package fxjson
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// BinaryDataError 输入不是文本 JSON：包含 NUL 字节或字符串外的控制字符
+// 常见于误投递到 JSON 接口的 protobuf、压缩包等二进制负载
+type BinaryDataError struct {
+	Offset int    // 第一个可疑字节的位置
+	Byte   byte   // 可疑字节
+	Format string // 按文件头识别出的格式（如 "gzip"、"zip"），无法识别时为空
+}
+
+// Error 实现 error 接口
+func (e *BinaryDataError) Error() string {
+	msg := fmt.Sprintf("binary data detected: byte 0x%02x at offset %d", e.Byte, e.Offset)
+	if e.Format != "" {
+		msg += fmt.Sprintf(" (input looks like %s)", e.Format)
+	}
+	return msg
+}
+
+// binaryMagic 常见二进制格式的文件头
+var binaryMagic = []struct {
+	prefix []byte
+	format string
+}{
+	{[]byte{0x1f, 0x8b}, "gzip"},
+	{[]byte("PK\x03\x04"), "zip"},
+	{[]byte{0x28, 0xb5, 0x2f, 0xfd}, "zstd"},
+	{[]byte("%PDF-"), "pdf"},
+	{[]byte("\x89PNG"), "png"},
+	{[]byte{0xff, 0xd8, 0xff}, "jpeg"},
+}
+
+// detectBinaryData 扫描输入中的 NUL 字节（任意位置）与字符串外除空白以外的控制字符
+func detectBinaryData(data []byte) error {
+	inString, escaped := false, false
+	for i, c := range data {
+		if c >= 0x20 {
+			if inString {
+				if escaped {
+					escaped = false
+				} else if c == '\\' {
+					escaped = true
+				} else if c == '"' {
+					inString = false
+				}
+			} else if c == '"' {
+				inString = true
+			}
+			continue
+		}
+		escaped = false
+		if c == 0 || (!inString && c != ' ' && c != '\t' && c != '\n' && c != '\r') {
+			return &BinaryDataError{Offset: i, Byte: c, Format: sniffBinaryFormat(data)}
+		}
+	}
+	return nil
+}
+
+// sniffBinaryFormat 按文件头识别二进制格式
+func sniffBinaryFormat(data []byte) string {
+	for _, m := range binaryMagic {
+		if bytes.HasPrefix(data, m.prefix) {
+			return m.format
+		}
+	}
+	return ""
+}
@@ -0,0 +1,41 @@
+package fxjson
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestDetectBinary 测试二进制负载检测
+func TestDetectBinary(t *testing.T) {
+	opts := DefaultParseOptions
+	opts.DetectBinary = true
+
+	tests := []struct {
+		input  string
+		offset int
+		format string
+	}{
+		{"\x1f\x8b\x08\x00\x00\x00", 0, "gzip"},
+		{"PK\x03\x04\x14\x00", 2, "zip"},
+		{"\x0a\x05hello\x10\x01", 1, ""},
+		{`{"a":"x` + "\x00" + `"}`, 7, ""},
+		{"{\"a\":1,\x01\"b\":2}", 7, ""},
+	}
+	for _, tt := range tests {
+		_, err := ParseBytes([]byte(tt.input), opts)
+		var binErr *BinaryDataError
+		if !errors.As(err, &binErr) {
+			t.Errorf("%q: err = %v, want BinaryDataError", tt.input, err)
+			continue
+		}
+		if binErr.Offset != tt.offset || binErr.Format != tt.format {
+			t.Errorf("%q: offset %d format %q, want %d %q", tt.input, binErr.Offset, binErr.Format, tt.offset, tt.format)
+		}
+	}
+
+	for _, input := range []string{"{\"a\":\t\"b\\\"\"}\r\n", "\xef\xbb\xbf[1, \"\x7f\"]"} {
+		if node, err := ParseBytes([]byte(input), opts); err != nil || !node.Exists() {
+			t.Errorf("%q: err = %v", input, err)
+		}
+	}
+}
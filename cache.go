@@ -46,6 +46,8 @@ type MemoryCache struct {
 }
 
 // NewMemoryCache 创建内存缓存
+//
+// Deprecated: 新代码请使用 cache.NewMemory，该函数仅为兼容保留
 func NewMemoryCache(maxSize int) *MemoryCache {
 	cache := &MemoryCache{
 		items:   make(map[string]*CacheItem),
@@ -199,11 +201,15 @@ func (mc *MemoryCache) cleanupExpired() {
 var globalCache Cache = NewMemoryCache(1000)
 
 // EnableCaching 启用全局缓存
+//
+// Deprecated: 新代码请使用 cache.Enable，该函数仅为兼容保留
 func EnableCaching(cache Cache) {
 	globalCache = cache
 }
 
 // DisableCaching 禁用全局缓存
+//
+// Deprecated: 新代码请使用 cache.Disable，该函数仅为兼容保留
 func DisableCaching() {
 	globalCache = nil
 }
@@ -216,6 +222,8 @@ func generateCacheKey(data []byte) string {
 }
 
 // FromBytesWithCache 带缓存的JSON解析
+//
+// Deprecated: 新代码请使用 cache.Parse，该函数仅为兼容保留
 func FromBytesWithCache(b []byte, ttl time.Duration) Node {
 	if globalCache == nil {
 		return FromBytes(b)
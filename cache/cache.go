@@ -0,0 +1,55 @@
+// Package cache groups fxjson's caching APIs: the parse result cache used by
+// FromBytesWithCache, per-document path caches and invalidation of the
+// internal array index cache.
+//
+// The types are aliases of the ones in the root package. The root package
+// keeps the implementation because the parser consults the cache and cannot
+// import this package back; the root constructors and functions wrapped here
+// are marked deprecated and remain only for compatibility. This package is
+// the entry point for new code.
+// Its exported surface is pinned by internal/apicheck.
+package cache
+
+import (
+	"time"
+
+	"github.com/icloudza/fxjson"
+)
+
+type (
+	Cache  = fxjson.Cache       // 解析结果缓存接口
+	Stats  = fxjson.CacheStats  // 缓存统计
+	Item   = fxjson.CacheItem   // 缓存项
+	Memory = fxjson.MemoryCache // 内存缓存实现
+	Path   = fxjson.PathCache   // 单个只读文档的路径解析缓存
+)
+
+// NewMemory 创建最多保存 maxSize 项的内存缓存，同 fxjson.NewMemoryCache
+func NewMemory(maxSize int) *Memory {
+	return fxjson.NewMemoryCache(maxSize)
+}
+
+// NewPath 为 root 创建路径缓存，同 fxjson.NewPathCache
+func NewPath(root fxjson.Node) *Path {
+	return fxjson.NewPathCache(root)
+}
+
+// Enable 设置全局解析缓存，同 fxjson.EnableCaching
+func Enable(c Cache) {
+	fxjson.EnableCaching(c)
+}
+
+// Disable 禁用全局解析缓存，同 fxjson.DisableCaching
+func Disable() {
+	fxjson.DisableCaching()
+}
+
+// Parse 解析数据并使用全局缓存，同 fxjson.FromBytesWithCache
+func Parse(b []byte, ttl time.Duration) fxjson.Node {
+	return fxjson.FromBytesWithCache(b, ttl)
+}
+
+// InvalidateArrayIndex 丢弃 data 的数组下标缓存，同 fxjson.InvalidateArrayCacheFor
+func InvalidateArrayIndex(data []byte) {
+	fxjson.InvalidateArrayCacheFor(data)
+}
@@ -0,0 +1,36 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/icloudza/fxjson"
+)
+
+// TestFacade 测试子包入口与根包 API 互通
+func TestFacade(t *testing.T) {
+	var mem *fxjson.MemoryCache = NewMemory(10)
+	Enable(mem)
+	defer Enable(fxjson.NewMemoryCache(1000))
+
+	data := []byte(`{"a":[1,2,3]}`)
+	first := Parse(data, time.Minute)
+	second := Parse(data, time.Minute)
+	if !first.Exists() || !second.Equals(first) {
+		t.Fatalf("Parse = %s, %s", first.Raw(), second.Raw())
+	}
+	if stats := mem.Stats(); stats.Hits != 1 || stats.Sets != 1 {
+		t.Errorf("stats = %+v", stats)
+	}
+
+	Disable()
+	if !Parse(data, time.Minute).Exists() {
+		t.Error("Parse without cache failed")
+	}
+
+	paths := NewPath(first)
+	if v, _ := paths.GetPath("a[1]").Int(); v != 2 || paths.Len() != 1 {
+		t.Errorf("path cache = %d, len %d", v, paths.Len())
+	}
+	InvalidateArrayIndex(data)
+}
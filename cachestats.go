@@ -0,0 +1,122 @@
+package fxjson
+
+import (
+	"reflect"
+	"strconv"
+	"sync/atomic"
+)
+
+// cacheCounter 进程级缓存命中与未命中计数
+type cacheCounter struct {
+	hits, misses atomic.Int64
+}
+
+// record 记录一次查找
+func (c *cacheCounter) record(hit bool) {
+	if hit {
+		c.hits.Add(1)
+	} else {
+		c.misses.Add(1)
+	}
+}
+
+var (
+	arrIdxCacheCounter      cacheCounter // 数组下标缓存
+	structFieldCacheCounter cacheCounter // 解码结构体字段映射缓存
+	typeCacheCounter        cacheCounter // 序列化类型信息缓存
+)
+
+// 缓存条目的估算开销；类型大小通过反射获取以便在 fxjson_safe 构建标签下不依赖 unsafe
+const cacheEntryOverhead = 64 // sync.Map 条目、键与接口包装
+
+var (
+	structFieldSize = int(reflect.TypeFor[structFieldInfo]().Size()) // 字段映射中的单个值
+	fieldInfoSize   = int(reflect.TypeFor[fieldInfo]().Size())       // 序列化类型信息中的单个字段
+)
+
+// CacheUsage 单个内部缓存的使用情况
+type CacheUsage struct {
+	Entries        int     `json:"entries"`
+	Hits           int64   `json:"hits"`   // 进程启动以来的命中次数
+	Misses         int64   `json:"misses"` // 进程启动以来的未命中次数（即构建次数）
+	HitRate        float64 `json:"hit_rate"`
+	EstimatedBytes int     `json:"estimated_bytes"`
+}
+
+// InternalCacheStats 包内全局缓存的统计信息
+// （Cache 接口的统计类型为 CacheStats，这里统计的是解析与解码使用的内部缓存）
+type InternalCacheStats struct {
+	ArrayIndex   CacheUsage `json:"array_index"`   // 数组元素偏移量缓存，按底层数据与数组范围索引
+	StructFields CacheUsage `json:"struct_fields"` // 解码使用的结构体字段映射缓存
+	MarshalTypes CacheUsage `json:"marshal_types"` // 序列化使用的类型信息缓存
+}
+
+// ReadCacheStats 返回内部缓存的条目数、命中计数与估算内存，用于容量规划与诊断
+// 条目数与内存通过遍历缓存得到，开销与条目数成正比，不宜在热路径上调用
+//
+// Deprecated: 新代码请使用 debugx.ReadCacheStats，该函数仅为兼容保留
+func ReadCacheStats() InternalCacheStats {
+	var stats InternalCacheStats
+
+	stats.ArrayIndex = arrIdxCacheCounter.usage()
+	arrIdxCache.Range(func(_, v any) bool {
+		stats.ArrayIndex.Entries++
+		stats.ArrayIndex.EstimatedBytes += cacheEntryOverhead + cap(v.([]int))*strconv.IntSize/8
+		return true
+	})
+
+	stats.StructFields = structFieldCacheCounter.usage()
+	structFieldCache.Range(func(_, v any) bool {
+		stats.StructFields.Entries++
+		stats.StructFields.EstimatedBytes += cacheEntryOverhead
+		for name := range v.(map[string]structFieldInfo) {
+			stats.StructFields.EstimatedBytes += decodedStringSize + structFieldSize
+			stats.StructFields.EstimatedBytes += len(name) // 名称通常引用标签字符串，按上限估算
+		}
+		return true
+	})
+
+	stats.MarshalTypes = typeCacheCounter.usage()
+	typeCache.Range(func(_, v any) bool {
+		stats.MarshalTypes.Entries++
+		stats.MarshalTypes.EstimatedBytes += cacheEntryOverhead + cap(v.(*typeInfo).fields)*fieldInfoSize
+		return true
+	})
+	return stats
+}
+
+// usage 返回计数部分的统计
+func (c *cacheCounter) usage() CacheUsage {
+	u := CacheUsage{Hits: c.hits.Load(), Misses: c.misses.Load()}
+	if total := u.Hits + u.Misses; total > 0 {
+		u.HitRate = float64(u.Hits) / float64(total)
+	}
+	return u
+}
+
+// InvalidateArrayCacheFor 删除以 data 为底层数据的全部数组下标缓存条目
+// 复用或修改了曾被解析的缓冲区时调用，data 应为解析时传入的同一切片
+//
+// Deprecated: 新代码请使用 cache.InvalidateArrayIndex，该函数仅为兼容保留
+func InvalidateArrayCacheFor(data []byte) {
+	ptr := dataPtr(data)
+	if ptr == 0 {
+		return
+	}
+	arrIdxCache.Range(func(k, _ any) bool {
+		if k.(arrKey).data == ptr {
+			arrIdxCache.Delete(k)
+		}
+		return true
+	})
+}
+
+// loadStructFieldMap 从结构体字段缓存中读取字段映射并记录命中情况
+func loadStructFieldMap(key any) (map[string]structFieldInfo, bool) {
+	cached, ok := structFieldCache.Load(key)
+	structFieldCacheCounter.record(ok)
+	if !ok {
+		return nil, false
+	}
+	return cached.(map[string]structFieldInfo), true
+}
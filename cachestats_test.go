@@ -0,0 +1,48 @@
+package fxjson
+
+import "testing"
+
+type testCacheStatsItem struct {
+	Name string `json:"name"`
+}
+
+// TestReadCacheStats 测试内部缓存统计与按数据失效
+func TestReadCacheStats(t *testing.T) {
+	data := []byte(`[1,2,3,[4,5]]`)
+	arr := FromBytes(data)
+	before := ReadCacheStats()
+
+	arr.Index(1)
+	arr.Index(2)
+	arr.Index(3).Index(0)
+
+	after := ReadCacheStats()
+	if after.ArrayIndex.Misses < before.ArrayIndex.Misses+2 || after.ArrayIndex.Hits < before.ArrayIndex.Hits+1 {
+		t.Errorf("ArrayIndex counters before %+v, after %+v", before.ArrayIndex, after.ArrayIndex)
+	}
+	if after.ArrayIndex.Entries == 0 || after.ArrayIndex.EstimatedBytes == 0 || after.ArrayIndex.HitRate <= 0 {
+		t.Errorf("ArrayIndex = %+v", after.ArrayIndex)
+	}
+	if !arrOffsetsCached(arr) {
+		t.Fatal("array offsets should be cached")
+	}
+
+	InvalidateArrayCacheFor(data)
+	if arrOffsetsCached(arr) || arrOffsetsCached(arr.Index(3)) {
+		t.Error("InvalidateArrayCacheFor left entries for data")
+	}
+
+	var items []testCacheStatsItem
+	if err := FromString(`[{"name":"a"},{"name":"b"}]`).Decode(&items); err != nil {
+		t.Fatalf("Decode error: %v", err)
+	}
+	if s := ReadCacheStats().StructFields; s.Entries == 0 || s.Hits+s.Misses == 0 || s.EstimatedBytes == 0 {
+		t.Errorf("StructFields = %+v", s)
+	}
+	if _, err := Marshal(testCacheStatsItem{Name: "x"}); err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	if s := ReadCacheStats().MarshalTypes; s.Entries == 0 || s.Misses == 0 {
+		t.Errorf("MarshalTypes = %+v", s)
+	}
+}
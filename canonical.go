@@ -0,0 +1,170 @@
+package fxjson
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"unicode/utf16"
+)
+
+// Canonical 返回节点的规范化序列化（RFC 8785 JSON Canonicalization Scheme），
+// 语义相同的文档得到逐字节相同的输出，可用于签名、哈希和去重：
+//   - 无空白，对象键按 UTF-16 码元排序，重复键返回错误
+//   - 字符串只转义引号、反斜杠和控制字符，其余字符原样输出为 UTF-8
+//   - 数字按 IEEE 754 双精度值以 ECMAScript 规则输出（1.0 输出为 1，1e21 输出为 1e+21），
+//     超出双精度范围精度的整数会丢失精度
+func (n Node) Canonical() (_ []byte, err error) {
+	defer recoverPanic(&err, "Canonical", n)
+	if !n.Exists() {
+		return nil, fmt.Errorf("cannot canonicalize a missing node")
+	}
+	return n.appendCanonical(nil)
+}
+
+// canonicalMember 规范化输出中的对象成员
+type canonicalMember struct {
+	key   string
+	value Node
+}
+
+// appendCanonical 将节点的规范化序列化追加到 dst
+func (n Node) appendCanonical(dst []byte) ([]byte, error) {
+	switch n.typ {
+	case 'o':
+		return n.appendCanonicalObject(dst, "", canonicalMember{})
+	case 'a':
+		dst = append(dst, '[')
+		var err error
+		n.ArrayForEach(func(i int, v Node) bool {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			dst, err = v.appendCanonical(dst)
+			return err == nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return append(dst, ']'), nil
+	case 's':
+		raw := n.Raw()
+		return appendCanonicalString(dst, string(appendUnescaped(nil, raw[1:len(raw)-1]))), nil
+	case 'n':
+		f, err := strconv.ParseFloat(bytesToString(n.Raw()), 64)
+		if err != nil {
+			return nil, fmt.Errorf("number %s cannot be canonicalized: %w", n.Raw(), err)
+		}
+		return appendCanonicalNumber(dst, f), nil
+	case 'b', 'l':
+		return append(dst, n.Raw()...), nil
+	}
+	return nil, fmt.Errorf("unknown node type: %q", n.typ)
+}
+
+// appendCanonicalObject 规范化输出对象，跳过键 omit，并在 extra.key 非空时加入额外成员
+func (n Node) appendCanonicalObject(dst []byte, omit string, extra canonicalMember) ([]byte, error) {
+	members := make([]canonicalMember, 0, n.Len()+1)
+	n.ForEach(func(key string, value Node) bool {
+		if omit == "" || key != omit {
+			members = append(members, canonicalMember{key: key, value: value})
+		}
+		return true
+	})
+	if extra.key != "" {
+		members = append(members, extra)
+	}
+
+	slices.SortStableFunc(members, func(a, b canonicalMember) int {
+		return compareUTF16(a.key, b.key)
+	})
+
+	dst = append(dst, '{')
+	for i, m := range members {
+		if i > 0 {
+			if members[i-1].key == m.key {
+				return nil, fmt.Errorf("duplicate key %q cannot be canonicalized", m.key)
+			}
+			dst = append(dst, ',')
+		}
+		dst = appendCanonicalString(dst, m.key)
+		dst = append(dst, ':')
+		var err error
+		if dst, err = m.value.appendCanonical(dst); err != nil {
+			return nil, err
+		}
+	}
+	return append(dst, '}'), nil
+}
+
+// compareUTF16 按 UTF-16 码元比较两个字符串
+func compareUTF16(a, b string) int {
+	if isASCII(a) && isASCII(b) {
+		switch {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		}
+		return 0
+	}
+	return slices.Compare(utf16.Encode([]rune(a)), utf16.Encode([]rune(b)))
+}
+
+// isASCII 字符串是否只含 ASCII 字符
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
+
+// appendCanonicalString 按 RFC 8785 规则输出字符串
+func appendCanonicalString(dst []byte, s string) []byte {
+	const hex = "0123456789abcdef"
+	dst = append(dst, '"')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '"' || c == '\\':
+			dst = append(dst, '\\', c)
+		case c >= 0x20:
+			dst = append(dst, c)
+		case c == '\b':
+			dst = append(dst, '\\', 'b')
+		case c == '\f':
+			dst = append(dst, '\\', 'f')
+		case c == '\n':
+			dst = append(dst, '\\', 'n')
+		case c == '\r':
+			dst = append(dst, '\\', 'r')
+		case c == '\t':
+			dst = append(dst, '\\', 't')
+		default:
+			dst = append(dst, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xF])
+		}
+	}
+	return append(dst, '"')
+}
+
+// appendCanonicalNumber 按 ECMAScript Number.prototype.toString 规则输出数字
+func appendCanonicalNumber(dst []byte, f float64) []byte {
+	if f == 0 {
+		return append(dst, '0') // -0 也输出为 0
+	}
+	if abs := math.Abs(f); abs >= 1e-6 && abs < 1e21 {
+		return strconv.AppendFloat(dst, f, 'f', -1, 64)
+	}
+
+	// 指数形式：Go 输出 "1e-07"，ECMAScript 为 "1e-7"
+	start := len(dst)
+	dst = strconv.AppendFloat(dst, f, 'e', -1, 64)
+	e := start + slices.Index(dst[start:], 'e')
+	digits := e + 2
+	for digits < len(dst)-1 && dst[digits] == '0' {
+		dst = append(dst[:digits], dst[digits+1:]...)
+	}
+	return dst
+}
@@ -0,0 +1,30 @@
+package fxjson
+
+import "testing"
+
+// TestCanonical 测试 RFC 8785 规范化序列化
+func TestCanonical(t *testing.T) {
+	tests := []struct{ input, want string }{
+		{`{ "b" : 2, "a" : [1.0, -0, 1e21, 1E-7, 0.000001, 123.456e3] }`, `{"a":[1,0,1e+21,1e-7,0.000001,123456],"b":2}`},
+		{`{"€":1,"\r":2,"דּ":6,"😀":3,"é":4,"1":5}`, `{"\r":2,"1":5,"é":4,"€":1,"😀":3,"דּ":6}`},
+		{`"a\u0001\"\\\/é\n"`, `"a\u0001\"\\/é\n"`},
+		{`[true, null, {"z":{}, "y":[]}]`, `[true,null,{"y":[],"z":{}}]`},
+		{`1E400`, ``},
+	}
+	for _, tt := range tests {
+		got, err := FromString(tt.input).Canonical()
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("Canonical(%s) = %s, want error", tt.input, got)
+			}
+			continue
+		}
+		if err != nil || string(got) != tt.want {
+			t.Errorf("Canonical(%s) = %s, %v, want %s", tt.input, got, err, tt.want)
+		}
+	}
+
+	if _, err := FromString(`{"a":1,"a":2}`).Canonical(); err == nil {
+		t.Error("duplicate keys should fail")
+	}
+}
@@ -0,0 +1,115 @@
+package fxjson
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// EncoderFunc 自定义类型的序列化函数，需要向 buf 写入一个完整的 JSON 值
+type EncoderFunc func(buf *Buffer, v reflect.Value) error
+
+// DecoderFunc 自定义类型的解码函数，将节点解码到可设置的 v 中
+type DecoderFunc func(n Node, v reflect.Value) error
+
+var (
+	customEncoders     sync.Map // map[reflect.Type]EncoderFunc
+	customDecoders     sync.Map // map[reflect.Type]DecoderFunc
+	customEncoderCount atomic.Int32
+	customDecoderCount atomic.Int32
+)
+
+// RegisterEncoder 为类型 t 全局注册序列化函数，适用于无法实现 json.Marshaler 的第三方类型（如 decimal.Decimal、net.IP）
+// 查找使用解引用后的类型：注册 T 同样作用于 *T（nil 指针输出 null）。fn 为 nil 时取消注册
+func RegisterEncoder(t reflect.Type, fn EncoderFunc) {
+	registerCodec(&customEncoders, &customEncoderCount, t, fn, fn == nil)
+}
+
+// RegisterDecoder 为类型 t 全局注册解码函数，Decode 与 DecodeStruct 系列遇到该类型（或 *T）时调用
+// JSON null 不会调用 fn，目标被置为零值。fn 为 nil 时取消注册
+func RegisterDecoder(t reflect.Type, fn DecoderFunc) {
+	registerCodec(&customDecoders, &customDecoderCount, t, fn, fn == nil)
+}
+
+// registerCodec 更新注册表并维护计数，计数为 0 时序列化与解码跳过查找
+func registerCodec(m *sync.Map, count *atomic.Int32, t reflect.Type, fn any, remove bool) {
+	if t == nil {
+		return
+	}
+	if remove {
+		if _, loaded := m.LoadAndDelete(t); loaded {
+			count.Add(-1)
+		}
+		return
+	}
+	if _, loaded := m.Swap(t, fn); !loaded {
+		count.Add(1)
+	}
+}
+
+// lookupEncoder 查找类型的自定义序列化函数
+func lookupEncoder(t reflect.Type) (EncoderFunc, bool) {
+	if customEncoderCount.Load() == 0 {
+		return nil, false
+	}
+	fn, ok := customEncoders.Load(t)
+	if !ok {
+		return nil, false
+	}
+	return fn.(EncoderFunc), true
+}
+
+// lookupDecoder 查找类型的自定义解码函数
+func lookupDecoder(t reflect.Type) (DecoderFunc, bool) {
+	if customDecoderCount.Load() == 0 {
+		return nil, false
+	}
+	fn, ok := customDecoders.Load(t)
+	if !ok {
+		return nil, false
+	}
+	return fn.(DecoderFunc), true
+}
+
+// encodeCustom 使用已注册的序列化函数写入 rv，返回是否已处理
+func encodeCustom(buf *Buffer, rv reflect.Value) (bool, error) {
+	fn, ok := lookupEncoder(rv.Type())
+	if !ok {
+		return false, nil
+	}
+	if err := fn(buf, rv); err != nil {
+		return true, fmt.Errorf("encoder for %s: %w", rv.Type(), err)
+	}
+	return true, nil
+}
+
+// decodeCustom 使用已注册的解码函数解码到 rv（支持 *T 目标），返回是否已处理
+func (n Node) decodeCustom(rv reflect.Value) (bool, error) {
+	if customDecoderCount.Load() == 0 {
+		return false, nil
+	}
+
+	t := rv.Type()
+	fn, ok := lookupDecoder(t)
+	if !ok && t.Kind() == reflect.Ptr {
+		if fn, ok = lookupDecoder(t.Elem()); ok && n.typ != 'l' {
+			if rv.IsNil() {
+				rv.Set(reflect.New(t.Elem()))
+			}
+			rv = rv.Elem()
+		}
+	}
+	if !ok {
+		return false, nil
+	}
+
+	if n.typ == 'l' {
+		rv.Set(reflect.Zero(rv.Type()))
+		return true, nil
+	}
+	if err := fn(n, rv); err != nil {
+		return true, fmt.Errorf("decoder for %s: %w", rv.Type(), err)
+	}
+	return true, nil
+}
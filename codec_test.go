@@ -0,0 +1,217 @@
+package fxjson
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type codecLevel int
+
+const (
+	codecLevelLow codecLevel = iota
+	codecLevelHigh
+)
+
+var codecLevelNames = []string{"low", "high"}
+
+type codecAlert struct {
+	Level  codecLevel  `json:"level"`
+	Addr   net.IP      `json:"addr"`
+	At     time.Time   `json:"at"`
+	Prev   *codecLevel `json:"prev"`
+	Levels []codecLevel
+}
+
+// registerCodecTestTypes 注册测试用的编解码函数，并在测试结束时取消注册
+func registerCodecTestTypes(t *testing.T) {
+	levelType := reflect.TypeOf(codecLevel(0))
+	ipType := reflect.TypeOf(net.IP(nil))
+	timeType := reflect.TypeOf(time.Time{})
+
+	RegisterEncoder(levelType, func(buf *Buffer, v reflect.Value) error {
+		i := int(v.Int())
+		if i < 0 || i >= len(codecLevelNames) {
+			return fmt.Errorf("unknown level %d", i)
+		}
+		buf.WriteJSONString(codecLevelNames[i])
+		return nil
+	})
+	RegisterDecoder(levelType, func(n Node, v reflect.Value) error {
+		s, err := n.String()
+		if err != nil {
+			return err
+		}
+		for i, name := range codecLevelNames {
+			if name == s {
+				v.SetInt(int64(i))
+				return nil
+			}
+		}
+		return fmt.Errorf("unknown level %q", s)
+	})
+	RegisterEncoder(ipType, func(buf *Buffer, v reflect.Value) error {
+		buf.WriteJSONString(v.Interface().(net.IP).String())
+		return nil
+	})
+	RegisterDecoder(ipType, func(n Node, v reflect.Value) error {
+		s, _ := n.String()
+		ip := net.ParseIP(s)
+		if ip == nil {
+			return fmt.Errorf("invalid ip %q", s)
+		}
+		v.Set(reflect.ValueOf(ip))
+		return nil
+	})
+	RegisterEncoder(timeType, func(buf *Buffer, v reflect.Value) error {
+		buf.WriteString(strconv.FormatInt(v.Interface().(time.Time).Unix(), 10))
+		return nil
+	})
+	RegisterDecoder(timeType, func(n Node, v reflect.Value) error {
+		sec, err := n.Int()
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(time.Unix(sec, 0).UTC()))
+		return nil
+	})
+
+	t.Cleanup(func() {
+		for _, typ := range []reflect.Type{levelType, ipType, timeType} {
+			RegisterEncoder(typ, nil)
+			RegisterDecoder(typ, nil)
+		}
+	})
+}
+
+// TestCustomCodecs 测试自定义编解码函数注册
+func TestCustomCodecs(t *testing.T) {
+	registerCodecTestTypes(t)
+
+	high := codecLevelHigh
+	alert := codecAlert{
+		Level:  codecLevelHigh,
+		Addr:   net.ParseIP("10.0.0.1"),
+		At:     time.Unix(1700000000, 0).UTC(),
+		Prev:   &high,
+		Levels: []codecLevel{codecLevelLow, codecLevelHigh},
+	}
+
+	want := `{"level":"high","addr":"10.0.0.1","at":1700000000,"prev":"high","Levels":["low","high"]}`
+	got, err := Marshal(alert)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if string(got) != want {
+		t.Errorf("Marshal = %s, want %s", got, want)
+	}
+	if fast := FastMarshal(alert); string(fast) != want {
+		t.Errorf("FastMarshal = %s, want %s", fast, want)
+	}
+
+	var decoded codecAlert
+	if err := FromBytes(got).Decode(&decoded); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, alert) {
+		t.Errorf("Decode = %+v, want %+v", decoded, alert)
+	}
+
+	var direct codecAlert
+	if err := DecodeStruct([]byte(`{"level":"low","prev":null,"at":0}`), &direct); err != nil {
+		t.Fatalf("DecodeStruct failed: %v", err)
+	}
+	if direct.Level != codecLevelLow || direct.Prev != nil || !direct.At.Equal(time.Unix(0, 0)) {
+		t.Errorf("DecodeStruct = %+v", direct)
+	}
+
+	if _, err := Marshal(codecLevel(9)); err == nil || !strings.Contains(err.Error(), "unknown level") {
+		t.Errorf("Marshal(invalid) error = %v", err)
+	}
+	if err := FromString(`{"level":"urgent"}`).Decode(&decoded); err == nil {
+		t.Error("Decode(invalid) should fail")
+	}
+}
+
+// TestCustomCodecsUnregister 取消注册后恢复默认行为
+func TestCustomCodecsUnregister(t *testing.T) {
+	registerCodecTestTypes(t)
+
+	levelType := reflect.TypeOf(codecLevel(0))
+	RegisterEncoder(levelType, nil)
+	RegisterDecoder(levelType, nil)
+
+	if got, err := Marshal(codecLevelHigh); err != nil || string(got) != "1" {
+		t.Errorf("Marshal = %s, %v", got, err)
+	}
+	var level codecLevel
+	if err := FromString(`1`).Decode(&level); err != nil || level != codecLevelHigh {
+		t.Errorf("Decode = %v, %v", level, err)
+	}
+}
+
+// panicky 注册为会 panic 的自定义类型
+type panicky struct{ idx int }
+
+// TestPanicRecovery 导出接口将内部 panic 转换为错误
+func TestPanicRecovery(t *testing.T) {
+	typ := reflect.TypeOf(panicky{})
+	RegisterEncoder(typ, func(buf *Buffer, v reflect.Value) error {
+		var empty []byte
+		buf.WriteByte(empty[v.Field(0).Int()])
+		return nil
+	})
+	RegisterDecoder(typ, func(n Node, v reflect.Value) error {
+		panic("decoder exploded")
+	})
+	t.Cleanup(func() {
+		RegisterEncoder(typ, nil)
+		RegisterDecoder(typ, nil)
+	})
+
+	_, err := Marshal(map[string]any{"p": panicky{idx: 7}})
+	var fxErr *FxJSONError
+	if !errors.As(err, &fxErr) || fxErr.Type != ErrorTypeInternal || fxErr.Pos != 7 {
+		t.Fatalf("Marshal err = %#v", err)
+	}
+	var rtErr interface{ RuntimeError() }
+	if !errors.As(err, &rtErr) {
+		t.Errorf("cause should be the runtime error, got %v", fxErr.Cause)
+	}
+
+	buf := NewBuffer()
+	defer buf.Release()
+	buf.WriteString("ok")
+	if err := buf.EncodeValue(panicky{idx: 1}); err == nil || buf.String() != "ok" {
+		t.Errorf("EncodeValue = %v, buffer %q", err, buf.String())
+	}
+
+	var target struct {
+		P panicky `json:"p"`
+	}
+	node := FromString(`{"p": 1}`)
+	err = node.Decode(&target)
+	if !errors.As(err, &fxErr) || fxErr.Type != ErrorTypeInternal || !strings.Contains(err.Error(), "decoder exploded") {
+		t.Fatalf("Decode err = %v", err)
+	}
+	if fxErr.Context != "node range [0:8]" {
+		t.Errorf("Context = %q", fxErr.Context)
+	}
+	if err := DecodeStruct([]byte(`{"p": 1}`), &target); err == nil {
+		t.Error("DecodeStruct should return the recovered panic")
+	}
+
+	SetPanicRecovery(false)
+	defer SetPanicRecovery(true)
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("panic should propagate when recovery is disabled")
+		}
+	}()
+	_ = node.Decode(&target)
+}
@@ -0,0 +1,101 @@
+package fxjson
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Collator 区域相关的字符串比较规则，返回 -1、0 或 1
+// golang.org/x/text/collate 的 *collate.Collator 满足该接口，可直接用于 SortField.Collator：
+//
+//	c := collate.New(language.German, collate.IgnoreCase)
+//	qb.SortWith(fxjson.SortField{Field: "title", Collator: c})
+type Collator interface {
+	CompareString(a, b string) int
+}
+
+// hasCollation 是否设置了字符串比较选项
+func (sf SortField) hasCollation() bool {
+	return sf.Collator != nil || sf.CaseInsensitive || sf.Natural
+}
+
+// compareStrings 按排序字段的规则比较两个字符串节点
+func (sf SortField) compareStrings(a, b Node) int {
+	as, _ := a.String()
+	bs, _ := b.String()
+	if sf.Collator != nil {
+		return sf.Collator.CompareString(as, bs)
+	}
+	return collateStrings(as, bs, sf.Natural, sf.CaseInsensitive)
+}
+
+// collateStrings 逐字符比较字符串；natural 时连续数字按数值比较，fold 时忽略大小写
+func collateStrings(a, b string, natural, fold bool) int {
+	for a != "" && b != "" {
+		if natural && isDigitByte(a[0]) && isDigitByte(b[0]) {
+			da, db := digitRun(a), digitRun(b)
+			if cmp := compareDigits(a[:da], b[:db]); cmp != 0 {
+				return cmp
+			}
+			a, b = a[da:], b[db:]
+			continue
+		}
+
+		ra, na := utf8.DecodeRuneInString(a)
+		rb, nb := utf8.DecodeRuneInString(b)
+		if fold {
+			ra, rb = unicode.ToLower(ra), unicode.ToLower(rb)
+		}
+		if ra != rb {
+			if ra < rb {
+				return -1
+			}
+			return 1
+		}
+		a, b = a[na:], b[nb:]
+	}
+
+	switch {
+	case a == "" && b == "":
+		return 0
+	case a == "":
+		return -1
+	}
+	return 1
+}
+
+// digitRun 返回开头连续 ASCII 数字的长度
+func digitRun(s string) int {
+	i := 0
+	for i < len(s) && isDigitByte(s[i]) {
+		i++
+	}
+	return i
+}
+
+// compareDigits 按数值比较两个数字串，不受长度限制；数值相同时前导零较少的在前
+func compareDigits(a, b string) int {
+	ta, tb := strings.TrimLeft(a, "0"), strings.TrimLeft(b, "0")
+	if len(ta) != len(tb) {
+		if len(ta) < len(tb) {
+			return -1
+		}
+		return 1
+	}
+	if cmp := strings.Compare(ta, tb); cmp != 0 {
+		return cmp
+	}
+	switch {
+	case len(a) < len(b):
+		return -1
+	case len(a) > len(b):
+		return 1
+	}
+	return 0
+}
+
+// isDigitByte 是否为 ASCII 数字
+func isDigitByte(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,79 @@
+package fxjson
+
+import (
+	"strings"
+	"testing"
+)
+
+// accentCollator 测试用比较规则：忽略大小写并把 é 视为 e
+type accentCollator struct{}
+
+func (accentCollator) CompareString(a, b string) int {
+	fold := strings.NewReplacer("é", "e", "É", "e")
+	return strings.Compare(strings.ToLower(fold.Replace(a)), strings.ToLower(fold.Replace(b)))
+}
+
+// TestSortCollation 测试排序的字符串比较规则
+func TestSortCollation(t *testing.T) {
+	node := FromBytes([]byte(`[
+		{"t": "item 10"}, {"t": "banana"}, {"t": "Item 2"}, {"t": "été"}, {"t": "Apple"},
+		{"t": "item 02"}, {"t": "eu"}, {"t": null}
+	]`))
+
+	titles := func(qb *QueryBuilder) string {
+		items, err := qb.ToSlice()
+		if err != nil {
+			t.Fatalf("ToSlice failed: %v", err)
+		}
+		var out []string
+		for _, item := range items {
+			out = append(out, string(item.Get("t").Raw()))
+		}
+		return strings.Join(out, " | ")
+	}
+
+	cases := []struct {
+		name  string
+		field SortField
+		want  string
+	}{
+		{"bytes", SortField{Field: "t"},
+			`"Apple" | "Item 2" | "banana" | "eu" | "item 02" | "item 10" | "été" | null`},
+		{"case insensitive", SortField{Field: "t", CaseInsensitive: true},
+			`"Apple" | "banana" | "eu" | "item 02" | "item 10" | "Item 2" | "été" | null`},
+		{"natural", SortField{Field: "t", Natural: true, CaseInsensitive: true},
+			`"Apple" | "banana" | "eu" | "Item 2" | "item 02" | "item 10" | "été" | null`},
+		{"natural desc", SortField{Field: "t", Order: "desc", Natural: true, CaseInsensitive: true},
+			`"été" | "item 10" | "item 02" | "Item 2" | "eu" | "banana" | "Apple" | null`},
+		{"collator", SortField{Field: "t", Collator: accentCollator{}, Natural: true},
+			`"Apple" | "banana" | "été" | "eu" | "item 02" | "item 10" | "Item 2" | null`},
+	}
+	for _, c := range cases {
+		if got := titles(node.Query().SortWith(c.field)); got != c.want {
+			t.Errorf("%s: got %s\nwant %s", c.name, got, c.want)
+		}
+	}
+}
+
+// TestCollateStrings 测试自然排序与大小写折叠的比较结果
+func TestCollateStrings(t *testing.T) {
+	cases := []struct {
+		a, b          string
+		natural, fold bool
+		want          int
+	}{
+		{"file2", "file10", true, false, -1},
+		{"file2", "file10", false, false, 1},
+		{"a007", "a7", true, false, 1},
+		{"v1.10", "v1.9", true, false, 1},
+		{"x99999999999999999999999", "x100000000000000000000000", true, false, -1},
+		{"ÄBC", "äbc", false, true, 0},
+		{"abc", "abcd", true, true, -1},
+		{"", "", true, true, 0},
+	}
+	for _, c := range cases {
+		if got := collateStrings(c.a, c.b, c.natural, c.fold); got != c.want {
+			t.Errorf("collateStrings(%q, %q, %v, %v) = %d, want %d", c.a, c.b, c.natural, c.fold, got, c.want)
+		}
+	}
+}
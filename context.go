@@ -0,0 +1,48 @@
+package fxjson
+
+import "context"
+
+// ctxCheckInterval 循环中每处理多少项检查一次 ctx，避免每次迭代都读取 ctx 状态
+const ctxCheckInterval = 256
+
+// checkCtx 每 ctxCheckInterval 次迭代检查一次 ctx，ctx 为 nil 时不检查
+func checkCtx(ctx context.Context, i int) error {
+	if ctx == nil || i%ctxCheckInterval != 0 {
+		return nil
+	}
+	return ctx.Err()
+}
+
+// FromBytesCtx 与 ParseBytes 相同，但在安全检查后与每次嵌套 JSON 展开前检查 ctx，
+// ctx 取消或超时（如 context.WithTimeout 设置的时间预算）时返回 ctx.Err()
+func FromBytesCtx(ctx context.Context, b []byte, opts ParseOptions) (Node, error) {
+	if err := ctx.Err(); err != nil {
+		return Node{}, err
+	}
+	return parseBytes(ctx, b, opts)
+}
+
+// WalkCtx 与 Walk 相同，但每访问 ctxCheckInterval 个节点检查一次 ctx，
+// ctx 取消或超时后停止遍历并返回 ctx.Err()
+func (n Node) WalkCtx(ctx context.Context, fn WalkFunc) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if fn == nil {
+		return nil
+	}
+
+	var err error
+	visited := 0
+	n.Walk(func(path string, node Node) bool {
+		if err != nil {
+			return false
+		}
+		visited++
+		if err = checkCtx(ctx, visited); err != nil {
+			return false
+		}
+		return fn(path, node)
+	})
+	return err
+}
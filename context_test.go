@@ -0,0 +1,85 @@
+package fxjson
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// contextTestArray 构造包含 n 个对象的数组
+func contextTestArray(n int) Node {
+	var sb strings.Builder
+	sb.WriteByte('[')
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(`{"id":` + strconv.Itoa(i) + `,"g":"` + strconv.Itoa(i%3) + `"}`)
+	}
+	sb.WriteByte(']')
+	return FromString(sb.String())
+}
+
+// TestContextCancellation 测试解析、遍历、查询与聚合在 ctx 取消后返回 ctx.Err()
+func TestContextCancellation(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancelTimeout := context.WithTimeout(context.Background(), -time.Second)
+	defer cancelTimeout()
+
+	data := []byte(`{"a":"{\"b\":1}"}`)
+	if _, err := FromBytesCtx(canceled, data, DefaultParseOptions); !errors.Is(err, context.Canceled) {
+		t.Errorf("FromBytesCtx err = %v", err)
+	}
+	if _, err := FromBytesCtx(expired, data, DefaultParseOptions); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("FromBytesCtx err = %v", err)
+	}
+	node, err := FromBytesCtx(context.Background(), data, DefaultParseOptions)
+	if err != nil || node.GetPath("a.b").IntOr(0) != 1 {
+		t.Errorf("FromBytesCtx = %s, %v", node.Raw(), err)
+	}
+
+	arr := contextTestArray(1000)
+
+	if err := arr.WalkCtx(canceled, func(string, Node) bool { return true }); !errors.Is(err, context.Canceled) {
+		t.Errorf("WalkCtx err = %v", err)
+	}
+	// 遍历途中取消
+	ctx, stop := context.WithCancel(context.Background())
+	visited := 0
+	err = arr.WalkCtx(ctx, func(string, Node) bool {
+		visited++
+		if visited == 10 {
+			stop()
+		}
+		return true
+	})
+	if !errors.Is(err, context.Canceled) || visited >= 3000 {
+		t.Errorf("WalkCtx err = %v after %d nodes", err, visited)
+	}
+	visited = 0
+	if err := arr.WalkCtx(context.Background(), func(string, Node) bool { visited++; return true }); err != nil || visited != 3001 {
+		t.Errorf("WalkCtx = %v, visited %d", err, visited)
+	}
+
+	if _, err := arr.Query().WithContext(canceled).Where("id", ">", 10).ToSlice(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Query err = %v", err)
+	}
+	if n, err := arr.Query().WithContext(context.Background()).Where("id", "<", 10).Count(); err != nil || n != 10 {
+		t.Errorf("Query Count = %d, %v", n, err)
+	}
+
+	if _, err := arr.Aggregate().WithContext(canceled).Count("n").Execute(arr); !errors.Is(err, context.Canceled) {
+		t.Errorf("Aggregate err = %v", err)
+	}
+	if _, err := arr.Aggregate().WithContext(expired).GroupBy("g").Count("n").Execute(arr); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("grouped Aggregate err = %v", err)
+	}
+	res, err := arr.Aggregate().WithContext(context.Background()).GroupBy("g").Count("n").Execute(arr)
+	if err != nil || len(res) != 3 {
+		t.Errorf("Aggregate = %v, %v", res, err)
+	}
+}
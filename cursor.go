@@ -0,0 +1,202 @@
+package fxjson
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// CursorState 游标的可序列化位置：最近返回节点的路径与其在文档中的字节偏移
+type CursorState struct {
+	Path         string `json:"path"`
+	Offset       int    `json:"offset"`        // -1 表示尚未返回任何节点
+	SkipChildren bool   `json:"skip_children"` // 最近返回的节点的子节点是否已跳过
+}
+
+// cursorFrame 游标栈帧：正在遍历的容器节点及下一个子元素的扫描位置
+type cursorFrame struct {
+	node  Node
+	path  string
+	pos   int
+	index int
+}
+
+// Cursor 可暂停、可恢复的深度优先遍历游标，遍历顺序与 Walk 相同
+type Cursor struct {
+	root     Node
+	stack    []cursorFrame
+	started  bool
+	lastPath string
+	lastPos  int
+	lastNode Node
+	skipped  bool
+}
+
+// NewCursor 创建从根节点开始的游标
+func (n Node) NewCursor() *Cursor {
+	return &Cursor{root: n, lastPos: -1}
+}
+
+// Next 返回下一个节点及其路径，遍历结束时 ok 为 false
+func (c *Cursor) Next() (path string, node Node, ok bool) {
+	if !c.started {
+		c.started = true
+		if !c.root.Exists() {
+			return "", Node{}, false
+		}
+		c.emit("", c.root)
+		return "", c.root, true
+	}
+
+	for len(c.stack) > 0 {
+		frame := &c.stack[len(c.stack)-1]
+		childPath, child, found := frame.next()
+		if !found {
+			c.stack = c.stack[:len(c.stack)-1]
+			continue
+		}
+		c.emit(childPath, child)
+		return childPath, child, true
+	}
+	return "", Node{}, false
+}
+
+// SkipChildren 跳过最近一次 Next 返回节点的子节点
+func (c *Cursor) SkipChildren() {
+	if c.skipped || c.lastPos < 0 || !isContainer(c.lastNode) {
+		return
+	}
+	if len(c.stack) > 0 && c.stack[len(c.stack)-1].node.start == c.lastNode.start {
+		c.stack = c.stack[:len(c.stack)-1]
+	}
+	c.skipped = true
+}
+
+// State 返回当前游标位置，可序列化保存并在之后通过 ResumeCursor 恢复
+func (c *Cursor) State() CursorState {
+	return CursorState{Path: c.lastPath, Offset: c.lastPos, SkipChildren: c.skipped}
+}
+
+// ResumeCursor 基于同一文档与保存的位置恢复游标；文档与位置不匹配时返回错误
+func (n Node) ResumeCursor(state CursorState) (*Cursor, error) {
+	c := n.NewCursor()
+	if state.Offset < 0 {
+		return c, nil
+	}
+	if !n.Exists() {
+		return nil, fmt.Errorf("cannot resume cursor on a non-existent node")
+	}
+
+	c.started = true
+	current, currentPath := n, ""
+	for current.start != state.Offset {
+		if !isContainer(current) || state.Offset < current.start || state.Offset >= current.end {
+			return nil, fmt.Errorf("cursor offset %d does not match the document", state.Offset)
+		}
+
+		frame := cursorFrame{node: current, path: currentPath, pos: current.start + 1}
+		var child Node
+		var childPath string
+		for {
+			var found bool
+			childPath, child, found = frame.next()
+			if !found {
+				return nil, fmt.Errorf("cursor offset %d does not match the document", state.Offset)
+			}
+			if state.Offset < child.end {
+				break
+			}
+		}
+		c.stack = append(c.stack, frame)
+		current, currentPath = child, childPath
+	}
+
+	if currentPath != state.Path {
+		return nil, fmt.Errorf("cursor path mismatch at offset %d: expected %q, found %q", state.Offset, state.Path, currentPath)
+	}
+
+	c.emit(currentPath, current)
+	if state.SkipChildren {
+		c.SkipChildren()
+	}
+	return c, nil
+}
+
+// emit 记录返回的节点，并在其为容器时压栈以便后续遍历子节点
+func (c *Cursor) emit(path string, node Node) {
+	c.lastPath = path
+	c.lastPos = node.start
+	c.lastNode = node
+	c.skipped = false
+	if isContainer(node) {
+		c.stack = append(c.stack, cursorFrame{node: node, path: path, pos: node.start + 1})
+	}
+}
+
+// isContainer 判断节点是否为对象或数组
+func isContainer(n Node) bool {
+	return n.typ == 'o' || n.typ == 'a'
+}
+
+// next 扫描容器的下一个子元素
+func (f *cursorFrame) next() (string, Node, bool) {
+	data := f.node.getWorkingData()
+	end := f.node.end - 1
+	pos := f.pos
+
+	for pos < end && (data[pos] <= ' ' || data[pos] == ',') {
+		pos++
+	}
+	if pos >= end {
+		f.pos = pos
+		return "", Node{}, false
+	}
+
+	var childPath string
+	if f.node.typ == 'o' {
+		if data[pos] != '"' {
+			return "", Node{}, false
+		}
+		keyEnd := skipStringSimple(data, pos, end)
+		if keyEnd < pos+2 || data[keyEnd-1] != '"' {
+			return "", Node{}, false
+		}
+		var scratch []byte
+		key := keyString(data[pos+1:keyEnd-1], &scratch)
+		if f.path == "" {
+			childPath = key
+		} else {
+			childPath = f.path + "." + key
+		}
+
+		pos = keyEnd
+		for pos < end && data[pos] <= ' ' {
+			pos++
+		}
+		if pos >= end || data[pos] != ':' {
+			return "", Node{}, false
+		}
+		pos++
+		for pos < end && data[pos] <= ' ' {
+			pos++
+		}
+	} else {
+		childPath = f.path + "[" + strconv.Itoa(f.index) + "]"
+	}
+
+	valueEnd := skipValueFast(data, pos, end)
+	if valueEnd <= pos {
+		return "", Node{}, false
+	}
+
+	child := Node{
+		raw:      f.node.raw,
+		start:    pos,
+		end:      valueEnd,
+		typ:      detectType(data[pos]),
+		expanded: f.node.expanded,
+		lazy:     f.node.lazy,
+	}
+	f.pos = valueEnd
+	f.index++
+	return childPath, child, true
+}
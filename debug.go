@@ -3,9 +3,13 @@ package fxjson
 import (
 	"encoding/json"
 	"fmt"
+	"path"
 	"runtime"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 // DebugInfo 调试信息
@@ -19,6 +23,34 @@ type DebugInfo struct {
 	Suggestions      []string      `json:"suggestions"`
 	PerformanceHints []string      `json:"performance_hints"`
 	StackTrace       []string      `json:"stack_trace,omitempty"`
+	MemoryCaptured   bool          `json:"memory_captured"`            // MemoryUsage 是否来自 runtime.ReadMemStats
+	SlowestSubtrees  []SubtreeTime `json:"slowest_subtrees,omitempty"` // 分析耗时最长的容器子树，按耗时降序
+}
+
+// SubtreeTime 单个容器子树的分析耗时
+type SubtreeTime struct {
+	Path      string        `json:"path"` // 路径格式同 Walk，根节点为 ""
+	Duration  time.Duration `json:"duration"`
+	NodeCount int           `json:"node_count"` // 子树节点数（包括容器自身）
+}
+
+// DebugOptions 调试解析选项
+type DebugOptions struct {
+	// CaptureMemStats 解析前后调用 runtime.ReadMemStats 计算 MemoryUsage；
+	// ReadMemStats 会短暂暂停所有 goroutine，生产环境可关闭
+	CaptureMemStats bool
+	SlowestSubtrees int // 记录分析耗时最长的容器子树数量，0 表示不计时
+}
+
+// DefaultDebugOptions 默认调试选项
+var DefaultDebugOptions = DebugOptions{
+	CaptureMemStats: true,
+	SlowestSubtrees: 5,
+}
+
+// ToJSON 使用 fxjson 序列化调试信息，字段名为结构体标签中的名称且保持稳定，时长以纳秒整数表示
+func (d *DebugInfo) ToJSON() ([]byte, error) {
+	return Marshal(d)
 }
 
 // ParseError 增强的解析错误
@@ -84,6 +116,8 @@ func (dl *DefaultLogger) Error(message string, fields map[string]interface{}) {
 var globalLogger Logger = &DefaultLogger{}
 
 // SetLogger 设置全局日志器
+//
+// Deprecated: 新代码请使用 debugx.SetLogger，该函数仅为兼容保留
 func SetLogger(logger Logger) {
 	globalLogger = logger
 }
@@ -102,27 +136,42 @@ func DisableDebugMode() {
 }
 
 // FromBytesWithDebug 带调试信息的JSON解析
+//
+// Deprecated: 新代码请使用 debugx.Parse，该函数仅为兼容保留
 func FromBytesWithDebug(b []byte) (Node, *DebugInfo) {
+	return FromBytesWithDebugOptions(b, DefaultDebugOptions)
+}
+
+// FromBytesWithDebugOptions 按选项进行带调试信息的JSON解析
+//
+// Deprecated: 新代码请使用 debugx.ParseWithOptions，该函数仅为兼容保留
+func FromBytesWithDebugOptions(b []byte, opts DebugOptions) (Node, *DebugInfo) {
 	debugInfo := &DebugInfo{
 		Warnings:         make([]string, 0),
 		Suggestions:      make([]string, 0),
 		PerformanceHints: make([]string, 0),
+		MemoryCaptured:   opts.CaptureMemStats,
 	}
 
 	start := time.Now()
 	var m1, m2 runtime.MemStats
-	runtime.ReadMemStats(&m1)
+	if opts.CaptureMemStats {
+		runtime.ReadMemStats(&m1)
+	}
 
 	// 执行解析
 	node := FromBytes(b)
 
 	// 收集调试信息
 	debugInfo.ParseTime = time.Since(start)
-	runtime.ReadMemStats(&m2)
-	debugInfo.MemoryUsage = int64(m2.Alloc - m1.Alloc)
+	if opts.CaptureMemStats {
+		runtime.ReadMemStats(&m2)
+		debugInfo.MemoryUsage = int64(m2.Alloc - m1.Alloc)
+	}
 
 	// 分析节点结构
-	analyzeNode(node, debugInfo, 0)
+	analyzer := debugAnalyzer{info: debugInfo, slowest: opts.SlowestSubtrees}
+	analyzer.analyze(node, "", 0)
 
 	// 生成性能建议
 	generatePerformanceHints(b, debugInfo)
@@ -141,8 +190,15 @@ func FromBytesWithDebug(b []byte) (Node, *DebugInfo) {
 	return node, debugInfo
 }
 
-// analyzeNode 分析节点结构
-func analyzeNode(node Node, debugInfo *DebugInfo, depth int) {
+// debugAnalyzer 分析节点结构并记录耗时最长的容器子树
+type debugAnalyzer struct {
+	info    *DebugInfo
+	slowest int
+}
+
+// analyze 分析节点结构
+func (a *debugAnalyzer) analyze(node Node, path string, depth int) {
+	debugInfo := a.info
 	debugInfo.NodeCount++
 
 	if depth > debugInfo.MaxDepth {
@@ -155,11 +211,22 @@ func analyzeNode(node Node, debugInfo *DebugInfo, depth int) {
 			fmt.Sprintf("Deep nesting detected at depth %d, consider flattening the structure", depth))
 	}
 
+	var start time.Time
+	nodesBefore := debugInfo.NodeCount
+	timed := a.slowest > 0 && (node.typ == 'o' || node.typ == 'a')
+	if timed {
+		start = time.Now()
+	}
+
 	switch node.Type() {
 	case 'o':
 		// 分析对象
 		node.ForEach(func(key string, value Node) bool {
-			analyzeNode(value, debugInfo, depth+1)
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			a.analyze(value, childPath, depth+1)
 
 			// 检查空字符串键
 			if key == "" {
@@ -184,7 +251,7 @@ func analyzeNode(node Node, debugInfo *DebugInfo, depth int) {
 		}
 
 		for i := 0; i < arrayLen; i++ {
-			analyzeNode(node.Index(i), debugInfo, depth+1)
+			a.analyze(node.Index(i), path+"["+strconv.Itoa(i)+"]", depth+1)
 		}
 
 	case 's':
@@ -196,6 +263,25 @@ func analyzeNode(node Node, debugInfo *DebugInfo, depth int) {
 			}
 		}
 	}
+
+	if timed {
+		a.record(SubtreeTime{Path: path, Duration: time.Since(start), NodeCount: debugInfo.NodeCount - nodesBefore + 1})
+	}
+}
+
+// record 按耗时降序插入子树耗时，只保留最慢的 slowest 个
+func (a *debugAnalyzer) record(t SubtreeTime) {
+	list := a.info.SlowestSubtrees
+	if len(list) == a.slowest && t.Duration <= list[len(list)-1].Duration {
+		return
+	}
+	i := sort.Search(len(list), func(i int) bool { return list[i].Duration < t.Duration })
+	if len(list) < a.slowest {
+		list = append(list, SubtreeTime{})
+	}
+	copy(list[i+1:], list[i:])
+	list[i] = t
+	a.info.SlowestSubtrees = list
 }
 
 // generatePerformanceHints 生成性能建议
@@ -287,11 +373,7 @@ func prettyPrintNode(node Node, depth int, indent string) string {
 
 	case 'n':
 		if num, err := node.Float(); err == nil {
-			// 检查是否为整数
-			if float64(int64(num)) == num {
-				return fmt.Sprintf("%d", int64(num))
-			}
-			return fmt.Sprintf("%g", num)
+			return FormatNumber(num)
 		}
 		return "<invalid number>"
 
@@ -320,24 +402,69 @@ func escapeString(s string) string {
 	return s
 }
 
+// InspectOptions 节点检查选项，用于控制检查结果的大小与敏感数据
+type InspectOptions struct {
+	IncludeRaw     bool     // 输出原始内容（raw），按 RedactPatterns 脱敏并按 MaxValueLen 截断
+	MaxValueLen    int      // 字符串值与原始内容的最大字节数，超出部分截断并追加 "..."，0 表示不限制
+	MaxKeys        int      // 列出的对象键数量上限（key_count 仍为总数），0 表示不限制
+	RedactPatterns []string // 键名匹配任一模式（path.Match 语法，但 * 与 ? 也匹配 '/'，不区分大小写）的值在 raw 中替换为 "[REDACTED]"
+}
+
+// DefaultRedactPatterns 常见敏感字段的键名模式
+var DefaultRedactPatterns = []string{
+	"*password*", "*passwd*", "*secret*", "*token*", "*api_key*", "*api-key*", "*apikey*",
+	"*authorization*", "*cookie*", "*session*", "*ssn*", "*card_number*", "*credit_card*",
+}
+
+// SafeInspectOptions 适合生产环境日志的检查选项
+var SafeInspectOptions = InspectOptions{
+	IncludeRaw:     true,
+	MaxValueLen:    64,
+	MaxKeys:        50,
+	RedactPatterns: DefaultRedactPatterns,
+}
+
+// redactedPlaceholder 脱敏后的值
+const redactedPlaceholder = `"[REDACTED]"`
+
 // Inspect 详细检查节点
 func (n Node) Inspect() map[string]interface{} {
+	return n.InspectWithOptions(InspectOptions{IncludeRaw: true})
+}
+
+// InspectSafe 按 SafeInspectOptions 检查节点：原始内容脱敏并截断，限制键列表与值预览的长度
+func (n Node) InspectSafe() map[string]interface{} {
+	return n.InspectWithOptions(SafeInspectOptions)
+}
+
+// InspectWithOptions 按选项检查节点
+func (n Node) InspectWithOptions(opts InspectOptions) map[string]interface{} {
 	info := map[string]interface{}{
 		"type":   n.Type(),
 		"exists": n.Exists(),
-		"raw":    string(n.Raw()),
+		"size":   n.SizeBytes(),
+	}
+	if opts.IncludeRaw {
+		info["raw"] = n.inspectRaw(opts)
 	}
 
 	switch n.Type() {
 	case 'o':
 		info["key_count"] = 0
 		keys := make([]string, 0)
+		count := 0
 		n.ForEach(func(key string, value Node) bool {
-			keys = append(keys, key)
+			count++
+			if opts.MaxKeys <= 0 || len(keys) < opts.MaxKeys {
+				keys = append(keys, key)
+			}
 			return true
 		})
-		info["key_count"] = len(keys)
+		info["key_count"] = count
 		info["keys"] = keys
+		if len(keys) < count {
+			info["keys_truncated"] = true
+		}
 
 	case 'a':
 		info["length"] = n.Len()
@@ -348,6 +475,14 @@ func (n Node) Inspect() map[string]interface{} {
 	case 's':
 		if str, err := n.String(); err == nil {
 			info["length"] = len(str)
+			if opts.MaxValueLen > 0 {
+				if len(str) > opts.MaxValueLen {
+					str = truncateUTF8(str, opts.MaxValueLen) + "..."
+					info["truncated"] = true
+				}
+				info["value"] = str
+				break
+			}
 			info["value"] = str
 			if len(str) > 100 {
 				info["preview"] = str[:100] + "..."
@@ -369,6 +504,93 @@ func (n Node) Inspect() map[string]interface{} {
 	return info
 }
 
+// inspectRaw 返回按选项脱敏并截断后的原始内容
+func (n Node) inspectRaw(opts InspectOptions) string {
+	if len(opts.RedactPatterns) == 0 {
+		raw := string(n.Raw())
+		if opts.MaxValueLen > 0 && len(raw) > opts.MaxValueLen {
+			raw = truncateUTF8(raw, opts.MaxValueLen) + "..."
+		}
+		return raw
+	}
+
+	patterns := make([]string, len(opts.RedactPatterns))
+	for i, p := range opts.RedactPatterns {
+		patterns[i] = redactMatchForm(p)
+	}
+	var buf []byte
+	n.appendRedacted(&buf, patterns, opts.MaxValueLen)
+	if opts.MaxValueLen > 0 && len(buf) > opts.MaxValueLen {
+		return truncateUTF8(string(buf), opts.MaxValueLen) + "..."
+	}
+	return string(buf)
+}
+
+// appendRedacted 输出紧凑 JSON，键名匹配模式的值替换为占位符；limit 大于 0 时输出超过 limit 后停止
+func (n Node) appendRedacted(buf *[]byte, patterns []string, limit int) {
+	full := func() bool { return limit > 0 && len(*buf) > limit }
+	switch n.typ {
+	case 'o':
+		*buf = append(*buf, '{')
+		first := true
+		n.ForEach(func(key string, value Node) bool {
+			if !first {
+				*buf = append(*buf, ',')
+			}
+			first = false
+			*buf = append(*buf, quoteKey(key)...)
+			*buf = append(*buf, ':')
+			if matchRedactPattern(patterns, key) {
+				*buf = append(*buf, redactedPlaceholder...)
+			} else {
+				value.appendRedacted(buf, patterns, limit)
+			}
+			return !full()
+		})
+		*buf = append(*buf, '}')
+	case 'a':
+		*buf = append(*buf, '[')
+		n.ArrayForEach(func(i int, value Node) bool {
+			if i > 0 {
+				*buf = append(*buf, ',')
+			}
+			value.appendRedacted(buf, patterns, limit)
+			return !full()
+		})
+		*buf = append(*buf, ']')
+	default:
+		*buf = append(*buf, n.Raw()...)
+	}
+}
+
+// matchRedactPattern 键名是否匹配任一脱敏模式（模式已经过 redactMatchForm 转换）
+func matchRedactPattern(patterns []string, key string) bool {
+	key = redactMatchForm(key)
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// redactMatchForm 将模式或键名转为小写，并把 '/' 替换为 NUL，
+// 使 path.Match 的 * 与 ? 也能匹配键名中的 '/'（如 "auth/token"），模式中的 '/' 仍按字面匹配
+func redactMatchForm(s string) string {
+	return strings.ReplaceAll(strings.ToLower(s), "/", "\x00")
+}
+
+// truncateUTF8 将字符串截断到最多 n 个字节，不拆分多字节字符
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
 // Diff 比较两个JSON节点的差异
 func (n Node) Diff(other Node) []DiffResult {
 	var results []DiffResult
@@ -0,0 +1,92 @@
+package fxjson
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// TestDebugOptions 测试调试选项、子树计时与调试信息序列化
+func TestDebugOptions(t *testing.T) {
+	data := []byte(`{"users":[{"name":"a","tags":["x","y"]},{"name":"b"}],"meta":{"total":2}}`)
+
+	_, info := FromBytesWithDebugOptions(data, DebugOptions{SlowestSubtrees: 3})
+	if info.MemoryCaptured || info.MemoryUsage != 0 {
+		t.Errorf("memory stats captured when disabled: %+v", info)
+	}
+	if len(info.SlowestSubtrees) != 3 {
+		t.Fatalf("SlowestSubtrees = %+v", info.SlowestSubtrees)
+	}
+	for i := 1; i < len(info.SlowestSubtrees); i++ {
+		if info.SlowestSubtrees[i].Duration > info.SlowestSubtrees[i-1].Duration {
+			t.Errorf("SlowestSubtrees not sorted: %+v", info.SlowestSubtrees)
+		}
+	}
+	paths := map[string]int{}
+	_, all := FromBytesWithDebugOptions(data, DebugOptions{SlowestSubtrees: 10})
+	for _, s := range all.SlowestSubtrees {
+		paths[s.Path] = s.NodeCount
+	}
+	if len(paths) != 6 || paths[""] != all.NodeCount || paths["users[0].tags"] != 3 || paths["meta"] != 2 {
+		t.Errorf("subtree paths = %v", paths)
+	}
+
+	_, info = FromBytesWithDebug(data)
+	if !info.MemoryCaptured {
+		t.Error("FromBytesWithDebug should capture memory stats")
+	}
+	out, err := info.ToJSON()
+	if err != nil {
+		t.Fatalf("ToJSON error: %v", err)
+	}
+	node := FromBytes(out)
+	if n, _ := node.Get("node_count").Int(); int(n) != info.NodeCount {
+		t.Errorf("node_count = %d, want %d in %s", n, info.NodeCount, out)
+	}
+	if !node.Get("slowest_subtrees").IsArray() || !node.Get("parse_time").IsNumber() || !node.Get("memory_captured").IsBool() {
+		t.Errorf("ToJSON = %s", out)
+	}
+}
+
+// TestInspectSafe 测试检查结果的脱敏与截断
+func TestInspectSafe(t *testing.T) {
+	node := FromString(`{"user":"ann","Password":"hunter2","auth":{"api_token":"t0k","scopes":["a","b"]},"bio":"` + strings.Repeat("é", 50) + `"}`)
+
+	info := node.InspectSafe()
+	raw := info["raw"].(string)
+	if strings.Contains(raw, "hunter2") || strings.Contains(raw, "t0k") || !strings.Contains(raw, `"Password":"[REDACTED]"`) {
+		t.Errorf("raw not redacted: %s", raw)
+	}
+	if len(raw) > SafeInspectOptions.MaxValueLen+3 || !strings.HasSuffix(raw, "...") {
+		t.Errorf("raw not truncated: %d bytes", len(raw))
+	}
+	if info["key_count"] != 4 || info["size"] != node.SizeBytes() {
+		t.Errorf("key_count = %v, size = %v", info["key_count"], info["size"])
+	}
+
+	bio := node.Get("bio").InspectWithOptions(InspectOptions{MaxValueLen: 9})
+	if v := bio["value"].(string); v != "éééé..." || !utf8.ValidString(v) || bio["truncated"] != true {
+		t.Errorf("bio value = %q", v)
+	}
+	if _, ok := bio["raw"]; ok {
+		t.Error("raw included without IncludeRaw")
+	}
+
+	keys := node.InspectWithOptions(InspectOptions{MaxKeys: 2})
+	if k := keys["keys"].([]string); len(k) != 2 || keys["keys_truncated"] != true {
+		t.Errorf("keys = %v", keys["keys"])
+	}
+
+	if full := node.Inspect(); full["raw"] != string(node.Raw()) {
+		t.Errorf("Inspect raw = %v", full["raw"])
+	}
+
+	// 模式中的 * 匹配键名中的 '/'
+	slashed := FromString(`{"auth/token":"abc123","x-api-key/v2":"k9","a/b":1}`).InspectWithOptions(InspectOptions{
+		IncludeRaw:     true,
+		RedactPatterns: append([]string{"a/*"}, DefaultRedactPatterns...),
+	})
+	if raw := slashed["raw"].(string); raw != `{"auth/token":"[REDACTED]","x-api-key/v2":"[REDACTED]","a/b":"[REDACTED]"}` {
+		t.Errorf("slashed keys raw = %s", raw)
+	}
+}
@@ -0,0 +1,62 @@
+// Package debugx groups fxjson's diagnostics: debug parsing, node
+// inspection and diffing, internal cache statistics and slow-operation
+// monitoring.
+//
+// The types are aliases of the ones in the root package. The root package
+// keeps the implementation, since the parser and Node methods report into it;
+// the root functions wrapped here are marked deprecated and remain only for
+// compatibility. This package is the entry point for new code.
+// Its exported surface is pinned by internal/apicheck.
+package debugx
+
+import (
+	"github.com/icloudza/fxjson"
+)
+
+type (
+	Info           = fxjson.DebugInfo          // 调试解析的统计信息
+	Options        = fxjson.DebugOptions       // 调试解析选项
+	SubtreeTime    = fxjson.SubtreeTime        // 子树解析耗时
+	InspectOptions = fxjson.InspectOptions     // 节点检查选项
+	DiffResult     = fxjson.DiffResult         // 节点差异
+	Logger         = fxjson.Logger             // 调试日志接口
+	CacheStats     = fxjson.InternalCacheStats // 内部缓存统计
+	CacheUsage     = fxjson.CacheUsage         // 单个内部缓存的使用情况
+	SlowOp         = fxjson.SlowOp             // 超过阈值的操作描述
+	SlowOpConfig   = fxjson.SlowOpConfig       // 慢操作监控配置
+)
+
+// Parse 解析数据并收集调试信息，同 fxjson.FromBytesWithDebug
+func Parse(b []byte) (fxjson.Node, *Info) {
+	return fxjson.FromBytesWithDebug(b)
+}
+
+// ParseWithOptions 按选项解析数据并收集调试信息，同 fxjson.FromBytesWithDebugOptions
+func ParseWithOptions(b []byte, opts Options) (fxjson.Node, *Info) {
+	return fxjson.FromBytesWithDebugOptions(b, opts)
+}
+
+// Inspect 返回节点的结构描述，同 n.InspectWithOptions(opts)
+func Inspect(n fxjson.Node, opts InspectOptions) map[string]interface{} {
+	return n.InspectWithOptions(opts)
+}
+
+// Diff 比较两个节点，同 a.Diff(b)
+func Diff(a, b fxjson.Node) []DiffResult {
+	return a.Diff(b)
+}
+
+// SetLogger 设置调试日志输出，同 fxjson.SetLogger
+func SetLogger(logger Logger) {
+	fxjson.SetLogger(logger)
+}
+
+// ReadCacheStats 返回内部缓存统计，同 fxjson.ReadCacheStats
+func ReadCacheStats() CacheStats {
+	return fxjson.ReadCacheStats()
+}
+
+// SetSlowOpConfig 设置慢操作监控，同 fxjson.SetSlowOpConfig
+func SetSlowOpConfig(cfg SlowOpConfig) {
+	fxjson.SetSlowOpConfig(cfg)
+}
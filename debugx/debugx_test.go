@@ -0,0 +1,32 @@
+package debugx
+
+import (
+	"testing"
+
+	"github.com/icloudza/fxjson"
+)
+
+// TestFacade 测试子包入口与根包 API 互通
+func TestFacade(t *testing.T) {
+	node, info := Parse([]byte(`{"a":[1,2],"b":{"c":true}}`))
+	if !node.Exists() || info == nil || info.NodeCount == 0 {
+		t.Fatalf("Parse = %v, %+v", node.Exists(), info)
+	}
+
+	diffs := Diff(fxjson.FromString(`{"a":1}`), fxjson.FromString(`{"a":2}`))
+	if len(diffs) != 1 {
+		t.Errorf("Diff = %+v", diffs)
+	}
+	if Inspect(node, InspectOptions{}) == nil {
+		t.Error("Inspect returned nil")
+	}
+
+	var calls int
+	SetSlowOpConfig(SlowOpConfig{MinBytes: 1, Handler: func(SlowOp) { calls++ }})
+	fxjson.FromString(`[1]`)
+	SetSlowOpConfig(SlowOpConfig{})
+	if calls != 1 {
+		t.Errorf("slow op calls = %d", calls)
+	}
+	_ = ReadCacheStats()
+}
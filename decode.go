@@ -0,0 +1,679 @@
+package fxjson
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DecodeOptions 用于控制结构体解码行为
+type DecodeOptions struct {
+	TagName string // 优先读取的结构体标签名（如 "fx"），未设置该标签的字段回退到 json 标签
+
+	// MatchNamingConventions 未显式命名的字段跨命名风格匹配 JSON 键
+	// （user_name / userName / UserName 均匹配字段 UserName）
+	MatchNamingConventions bool
+
+	// Int64Mode 解码到 any 时整数保持精确：超出 int64 的正整数解码为 uint64，
+	// 仍无法精确表示的整数返回错误，而不是降级为 float64
+	Int64Mode bool
+
+	// Pool 解码到 any 时从池中获取 map[string]any 与 []any，用完后通过 Pool.ReleaseValue 归还
+	Pool *ValuePool
+
+	// FloatMode 解码浮点数的精度模式，默认 FloatExact 与 strconv.ParseFloat 逐位一致
+	FloatMode FloatMode
+
+	// TypeField 多态解码的判别字段名（如 "type"），非空时解码到接口的对象按该字段的值
+	// 实例化 RegisterType 注册的具体类型
+	TypeField string
+}
+
+// DefaultDecodeOptions 默认解码选项
+var DefaultDecodeOptions = DecodeOptions{
+	TagName:                "", // 默认只使用 json 标签
+	MatchNamingConventions: false,
+	Int64Mode:              false,
+	Pool:                   nil, // 默认不使用池
+	FloatMode:              FloatExact,
+}
+
+// ValuePool 解码到 any 时复用的 map 与切片池，适用于解码后立即重新编码的请求级生命周期
+type ValuePool struct {
+	maps   sync.Pool // map[string]any
+	slices sync.Pool // *[]any
+}
+
+// NewValuePool 创建值池
+func NewValuePool() *ValuePool {
+	return &ValuePool{}
+}
+
+// DefaultValuePool 默认值池，配合包级 ReleaseValue 使用
+var DefaultValuePool = NewValuePool()
+
+// getMap 从池中获取空 map，池为空时按容量提示新建
+func (p *ValuePool) getMap(sizeHint int) map[string]any {
+	if m, ok := p.maps.Get().(map[string]any); ok {
+		return m
+	}
+	return make(map[string]any, sizeHint)
+}
+
+// getSlice 从池中获取长度为 0 的切片，池为空时按容量提示新建
+func (p *ValuePool) getSlice(sizeHint int) []any {
+	if s, ok := p.slices.Get().(*[]any); ok {
+		return (*s)[:0]
+	}
+	return make([]any, 0, sizeHint)
+}
+
+// ReleaseValue 递归归还解码结果中的 map[string]any 与 []any，归还后 v 及其子值不可再使用
+func (p *ValuePool) ReleaseValue(v any) {
+	switch val := v.(type) {
+	case map[string]any:
+		if val == nil {
+			return
+		}
+		for _, child := range val {
+			p.ReleaseValue(child)
+		}
+		clear(val)
+		p.maps.Put(val)
+	case []any:
+		if cap(val) == 0 {
+			return
+		}
+		for _, child := range val {
+			p.ReleaseValue(child)
+		}
+		clear(val)
+		val = val[:0]
+		p.slices.Put(&val)
+	}
+}
+
+// ReleaseValue 将值归还到 DefaultValuePool
+func ReleaseValue(v any) {
+	DefaultValuePool.ReleaseValue(v)
+}
+
+// DecodeStructWithOptions 使用指定解码选项将 JSON 对象直接解码到结构体
+func DecodeStructWithOptions(data []byte, v any, opts DecodeOptions) (err error) {
+	defer recoverPanic(&err, "DecodeStructWithOptions", Node{})
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr {
+		return fmt.Errorf("v must be a pointer: got kind=%s, type=%T", rv.Kind(), v)
+	}
+	if rv.IsNil() {
+		return fmt.Errorf("v must be a non-nil pointer: type=%T", v)
+	}
+
+	elem := rv.Elem()
+	if elem.Kind() != reflect.Struct {
+		return fmt.Errorf("v must point to a struct, got %s", elem.Kind())
+	}
+
+	return decodeStructDirectly(data, elem, &opts)
+}
+
+// structFieldKey 带标签命名空间的结构体字段缓存键
+type structFieldKey struct {
+	typ        reflect.Type
+	tagName    string
+	normalized bool
+}
+
+// getStructFieldMapWithTag 获取按指定标签命名空间解析的字段映射（带缓存）
+func getStructFieldMapWithTag(t reflect.Type, tagName string) map[string]structFieldInfo {
+	key := structFieldKey{typ: t, tagName: tagName}
+	if cached, ok := loadStructFieldMap(key); ok {
+		return cached
+	}
+
+	fieldMap := make(map[string]structFieldInfo, t.NumField())
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		// 跳过未导出的字段
+		if !field.IsExported() {
+			continue
+		}
+
+		name := getTaggedFieldName(field, tagName)
+		if name == "-" || isRemainField(field) {
+			continue
+		}
+
+		fieldMap[name] = structFieldInfo{
+			Index:    i,
+			JSONName: name,
+		}
+		addStructFieldAliases(fieldMap, field, i, name)
+	}
+
+	structFieldCache.Store(key, fieldMap)
+	return fieldMap
+}
+
+// getTaggedFieldName 优先从指定标签获取字段名，缺失时回退到 json 标签
+func getTaggedFieldName(field reflect.StructField, tagName string) string {
+	tag, ok := field.Tag.Lookup(tagName)
+	if !ok {
+		return getJSONFieldNameFast(field)
+	}
+
+	if idx := strings.IndexByte(tag, ','); idx != -1 {
+		tag = tag[:idx]
+	}
+	tag = strings.TrimSpace(tag)
+	if tag == "" {
+		return getJSONFieldNameFast(field)
+	}
+	return tag
+}
+
+// getStructFieldNormMap 获取未显式命名字段的规范化名称映射（带缓存）
+func getStructFieldNormMap(t reflect.Type, tagName string) map[string]structFieldInfo {
+	key := structFieldKey{typ: t, tagName: tagName, normalized: true}
+	if cached, ok := loadStructFieldMap(key); ok {
+		return cached
+	}
+
+	fieldMap := make(map[string]structFieldInfo, t.NumField())
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		// 显式命名的字段只按标签精确匹配
+		name := getTaggedFieldName(field, tagName)
+		if name != field.Name || isRemainField(field) {
+			continue
+		}
+
+		fieldMap[normalizeFieldName(name)] = structFieldInfo{
+			Index:    i,
+			JSONName: name,
+		}
+	}
+
+	structFieldCache.Store(key, fieldMap)
+	return fieldMap
+}
+
+// lookupStructField 查找 JSON 键对应的结构体字段，按需回退到跨命名风格匹配
+func lookupStructField(fieldMap map[string]structFieldInfo, t reflect.Type, key string, opts *DecodeOptions) (structFieldInfo, bool) {
+	if info, ok := fieldMap[key]; ok {
+		return info, true
+	}
+	if !opts.MatchNamingConventions {
+		return structFieldInfo{}, false
+	}
+	info, ok := getStructFieldNormMap(t, opts.TagName)[normalizeFieldName(key)]
+	return info, ok
+}
+
+// addStructFieldAliases 将 fxalias 标签中的旧名称（逗号分隔）加入字段映射
+// 别名不会覆盖其他字段的主名称；后声明字段的主名称会覆盖同名别名
+func addStructFieldAliases(fieldMap map[string]structFieldInfo, field reflect.StructField, index int, name string) {
+	tag, ok := field.Tag.Lookup("fxalias")
+	if !ok || tag == "" {
+		return
+	}
+	primary := fieldMap[name]
+	primary.HasAliases = true
+	fieldMap[name] = primary
+
+	for tag != "" {
+		var alias string
+		alias, tag, _ = strings.Cut(tag, ",")
+		alias = strings.TrimSpace(alias)
+		if alias == "" || alias == name {
+			continue
+		}
+		if existing, exists := fieldMap[alias]; exists && !existing.Alias {
+			continue
+		}
+		fieldMap[alias] = structFieldInfo{Index: index, JSONName: name, Alias: true, HasAliases: true}
+	}
+}
+
+// aliasTracker 记录带别名字段的赋值来源，保证主名称优先于别名（与键的顺序无关）
+type aliasTracker map[int]bool // 字段索引 -> 是否由主名称赋值
+
+// allow 返回是否应将当前键的值写入字段：主名称已赋值时忽略别名；
+// 主名称出现在别名之后时先清零字段，避免与别名的值合并
+func (t *aliasTracker) allow(info structFieldInfo, field reflect.Value) bool {
+	if !info.HasAliases {
+		return true
+	}
+	if *t == nil {
+		*t = make(aliasTracker, 2)
+	}
+	fromPrimary, set := (*t)[info.Index]
+	if info.Alias {
+		if fromPrimary {
+			return false
+		}
+		(*t)[info.Index] = false
+		return true
+	}
+	if set && !fromPrimary {
+		field.Set(reflect.Zero(field.Type()))
+	}
+	(*t)[info.Index] = true
+	return true
+}
+
+// structRemainCache 结构体 remain 字段索引缓存
+var structRemainCache = sync.Map{} // map[reflect.Type]int
+
+// isRemainField 字段是否在 fx 或 json 标签选项中标记了 remain（如 `fx:",remain"`）
+func isRemainField(field reflect.StructField) bool {
+	return hasTagOption(field, "remain")
+}
+
+// hasTagOption 字段的 fx 或 json 标签选项中是否包含 option
+func hasTagOption(field reflect.StructField, option string) bool {
+	for _, tagName := range [...]string{"fx", "json"} {
+		tag, ok := field.Tag.Lookup(tagName)
+		if !ok {
+			continue
+		}
+		_, options, _ := strings.Cut(tag, ",")
+		for options != "" {
+			var opt string
+			opt, options, _ = strings.Cut(options, ",")
+			if strings.TrimSpace(opt) == option {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// getStructRemainField 返回收集未匹配键的 remain 字段索引，没有时返回 -1
+func getStructRemainField(t reflect.Type) int {
+	if cached, ok := structRemainCache.Load(t); ok {
+		return cached.(int)
+	}
+
+	index := -1
+	for i := 0; i < t.NumField(); i++ {
+		if field := t.Field(i); field.IsExported() && isRemainField(field) {
+			index = i
+			break
+		}
+	}
+
+	structRemainCache.Store(t, index)
+	return index
+}
+
+// decodeRemainField 将未匹配到结构体字段的键值解码到 remain map 字段中（如 map[string]Node 或 map[string]any）
+func decodeRemainField(field reflect.Value, key string, child Node, opts *DecodeOptions) error {
+	mapType := field.Type()
+	if mapType.Kind() != reflect.Map || mapType.Key().Kind() != reflect.String {
+		return fmt.Errorf("remain field must be a map with string keys, got %s", mapType)
+	}
+	if field.IsNil() {
+		field.Set(reflect.MakeMap(mapType))
+	}
+
+	elem := reflect.New(mapType.Elem()).Elem()
+	if err := child.decodeValueFast(elem, opts); err != nil {
+		return fmt.Errorf("failed to decode field %s: %v", key, err)
+	}
+	field.SetMapIndex(reflect.ValueOf(strings.Clone(key)).Convert(mapType.Key()), elem)
+	return nil
+}
+
+// structDefault 结构体字段默认值信息
+type structDefault struct {
+	index  int
+	name   string
+	value  string // default 标签原文，字符串字段直接使用，其余类型按 JSON 解析
+	hasTag bool
+}
+
+// structDefaultCache 结构体默认值缓存
+var structDefaultCache = sync.Map{} // map[reflect.Type][]structDefault
+
+// getStructDefaults 获取结构体中需要填充默认值的字段（含内部带默认值的嵌套结构体）
+func getStructDefaults(t reflect.Type) []structDefault {
+	if cached, ok := structDefaultCache.Load(t); ok {
+		return cached.([]structDefault)
+	}
+
+	var defaults []structDefault
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		if value, ok := field.Tag.Lookup("default"); ok {
+			defaults = append(defaults, structDefault{index: i, name: field.Name, value: value, hasTag: true})
+			continue
+		}
+
+		if field.Type.Kind() == reflect.Struct && len(getStructDefaults(field.Type)) > 0 {
+			defaults = append(defaults, structDefault{index: i, name: field.Name})
+		}
+	}
+
+	structDefaultCache.Store(t, defaults)
+	return defaults
+}
+
+// applyStructDefaults 为缺失或为 null 的字段填充默认值，seen 为 nil 表示所有字段均缺失
+func applyStructDefaults(rv reflect.Value, defaults []structDefault, seen []bool) error {
+	for _, d := range defaults {
+		if seen != nil && seen[d.index] {
+			continue
+		}
+
+		fieldValue := rv.Field(d.index)
+		if !fieldValue.CanSet() {
+			continue
+		}
+
+		if !d.hasTag {
+			if err := applyStructDefaults(fieldValue, getStructDefaults(fieldValue.Type()), nil); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := setDefaultValue(fieldValue, d.value); err != nil {
+			return fmt.Errorf("invalid default value %q for field %s: %w", d.value, d.name, err)
+		}
+	}
+	return nil
+}
+
+// setDefaultValue 将 default 标签值写入字段
+func setDefaultValue(rv reflect.Value, value string) error {
+	if rv.Kind() == reflect.String {
+		rv.SetString(value)
+		return nil
+	}
+
+	node := FromBytes([]byte(value))
+	if !node.Exists() {
+		return fmt.Errorf("not a valid JSON value")
+	}
+	return node.decodeValueFast(rv, &DefaultDecodeOptions)
+}
+
+// fieldSelection 部分解码的字段选择树
+type fieldSelection struct {
+	all      bool // 解码整个字段
+	children map[string]*fieldSelection
+}
+
+// newFieldSelection 根据字段路径（支持点号分隔的嵌套路径）构建选择树
+func newFieldSelection(fields []string) (map[string]*fieldSelection, error) {
+	root := make(map[string]*fieldSelection, len(fields))
+	for _, field := range fields {
+		if field == "" {
+			return nil, fmt.Errorf("empty field path")
+		}
+
+		level := root
+		segments := strings.Split(field, ".")
+		for i, seg := range segments {
+			if seg == "" {
+				return nil, fmt.Errorf("invalid field path %q", field)
+			}
+			sel, ok := level[seg]
+			if !ok {
+				sel = &fieldSelection{}
+				level[seg] = sel
+			}
+			if sel.all {
+				break // 父字段已整体选中
+			}
+			if i == len(segments)-1 {
+				sel.all = true
+				sel.children = nil
+				break
+			}
+			if sel.children == nil {
+				sel.children = make(map[string]*fieldSelection)
+			}
+			level = sel.children
+		}
+	}
+	return root, nil
+}
+
+// DecodeFields 仅将指定字段解码到结构体，其余字段直接跳过
+// 字段名为 JSON 键名，支持 "address.city" 形式的嵌套路径
+func (n Node) DecodeFields(v any, fields ...string) (err error) {
+	defer recoverPanic(&err, "DecodeFields", n)
+
+	if !n.Exists() {
+		return fmt.Errorf("node does not exist: start=%d, end=%d, type=%q", n.start, n.end, n.Kind())
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr {
+		return fmt.Errorf("v must be a pointer: got kind=%s, type=%T", rv.Kind(), v)
+	}
+	if rv.IsNil() {
+		return fmt.Errorf("v must be a non-nil pointer: type=%T", v)
+	}
+
+	selection, err := newFieldSelection(fields)
+	if err != nil {
+		return err
+	}
+	return n.decodeSelectedFields(rv.Elem(), selection, &DefaultDecodeOptions)
+}
+
+// decodeSelectedFields 按选择树解码对象字段，全部命中后提前结束扫描
+func (n Node) decodeSelectedFields(rv reflect.Value, selection map[string]*fieldSelection, opts *DecodeOptions) error {
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("DecodeFields target must be a struct, got %s", rv.Kind())
+	}
+	if n.typ != 'o' {
+		return fmt.Errorf("DecodeFields requires a JSON object, got %s", n.Kind())
+	}
+	if len(selection) == 0 {
+		return nil
+	}
+
+	structType := rv.Type()
+	fieldMap := getStructFieldMapFast(structType, opts)
+	remaining := len(selection)
+	matched := make(map[string]struct{}, len(selection))
+
+	var decodeErr error
+	n.ForEach(func(key string, child Node) bool {
+		sel, ok := selection[key]
+		if !ok {
+			return true
+		}
+		// 重复的键只在第一次命中时计数，避免提前结束而漏掉其他选择的字段
+		if _, dup := matched[key]; !dup {
+			matched[key] = struct{}{}
+			remaining--
+		}
+
+		if fieldInfo, exists := lookupStructField(fieldMap, structType, key, opts); exists {
+			fieldValue := rv.Field(fieldInfo.Index)
+			if fieldValue.CanSet() {
+				switch {
+				case sel.all:
+					decodeErr = child.decodeValueFast(fieldValue, opts)
+				case child.typ == 'l':
+					// null 的父对象没有可选字段
+				default:
+					decodeErr = child.decodeSelectedFields(fieldValue, sel.children, opts)
+				}
+				if decodeErr != nil {
+					decodeErr = fmt.Errorf("failed to decode field %s: %w", key, decodeErr)
+				}
+			}
+		}
+		return decodeErr == nil && remaining > 0
+	})
+
+	return decodeErr
+}
+
+// DecodeArrayForEach 定位 path 处的数组并逐个解码元素后回调，fn 返回 false 时停止遍历
+// path 为空表示根节点本身；元素解码后即交给回调，不会构建完整切片，也不展开嵌套的 JSON 字符串
+func DecodeArrayForEach[T any](data []byte, path string, fn func(idx int, v T) bool) error {
+	if fn == nil {
+		return fmt.Errorf("callback must not be nil")
+	}
+
+	root := parseRootNode(data)
+	if !root.Exists() {
+		return fmt.Errorf("invalid JSON data")
+	}
+
+	arr := root
+	if path != "" {
+		arr = root.Get(path)
+		if !arr.Exists() {
+			return fmt.Errorf("path %q not found", path)
+		}
+	}
+	if arr.typ != 'a' {
+		return fmt.Errorf("value at path %q must be an array, got %s", path, arr.Kind())
+	}
+
+	buf := arr.getWorkingData()
+	pos := arr.start + 1
+	end := arr.end - 1
+
+	for idx := 0; ; idx++ {
+		for pos < end && buf[pos] <= ' ' {
+			pos++
+		}
+		if pos >= end {
+			return nil
+		}
+
+		valueEnd := skipValueFast(buf, pos, end)
+		if valueEnd <= pos {
+			return fmt.Errorf("invalid array element %d at position %d", idx, pos)
+		}
+
+		elem := Node{
+			raw:      arr.raw,
+			start:    pos,
+			end:      valueEnd,
+			typ:      detectType(buf[pos]),
+			expanded: arr.expanded,
+			lazy:     arr.lazy,
+		}
+
+		var v T
+		if err := elem.decodeValueFast(reflect.ValueOf(&v).Elem(), &DefaultDecodeOptions); err != nil {
+			return fmt.Errorf("element %d: %w", idx, err)
+		}
+		if !fn(idx, v) {
+			return nil
+		}
+
+		pos = valueEnd
+		for pos < end && buf[pos] <= ' ' {
+			pos++
+		}
+		if pos < end {
+			if buf[pos] != ',' {
+				return fmt.Errorf("expected ',' after array element %d at position %d", idx, pos)
+			}
+			pos++
+		}
+	}
+}
+
+// decodeRaw 将节点原样保存到 Node 或 json.RawMessage 目标中，返回是否已处理
+// Node 目标直接引用输入数据（零拷贝），json.RawMessage 目标保存原始字节的副本
+func (n Node) decodeRaw(rv reflect.Value) bool {
+	switch rv.Type() {
+	case nodeType:
+		rv.Set(reflect.ValueOf(n))
+		return true
+	case rawMessageType:
+		rv.SetBytes(append(json.RawMessage(nil), n.Raw()...))
+		return true
+	}
+	return false
+}
+
+var (
+	syncMapType         = reflect.TypeOf(sync.Map{})
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// mapKeyDecoder 返回将 JSON 对象键转换为 keyType 值的函数，规则同 encoding/json：
+// 实现 encoding.TextUnmarshaler 的类型优先，其次是字符串类型与按十进制解析的整数类型
+func mapKeyDecoder(keyType reflect.Type) (func(key string) (reflect.Value, error), error) {
+	switch {
+	case reflect.PointerTo(keyType).Implements(textUnmarshalerType):
+		return func(key string) (reflect.Value, error) {
+			kv := reflect.New(keyType)
+			if err := kv.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(key)); err != nil {
+				return reflect.Value{}, fmt.Errorf("map key %q: %w", key, err)
+			}
+			return kv.Elem(), nil
+		}, nil
+	case keyType.Kind() == reflect.String:
+		return func(key string) (reflect.Value, error) {
+			return reflect.ValueOf(key).Convert(keyType), nil
+		}, nil
+	}
+
+	switch keyType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(key string) (reflect.Value, error) {
+			i, err := strconv.ParseInt(key, 10, keyType.Bits())
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("invalid map key %q for %s", key, keyType)
+			}
+			kv := reflect.New(keyType).Elem()
+			kv.SetInt(i)
+			return kv, nil
+		}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return func(key string) (reflect.Value, error) {
+			u, err := strconv.ParseUint(key, 10, keyType.Bits())
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("invalid map key %q for %s", key, keyType)
+			}
+			kv := reflect.New(keyType).Elem()
+			kv.SetUint(u)
+			return kv, nil
+		}, nil
+	}
+	return nil, fmt.Errorf("map key must be a string, an integer or implement encoding.TextUnmarshaler, got %s", keyType)
+}
+
+// decodeSyncMap 将对象解码到 sync.Map，键为字符串，值按 any 解码
+func (n Node) decodeSyncMap(rv reflect.Value, decode func(child Node, v reflect.Value) error) error {
+	m := rv.Addr().Interface().(*sync.Map)
+	var decodeErr error
+	n.ForEach(func(key string, child Node) bool {
+		var val interface{}
+		if decodeErr = decode(child, reflect.ValueOf(&val).Elem()); decodeErr != nil {
+			return false
+		}
+		m.Store(strings.Clone(key), val)
+		return true
+	})
+	return decodeErr
+}
@@ -0,0 +1,525 @@
+package fxjson
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// TestDecodeTagName 测试自定义标签命名空间
+func TestDecodeTagName(t *testing.T) {
+	type User struct {
+		Name  string `json:"name" fx:"user_name"`
+		Email string `json:"email"`
+		Age   int    `json:"age" fx:"-"`
+	}
+
+	data := []byte(`{"user_name":"Alice","name":"ignored","email":"a@example.com","age":30}`)
+
+	var u User
+	if err := FromBytes(data).DecodeWithOptions(&u, DecodeOptions{TagName: "fx"}); err != nil {
+		t.Fatalf("DecodeWithOptions failed: %v", err)
+	}
+	if u.Name != "Alice" {
+		t.Errorf("fx tag should override json tag, got %q", u.Name)
+	}
+	if u.Email != "a@example.com" {
+		t.Errorf("field without fx tag should fall back to json tag, got %q", u.Email)
+	}
+	if u.Age != 0 {
+		t.Errorf("fx:\"-\" should skip field, got %d", u.Age)
+	}
+
+	var direct User
+	if err := DecodeStructWithOptions(data, &direct, DecodeOptions{TagName: "fx"}); err != nil {
+		t.Fatalf("DecodeStructWithOptions failed: %v", err)
+	}
+	if direct != u {
+		t.Errorf("DecodeStructWithOptions mismatch: got %+v, want %+v", direct, u)
+	}
+
+	// 默认选项仍使用 json 标签
+	var plain User
+	if err := FromBytes(data).Decode(&plain); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if plain.Name != "ignored" || plain.Age != 30 {
+		t.Errorf("default decode should use json tags, got %+v", plain)
+	}
+}
+
+// TestDecodeMatchNamingConventions 测试跨命名风格匹配字段
+func TestDecodeMatchNamingConventions(t *testing.T) {
+	type Profile struct {
+		UserName  string
+		FirstName string
+		LastLogin int
+		Nick      string `json:"nick_name"`
+	}
+
+	data := []byte(`{"user_name":"alice","firstName":"Al","LastLogin":42,"nickName":"ignored","nick_name":"ali"}`)
+	opts := DecodeOptions{MatchNamingConventions: true}
+
+	var p Profile
+	if err := FromBytes(data).DecodeWithOptions(&p, opts); err != nil {
+		t.Fatalf("DecodeWithOptions failed: %v", err)
+	}
+	want := Profile{UserName: "alice", FirstName: "Al", LastLogin: 42, Nick: "ali"}
+	if p != want {
+		t.Errorf("got %+v, want %+v", p, want)
+	}
+
+	var direct Profile
+	if err := DecodeStructWithOptions(data, &direct, opts); err != nil {
+		t.Fatalf("DecodeStructWithOptions failed: %v", err)
+	}
+	if direct != want {
+		t.Errorf("DecodeStructWithOptions got %+v, want %+v", direct, want)
+	}
+
+	// 默认选项不做跨风格匹配
+	var plain Profile
+	if err := FromBytes(data).Decode(&plain); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if plain.UserName != "" || plain.FirstName != "" || plain.LastLogin != 42 {
+		t.Errorf("default decode should match exactly, got %+v", plain)
+	}
+}
+
+// TestDecodeDefaultTags 测试 default 标签在字段缺失或为 null 时生效
+func TestDecodeDefaultTags(t *testing.T) {
+	type Limits struct {
+		Max     int     `json:"max" default:"100"`
+		Ratio   float64 `json:"ratio" default:"0.5"`
+		Enabled bool    `json:"enabled" default:"true"`
+	}
+	type Item struct {
+		Name  string `json:"name" default:"unnamed"`
+		Count int    `json:"count" default:"1"`
+	}
+	type Config struct {
+		Host   string   `json:"host" default:"localhost"`
+		Port   int      `json:"port" default:"8080"`
+		Tags   []string `json:"tags" default:"[\"a\",\"b\"]"`
+		Limits Limits   `json:"limits"`
+		Items  []Item   `json:"items"`
+	}
+
+	data := []byte(`{"host":null,"port":9090,"limits":{"max":5},"items":[{"name":"x"},{"count":3}]}`)
+	want := Config{
+		Host:   "localhost",
+		Port:   9090,
+		Tags:   []string{"a", "b"},
+		Limits: Limits{Max: 5, Ratio: 0.5, Enabled: true},
+		Items:  []Item{{Name: "x", Count: 1}, {Name: "unnamed", Count: 3}},
+	}
+
+	check := func(name string, got Config) {
+		if got.Host != want.Host || got.Port != want.Port || got.Limits != want.Limits {
+			t.Errorf("%s: got %+v, want %+v", name, got, want)
+		}
+		if len(got.Tags) != 2 || got.Tags[0] != "a" || got.Tags[1] != "b" {
+			t.Errorf("%s: tags got %v", name, got.Tags)
+		}
+		if len(got.Items) != 2 || got.Items[0] != want.Items[0] || got.Items[1] != want.Items[1] {
+			t.Errorf("%s: items got %+v", name, got.Items)
+		}
+	}
+
+	var viaNode Config
+	if err := FromBytes(data).Decode(&viaNode); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	check("Decode", viaNode)
+
+	var direct Config
+	if err := DecodeStruct(data, &direct); err != nil {
+		t.Fatalf("DecodeStruct failed: %v", err)
+	}
+	check("DecodeStruct", direct)
+
+	// 缺失的嵌套结构体同样填充默认值
+	var empty Config
+	if err := FromBytes([]byte(`{}`)).Decode(&empty); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if empty.Limits != (Limits{Max: 100, Ratio: 0.5, Enabled: true}) {
+		t.Errorf("nested defaults not applied, got %+v", empty.Limits)
+	}
+
+	type Bad struct {
+		N int `json:"n" default:"abc"`
+	}
+	var bad Bad
+	if err := FromBytes([]byte(`{}`)).Decode(&bad); err == nil {
+		t.Error("expected error for invalid default value")
+	}
+}
+
+// TestDecodeFields 测试按字段白名单部分解码
+func TestDecodeFields(t *testing.T) {
+	type Address struct {
+		City    string `json:"city"`
+		Country string `json:"country"`
+	}
+	type User struct {
+		ID      int      `json:"id"`
+		Name    string   `json:"name"`
+		Email   string   `json:"email"`
+		Tags    []string `json:"tags"`
+		Address Address  `json:"address"`
+	}
+
+	data := []byte(`{"id":1,"name":"Alice","email":"a@example.com","tags":["x","y"],"address":{"city":"Paris","country":"FR"},"extra":{"big":[1,2,3]}}`)
+	node := FromBytes(data)
+
+	var u User
+	if err := node.DecodeFields(&u, "name", "address.city"); err != nil {
+		t.Fatalf("DecodeFields failed: %v", err)
+	}
+	want := User{Name: "Alice", Address: Address{City: "Paris"}}
+	if u.ID != want.ID || u.Name != want.Name || u.Email != "" || u.Tags != nil || u.Address != want.Address {
+		t.Errorf("got %+v, want %+v", u, want)
+	}
+
+	// 父字段整体选中时忽略子路径
+	var full User
+	if err := node.DecodeFields(&full, "address.city", "address", "tags"); err != nil {
+		t.Fatalf("DecodeFields failed: %v", err)
+	}
+	if full.Address != (Address{City: "Paris", Country: "FR"}) || len(full.Tags) != 2 {
+		t.Errorf("got %+v", full)
+	}
+
+	// 重复的键不会导致提前结束扫描
+	var dup User
+	if err := FromBytes([]byte(`{"name":"a","name":"b","id":7}`)).DecodeFields(&dup, "name", "id"); err != nil || dup.ID != 7 || dup.Name != "b" {
+		t.Errorf("duplicate keys = %+v, %v", dup, err)
+	}
+
+	if err := node.DecodeFields(&u, "name..x"); err == nil {
+		t.Error("expected error for invalid field path")
+	}
+	if err := node.DecodeFields(&u, "name.first"); err == nil {
+		t.Error("expected error when selecting into a non-object field")
+	}
+	if err := FromBytes([]byte(`[1,2]`)).DecodeFields(&u, "name"); err == nil {
+		t.Error("expected error for non-object JSON")
+	}
+}
+
+// TestDecodeArrayForEach 测试流式逐元素解码数组
+func TestDecodeArrayForEach(t *testing.T) {
+	type Event struct {
+		ID   int    `json:"id"`
+		Kind string `json:"kind"`
+	}
+
+	data := []byte(`{"meta":{"total":3},"events":[{"id":1,"kind":"a"}, {"id":2,"kind":"b"} ,{"id":3,"kind":"c"}]}`)
+
+	var got []Event
+	err := DecodeArrayForEach(data, "events", func(idx int, e Event) bool {
+		if idx != len(got) {
+			t.Errorf("unexpected index %d", idx)
+		}
+		got = append(got, e)
+		return true
+	})
+	if err != nil {
+		t.Fatalf("DecodeArrayForEach failed: %v", err)
+	}
+	if len(got) != 3 || got[0] != (Event{1, "a"}) || got[2] != (Event{3, "c"}) {
+		t.Errorf("got %+v", got)
+	}
+
+	// 提前停止
+	count := 0
+	_ = DecodeArrayForEach(data, "events", func(idx int, e Event) bool {
+		count++
+		return idx < 1
+	})
+	if count != 2 {
+		t.Errorf("expected early stop after 2 elements, got %d", count)
+	}
+
+	// 根数组与基础类型
+	sum := 0
+	if err := DecodeArrayForEach([]byte(`[1, 2, 3]`), "", func(_ int, v int) bool {
+		sum += v
+		return true
+	}); err != nil || sum != 6 {
+		t.Errorf("root array: sum=%d err=%v", sum, err)
+	}
+
+	if err := DecodeArrayForEach(data, "meta", func(int, Event) bool { return true }); err == nil {
+		t.Error("expected error for non-array path")
+	}
+	if err := DecodeArrayForEach(data, "missing", func(int, Event) bool { return true }); err == nil {
+		t.Error("expected error for missing path")
+	}
+	if err := DecodeArrayForEach([]byte(`["x"]`), "", func(int, int) bool { return true }); err == nil {
+		t.Error("expected error for element type mismatch")
+	}
+}
+
+// TestDecodeValuePool 测试解码到 any 时复用池中的 map 与切片
+func TestDecodeValuePool(t *testing.T) {
+	pool := NewValuePool()
+	opts := DecodeOptions{Pool: pool}
+
+	var first any
+	if err := FromBytes([]byte(`{"a":1,"b":[1,2,{"c":"x"}]}`)).DecodeWithOptions(&first, opts); err != nil {
+		t.Fatalf("DecodeWithOptions failed: %v", err)
+	}
+	m, ok := first.(map[string]any)
+	if !ok || len(m) != 2 {
+		t.Fatalf("unexpected result %#v", first)
+	}
+	inner := m["b"].([]any)
+	if len(inner) != 3 || inner[2].(map[string]any)["c"] != "x" {
+		t.Fatalf("unexpected nested result %#v", inner)
+	}
+
+	pool.ReleaseValue(first)
+	if len(m) != 0 {
+		t.Errorf("released map should be cleared, got %v", m)
+	}
+
+	// 复用的容器不得残留旧数据
+	for i := 0; i < 3; i++ {
+		var next map[string]any
+		if err := FromBytes([]byte(`{"z":[true]}`)).DecodeWithOptions(&next, opts); err != nil {
+			t.Fatalf("DecodeWithOptions failed: %v", err)
+		}
+		if len(next) != 1 || len(next["z"].([]any)) != 1 || next["z"].([]any)[0] != true {
+			t.Errorf("unexpected reused result %#v", next)
+		}
+		pool.ReleaseValue(next)
+	}
+
+	// 包级 ReleaseValue 使用默认池
+	var v any
+	if err := FromBytes([]byte(`[{"k":1}]`)).DecodeWithOptions(&v, DecodeOptions{Pool: DefaultValuePool}); err != nil {
+		t.Fatalf("DecodeWithOptions failed: %v", err)
+	}
+	ReleaseValue(v)
+	ReleaseValue("scalar")
+	ReleaseValue(nil)
+}
+
+// TestDecodeInt64Mode 测试解码到 any 时大整数不降级为 float64
+func TestDecodeInt64Mode(t *testing.T) {
+	data := []byte(`{"id":9007199254740993,"big":18446744073709551615,"f":1.5}`)
+
+	var loose map[string]any
+	if err := FromBytes(data).Decode(&loose); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if loose["id"] != int64(9007199254740993) {
+		t.Errorf("id got %#v", loose["id"])
+	}
+	if f, ok := loose["big"].(float64); !ok || f != 18446744073709551615.0 {
+		t.Errorf("big should fall back to float64 by default, got %#v", loose["big"])
+	}
+
+	var exact map[string]any
+	if err := FromBytes(data).DecodeWithOptions(&exact, DecodeOptions{Int64Mode: true}); err != nil {
+		t.Fatalf("DecodeWithOptions failed: %v", err)
+	}
+	if exact["big"] != uint64(18446744073709551615) || exact["f"] != 1.5 {
+		t.Errorf("got %#v", exact)
+	}
+
+	var v any
+	if err := FromBytes([]byte(`[-99999999999999999999]`)).DecodeWithOptions(&v, DecodeOptions{Int64Mode: true}); err == nil {
+		t.Errorf("expected error for integer beyond uint64/int64, got %#v", v)
+	}
+}
+
+// TestDecodeRemainFields 测试未匹配键收集到 remain 字段
+func TestDecodeRemainFields(t *testing.T) {
+	type Vendor struct {
+		ID    int             `json:"id"`
+		Name  string          `json:"name"`
+		Extra map[string]Node `fx:",remain"`
+	}
+	type Loose struct {
+		ID   int            `json:"id"`
+		Rest map[string]any `json:"rest,remain"`
+	}
+
+	data := []byte(`{"id":7,"name":"acme","region":"eu","limits":{"rps":100},"Extra":1}`)
+
+	var v Vendor
+	if err := DecodeStructFast(data, &v); err != nil {
+		t.Fatalf("DecodeStructFast failed: %v", err)
+	}
+	if v.ID != 7 || v.Name != "acme" || len(v.Extra) != 3 {
+		t.Fatalf("got %+v", v)
+	}
+	if string(v.Extra["limits"].Raw()) != `{"rps":100}` || v.Extra["region"].StringOr("") != "eu" {
+		t.Errorf("Extra = %v", v.Extra)
+	}
+	// remain 字段本身不按字段名匹配
+	if n, _ := v.Extra["Extra"].Int(); n != 1 {
+		t.Errorf("Extra[Extra] = %s", v.Extra["Extra"].Raw())
+	}
+
+	var viaNode Vendor
+	if err := FromBytes(data).Decode(&viaNode); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if len(viaNode.Extra) != 3 || viaNode.Extra["limits"].Get("rps").IntOr(0) != 100 {
+		t.Errorf("Decode Extra = %v", viaNode.Extra)
+	}
+
+	var l Loose
+	if err := DecodeStruct([]byte(`{"id":1,"rest":true,"tags":["a"],"n":2}`), &l); err != nil {
+		t.Fatalf("DecodeStruct failed: %v", err)
+	}
+	if l.ID != 1 || len(l.Rest) != 3 || l.Rest["rest"] != true || l.Rest["n"] != int64(2) {
+		t.Errorf("got %+v", l)
+	}
+	if tags, ok := l.Rest["tags"].([]any); !ok || len(tags) != 1 || tags[0] != "a" {
+		t.Errorf("tags = %#v", l.Rest["tags"])
+	}
+
+	var none Loose
+	if err := DecodeStruct([]byte(`{"id":2}`), &none); err != nil || none.Rest != nil {
+		t.Errorf("no unknown keys should leave Rest nil, got %+v, %v", none, err)
+	}
+
+	type Bad struct {
+		Rest []string `fx:",remain"`
+	}
+	var b Bad
+	if err := DecodeStruct([]byte(`{"x":1}`), &b); err == nil {
+		t.Error("remain field of non-map type should fail")
+	}
+}
+
+// testTextKey 实现 encoding.TextUnmarshaler 的 map 键
+type testTextKey struct{ a, b string }
+
+func (k *testTextKey) UnmarshalText(text []byte) error {
+	a, b, ok := strings.Cut(string(text), ":")
+	if !ok {
+		return fmt.Errorf("missing ':'")
+	}
+	*k = testTextKey{a, b}
+	return nil
+}
+
+// TestDecodeMapKeys 测试整数键、TextUnmarshaler 键与 sync.Map 目标
+func TestDecodeMapKeys(t *testing.T) {
+	type label string
+	var v struct {
+		ByID    map[int]string        `json:"by_id"`
+		ByU8    map[uint8]int         `json:"by_u8"`
+		ByText  map[testTextKey]int   `json:"by_text"`
+		ByLabel map[label]bool        `json:"by_label"`
+		Shared  sync.Map              `json:"shared"`
+		Other   map[int64]interface{} `json:"other"`
+	}
+	data := `{"by_id":{"1":"a","-20":"b"},"by_u8":{"255":1},"by_text":{"x:y":3},"by_label":{"ok":true},` +
+		`"shared":{"k":[1,"s"]},"other":{"9007199254740993":null}}`
+
+	decoders := map[string]func(any) error{
+		"Decode":           FromString(data).Decode,
+		"DecodeStruct":     func(v any) error { return DecodeStruct([]byte(data), v) },
+		"DecodeStructFast": func(v any) error { return DecodeStructFast([]byte(data), v) },
+	}
+	for name, decode := range decoders {
+		if err := decode(&v); err != nil {
+			t.Fatalf("%s error: %v", name, err)
+		}
+		if !reflect.DeepEqual(v.ByID, map[int]string{1: "a", -20: "b"}) {
+			t.Errorf("%s ByID = %v", name, v.ByID)
+		}
+		if v.ByU8[255] != 1 || v.ByText[testTextKey{"x", "y"}] != 3 || !v.ByLabel["ok"] {
+			t.Errorf("%s ByU8 = %v, ByText = %v, ByLabel = %v", name, v.ByU8, v.ByText, v.ByLabel)
+		}
+		if got, ok := v.Shared.Load("k"); !ok || !reflect.DeepEqual(got, []interface{}{int64(1), "s"}) {
+			t.Errorf("%s Shared[k] = %#v", name, got)
+		}
+		if _, ok := v.Other[9007199254740993]; !ok {
+			t.Errorf("%s Other = %v", name, v.Other)
+		}
+	}
+
+	var bad map[uint8]int
+	if err := FromString(`{"256":1}`).Decode(&bad); err == nil {
+		t.Error("overflowing uint8 key accepted")
+	}
+	var badText map[testTextKey]int
+	if err := FromString(`{"nocolon":1}`).Decode(&badText); err == nil {
+		t.Error("invalid TextUnmarshaler key accepted")
+	}
+	var badKind map[float64]int
+	if err := FromString(`{"1.5":1}`).Decode(&badKind); err == nil {
+		t.Error("float map key accepted")
+	}
+}
+
+// TestDecodeFieldAliases 测试 fxalias 旧字段名：主名称优先，序列化只输出主名称
+func TestDecodeFieldAliases(t *testing.T) {
+	type Profile struct {
+		City string `json:"city"`
+		Zip  string `json:"zip"`
+	}
+	type User struct {
+		UserID  int     `json:"user_id" fxalias:"uid, userId"`
+		Name    string  `json:"name"`
+		Profile Profile `json:"profile" fxalias:"address"`
+	}
+
+	decoders := map[string]func([]byte, *User) error{
+		"Decode":           func(b []byte, u *User) error { return FromBytes(b).Decode(u) },
+		"DecodeStruct":     func(b []byte, u *User) error { return DecodeStruct(b, u) },
+		"DecodeStructFast": func(b []byte, u *User) error { return DecodeStructFast(b, u) },
+		"TagName": func(b []byte, u *User) error {
+			return FromBytes(b).DecodeWithOptions(u, DecodeOptions{TagName: "fx"})
+		},
+	}
+	cases := []struct {
+		doc  string
+		want User
+	}{
+		{`{"uid":7,"name":"a"}`, User{UserID: 7, Name: "a"}},
+		{`{"userId":8}`, User{UserID: 8}},
+		{`{"user_id":1,"uid":2}`, User{UserID: 1}},
+		{`{"uid":2,"user_id":1}`, User{UserID: 1}},
+		// 主名称在别名之后出现时不与别名的值合并
+		{`{"address":{"city":"x","zip":"1"},"profile":{"city":"y"}}`, User{Profile: Profile{City: "y"}}},
+		{`{"profile":{"city":"y"},"address":{"zip":"1"}}`, User{Profile: Profile{City: "y"}}},
+	}
+	for name, decode := range decoders {
+		for _, c := range cases {
+			var u User
+			if err := decode([]byte(c.doc), &u); err != nil {
+				t.Fatalf("%s(%s) failed: %v", name, c.doc, err)
+			}
+			if u != c.want {
+				t.Errorf("%s(%s) = %+v, want %+v", name, c.doc, u, c.want)
+			}
+		}
+	}
+
+	out, err := Marshal(User{UserID: 7})
+	if err != nil || string(out) != `{"user_id":7,"name":"","profile":{"city":"","zip":""}}` {
+		t.Errorf("Marshal = %s, %v", out, err)
+	}
+
+	// 别名不会抢占其他字段的主名称
+	type Renamed struct {
+		New string `json:"new" fxalias:"old"`
+		Old string `json:"old"`
+	}
+	var r Renamed
+	if err := FromString(`{"old":"o"}`).Decode(&r); err != nil || r.Old != "o" || r.New != "" {
+		t.Errorf("alias shadowing primary: %+v, %v", r, err)
+	}
+}
@@ -0,0 +1,58 @@
+package fxjson
+
+import (
+	"fmt"
+)
+
+// WithDefaults 将默认值文档深度合并到节点之下，只补齐缺失的键，返回完整的新文档
+//
+// 两边都是对象时按键递归：节点已有的键保留其值（包括 null）并保持原顺序，
+// 默认值中缺失的键按默认值文档的顺序追加在后；其他情况（数组、标量、类型不同）以节点的值为准。
+// 节点与默认值的原始字节原样复制，不会规范化数字或字符串，默认值中内容为 JSON 的字符串也保持为字符串
+func (n Node) WithDefaults(defaults []byte) ([]byte, error) {
+	if !n.Exists() {
+		return nil, fmt.Errorf("node does not exist")
+	}
+	d, err := parseUnexpanded(defaults)
+	if err != nil {
+		return nil, fmt.Errorf("invalid defaults: %w", err)
+	}
+	return appendWithDefaults(nil, n, d), nil
+}
+
+// appendWithDefaults 写入以 d 补齐缺失键后的 n
+func appendWithDefaults(dst []byte, n, d Node) []byte {
+	if n.typ != 'o' || d.typ != 'o' {
+		return append(dst, n.Raw()...)
+	}
+
+	dst = append(dst, '{')
+	first := true
+	add := func(key string, value Node, def Node) {
+		if !first {
+			dst = append(dst, ',')
+		}
+		first = false
+		dst = append(append(dst, quoteKey(key)...), ':')
+		if def.Exists() {
+			dst = appendWithDefaults(dst, value, def)
+		} else {
+			dst = append(dst, value.Raw()...)
+		}
+	}
+
+	seen := make(map[string]bool)
+	n.ForEach(func(key string, value Node) bool {
+		seen[key] = true
+		add(key, value, d.GetSegments(key))
+		return true
+	})
+	d.ForEach(func(key string, value Node) bool {
+		if !seen[key] {
+			seen[key] = true
+			add(key, value, Node{})
+		}
+		return true
+	})
+	return append(dst, '}')
+}
@@ -0,0 +1,49 @@
+package fxjson
+
+import (
+	"testing"
+)
+
+// TestWithDefaults 测试以默认值补齐缺失的键
+func TestWithDefaults(t *testing.T) {
+	node := FromString(`{"server":{"port":8443,"tls":null},"name":"api","tags":["a"],"limit":1.50}`)
+	defaults := []byte(`{
+  "name": "default",
+  "server": {"host": "0.0.0.0", "port": 80, "tls": {"enabled": false}, "timeouts": {"read": 5}},
+  "tags": ["x", "y"],
+  "log": {"level": "info"}
+}`)
+
+	got, err := node.WithDefaults(defaults)
+	if err != nil {
+		t.Fatalf("WithDefaults failed: %v", err)
+	}
+	want := `{"server":{"port":8443,"tls":null,"host":"0.0.0.0","timeouts":{"read": 5}},"name":"api","tags":["a"],"limit":1.50,"log":{"level": "info"}}`
+	if string(got) != want {
+		t.Errorf("WithDefaults =\n%s\nwant\n%s", got, want)
+	}
+	if !ValidateJSON(got) {
+		t.Errorf("invalid JSON: %s", got)
+	}
+
+	// 非对象节点以节点的值为准
+	if got, err := FromString(`[1]`).WithDefaults([]byte(`{"a":1}`)); err != nil || string(got) != `[1]` {
+		t.Errorf("array node = %s, %v", got, err)
+	}
+	if _, err := node.WithDefaults([]byte(`{"a":`)); err == nil {
+		t.Error("expected error for invalid defaults")
+	}
+	if _, err := (Node{}).WithDefaults([]byte(`{}`)); err == nil {
+		t.Error("expected error for missing node")
+	}
+
+	// 默认值中内容为 JSON 的字符串原样保留
+	lazy, err := ParseBytes([]byte(`{"a":1}`), ParseOptions{LazyExpansion: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err = lazy.WithDefaults([]byte(`{"a":"x","meta":"{\"v\":1}","list":"[1]"}`))
+	if want := `{"a":1,"meta":"{\"v\":1}","list":"[1]"}`; err != nil || string(got) != want {
+		t.Errorf("string defaults = %s, %v\nwant %s", got, err, want)
+	}
+}
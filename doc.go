@@ -33,6 +33,34 @@
 //
 // Full benchmark results in README.
 //
+// # Allocation guarantees
+//
+// Get, GetPath, Index, Len, Exists, ForEach and ArrayForEach perform 0
+// allocations per call once a document is parsed. This holds for expanded
+// documents (nested JSON strings) as well; object keys containing escape
+// sequences are unescaped once and then served from a process-wide cache
+// guarded by a read-write mutex. The cache holds up to 4096 keys and is
+// cleared when full, so a document with more distinct escaped keys than that
+// (for example untrusted input) allocates while it churns the cache, and hot
+// keys are cached again afterwards. The guarantee does not hold with the
+// fxjson_safe build tag, which copies instead of aliasing input bytes.
+//
+// The contract is enforced by TestZeroAllocContract, and the fxjsontest
+// package exports AssertZeroAllocs and AssertNodeZeroAllocs so applications
+// can pin it against their own documents in CI.
+//
+// # Sub-packages
+//
+// The query, validate, debugx and cache packages group the
+// query/aggregation/jq, validation, diagnostics and caching APIs into
+// smaller, discoverable surfaces. Their
+// types are aliases of the root types, so both spellings interoperate; the
+// exported surface of each is pinned by internal/apicheck. The
+// implementations stay in this package because they operate on Node
+// internals and a sub-package cannot be imported back without a cycle;
+// root free functions that have a sub-package equivalent are marked
+// deprecated and remain only for compatibility.
+//
 // # Notes
 //
 //   - Assumes valid JSON input (no heavy fault tolerance).
@@ -0,0 +1,438 @@
+package fxjson
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Document 可变的 JSON 文档，通过 Set/Delete 按路径修改
+//
+// 修改以字节拼接方式完成，不会重新解析或验证整个文档：只验证新写入的值，
+// 已解析过的路径位置按编辑范围平移，被编辑覆盖的子树在下次访问时才重新定位，
+// 且只扫描其父容器。因此对大文档的少量修改不会每次都是 O(文档大小) 的解析开销。
+// Document 不会展开嵌套的转义 JSON，且不是并发安全的。
+type Document struct {
+	data   []byte
+	root   docSpan
+	spans  map[string]docSpan // 路径 -> 值在 data 中的位置（按编辑平移）
+	dirty  []ByteRange        // 自上次 ClearDirty 以来被修改的范围（当前坐标）
+	access Access             // Or 系列取值使用的转换策略
+	// preserve 保留格式模式：Set 只改写变化的部分，写入的容器按所在位置的缩进格式化
+	preserve bool
+
+	edited   []string      // 自上次 Commit 以来修改过的路径（数组元素记录为所在数组）
+	watchers []*docWatcher // Watch 注册的监听，按注册顺序触发
+}
+
+// docSpan 文档中一个值的位置
+type docSpan struct {
+	start, end int
+	typ        byte
+}
+
+// ByteRange 文档中的字节范围 [Start, End)
+type ByteRange struct {
+	Start, End int
+}
+
+// NewDocument 从 JSON 数据创建可变文档，数据会被复制
+func NewDocument(b []byte) (*Document, error) {
+	if err := validateJSON(b, DefaultParseOptions); err != nil {
+		return nil, err
+	}
+	root := parseRootNode(b)
+	if !root.Exists() {
+		return nil, &FxJSONError{Type: ErrorTypeInvalidJSON, Message: "invalid JSON data"}
+	}
+
+	return &Document{
+		data:  append([]byte(nil), b...),
+		root:  docSpan{start: root.start, end: root.end, typ: root.typ},
+		spans: make(map[string]docSpan),
+	}, nil
+}
+
+// Bytes 返回文档当前内容，返回的切片在下一次修改前有效
+func (d *Document) Bytes() []byte {
+	return d.data
+}
+
+// Root 返回根节点，返回的节点在下一次修改前有效
+func (d *Document) Root() Node {
+	return d.node(d.root)
+}
+
+// Get 按路径（同 GetPath）获取节点，返回的节点在下一次修改前有效
+func (d *Document) Get(path string) Node {
+	s, ok := d.resolve(path)
+	if !ok {
+		return Node{}
+	}
+	return d.node(s)
+}
+
+// SetAccess 设置 StringOr/IntOr 等取值方法使用的类型转换策略，默认为严格策略
+func (d *Document) SetAccess(a Access) {
+	d.access = a
+}
+
+// Access 返回文档的类型转换策略
+func (d *Document) Access() Access {
+	return d.access
+}
+
+// StringOr 按文档的转换策略读取路径处的字符串，失败返回默认值
+func (d *Document) StringOr(path, defaultValue string) string {
+	return d.access.StringOr(d.Get(path), defaultValue)
+}
+
+// IntOr 按文档的转换策略读取路径处的 int64，失败返回默认值
+func (d *Document) IntOr(path string, defaultValue int64) int64 {
+	return d.access.IntOr(d.Get(path), defaultValue)
+}
+
+// UintOr 按文档的转换策略读取路径处的 uint64，失败返回默认值
+func (d *Document) UintOr(path string, defaultValue uint64) uint64 {
+	return d.access.UintOr(d.Get(path), defaultValue)
+}
+
+// FloatOr 按文档的转换策略读取路径处的 float64，失败返回默认值
+func (d *Document) FloatOr(path string, defaultValue float64) float64 {
+	return d.access.FloatOr(d.Get(path), defaultValue)
+}
+
+// BoolOr 按文档的转换策略读取路径处的布尔值，失败返回默认值
+func (d *Document) BoolOr(path string, defaultValue bool) bool {
+	return d.access.BoolOr(d.Get(path), defaultValue)
+}
+
+// SetOptions Document.SetWithOptions 的选项
+type SetOptions struct {
+	// CreateMissing 创建缺失的中间节点：下一段为下标时创建数组，否则创建对象；
+	// false 时父节点不存在返回错误
+	CreateMissing bool
+	// PadArrays 下标超出数组长度时以 null 填充到该下标；false 时返回错误。
+	// 填充后的长度不能超过 DefaultParseOptions.MaxArrayItems
+	PadArrays bool
+}
+
+// Set 将路径处的值替换为 value（必须是合法 JSON）
+// 路径不存在时：父节点为对象则追加字段，父节点为数组且下标等于数组长度则追加元素，否则返回错误
+func (d *Document) Set(path string, value []byte) error {
+	return d.SetWithOptions(path, value, SetOptions{})
+}
+
+// SetCreatePath 同 Set，但会创建缺失的中间对象与数组，并以 null 填充数组空位，
+// 例如对 {} 设置 "a.b[2].c" 得到 {"a":{"b":[null,null,{"c":...}]}}
+func (d *Document) SetCreatePath(path string, value []byte) error {
+	return d.SetWithOptions(path, value, SetOptions{CreateMissing: true, PadArrays: true})
+}
+
+// SetWithOptions 按选项设置路径处的值，已存在的中间节点类型不匹配时返回错误
+func (d *Document) SetWithOptions(path string, value []byte, opts SetOptions) error {
+	v, err := documentValue(value)
+	if err != nil {
+		return err
+	}
+	if err := d.set(path, v, opts); err != nil {
+		return err
+	}
+	d.logEdit(path)
+	return nil
+}
+
+// set 设置已验证的值，缺失的父节点按选项以包装后的值递归创建
+func (d *Document) set(path string, v []byte, opts SetOptions) error {
+	if path == "" {
+		if d.preserve {
+			d.patchValue(Node{}, d.root.start, d.root.end, v)
+			d.root.typ = detectType(d.data[d.root.start])
+			return nil
+		}
+		d.splice(d.root.start, d.root.end, v)
+		d.root = docSpan{start: d.root.start, end: d.root.start + len(v), typ: detectType(v[0])}
+		return nil
+	}
+
+	if s, ok := d.resolve(path); ok {
+		if d.preserve {
+			// 只改写变化的部分，splice 已平移或丢弃受影响的缓存位置
+			d.patchValue(d.parentNode(path), s.start, s.end, v)
+			return nil
+		}
+		d.splice(s.start, s.end, v)
+		d.spans[path] = docSpan{start: s.start, end: s.start + len(v), typ: detectType(v[0])}
+		return nil
+	}
+
+	parentPath, key, index, err := splitDocumentPath(path)
+	if err != nil {
+		return err
+	}
+	ps, ok := d.resolve(parentPath)
+	if !ok {
+		if !opts.CreateMissing {
+			return fmt.Errorf("parent of path %q not found", path)
+		}
+		var wrapped []byte
+		if index >= 0 {
+			if err := checkArrayIndex(path, index, 0, opts); err != nil {
+				return err
+			}
+			wrapped = append(append(append([]byte{'['}, nullPadding(index)...), v...), ']')
+		} else {
+			wrapped = append(append(append(append([]byte{'{'}, quoteKey(key)...), ':'), v...), '}')
+		}
+		return d.set(parentPath, wrapped, opts)
+	}
+	parent := d.node(ps)
+	if d.preserve {
+		v = d.layoutValue(parent, d.memberIndent(parent), v)
+	}
+
+	var pos int
+	var insert []byte
+	switch {
+	case index < 0 && parent.typ == 'o':
+		pos, insert = fieldInsertion(d.data, parent, key, v)
+	case index >= 0 && parent.typ == 'a':
+		n := parent.Len()
+		if err := checkArrayIndex(path, index, n, opts); err != nil {
+			return err
+		}
+		pos, insert = elementInsertion(d.data, parent, append(nullPadding(index-n), v...))
+	default:
+		return fmt.Errorf("path %q does not match parent type %s", path, parent.Kind())
+	}
+
+	d.splice(pos, pos, insert)
+	start := pos + len(insert) - len(v)
+	d.spans[path] = docSpan{start: start, end: start + len(v), typ: detectType(v[0])}
+	return nil
+}
+
+// checkArrayIndex 检查能否在长度为 n 的数组中设置下标 index：等于长度时追加，
+// 超出长度时需开启 PadArrays 且填充后的长度不超过 DefaultParseOptions.MaxArrayItems
+func checkArrayIndex(path string, index, n int, opts SetOptions) error {
+	if index == n || (opts.PadArrays && index > n && index < DefaultParseOptions.MaxArrayItems) {
+		return nil
+	}
+	if opts.PadArrays && index > n {
+		return fmt.Errorf("index %d for path %q exceeds the array padding limit %d", index, path, DefaultParseOptions.MaxArrayItems)
+	}
+	return fmt.Errorf("index %d out of range for path %q (length %d)", index, path, n)
+}
+
+// nullPadding 返回 n 个 "null," 组成的数组填充
+func nullPadding(n int) []byte {
+	return []byte(strings.Repeat("null,", n))
+}
+
+// SetString 将路径处的值设置为字符串
+func (d *Document) SetString(path, s string) error {
+	return d.Set(path, quoteKey(s))
+}
+
+// Delete 删除路径处的对象字段或数组元素
+func (d *Document) Delete(path string) error {
+	parentPath, key, index, err := splitDocumentPath(path)
+	if err != nil {
+		return err
+	}
+	ps, ok := d.resolve(parentPath)
+	if !ok {
+		return fmt.Errorf("path %q not found", path)
+	}
+	parent := d.node(ps)
+
+	start, end := -1, -1
+	switch {
+	case index < 0 && parent.typ == 'o':
+		if span, found := parent.findFieldSpan(key); found {
+			start, end = span.keyStart, span.valueEnd
+		}
+	case index >= 0 && parent.typ == 'a':
+		parent.arrayScan(func(idx int, _ []byte, s, e int) bool {
+			if idx == index {
+				start, end = s, e
+				return false
+			}
+			return true
+		})
+	}
+	if start < 0 {
+		return fmt.Errorf("path %q not found", path)
+	}
+
+	cutStart, cutEnd := removalRange(d.data, parent, start, end)
+	d.splice(cutStart, cutEnd, nil)
+	if index >= 0 {
+		// 后续元素的下标前移，已缓存的下标路径失效
+		containerEnd := ps.end - (cutEnd - cutStart)
+		for p, s := range d.spans {
+			if s.start >= cutStart && s.end <= containerEnd {
+				delete(d.spans, p)
+			}
+		}
+	}
+	d.logEdit(path)
+	return nil
+}
+
+// DirtyRanges 返回自上次 ClearDirty 以来被修改的字节范围（当前坐标，已排序合并）
+// 删除产生的范围长度可能为 0，表示该位置有内容被移除
+func (d *Document) DirtyRanges() []ByteRange {
+	return append([]ByteRange(nil), d.dirty...)
+}
+
+// ClearDirty 清空已记录的修改范围
+func (d *Document) ClearDirty() {
+	d.dirty = d.dirty[:0]
+}
+
+// node 将位置转换为节点
+func (d *Document) node(s docSpan) Node {
+	return Node{raw: d.data, start: s.start, end: s.end, typ: s.typ}
+}
+
+// resolve 定位路径，只扫描未缓存路径的父容器
+func (d *Document) resolve(path string) (docSpan, bool) {
+	if path == "" {
+		return d.root, true
+	}
+	if s, ok := d.spans[path]; ok {
+		return s, true
+	}
+
+	parentPath, key, index, err := splitDocumentPath(path)
+	if err != nil {
+		return docSpan{}, false
+	}
+	ps, ok := d.resolve(parentPath)
+	if !ok {
+		return docSpan{}, false
+	}
+	parent := d.node(ps)
+
+	start, end := -1, -1
+	switch {
+	case index < 0 && parent.typ == 'o':
+		if span, found := parent.findFieldSpan(key); found {
+			start, end = span.valueStart, span.valueEnd
+		}
+	case index >= 0 && parent.typ == 'a':
+		parent.arrayScan(func(idx int, _ []byte, s, e int) bool {
+			if idx == index {
+				start, end = s, e
+				return false
+			}
+			return true
+		})
+	}
+	if start < 0 {
+		return docSpan{}, false
+	}
+
+	s := docSpan{start: start, end: end, typ: detectType(d.data[start])}
+	d.spans[path] = s
+	return s, true
+}
+
+// splice 将 [start, end) 替换为 repl，并平移受影响的缓存位置与修改范围
+func (d *Document) splice(start, end int, repl []byte) {
+	delta := len(repl) - (end - start)
+	oldLen := len(d.data)
+
+	switch {
+	case delta <= 0:
+		copy(d.data[start:], repl)
+		copy(d.data[start+len(repl):], d.data[end:])
+		d.data = d.data[:oldLen+delta]
+	case oldLen+delta <= cap(d.data):
+		d.data = d.data[:oldLen+delta]
+		copy(d.data[end+delta:], d.data[end:oldLen])
+		copy(d.data[start:], repl)
+	default:
+		grown := make([]byte, oldLen+delta, (oldLen+delta)*5/4)
+		copy(grown, d.data[:start])
+		copy(grown[start:], repl)
+		copy(grown[start+len(repl):], d.data[end:])
+		d.data = grown
+	}
+
+	// 编辑之前的位置不变，之后的平移，包含编辑范围的祖先只调整结尾，其余（被覆盖的子树）丢弃
+	for p, s := range d.spans {
+		switch {
+		case s.end <= start:
+		case s.start >= end:
+			d.spans[p] = docSpan{start: s.start + delta, end: s.end + delta, typ: s.typ}
+		case s.start <= start && s.end >= end && (s.start < start || s.end > end):
+			d.spans[p] = docSpan{start: s.start, end: s.end + delta, typ: s.typ}
+		default:
+			delete(d.spans, p)
+		}
+	}
+	if d.root.start <= start && d.root.end >= end {
+		d.root.end += delta
+	}
+
+	d.markDirty(start, end, len(repl))
+}
+
+// markDirty 记录一次编辑：旧范围 [start, end) 被替换为长度 n 的内容
+func (d *Document) markDirty(start, end, n int) {
+	delta := n - (end - start)
+	merged := ByteRange{Start: start, End: start + n}
+
+	kept := d.dirty[:0]
+	for _, r := range d.dirty {
+		switch {
+		case r.End < start:
+			kept = append(kept, r)
+		case r.Start > end:
+			kept = append(kept, ByteRange{Start: r.Start + delta, End: r.End + delta})
+		default:
+			merged.Start = min(merged.Start, r.Start)
+			if r.End > end {
+				merged.End = max(merged.End, r.End+delta)
+			}
+		}
+	}
+	d.dirty = append(kept, merged)
+	sort.Slice(d.dirty, func(i, j int) bool { return d.dirty[i].Start < d.dirty[j].Start })
+}
+
+// documentValue 验证并返回去除首尾空白的 JSON 值
+func documentValue(value []byte) ([]byte, error) {
+	if err := validateJSON(value, DefaultParseOptions); err != nil {
+		return nil, err
+	}
+	node := parseRootNode(value)
+	if !node.Exists() {
+		return nil, fmt.Errorf("invalid JSON value: %s", value)
+	}
+	return value[node.start:node.end], nil
+}
+
+// splitDocumentPath 拆分路径的最后一段：对象键返回 index = -1，数组下标返回 index >= 0
+func splitDocumentPath(path string) (parent, key string, index int, err error) {
+	if path == "" {
+		return "", "", -1, fmt.Errorf("empty path")
+	}
+
+	if strings.HasSuffix(path, "]") {
+		open := strings.LastIndexByte(path, '[')
+		if open < 0 {
+			return "", "", -1, fmt.Errorf("invalid path %q", path)
+		}
+		idx, ok := parseSegmentIndex(path[open+1 : len(path)-1])
+		if !ok {
+			return "", "", -1, fmt.Errorf("invalid array index in path %q", path)
+		}
+		return path[:open], "", idx, nil
+	}
+
+	parent, key, err = splitFieldPath(path)
+	return parent, key, -1, err
+}
@@ -0,0 +1,267 @@
+package fxjson
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestDocumentEdits 测试 Document 的基本修改
+func TestDocumentEdits(t *testing.T) {
+	doc, err := NewDocument([]byte(`{
+  "name": "a",
+  "tags": ["x", "y", "z"],
+  "meta": {"v": 1}
+}`))
+	if err != nil {
+		t.Fatalf("NewDocument failed: %v", err)
+	}
+
+	steps := []struct {
+		op    string
+		path  string
+		value string
+	}{
+		{"set", "name", `"renamed"`},
+		{"set", "meta.v", `{"major": 2}`},
+		{"set", "meta.v.minor", `0`},
+		{"set", "tags[3]", `"w"`},
+		{"delete", "tags[0]", ""},
+		{"set", "extra", `null`},
+		{"delete", "name", ""},
+	}
+	for _, s := range steps {
+		var err error
+		if s.op == "set" {
+			err = doc.Set(s.path, []byte(s.value))
+		} else {
+			err = doc.Delete(s.path)
+		}
+		if err != nil {
+			t.Fatalf("%s %s failed: %v", s.op, s.path, err)
+		}
+	}
+
+	want := `{
+  "tags": ["y", "z", "w"],
+  "meta": {"v": {"major": 2,"minor": 0}},
+  "extra": null
+}`
+	if got := string(doc.Bytes()); got != want {
+		t.Errorf("document =\n%s\nwant\n%s", got, want)
+	}
+	if got := doc.Get("tags[0]").StringOr(""); got != "y" {
+		t.Errorf("tags[0] = %q, want y", got)
+	}
+	if got := doc.Get("meta.v.minor").IntOr(-1); got != 0 {
+		t.Errorf("meta.v.minor = %d, want 0", got)
+	}
+
+	if err := doc.Set("tags[9]", []byte(`1`)); err == nil {
+		t.Error("expected error for out-of-range append")
+	}
+	if err := doc.Set("meta.v", []byte(`{bad`)); err == nil {
+		t.Error("expected error for invalid value")
+	}
+	if err := doc.Delete("missing"); err == nil {
+		t.Error("expected error for missing path")
+	}
+	if err := doc.Set("tags.x", []byte(`1`)); err == nil {
+		t.Error("expected error for key on array parent")
+	}
+
+	if err := doc.Set("", []byte(` [1] `)); err != nil || string(doc.Bytes()) != "[1]" {
+		t.Errorf("root replace: %s, %v", doc.Bytes(), err)
+	}
+}
+
+// TestDocumentDirtyRanges 测试修改范围的记录与合并
+func TestDocumentDirtyRanges(t *testing.T) {
+	doc, err := NewDocument([]byte(`{"a":1,"b":2,"c":3}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_ = doc.Set("c", []byte(`30`))
+	_ = doc.Set("a", []byte(`100`))
+	want := []ByteRange{{5, 8}, {19, 21}}
+	if got := doc.DirtyRanges(); !reflect.DeepEqual(got, want) {
+		t.Errorf("DirtyRanges = %v, want %v", got, want)
+	}
+	for _, r := range doc.DirtyRanges() {
+		if s := string(doc.Bytes()[r.Start:r.End]); s != "100" && s != "30" {
+			t.Errorf("dirty range %v covers %q", r, s)
+		}
+	}
+
+	// 覆盖已修改的范围时合并
+	_ = doc.Set("a", []byte(`1`))
+	if got := doc.DirtyRanges(); !reflect.DeepEqual(got, []ByteRange{{5, 6}, {17, 19}}) {
+		t.Errorf("DirtyRanges after overwrite = %v", got)
+	}
+
+	doc.ClearDirty()
+	if len(doc.DirtyRanges()) != 0 {
+		t.Error("ClearDirty should reset ranges")
+	}
+}
+
+// TestDocumentSetCreatePath 测试创建缺失的中间节点与数组填充
+func TestDocumentSetCreatePath(t *testing.T) {
+	doc, err := NewDocument([]byte(`{"x":1}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := doc.Set("a.b[2].c", []byte(`1`)); err == nil {
+		t.Error("Set should fail when the parent is missing")
+	}
+	if err := doc.SetCreatePath("a.b[2].c", []byte(`1`)); err != nil {
+		t.Fatalf("SetCreatePath failed: %v", err)
+	}
+	if want := `{"x":1,"a":{"b":[null,null,{"c":1}]}}`; string(doc.Bytes()) != want {
+		t.Errorf("document = %s, want %s", doc.Bytes(), want)
+	}
+	if v, _ := doc.Get("a.b[2].c").Int(); v != 1 {
+		t.Errorf("created value not reachable")
+	}
+
+	// 已存在的数组末尾之后填充 null，已存在的对象中追加字段
+	if err := doc.SetCreatePath("a.b[4]", []byte(`"e"`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.SetCreatePath("a.b[2].d[0]", []byte(`true`)); err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"x":1,"a":{"b":[null,null,{"c":1,"d":[true]},null,"e"]}}`; string(doc.Bytes()) != want {
+		t.Errorf("document = %s, want %s", doc.Bytes(), want)
+	}
+
+	// 只填充数组、不创建中间节点
+	opts := SetOptions{PadArrays: true}
+	if err := doc.SetWithOptions("a.b[6]", []byte(`6`), opts); err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.SetWithOptions("y.z", []byte(`1`), opts); err == nil {
+		t.Error("missing parent should fail without CreateMissing")
+	}
+	if err := doc.Set("a.b[9]", []byte(`9`)); err == nil {
+		t.Error("Set should not pad arrays")
+	}
+
+	// 创建中间数组时同样只在 PadArrays 下填充，且填充长度有上限
+	before := string(doc.Bytes())
+	if err := doc.SetWithOptions("c[2]", []byte(`1`), SetOptions{CreateMissing: true}); err == nil {
+		t.Error("creating a padded array should fail without PadArrays")
+	}
+	if err := doc.SetCreatePath("c[100000000000]", []byte(`1`)); err == nil {
+		t.Error("expected error for padding beyond the limit")
+	}
+	if err := doc.SetCreatePath("a.b[100000000000]", []byte(`1`)); err == nil {
+		t.Error("expected error for padding an existing array beyond the limit")
+	}
+	if string(doc.Bytes()) != before {
+		t.Errorf("failed sets changed the document: %s", doc.Bytes())
+	}
+	if err := doc.SetWithOptions("c[0]", []byte(`0`), SetOptions{CreateMissing: true}); err != nil {
+		t.Errorf("appending to a created array: %v", err)
+	}
+	if err := doc.Delete("c"); err != nil {
+		t.Fatal(err)
+	}
+
+	// 中间节点类型不匹配
+	if err := doc.SetCreatePath("x.y", []byte(`1`)); err == nil {
+		t.Error("expected error when intermediate node is a scalar")
+	}
+	if err := doc.SetCreatePath("a[0]", []byte(`1`)); err == nil {
+		t.Error("expected error when indexing an object")
+	}
+
+	if reparsed := FromBytes(doc.Bytes()); string(reparsed.Get("a").Raw()) != `{"b":[null,null,{"c":1,"d":[true]},null,"e",null,6]}` {
+		t.Errorf("reparsed = %s", reparsed.Raw())
+	}
+}
+
+// TestDocumentMatchesReparse 随机修改后，缓存的路径位置必须与重新解析的结果一致
+func TestDocumentMatchesReparse(t *testing.T) {
+	var sb strings.Builder
+	sb.WriteString(`{"items":[`)
+	for i := 0; i < 50; i++ {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		fmt.Fprintf(&sb, `{"id":%d,"name":"n%d","tags":["a","b"]}`, i, i)
+	}
+	sb.WriteString(`],"count":50}`)
+
+	doc, err := NewDocument([]byte(sb.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	for step := 0; step < 300; step++ {
+		n := doc.Get("items").Len()
+		i := rng.Intn(n)
+		path := "items[" + strconv.Itoa(i) + "]"
+
+		switch rng.Intn(4) {
+		case 0:
+			err = doc.Set(path+".name", []byte(strconv.Quote(strings.Repeat("x", rng.Intn(8)))))
+		case 1:
+			err = doc.Set(path+".tags["+strconv.Itoa(rng.Intn(2))+"]", []byte(strconv.Itoa(step)))
+		case 2:
+			if n > 10 {
+				err = doc.Delete(path)
+			}
+		case 3:
+			err = doc.Set("items["+strconv.Itoa(n)+"]", []byte(`{"id":-1,"name":"new","tags":[1,2]}`))
+		}
+		if err != nil {
+			t.Fatalf("step %d: %v", step, err)
+		}
+
+		fresh := FromBytes(doc.Bytes())
+		if !fresh.Exists() {
+			t.Fatalf("step %d: document no longer valid: %s", step, doc.Bytes())
+		}
+		for _, p := range []string{path, path + ".name", path + ".tags[1]", "items[0].id", "count"} {
+			got, want := doc.Get(p), fresh.GetPath(p)
+			if string(got.Raw()) != string(want.Raw()) {
+				t.Fatalf("step %d: Get(%q) = %s, want %s", step, p, got.Raw(), want.Raw())
+			}
+		}
+	}
+}
+
+// BenchmarkDocumentSet 在大文档上反复修改同一字段
+func BenchmarkDocumentSet(b *testing.B) {
+	var sb strings.Builder
+	sb.WriteString(`{"items":[`)
+	for i := 0; sb.Len() < 5<<20; i++ {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		fmt.Fprintf(&sb, `{"id":%d,"payload":"%s"}`, i, strings.Repeat("x", 100))
+	}
+	sb.WriteString(`],"version":0}`)
+
+	doc, err := NewDocument([]byte(sb.String()))
+	if err != nil {
+		b.Fatal(err)
+	}
+	_ = doc.Get("version")
+
+	values := [][]byte{[]byte("1"), []byte("22")}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := doc.Set("version", values[i&1]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
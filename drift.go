@@ -0,0 +1,202 @@
+package fxjson
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// DriftSeverity 配置漂移的严重程度
+type DriftSeverity int
+
+const (
+	DriftInfo     DriftSeverity = iota // 实际配置多出的条目
+	DriftWarning                       // 值不同但类型相同
+	DriftCritical                      // 期望的条目缺失或类型不同
+)
+
+// String 返回严重程度名称
+func (s DriftSeverity) String() string {
+	switch s {
+	case DriftInfo:
+		return "info"
+	case DriftWarning:
+		return "warning"
+	case DriftCritical:
+		return "critical"
+	}
+	return fmt.Sprintf("DriftSeverity(%d)", int(s))
+}
+
+// DriftEntry 单个漂移条目
+type DriftEntry struct {
+	Path     string        // 条目路径，如 "spec.replicas" 或 "ports[0]"
+	Severity DriftSeverity // 严重程度
+	Desired  Node          // 期望值，extra 条目中不存在
+	Actual   Node          // 实际值，missing 条目中不存在
+}
+
+// DriftResult 期望配置与实际配置的比较结果，各分类按文档顺序排列
+type DriftResult struct {
+	Missing []DriftEntry // 期望存在但实际缺失（critical）
+	Extra   []DriftEntry // 实际存在但未被期望（info）
+	Changed []DriftEntry // 两侧都存在但值不同（类型相同为 warning，类型不同为 critical）
+}
+
+// HasDrift 是否存在任何漂移
+func (r DriftResult) HasDrift() bool {
+	return len(r.Missing)+len(r.Extra)+len(r.Changed) > 0
+}
+
+// MaxSeverity 返回所有条目中最高的严重程度，没有漂移时返回 DriftInfo
+func (r DriftResult) MaxSeverity() DriftSeverity {
+	severity := DriftInfo
+	for _, entries := range [][]DriftEntry{r.Missing, r.Extra, r.Changed} {
+		for _, e := range entries {
+			severity = max(severity, e.Severity)
+		}
+	}
+	return severity
+}
+
+// DriftReport 比较期望配置 desired 与实际配置 actual，按缺失、多出和变更分类漂移条目
+//
+// ignore 中的路径及其子树不参与比较（如时间戳、状态字段）。路径段 "*" 匹配任意键，
+// "[*]" 匹配任意下标，例如 "metadata.*.updatedAt"、"items[*].status"。
+// 数字按数值比较（1 与 1.0 相等），字符串按解转义后的内容比较，数组按下标逐个比较。
+func DriftReport(desired, actual Node, ignore []string) DriftResult {
+	d := driftWalker{ignore: make([][]string, 0, len(ignore))}
+	for _, p := range ignore {
+		d.ignore = append(d.ignore, splitDriftPath(p))
+	}
+	d.compare(desired, actual, "")
+	return d.result
+}
+
+// driftWalker 递归比较两侧配置的状态
+type driftWalker struct {
+	ignore  [][]string
+	result  DriftResult
+	scratch [2][]byte
+}
+
+// compare 比较 path 处的两侧节点
+func (d *driftWalker) compare(desired, actual Node, path string) {
+	if path != "" && d.ignored(path) {
+		return
+	}
+	switch {
+	case !desired.Exists() && !actual.Exists():
+		return
+	case !actual.Exists():
+		d.result.Missing = append(d.result.Missing, DriftEntry{Path: path, Severity: DriftCritical, Desired: desired})
+		return
+	case !desired.Exists():
+		d.result.Extra = append(d.result.Extra, DriftEntry{Path: path, Severity: DriftInfo, Actual: actual})
+		return
+	case desired.typ != actual.typ:
+		d.result.Changed = append(d.result.Changed, DriftEntry{Path: path, Severity: DriftCritical, Desired: desired, Actual: actual})
+		return
+	}
+
+	switch desired.typ {
+	case 'o':
+		desired.ForEach(func(key string, value Node) bool {
+			d.compare(value, actual.Get(key), joinDriftPath(path, key))
+			return true
+		})
+		actual.ForEach(func(key string, value Node) bool {
+			if !desired.Get(key).Exists() {
+				d.compare(Node{}, value, joinDriftPath(path, key))
+			}
+			return true
+		})
+	case 'a':
+		n := max(desired.Len(), actual.Len())
+		for i := 0; i < n; i++ {
+			d.compare(desired.Index(i), actual.Index(i), fmt.Sprintf("%s[%d]", path, i))
+		}
+	default:
+		if !d.scalarEqual(desired, actual) {
+			d.result.Changed = append(d.result.Changed, DriftEntry{Path: path, Severity: DriftWarning, Desired: desired, Actual: actual})
+		}
+	}
+}
+
+// scalarEqual 比较两个同类型的标量节点，数字按精确数值比较（超过 2^53 的整数 ID 不会被视为相同）
+func (d *driftWalker) scalarEqual(a, b Node) bool {
+	switch a.typ {
+	case 'n':
+		if cmp, ok := compareExactNumbers(numberLiteral(a.Raw()), numberLiteral(b.Raw())); ok {
+			return cmp == 0
+		}
+	case 's':
+		ra, rb := a.Raw(), b.Raw()
+		d.scratch[0] = appendUnescaped(d.scratch[0][:0], ra[1:len(ra)-1])
+		d.scratch[1] = appendUnescaped(d.scratch[1][:0], rb[1:len(rb)-1])
+		return bytes.Equal(d.scratch[0], d.scratch[1])
+	case 'l':
+		return true
+	}
+	return bytes.Equal(a.Raw(), b.Raw())
+}
+
+// ignored path 是否位于某个忽略路径（含其子树）之下
+func (d *driftWalker) ignored(path string) bool {
+	if len(d.ignore) == 0 {
+		return false
+	}
+	segments := splitDriftPath(path)
+	for _, pattern := range d.ignore {
+		if matchDriftPath(pattern, segments) {
+			return true
+		}
+	}
+	return false
+}
+
+// joinDriftPath 拼接对象键路径
+func joinDriftPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// splitDriftPath 将 "a.b[0]" 拆分为 "a"、"b"、"[0]" 路径段
+func splitDriftPath(path string) []string {
+	var segments []string
+	for _, part := range strings.Split(path, ".") {
+		for part != "" {
+			end := len(part)
+			if part[0] == '[' {
+				if j := strings.IndexByte(part, ']'); j >= 0 {
+					end = j + 1
+				}
+			} else if j := strings.IndexByte(part, '['); j >= 0 {
+				end = j
+			}
+			segments = append(segments, part[:end])
+			part = part[end:]
+		}
+	}
+	return segments
+}
+
+// matchDriftPath pattern 的每一段是否与 segments 的前缀逐段匹配
+func matchDriftPath(pattern, segments []string) bool {
+	if len(pattern) == 0 || len(pattern) > len(segments) {
+		return false
+	}
+	for i, p := range pattern {
+		s := segments[i]
+		switch {
+		case p == s:
+		case p == "*" && s[0] != '[':
+		case p == "[*]" && s[0] == '[':
+		default:
+			return false
+		}
+	}
+	return true
+}
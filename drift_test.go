@@ -0,0 +1,46 @@
+package fxjson
+
+import "testing"
+
+// TestDriftReport 测试期望配置与实际配置的漂移分类与忽略路径
+func TestDriftReport(t *testing.T) {
+	desired := FromString(`{"name":"api","replicas":3,"cpu":1.0,"labels":{"team":"core","tier":"web"},"ports":[80,443],"image":"a/b","updatedAt":"2026-01-01"}`)
+	actual := FromString(`{"name":"api","replicas":"3","cpu":1,"labels":{"team":"edge","owner":"x","updatedAt":"t"},"ports":[80,8443,9000],"image":"a/b","updatedAt":"2026-10-16","status":{"ready":true}}`)
+
+	r := DriftReport(desired, actual, []string{"updatedAt", "status", "*.updatedAt"})
+	paths := func(entries []DriftEntry) []string {
+		var out []string
+		for _, e := range entries {
+			out = append(out, e.Path)
+		}
+		return out
+	}
+	if got := paths(r.Missing); len(got) != 1 || got[0] != "labels.tier" {
+		t.Errorf("Missing = %v", got)
+	}
+	if got := paths(r.Extra); len(got) != 2 || got[0] != "labels.owner" || got[1] != "ports[2]" {
+		t.Errorf("Extra = %v", got)
+	}
+	if got := paths(r.Changed); len(got) != 3 || got[0] != "replicas" || got[1] != "labels.team" || got[2] != "ports[1]" {
+		t.Errorf("Changed = %v", got)
+	}
+	if r.Changed[0].Severity != DriftCritical || r.Changed[1].Severity != DriftWarning {
+		t.Errorf("Changed severities = %v, %v", r.Changed[0].Severity, r.Changed[1].Severity)
+	}
+	if s, _ := r.Changed[1].Actual.String(); s != "edge" {
+		t.Errorf("Changed[1].Actual = %q", s)
+	}
+	if !r.HasDrift() || r.MaxSeverity() != DriftCritical || r.MaxSeverity().String() != "critical" {
+		t.Errorf("HasDrift = %v, MaxSeverity = %v", r.HasDrift(), r.MaxSeverity())
+	}
+
+	r = DriftReport(FromString(`{"items":[{"id":1,"status":"a"}]}`), FromString(`{"items":[{"id":1,"status":"b"}]}`), []string{"items[*].status"})
+	if r.HasDrift() {
+		t.Errorf("ignored wildcard path reported drift: %+v", r)
+	}
+	// 数字按精确数值比较：写法不同的相同值不算漂移，超过 2^53 的不同整数算漂移
+	r = DriftReport(FromString(`{"id":9007199254740993,"n":1.50,"e":1e2}`), FromString(`{"id":9007199254740992,"n":1.5,"e":100}`), nil)
+	if got := paths(r.Changed); len(got) != 1 || got[0] != "id" {
+		t.Errorf("Changed = %v", got)
+	}
+}
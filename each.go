@@ -0,0 +1,69 @@
+package fxjson
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+)
+
+// eachScalar 遍历对象或数组中的标量值，要求值的类型均为 typ，fn 返回 false 时停止
+// 数组元素的 key 为十进制下标；值以原始字节传给 fn，不构建中间 Node
+func (n Node) eachScalar(typ byte, fn func(key string, raw []byte) (bool, error)) error {
+	var err error
+	visit := func(key string, data []byte, start, end int) bool {
+		if t := detectType(data[start]); t != typ {
+			err = fmt.Errorf("value at %q is %s, expected %s", key, NodeType(t), NodeType(typ))
+			return false
+		}
+		var more bool
+		more, err = fn(key, data[start:end])
+		return err == nil && more
+	}
+
+	switch n.typ {
+	case 'o':
+		data := n.getWorkingData()
+		n.forEachFieldSpan(func(key string, span fieldSpan) bool {
+			return visit(key, data, span.valueStart, span.valueEnd)
+		})
+	case 'a':
+		n.arrayScan(func(idx int, data []byte, start, end int) bool {
+			return visit(strconv.Itoa(idx), data, start, end)
+		})
+	default:
+		return fmt.Errorf("Each requires an object or array, got %s", n.Kind())
+	}
+	return err
+}
+
+// EachString 遍历全部为字符串值的对象或数组，数组的 key 为十进制下标
+// 遇到非字符串值时停止并返回错误；fn 返回 false 时提前结束
+func (n Node) EachString(fn func(key string, s string) bool) error {
+	return n.eachScalar('s', func(key string, raw []byte) (bool, error) {
+		s := bytesToString(raw[1 : len(raw)-1])
+		if bytes.IndexByte(raw, '\\') >= 0 {
+			s = unescapeJSON(s)
+		}
+		return fn(key, s), nil
+	})
+}
+
+// EachInt 遍历全部为整数值的对象或数组，数组的 key 为十进制下标
+// 遇到非数字或非整数值时停止并返回错误；fn 返回 false 时提前结束
+func (n Node) EachInt(fn func(key string, v int64) bool) error {
+	return n.eachScalar('n', func(key string, raw []byte) (bool, error) {
+		v, err := parseIntFast(raw)
+		if err != nil {
+			return false, fmt.Errorf("value at %q is not an int64: %s", key, raw)
+		}
+		return fn(key, v), nil
+	})
+}
+
+// EachFloat 遍历全部为数字值的对象或数组，数组的 key 为十进制下标
+// 遇到非数字值时停止并返回错误；fn 返回 false 时提前结束
+func (n Node) EachFloat(fn func(key string, v float64) bool) error {
+	return n.eachScalar('n', func(key string, raw []byte) (bool, error) {
+		return fn(key, parseFloatMode(raw, FloatExact)), nil
+	})
+}
@@ -0,0 +1,88 @@
+package fxjson
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// utf8BOM UTF-8 字节顺序标记
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// FromReader 读取 r 的全部内容并按 opts 解析
+func FromReader(r io.Reader, opts ParseOptions) (Node, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return Node{}, err
+	}
+	return ParseBytes(b, opts)
+}
+
+// decodeInputEncoding 去除 UTF-8 BOM，按选项将 UTF-16 输入转码为 UTF-8 并替换非法 UTF-8 序列；
+// 未开启 TranscodeUTF16 时遇到 UTF-16 输入返回说明原因的错误
+func decodeInputEncoding(b []byte, opts ParseOptions) ([]byte, error) {
+	if bytes.HasPrefix(b, utf8BOM) {
+		b = b[len(utf8BOM):]
+	}
+
+	if bigEndian, bom, ok := detectUTF16(b); ok {
+		name := "UTF-16LE"
+		if bigEndian {
+			name = "UTF-16BE"
+		}
+		if !opts.TranscodeUTF16 {
+			return nil, &FxJSONError{
+				Type:    ErrorTypeInvalidJSON,
+				Message: fmt.Sprintf("input appears to be %s encoded; enable ParseOptions.TranscodeUTF16 to parse it", name),
+			}
+		}
+		body := b[bom:]
+		if len(body)%2 != 0 {
+			return nil, &FxJSONError{Type: ErrorTypeInvalidJSON, Message: fmt.Sprintf("truncated %s input: odd byte length %d", name, len(body))}
+		}
+		b = transcodeUTF16(body, bigEndian)
+	}
+
+	if opts.ReplaceInvalidUTF8 && !utf8.Valid(b) {
+		b = bytes.ToValidUTF8(b, []byte(string(utf8.RuneError)))
+	}
+	return b, nil
+}
+
+// detectUTF16 通过 BOM 或 ASCII 字符的零字节位置（RFC 4627 第 3 节）识别 UTF-16 输入，
+// 返回字节序与 BOM 长度
+func detectUTF16(b []byte) (bigEndian bool, bom int, ok bool) {
+	if len(b) < 2 {
+		return false, 0, false
+	}
+	switch {
+	case b[0] == 0xFE && b[1] == 0xFF:
+		return true, 2, true
+	case b[0] == 0xFF && b[1] == 0xFE:
+		return false, 2, true
+	case b[0] == 0 && b[1] != 0:
+		return true, 0, true
+	case b[0] != 0 && b[1] == 0:
+		return false, 0, true
+	}
+	return false, 0, false
+}
+
+// transcodeUTF16 将 UTF-16 字节转为 UTF-8，未配对的代理项替换为 U+FFFD
+func transcodeUTF16(b []byte, bigEndian bool) []byte {
+	units := make([]uint16, len(b)/2)
+	for i := range units {
+		if bigEndian {
+			units[i] = uint16(b[2*i])<<8 | uint16(b[2*i+1])
+		} else {
+			units[i] = uint16(b[2*i+1])<<8 | uint16(b[2*i])
+		}
+	}
+	out := make([]byte, 0, len(units))
+	for _, r := range utf16.Decode(units) {
+		out = utf8.AppendRune(out, r)
+	}
+	return out
+}
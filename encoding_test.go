@@ -0,0 +1,110 @@
+package fxjson
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"unicode/utf16"
+)
+
+// encodeUTF16 将字符串编码为 UTF-16，bom 为 true 时写入字节顺序标记
+func encodeUTF16(s string, bigEndian, bom bool) []byte {
+	var out []byte
+	units := utf16.Encode([]rune(s))
+	if bom {
+		units = append([]uint16{0xFEFF}, units...)
+	}
+	for _, u := range units {
+		if bigEndian {
+			out = append(out, byte(u>>8), byte(u))
+		} else {
+			out = append(out, byte(u), byte(u>>8))
+		}
+	}
+	return out
+}
+
+// TestInputEncoding 测试 BOM 跳过、UTF-16 转码与非法 UTF-8 替换
+func TestInputEncoding(t *testing.T) {
+	const doc = `{"name":"中文 😀","n":1}`
+
+	node := FromBytes(append([]byte{0xEF, 0xBB, 0xBF}, doc...))
+	if s, _ := node.Get("name").String(); s != "中文 😀" {
+		t.Errorf("UTF-8 BOM: name = %q", s)
+	}
+
+	opts := DefaultParseOptions
+	opts.TranscodeUTF16 = true
+	for _, tc := range []struct {
+		name           string
+		bigEndian, bom bool
+	}{
+		{"LE with BOM", false, true},
+		{"BE with BOM", true, true},
+		{"LE without BOM", false, false},
+		{"BE without BOM", true, false},
+	} {
+		input := encodeUTF16(doc, tc.bigEndian, tc.bom)
+		node, err := FromReader(bytes.NewReader(input), opts)
+		if err != nil {
+			t.Errorf("%s: %v", tc.name, err)
+			continue
+		}
+		if s, _ := node.Get("name").String(); s != "中文 😀" || node.Get("n").IntOr(0) != 1 {
+			t.Errorf("%s: got %s", tc.name, node.Raw())
+		}
+
+		if _, err := ParseBytes(input, DefaultParseOptions); err == nil || !strings.Contains(err.Error(), "TranscodeUTF16") {
+			t.Errorf("%s without TranscodeUTF16: err = %v", tc.name, err)
+		}
+	}
+
+	if _, err := ParseBytes([]byte{0xFF, 0xFE, '{', 0, '}'}, opts); err == nil {
+		t.Errorf("odd-length UTF-16 input accepted")
+	}
+
+	opts = DefaultParseOptions
+	opts.ReplaceInvalidUTF8 = true
+	node, err := ParseBytes([]byte("{\"s\":\"a\xffb\"}"), opts)
+	if err != nil {
+		t.Fatalf("ReplaceInvalidUTF8: %v", err)
+	}
+	if s, _ := node.Get("s").String(); s != "a�b" {
+		t.Errorf("ReplaceInvalidUTF8: s = %q", s)
+	}
+}
+
+// TestValidateUTF8 测试字符串中非法 UTF-8 的拒绝与替换
+func TestValidateUTF8(t *testing.T) {
+	input := []byte("{\"s\":\"a\xc3\x28b\",\"ok\":\"中文\"}")
+
+	opts := DefaultParseOptions
+	opts.ValidateUTF8 = true
+	if _, err := ParseBytes(input, opts); err == nil || !strings.Contains(err.Error(), "position 7") {
+		t.Errorf("ValidateUTF8 err = %v", err)
+	}
+	if _, err := ParseBytes([]byte(`{"ok":"中文 😀"}`), opts); err != nil {
+		t.Errorf("ValidateUTF8 rejected valid input: %v", err)
+	}
+
+	opts.ReplaceInvalidUTF8 = true
+	node, err := ParseBytes(input, opts)
+	if err != nil {
+		t.Fatalf("ValidateUTF8 with ReplaceInvalidUTF8: %v", err)
+	}
+	if s, _ := node.Get("s").String(); s != "a�(b" {
+		t.Errorf("sanitized s = %q", s)
+	}
+
+	// 默认不校验，SanitizedString 按需替换
+	node = FromBytes(input)
+	if s, _ := node.Get("s").String(); s != "a\xc3(b" {
+		t.Errorf("String = %q", s)
+	}
+	if s, _ := node.Get("s").SanitizedString(); s != "a�(b" {
+		t.Errorf("SanitizedString = %q", s)
+	}
+	if s, _ := node.Get("ok").SanitizedString(); s != "中文" {
+		t.Errorf("SanitizedString = %q", s)
+	}
+}
@@ -22,6 +22,10 @@ const (
 	ErrorTypeNotFound
 	// ErrorTypeValidation 验证错误
 	ErrorTypeValidation
+	// ErrorTypeInternal 内部错误（由导出接口拦截的 panic 转换而来）
+	ErrorTypeInternal
+	// ErrorTypeResultLimit 查询或聚合结果超出 ResultLimits
+	ErrorTypeResultLimit
 )
 
 // String 返回错误类型的字符串表示
@@ -41,6 +45,10 @@ func (et ErrorType) String() string {
 		return "NotFound"
 	case ErrorTypeValidation:
 		return "Validation"
+	case ErrorTypeInternal:
+		return "Internal"
+	case ErrorTypeResultLimit:
+		return "ResultLimit"
 	default:
 		return "Unknown"
 	}
@@ -160,3 +168,11 @@ func NewMemoryLimitError(limit, requested int) *FxJSONError {
 		Message: fmt.Sprintf("memory limit %d exceeded, requested: %d", limit, requested),
 	}
 }
+
+// NewResultLimitError 创建结果规模超限错误，limit 为 ResultLimits 中的字段名
+func NewResultLimitError(limit string, max, actual int) *FxJSONError {
+	return &FxJSONError{
+		Type:    ErrorTypeResultLimit,
+		Message: fmt.Sprintf("%s %d exceeded: %d", limit, max, actual),
+	}
+}
@@ -0,0 +1,107 @@
+package fxjson
+
+import "sync"
+
+// lazyExpansion 延迟展开的共享状态，由同一次解析得到的所有节点副本共享
+// 每个节点范围的展开结果只计算一次，多个 goroutine 并发调用 Expand/ExpandAll 是安全的
+type lazyExpansion struct {
+	opts    ParseOptions
+	depth   int // 当前数据所在的嵌套 JSON 字符串层数
+	mu      sync.Mutex
+	entries map[lazyKey]*lazyEntry
+}
+
+// lazyKey 展开结果的缓存键
+type lazyKey struct {
+	start, end int
+	all        bool // ExpandAll 的结果
+}
+
+// lazyEntry 一个节点范围的展开结果
+type lazyEntry struct {
+	once sync.Once
+	node Node
+	err  error
+}
+
+// newLazyExpansion 创建延迟展开状态
+func newLazyExpansion(opts ParseOptions, depth int) *lazyExpansion {
+	opts.LazyExpansion = true
+	return &lazyExpansion{opts: opts, depth: depth, entries: make(map[lazyKey]*lazyEntry)}
+}
+
+// entry 获取（或创建）指定范围的缓存项
+func (l *lazyExpansion) entry(key lazyKey) *lazyEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	e, ok := l.entries[key]
+	if !ok {
+		e = &lazyEntry{}
+		l.entries[key] = e
+	}
+	return e
+}
+
+// Expand 将内容为合法 JSON 的字符串节点展开为对应的节点（只展开一层），其他节点原样返回
+// 对 ParseOptions.LazyExpansion 解析得到的节点，结果按节点缓存且并发安全，
+// 展开后的节点同样是延迟的，其内部的嵌套 JSON 字符串可继续 Expand
+func (n Node) Expand() Node {
+	if n.typ != 's' {
+		return n
+	}
+	if n.lazy == nil {
+		return expandStringNode(n, DefaultParseOptions, 0)
+	}
+
+	e := n.lazy.entry(lazyKey{start: n.start, end: n.end})
+	e.once.Do(func() {
+		e.node = expandStringNode(n, n.lazy.opts, n.lazy.depth)
+	})
+	return e.node
+}
+
+// ExpandAll 立即递归展开节点内的全部嵌套 JSON 字符串，与非延迟解析（FromBytes）的结果一致
+// 对延迟解析得到的节点，结果按节点缓存且并发安全；超出展开预算时返回错误
+func (n Node) ExpandAll() (Node, error) {
+	if !n.Exists() {
+		return n, &FxJSONError{Type: ErrorTypeInvalidJSON, Message: "node does not exist"}
+	}
+	if n.lazy == nil {
+		return expandAllNode(n, DefaultParseOptions)
+	}
+
+	e := n.lazy.entry(lazyKey{start: n.start, end: n.end, all: true})
+	e.once.Do(func() {
+		e.node, e.err = expandAllNode(n, n.lazy.opts)
+	})
+	return e.node, e.err
+}
+
+// expandStringNode 将字符串节点的内容解析为延迟展开的节点，不是合法 JSON 或超出预算时原样返回
+func expandStringNode(n Node, opts ParseOptions, depth int) Node {
+	if opts.MaxExpansionDepth > 0 && depth >= opts.MaxExpansionDepth {
+		return n
+	}
+	s, err := n.String()
+	if err != nil || !isValidJSON(s) {
+		return n
+	}
+	if opts.MaxExpandedBytes > 0 && len(s) > opts.MaxExpandedBytes {
+		return n
+	}
+
+	opts.LazyExpansion = true
+	nested, err := ParseBytes([]byte(s), opts)
+	if err != nil {
+		return n
+	}
+	nested.lazy = newLazyExpansion(opts, depth+1)
+	return nested
+}
+
+// expandAllNode 递归展开节点内的全部嵌套 JSON 字符串
+func expandAllNode(n Node, opts ParseOptions) (Node, error) {
+	opts.LazyExpansion = false
+	data := n.getWorkingData()[n.start:n.end]
+	return ParseBytes(data, opts)
+}
@@ -0,0 +1,239 @@
+package fxjson
+
+import (
+	"fmt"
+	"strings"
+)
+
+// explainSampleSize Explain 检查字段是否存在时采样的元素数量
+const explainSampleSize = 100
+
+// ExecutionPlan 查询或聚合的执行计划，由 Explain 生成而不实际执行
+type ExecutionPlan struct {
+	Steps         []string      // 按执行顺序排列的操作
+	Fields        []FieldAccess // 访问的字段，按首次出现的顺序
+	Elements      int           // 源数组的元素数量
+	CachedOffsets bool          // 源数组的元素偏移量已在缓存中，按下标访问元素无需重新扫描
+	Warnings      []string      // 潜在问题：字段在采样元素中不存在、未知运算符或聚合参数错误等
+}
+
+// FieldAccess 执行计划访问的字段
+type FieldAccess struct {
+	Path       string // 字段路径
+	Usage      string // filter、sort、group_by 或 aggregate
+	Found      bool   // 采样的前 100 个元素中至少有一个包含该字段
+	Suggestion string // 未找到时采样元素中拼写最接近的字段路径
+}
+
+// String 以多行文本输出执行计划
+func (p *ExecutionPlan) String() string {
+	var sb strings.Builder
+	for i, step := range p.Steps {
+		fmt.Fprintf(&sb, "%d. %s\n", i+1, step)
+	}
+	for _, f := range p.Fields {
+		status := "found"
+		if !f.Found {
+			status = "not found"
+			if f.Suggestion != "" {
+				status += fmt.Sprintf(", did you mean %q?", f.Suggestion)
+			}
+		}
+		fmt.Fprintf(&sb, "field %q (%s): %s\n", f.Path, f.Usage, status)
+	}
+	for _, w := range p.Warnings {
+		sb.WriteString("warning: " + w + "\n")
+	}
+	return sb.String()
+}
+
+// Explain 返回查询的执行计划而不执行查询，可用于优化查询链或排查拼写错误的字段路径
+func (qb *QueryBuilder) Explain() (*ExecutionPlan, error) {
+	if qb.node.Type() != 'a' {
+		return nil, fmt.Errorf("node is not an array")
+	}
+
+	plan := newExecutionPlan(qb.node)
+	for _, cond := range qb.conditions {
+		plan.Steps = append(plan.Steps, fmt.Sprintf("filter %s %s %v", cond.Field, cond.Operator, cond.Value))
+		plan.addField(qb.node, cond.Field, "filter")
+		if !isQueryOperator(cond.Operator) {
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("unknown operator %q on %s matches no elements", cond.Operator, cond.Field))
+		}
+	}
+	if len(qb.sortFields) > 0 {
+		keys := make([]string, len(qb.sortFields))
+		for i, sf := range qb.sortFields {
+			order := sf.Order
+			if order != "desc" {
+				order = "asc"
+			}
+			keys[i] = sf.Field + " " + order
+			plan.addField(qb.node, sf.Field, "sort")
+		}
+		plan.Steps = append(plan.Steps, "sort by "+strings.Join(keys, ", "))
+	}
+	if qb.offsetVal > 0 {
+		plan.Steps = append(plan.Steps, fmt.Sprintf("skip %d results", qb.offsetVal))
+	}
+	if qb.limitCount > 0 {
+		plan.Steps = append(plan.Steps, fmt.Sprintf("take %d results", qb.limitCount))
+	}
+	plan.addLimitSteps(qb.limits)
+	return plan, nil
+}
+
+// Explain 返回在 node 上执行聚合的计划而不执行聚合，聚合参数错误以警告形式列出
+func (agg *Aggregator) Explain(node Node) (*ExecutionPlan, error) {
+	if node.Type() != 'a' {
+		return nil, fmt.Errorf("node must be an array for aggregation")
+	}
+
+	plan := newExecutionPlan(node)
+	if len(agg.groupBy) > 0 {
+		plan.Steps = append(plan.Steps, "group by "+strings.Join(agg.groupBy, ", "))
+		for _, field := range agg.groupBy {
+			plan.addField(node, field, "group_by")
+		}
+	}
+	for _, op := range agg.operations {
+		plan.Steps = append(plan.Steps, describeAggOperation(op))
+		if _, err := newAggAccumulator(op, agg.preserveInts, 0); err != nil {
+			plan.Warnings = append(plan.Warnings, err.Error())
+		}
+		for _, field := range []string{op.Field, op.Weight} {
+			if field != "" {
+				plan.addField(node, field, "aggregate")
+			}
+		}
+		if op.Condition != nil {
+			plan.addField(node, op.Condition.Field, "filter")
+		}
+	}
+	plan.addLimitSteps(agg.limits)
+	return plan, nil
+}
+
+// newExecutionPlan 创建计划并写入扫描步骤，缓存状态需在访问元素之前检查
+func newExecutionPlan(arr Node) *ExecutionPlan {
+	plan := &ExecutionPlan{CachedOffsets: arrOffsetsCached(arr), Elements: arr.Len()}
+	scan := fmt.Sprintf("scan %d elements", plan.Elements)
+	if plan.CachedOffsets {
+		scan += " using cached offsets"
+	} else {
+		scan += " (builds the offset cache)"
+	}
+	plan.Steps = append(plan.Steps, scan)
+	return plan
+}
+
+// addField 记录访问的字段并在采样元素中检查其是否存在，同一字段与用途只记录一次
+func (p *ExecutionPlan) addField(arr Node, path, usage string) {
+	for _, f := range p.Fields {
+		if f.Path == path && f.Usage == usage {
+			return
+		}
+	}
+
+	access := FieldAccess{Path: path, Usage: usage}
+	sample := min(arr.Len(), explainSampleSize)
+	for i := 0; i < sample && !access.Found; i++ {
+		access.Found = arr.Index(i).Get(path).Exists()
+	}
+	if !access.Found && sample > 0 {
+		access.Suggestion = closestPath(arr, sample, path)
+		msg := fmt.Sprintf("field %q not found in the first %d elements", path, sample)
+		if access.Suggestion != "" {
+			msg += fmt.Sprintf("; did you mean %q?", access.Suggestion)
+		}
+		p.Warnings = append(p.Warnings, msg)
+	}
+	p.Fields = append(p.Fields, access)
+}
+
+// addLimitSteps 写入结果规模上限检查步骤
+func (p *ExecutionPlan) addLimitSteps(limits ResultLimits) {
+	if limits.MaxResults > 0 {
+		p.Steps = append(p.Steps, fmt.Sprintf("fail if more than %d results match", limits.MaxResults))
+	}
+	if limits.MaxGroups > 0 {
+		p.Steps = append(p.Steps, fmt.Sprintf("fail if more than %d groups are created", limits.MaxGroups))
+	}
+	if limits.MaxMemoryBytes > 0 {
+		p.Steps = append(p.Steps, fmt.Sprintf("fail if estimated memory exceeds %d bytes", limits.MaxMemoryBytes))
+	}
+}
+
+// describeAggOperation 描述单个聚合操作
+func describeAggOperation(op AggOperation) string {
+	switch op.Type {
+	case "count":
+		return "count as " + op.Alias
+	case "histogram":
+		return fmt.Sprintf("histogram(%s, %d edges) as %s", op.Field, len(op.Edges), op.Alias)
+	case "weighted_avg":
+		return fmt.Sprintf("weighted_avg(%s, weight %s) as %s", op.Field, op.Weight, op.Alias)
+	case "top_n":
+		return fmt.Sprintf("top_n(%s, %d) as %s", op.Field, op.Limit, op.Alias)
+	case "sum_if", "count_if":
+		if op.Condition != nil {
+			c := op.Condition
+			return fmt.Sprintf("%s(%s where %s %s %v) as %s", op.Type, op.Field, c.Field, c.Operator, c.Value, op.Alias)
+		}
+	}
+	return fmt.Sprintf("%s(%s) as %s", op.Type, op.Field, op.Alias)
+}
+
+// arrOffsetsCached 数组的元素偏移量是否已缓存（不触发构建）
+func arrOffsetsCached(n Node) bool {
+	if n.typ != 'a' || n.start >= n.end {
+		return false
+	}
+	_, ok := arrIdxCache.Load(arrKey{data: dataPtr(n.getWorkingData()), s: n.start, e: n.end})
+	return ok
+}
+
+// closestPath 在前 sample 个元素的全部路径中查找与 path 编辑距离最小的路径，
+// 距离超过路径长度的三分之一（至少 1、最多 3）时视为无相近字段
+func closestPath(arr Node, sample int, path string) string {
+	maxDist := min(max(len(path)/3, 1), 3)
+	best, bestDist := "", maxDist+1
+	seen := make(map[string]bool)
+	for i := 0; i < sample; i++ {
+		for _, p := range arr.Index(i).Paths() {
+			if seen[p] {
+				continue
+			}
+			seen[p] = true
+			if d := editDistance(path, p); d < bestDist {
+				best, bestDist = p, d
+			}
+		}
+	}
+	return best
+}
+
+// editDistance 计算两个字符串按字节的编辑距离（相邻字符交换计为一次编辑）
+func editDistance(a, b string) int {
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(min(prev[j]+1, cur[j-1]+1), prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,90 @@
+package fxjson
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestQueryExplain 测试查询执行计划与字段拼写提示
+func TestQueryExplain(t *testing.T) {
+	arr := FromString(`[{"name":"a","age":30,"city":"bj"},{"name":"b","age":20,"city":"sh"}]`)
+
+	plan, err := arr.Query().Where("age", ">", 18).Where("naem", "=", "a").Where("city", "like", "b").
+		SortBy("age", "desc").Offset(1).Limit(5).WithLimits(ResultLimits{MaxResults: 10}).Explain()
+	if err != nil {
+		t.Fatalf("Explain error: %v", err)
+	}
+	if plan.Elements != 2 || plan.CachedOffsets {
+		t.Errorf("Elements = %d, CachedOffsets = %v", plan.Elements, plan.CachedOffsets)
+	}
+	wantSteps := []string{
+		"scan 2 elements (builds the offset cache)",
+		"filter age > 18",
+		"filter naem = a",
+		"filter city like b",
+		"sort by age desc",
+		"skip 1 results",
+		"take 5 results",
+		"fail if more than 10 results match",
+	}
+	if strings.Join(plan.Steps, "\n") != strings.Join(wantSteps, "\n") {
+		t.Errorf("Steps = %q", plan.Steps)
+	}
+
+	var naem *FieldAccess
+	for i := range plan.Fields {
+		if plan.Fields[i].Path == "naem" {
+			naem = &plan.Fields[i]
+		} else if !plan.Fields[i].Found {
+			t.Errorf("field %q should be found", plan.Fields[i].Path)
+		}
+	}
+	if naem == nil || naem.Found || naem.Suggestion != "name" {
+		t.Errorf("naem access = %+v", naem)
+	}
+	if len(plan.Warnings) != 2 || !strings.Contains(plan.String(), `did you mean "name"?`) {
+		t.Errorf("Warnings = %q", plan.Warnings)
+	}
+
+	// 查询执行后偏移量已缓存
+	if _, err := arr.Query().ToSlice(); err != nil {
+		t.Fatal(err)
+	}
+	if plan, _ := arr.Query().Explain(); !plan.CachedOffsets || !strings.Contains(plan.Steps[0], "cached") {
+		t.Errorf("CachedOffsets = %v, step %q", plan.CachedOffsets, plan.Steps[0])
+	}
+
+	if _, err := FromString(`{}`).Query().Explain(); err == nil {
+		t.Error("Explain on object should fail")
+	}
+}
+
+// TestAggregatorExplain 测试聚合执行计划
+func TestAggregatorExplain(t *testing.T) {
+	arr := FromString(`[{"dept":"x","salary":10,"w":1},{"dept":"y","salary":20,"w":2}]`)
+
+	agg := arr.Aggregate().GroupBy("dept").Count("n").Sum("salery", "total").
+		WeightedAvg("salary", "w", "wavg").Histogram("salary", []float64{1}, "h")
+	plan, err := agg.Explain(arr)
+	if err != nil {
+		t.Fatalf("Explain error: %v", err)
+	}
+	wantSteps := []string{
+		"scan 2 elements (builds the offset cache)",
+		"group by dept",
+		"count as n",
+		"sum(salery) as total",
+		"weighted_avg(salary, weight w) as wavg",
+		"histogram(salary, 1 edges) as h",
+	}
+	if strings.Join(plan.Steps, "\n") != strings.Join(wantSteps, "\n") {
+		t.Errorf("Steps = %q", plan.Steps)
+	}
+	if len(plan.Fields) != 4 {
+		t.Errorf("Fields = %+v", plan.Fields)
+	}
+	warnings := strings.Join(plan.Warnings, "\n")
+	if !strings.Contains(warnings, `did you mean "salary"?`) || !strings.Contains(warnings, "requires at least 2 edges") {
+		t.Errorf("Warnings = %q", plan.Warnings)
+	}
+}
@@ -1,7 +1,10 @@
 package fxjson
 
 import (
+	"context"
 	"fmt"
+	"math"
+	"math/big"
 	"reflect"
 	"sort"
 	"strconv"
@@ -22,32 +25,122 @@ type QueryBuilder struct {
 	sortFields []SortField
 	limitCount int
 	offsetVal  int
+
+	preserveInts bool            // 整数保真比较模式
+	ctx          context.Context // 非 nil 时执行过程中检查取消
+	limits       ResultLimits    // 结果规模上限
 }
 
 // Condition 查询条件
 type Condition struct {
 	Field    string      `json:"field"`
-	Operator string      `json:"operator"` // =, !=, >, <, >=, <=, in, not_in, contains
+	Operator string      `json:"operator"` // =, !=, >, <, >=, <=, in, not_in, contains, is_null, not_null
 	Value    interface{} `json:"value"`
 }
 
+// SubCond 构造 any/all 运算符使用的元素条件，Field 相对于数组元素（为空表示元素本身）：
+//
+//	node.Query().Where("reviews", "any", fxjson.SubCond("rating", ">=", 4))
+//
+// 多个元素条件需同时满足时，Value 传 []Condition
+func SubCond(field, operator string, value interface{}) Condition {
+	return Condition{Field: field, Operator: operator, Value: value}
+}
+
+// scalarOperators 比较单个值的查询运算符
+var scalarOperators = []string{"=", "!=", ">", "<", ">=", "<=", "in", "not_in", "contains", "is_null", "not_null"}
+
+// elementOperators any_/all_ 运算符后缀对应的标量运算符
+var elementOperators = map[string]string{
+	"eq": "=", "ne": "!=", "gt": ">", "lt": "<", "gte": ">=", "lte": "<=",
+	"in": "in", "not_in": "not_in", "contains": "contains",
+}
+
+// isQueryOperator 是否为支持的查询运算符
+func isQueryOperator(op string) bool {
+	if containsString(scalarOperators, op) || op == "any" || op == "all" {
+		return true
+	}
+	_, _, ok := splitElementOperator(op)
+	return ok
+}
+
+// splitElementOperator 拆分 any_eq、all_gt 形式的运算符，返回量词与标量运算符
+func splitElementOperator(op string) (quantifier, scalar string, ok bool) {
+	quantifier, suffix, found := strings.Cut(op, "_")
+	if !found || (quantifier != "any" && quantifier != "all") {
+		return "", "", false
+	}
+	scalar, ok = elementOperators[suffix]
+	return quantifier, scalar, ok
+}
+
+// subConditions 解析 any/all 的元素条件：Condition、[]Condition，
+// 或从 JSON 解码得到的 {"field","operator","value"} 对象及其数组
+func subConditions(value interface{}) ([]Condition, bool) {
+	switch v := value.(type) {
+	case Condition:
+		return []Condition{v}, true
+	case []Condition:
+		return v, len(v) > 0
+	case map[string]interface{}:
+		op, _ := v["operator"].(string)
+		field, _ := v["field"].(string)
+		return []Condition{{Field: field, Operator: op, Value: v["value"]}}, op != ""
+	case []interface{}:
+		conds := make([]Condition, 0, len(v))
+		for _, item := range v {
+			sub, ok := subConditions(item)
+			if !ok {
+				return nil, false
+			}
+			conds = append(conds, sub...)
+		}
+		return conds, len(conds) > 0
+	}
+	return nil, false
+}
+
 // SortField 排序字段
 type SortField struct {
 	Field string `json:"field"`
-	Order string `json:"order"` // asc, desc
+	Order string `json:"order"`           // asc, desc
+	Nulls string `json:"nulls,omitempty"` // first, last（默认）：null 与缺失值的位置，与排序方向无关
+
+	// 以下选项只作用于两个值都是字符串的比较，未设置时按字节比较（数字字符串按数值比较）
+	CaseInsensitive bool     `json:"case_insensitive,omitempty"` // 忽略大小写
+	Natural         bool     `json:"natural,omitempty"`          // 自然排序：数字片段按数值比较，如 "v2" < "v10"
+	Collator        Collator `json:"-"`                          // 区域相关的比较规则，设置后忽略上面两个选项
 }
 
 // Aggregator 聚合器
 type Aggregator struct {
 	operations []AggOperation
 	groupBy    []string
+	stream     map[string][]*aggAccumulator // Feed 增量聚合状态，按分组键保存
+
+	preserveInts bool            // 条件聚合按整数精确比较
+	ctx          context.Context // 非 nil 时执行过程中检查取消
+	limits       ResultLimits    // 分组数量与内存估算上限
+	streamBytes  int             // Feed 已创建分组的估算内存
 }
 
 // AggOperation 聚合操作
 type AggOperation struct {
-	Type  string `json:"type"`  // count, sum, avg, max, min
-	Field string `json:"field"` // 操作字段
-	Alias string `json:"alias"` // 结果别名
+	Type      string     `json:"type"`                // count, sum, avg, max, min, histogram, weighted_avg, sum_if, count_if, top_n
+	Field     string     `json:"field"`               // 操作字段
+	Alias     string     `json:"alias"`               // 结果别名
+	Edges     []float64  `json:"edges,omitempty"`     // 直方图分桶边界（升序）
+	Weight    string     `json:"weight,omitempty"`    // 加权平均的权重字段
+	Condition *Condition `json:"condition,omitempty"` // 条件聚合的过滤条件
+	Limit     int        `json:"limit,omitempty"`     // TopN 返回的元素数量
+}
+
+// HistogramBucket 直方图分桶，区间为 [Lower, Upper)，最后一个桶包含 Upper
+type HistogramBucket struct {
+	Lower float64 `json:"lower"`
+	Upper float64 `json:"upper"`
+	Count int     `json:"count"`
 }
 
 // ValidationRule 验证规则
@@ -61,8 +154,33 @@ type ValidationRule struct {
 	Pattern   string                        `json:"pattern"`
 	Default   interface{}                   `json:"default"`
 	Sanitize  func(interface{}) interface{} `json:"-"`
+
+	// HasMin/HasMax 显式启用 Min/Max，使 0 也能作为边界；非零的 Min/Max 总是生效。
+	// LoadValidator 在配置中出现 "min"/"max" 键时自动设置
+	HasMin       bool    `json:"-"`
+	HasMax       bool    `json:"-"`
+	ExclusiveMin bool    `json:"exclusive_min"` // 值必须大于 Min
+	ExclusiveMax bool    `json:"exclusive_max"` // 值必须小于 Max
+	MultipleOf   float64 `json:"multiple_of"`   // 大于 0 时值必须是其整数倍
+	Integer      bool    `json:"integer"`       // 只允许整数值（如 3 或 3.0）
+
+	MinItems    int             `json:"min_items"`    // 数组最少元素数
+	MaxItems    int             `json:"max_items"`    // 数组最多元素数，0 表示不限制
+	UniqueItems bool            `json:"unique_items"` // 数组元素不能重复（数字按数值、对象按规范化形式比较）
+	Items       *ValidationRule `json:"items"`        // 应用于每个数组元素的规则，可嵌套
+
+	// Enum 允许的取值，按 JSON 值比较（字符串按解码后内容、数字按数值、对象按规范化形式）
+	Enum []interface{} `json:"enum"`
+	// Const 值必须等于该值，nil 表示不限制（要求值为 null 时使用 Enum: []interface{}{nil}）
+	Const interface{} `json:"const"`
 }
 
+// hasMin Min 是否生效
+func (r ValidationRule) hasMin() bool { return r.HasMin || r.Min != 0 }
+
+// hasMax Max 是否生效
+func (r ValidationRule) hasMax() bool { return r.HasMax || r.Max != 0 }
+
 // DataValidator 数据验证器
 type DataValidator struct {
 	Rules map[string]ValidationRule `json:"rules"`
@@ -113,6 +231,253 @@ func (n Node) Transform(mapper FieldMapper) (map[string]interface{}, error) {
 	return result, nil
 }
 
+// TransformToJSON 按映射规则变换数据并直接输出嵌套的 JSON 文档
+// 目标字段支持点分路径（如 "user.profile.name"），会自动创建中间对象；
+// 对象/数组类型的源值按原结构序列化，数字在未指定类型转换时保持原始精度
+func (n Node) TransformToJSON(mapper FieldMapper) ([]byte, error) {
+	plan := newTransformPlan(mapper)
+
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	if err := plan.apply(n, buf); err != nil {
+		return nil, err
+	}
+
+	result := make([]byte, len(buf.buf))
+	copy(result, buf.buf)
+	return result, nil
+}
+
+// TransformArray 对数组中的每个元素应用字段映射，一次遍历输出新的 JSON 数组
+func (n Node) TransformArray(mapper FieldMapper) ([]byte, error) {
+	if !n.IsArray() {
+		return nil, fmt.Errorf("node must be an array for TransformArray, got %s", n.Kind())
+	}
+
+	plan := newTransformPlan(mapper)
+
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	buf.WriteByte('[')
+	var applyErr error
+	n.ArrayForEach(func(index int, item Node) bool {
+		if index > 0 {
+			buf.WriteByte(',')
+		}
+		if err := plan.apply(item, buf); err != nil {
+			applyErr = fmt.Errorf("element %d: %w", index, err)
+			return false
+		}
+		return true
+	})
+	if applyErr != nil {
+		return nil, applyErr
+	}
+	buf.WriteByte(']')
+
+	result := make([]byte, len(buf.buf))
+	copy(result, buf.buf)
+	return result, nil
+}
+
+// Invert 返回反向映射（目标字段 -> 源字段），用于把变换结果还原为原始结构
+// 类型转换按字段重新归属到反向后的目标字段；默认值只属于正向输出，不会被保留
+// 多个源字段映射到同一目标、或源路径包含数组下标时无法反转，返回错误
+func (m FieldMapper) Invert() (FieldMapper, error) {
+	inverted := FieldMapper{
+		Rules:    make(map[string]string, len(m.Rules)),
+		TypeCast: make(map[string]string, len(m.TypeCast)),
+	}
+
+	for sourceField, targetField := range m.Rules {
+		if strings.IndexByte(sourceField, '[') >= 0 {
+			return FieldMapper{}, fmt.Errorf("cannot invert rule %q: array index paths are not valid targets", sourceField)
+		}
+		if existing, exists := inverted.Rules[targetField]; exists {
+			return FieldMapper{}, fmt.Errorf("cannot invert rules: %q and %q both map to %q", existing, sourceField, targetField)
+		}
+		inverted.Rules[targetField] = sourceField
+
+		if castType, exists := m.TypeCast[targetField]; exists {
+			inverted.TypeCast[sourceField] = castType
+		}
+	}
+
+	return inverted, nil
+}
+
+// transformPlan 预先排序的变换计划，批量变换时复用
+type transformPlan struct {
+	mapper       FieldMapper
+	defaultKeys  []string
+	sourceFields []string
+}
+
+// newTransformPlan 根据映射配置创建变换计划（排序保证输出稳定）
+func newTransformPlan(mapper FieldMapper) *transformPlan {
+	plan := &transformPlan{
+		mapper:       mapper,
+		defaultKeys:  make([]string, 0, len(mapper.DefaultValues)),
+		sourceFields: make([]string, 0, len(mapper.Rules)),
+	}
+	for key := range mapper.DefaultValues {
+		plan.defaultKeys = append(plan.defaultKeys, key)
+	}
+	sort.Strings(plan.defaultKeys)
+	for sourceField := range mapper.Rules {
+		plan.sourceFields = append(plan.sourceFields, sourceField)
+	}
+	sort.Strings(plan.sourceFields)
+	return plan
+}
+
+// apply 对单个节点执行变换并写入缓冲区
+func (p *transformPlan) apply(n Node, buf *Buffer) error {
+	tree := newPathTree()
+
+	// 应用默认值
+	for _, key := range p.defaultKeys {
+		if err := tree.insert(key, p.mapper.DefaultValues[key]); err != nil {
+			return err
+		}
+	}
+
+	// 应用字段映射规则
+	for _, sourceField := range p.sourceFields {
+		targetField := p.mapper.Rules[sourceField]
+		sourceNode := n.Get(sourceField)
+		if !sourceNode.Exists() {
+			continue
+		}
+
+		var value interface{} = sourceNode
+		if sourceNode.IsNumber() {
+			switch p.mapper.TypeCast[targetField] {
+			case "int":
+				if i, err := sourceNode.Int(); err == nil {
+					value = i
+				} else {
+					f, _ := sourceNode.Float()
+					value = int64(f)
+				}
+			case "float":
+				value, _ = sourceNode.Float()
+			}
+		}
+
+		if err := tree.insert(targetField, value); err != nil {
+			return err
+		}
+	}
+
+	return tree.writeTo(buf, DefaultSerializeOptions, 0)
+}
+
+// pathTree 按点分路径组装的输出树，用于构建嵌套 JSON 文档
+type pathTree struct {
+	keys     []string // 保持插入顺序
+	children map[string]*pathTree
+	leaf     bool
+	value    interface{}
+}
+
+// newPathTree 创建空的输出树
+func newPathTree() *pathTree {
+	return &pathTree{children: make(map[string]*pathTree)}
+}
+
+// insert 在点分路径处写入值，同一路径重复写入时覆盖旧值
+func (t *pathTree) insert(path string, value interface{}) error {
+	if path == "" {
+		return fmt.Errorf("empty target path")
+	}
+
+	current := t
+	for {
+		seg := path
+		rest := ""
+		if idx := strings.IndexByte(path, '.'); idx >= 0 {
+			seg, rest = path[:idx], path[idx+1:]
+		}
+		if seg == "" {
+			return fmt.Errorf("invalid target path: empty segment")
+		}
+		if current.leaf {
+			return fmt.Errorf("target path conflict at %q: parent is already a value", seg)
+		}
+
+		child, exists := current.children[seg]
+		if !exists {
+			child = newPathTree()
+			current.children[seg] = child
+			current.keys = append(current.keys, seg)
+		}
+
+		if rest == "" {
+			if len(child.keys) > 0 {
+				return fmt.Errorf("target path conflict at %q: already contains nested fields", seg)
+			}
+			child.leaf = true
+			child.value = value
+			return nil
+		}
+
+		current = child
+		path = rest
+	}
+}
+
+// writeTo 将输出树序列化到缓冲区
+func (t *pathTree) writeTo(buf *Buffer, opts SerializeOptions, depth int) error {
+	if t.leaf {
+		if raw, ok := t.value.(rawValue); ok {
+			buf.Write(raw)
+			return nil
+		}
+		if node, ok := t.value.(Node); ok {
+			return node.marshalNode(buf, opts, depth)
+		}
+		return marshalValue(buf, reflect.ValueOf(t.value), opts, depth)
+	}
+
+	buf.WriteByte('{')
+
+	hasIndent := opts.Indent != ""
+	if hasIndent && len(t.keys) > 0 {
+		depth++
+	}
+
+	for i, key := range t.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if hasIndent {
+			buf.WriteByte('\n')
+			writeIndent(buf, opts.Indent, depth)
+		}
+
+		writeString(buf, key, opts.EscapeHTML)
+		buf.WriteByte(':')
+		if hasIndent {
+			buf.WriteByte(' ')
+		}
+
+		if err := t.children[key].writeTo(buf, opts, depth); err != nil {
+			return err
+		}
+	}
+
+	if hasIndent && len(t.keys) > 0 {
+		buf.WriteByte('\n')
+		writeIndent(buf, opts.Indent, depth-1)
+	}
+
+	buf.WriteByte('}')
+	return nil
+}
+
 // Query 创建查询构建器
 func (n Node) Query() *QueryBuilder {
 	return &QueryBuilder{
@@ -125,6 +490,10 @@ func (n Node) Query() *QueryBuilder {
 }
 
 // Where 添加查询条件
+//
+// 字段缺失或为 null 时按三值逻辑处理：比较结果未知，除 is_null 外的运算符均不满足（包括 != 与 not_in）。
+// is_null 匹配缺失或为 null 的字段，not_null 匹配其余情况，两者忽略 value；
+// value 为 nil 时 = 等同于 is_null，!= 等同于 not_null
 func (qb *QueryBuilder) Where(field, operator string, value interface{}) *QueryBuilder {
 	qb.conditions = append(qb.conditions, Condition{
 		Field:    field,
@@ -149,7 +518,7 @@ func (qb *QueryBuilder) WhereContains(field, substring string) *QueryBuilder {
 	return qb.Where(field, "contains", substring)
 }
 
-// SortBy 添加排序
+// SortBy 添加排序，字段缺失或为 null 的元素排在最后
 func (qb *QueryBuilder) SortBy(field, order string) *QueryBuilder {
 	qb.sortFields = append(qb.sortFields, SortField{
 		Field: field,
@@ -158,6 +527,37 @@ func (qb *QueryBuilder) SortBy(field, order string) *QueryBuilder {
 	return qb
 }
 
+// SortWith 按完整的排序字段配置添加排序，可指定字符串比较规则：
+//
+//	qb.SortWith(fxjson.SortField{Field: "title", Order: "asc", Natural: true, CaseInsensitive: true})
+func (qb *QueryBuilder) SortWith(field SortField) *QueryBuilder {
+	qb.sortFields = append(qb.sortFields, field)
+	return qb
+}
+
+// SortByNulls 添加排序并指定 null 与缺失值的位置：nulls 为 "first" 时排在最前，否则排在最后
+func (qb *QueryBuilder) SortByNulls(field, order, nulls string) *QueryBuilder {
+	qb.sortFields = append(qb.sortFields, SortField{
+		Field: field,
+		Order: order,
+		Nulls: nulls,
+	})
+	return qb
+}
+
+// PreserveIntegers 启用整数保真比较：整数按精确值比较而不是统一转换为 float64，
+// 超过 2^53 的 ID 等大整数在过滤与排序中不会因精度丢失而相互混淆
+func (qb *QueryBuilder) PreserveIntegers() *QueryBuilder {
+	qb.preserveInts = true
+	return qb
+}
+
+// WithContext 设置查询的 ctx，ToSlice 等执行方法在遍历过程中检查取消并返回 ctx.Err()
+func (qb *QueryBuilder) WithContext(ctx context.Context) *QueryBuilder {
+	qb.ctx = ctx
+	return qb
+}
+
 // Limit 限制结果数量
 func (qb *QueryBuilder) Limit(count int) *QueryBuilder {
 	qb.limitCount = count
@@ -171,26 +571,46 @@ func (qb *QueryBuilder) Offset(offset int) *QueryBuilder {
 }
 
 // ToSlice 执行查询并返回结果
-func (qb *QueryBuilder) ToSlice() ([]Node, error) {
+func (qb *QueryBuilder) ToSlice() (out []Node, err error) {
+	if done := slowOpStart(); done != nil {
+		defer func() {
+			done(SlowOp{Op: OpQuery, Keys: qb.fields(), InputBytes: qb.node.end - qb.node.start, OutputBytes: nodesBytes(out), Err: err})
+		}()
+	}
+	defer recoverPanic(&err, "Query", qb.node)
+
 	if qb.node.Type() != 'a' {
 		return nil, fmt.Errorf("node is not an array")
 	}
 
 	var results []Node
+	resultBytes := 0
 
 	// 遍历数组元素
 	for i := 0; i < qb.node.Len(); i++ {
+		if err := checkCtx(qb.ctx, i); err != nil {
+			return nil, err
+		}
 		item := qb.node.Index(i)
 
 		// 检查是否满足所有条件
 		if qb.matchesConditions(item) {
 			results = append(results, item)
+			resultBytes += resultNodeSize + item.end - item.start
+			if err := qb.limits.checkResults(len(results), resultBytes); err != nil {
+				return nil, err
+			}
 		}
 	}
 
 	// 排序
 	if len(qb.sortFields) > 0 {
 		qb.sortResults(results)
+		if qb.ctx != nil {
+			if err := qb.ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	// 应用偏移和限制
@@ -210,6 +630,56 @@ func (qb *QueryBuilder) ToSlice() ([]Node, error) {
 	return results[start:end], nil
 }
 
+// fields 返回查询条件与排序涉及的字段（去重，保持顺序）
+func (qb *QueryBuilder) fields() []string {
+	var fields []string
+	for _, c := range qb.conditions {
+		if !containsString(fields, c.Field) {
+			fields = append(fields, c.Field)
+		}
+	}
+	for _, s := range qb.sortFields {
+		if !containsString(fields, s.Field) {
+			fields = append(fields, s.Field)
+		}
+	}
+	return fields
+}
+
+// ToJSON 执行查询并将结果序列化为 JSON 数组，元素直接复用原始字节
+func (qb *QueryBuilder) ToJSON() ([]byte, error) {
+	results, err := qb.ToSlice()
+	if err != nil {
+		return nil, err
+	}
+
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	buf.WriteByte('[')
+	for i, item := range results {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.Write(item.Raw())
+	}
+	buf.WriteByte(']')
+
+	result := make([]byte, len(buf.buf))
+	copy(result, buf.buf)
+	return result, nil
+}
+
+// ToNode 执行查询并以数组 Node 形式返回结果，可继续进行查询或聚合；
+// 元素中内容为 JSON 的字符串保持为字符串，不会再次展开
+func (qb *QueryBuilder) ToNode() (Node, error) {
+	data, err := qb.ToJSON()
+	if err != nil {
+		return Node{}, err
+	}
+	return parseUnexpanded(data)
+}
+
 // Count 计算匹配条件的数量
 func (qb *QueryBuilder) Count() (int, error) {
 	results, err := qb.ToSlice()
@@ -242,14 +712,83 @@ func (qb *QueryBuilder) matchesConditions(node Node) bool {
 	return true
 }
 
-// evaluateCondition 评估单个条件
+// evaluateCondition 评估单个条件，Field 为空表示节点本身
 func (qb *QueryBuilder) evaluateCondition(node Node, condition Condition) bool {
-	fieldNode := node.Get(condition.Field)
-	if !fieldNode.Exists() {
-		return condition.Operator == "!=" || condition.Operator == "not_in"
+	fieldNode := node
+	if condition.Field != "" {
+		fieldNode = node.Get(condition.Field)
+	}
+
+	switch condition.Operator {
+	case "any", "all":
+		subs, ok := subConditions(condition.Value)
+		if !ok {
+			return false
+		}
+		return qb.evaluateElements(fieldNode, condition.Operator, func(item Node) bool {
+			for _, sub := range subs {
+				if !qb.evaluateCondition(item, sub) {
+					return false
+				}
+			}
+			return true
+		})
+	}
+	if quantifier, op, ok := splitElementOperator(condition.Operator); ok {
+		return qb.evaluateElements(fieldNode, quantifier, func(item Node) bool {
+			// 对象、数组与 null 元素不参与标量比较
+			return item.IsScalar() && !item.IsNull() && qb.evaluateValue(item, op, condition.Value)
+		})
+	}
+
+	op := condition.Operator
+	if condition.Value == nil {
+		switch op {
+		case "=":
+			op = "is_null"
+		case "!=":
+			op = "not_null"
+		}
+	}
+	switch op {
+	case "is_null":
+		return isNullOrMissing(fieldNode)
+	case "not_null":
+		return !isNullOrMissing(fieldNode)
 	}
 
+	// 与 null 或缺失值的比较结果未知，视为不满足
+	if isNullOrMissing(fieldNode) || condition.Value == nil {
+		return false
+	}
+	return qb.evaluateValue(fieldNode, op, condition.Value)
+}
+
+// isNullOrMissing 节点是否缺失或为 null
+func isNullOrMissing(n Node) bool {
+	return !n.Exists() || n.typ == 'l'
+}
+
+// evaluateElements 对数组元素求值：any 要求至少一个元素满足，all 要求全部满足（空数组满足），字段不是数组时不满足
+func (qb *QueryBuilder) evaluateElements(field Node, quantifier string, match func(Node) bool) bool {
+	if field.typ != 'a' {
+		return false
+	}
+	result := quantifier == "all"
+	field.ArrayForEach(func(_ int, item Node) bool {
+		if match(item) == result {
+			return true
+		}
+		result = !result
+		return false
+	})
+	return result
+}
+
+// evaluateValue 按标量运算符比较已存在的字段值
+func (qb *QueryBuilder) evaluateValue(fieldNode Node, operator string, value interface{}) bool {
 	fieldValue := qb.getNodeValue(fieldNode)
+	condition := Condition{Operator: operator, Value: value}
 
 	switch condition.Operator {
 	case "=":
@@ -302,6 +841,13 @@ func (qb *QueryBuilder) getNodeValue(node Node) interface{} {
 			return val
 		}
 	case 'n':
+		if qb.preserveInts {
+			raw := node.Raw()
+			if val, err := parseIntFast(raw); err == nil {
+				return val
+			}
+			return numberLiteral(raw)
+		}
 		if val, err := node.Float(); err == nil {
 			return val
 		}
@@ -315,6 +861,12 @@ func (qb *QueryBuilder) getNodeValue(node Node) interface{} {
 
 // compareValues 比较两个值
 func (qb *QueryBuilder) compareValues(a, b interface{}) int {
+	if qb.preserveInts {
+		if cmp, ok := compareExactNumbers(a, b); ok {
+			return cmp
+		}
+	}
+
 	// 类型转换和比较逻辑
 	aVal := qb.normalizeValue(a)
 	bVal := qb.normalizeValue(b)
@@ -373,18 +925,84 @@ func (qb *QueryBuilder) normalizeValue(value interface{}) interface{} {
 	}
 }
 
-// sortResults 对结果进行排序
+// numberLiteral 整数保真模式下无法用 int64 表示的数字原文
+type numberLiteral string
+
+// exactNumberPrec 精确比较时 big.Float 使用的精度（位）
+const exactNumberPrec = 256
+
+// exactInt 将整数类值转换为 int64，字符串按整数字面量解析
+func exactInt(v interface{}) (int64, bool) {
+	switch n := v.(type) {
+	case int, int8, int16, int32, int64:
+		return reflect.ValueOf(n).Int(), true
+	case uint, uint8, uint16, uint32, uint64:
+		u := reflect.ValueOf(n).Uint()
+		return int64(u), u <= maxInt64U
+	case string:
+		i, err := strconv.ParseInt(n, 10, 64)
+		return i, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// exactFloat 将数值类值转换为高精度浮点数
+func exactFloat(v interface{}) (*big.Float, bool) {
+	f := new(big.Float).SetPrec(exactNumberPrec)
+	switch n := v.(type) {
+	case int, int8, int16, int32, int64:
+		return f.SetInt64(reflect.ValueOf(n).Int()), true
+	case uint, uint8, uint16, uint32, uint64:
+		return f.SetUint64(reflect.ValueOf(n).Uint()), true
+	case float32, float64:
+		x := reflect.ValueOf(n).Float()
+		if math.IsNaN(x) {
+			return nil, false
+		}
+		return f.SetFloat64(x), true
+	case numberLiteral:
+		_, ok := f.SetString(string(n))
+		return f, ok
+	case string:
+		_, ok := f.SetString(n)
+		return f, ok
+	default:
+		return nil, false
+	}
+}
+
+// compareExactNumbers 精确比较两个数值，任一方不是数值时返回 false
+func compareExactNumbers(a, b interface{}) (int, bool) {
+	if ai, ok := exactInt(a); ok {
+		if bi, ok := exactInt(b); ok {
+			switch {
+			case ai < bi:
+				return -1, true
+			case ai > bi:
+				return 1, true
+			}
+			return 0, true
+		}
+	}
+
+	af, ok := exactFloat(a)
+	if !ok {
+		return 0, false
+	}
+	bf, ok := exactFloat(b)
+	if !ok {
+		return 0, false
+	}
+	return af.Cmp(bf), true
+}
+
+// sortResults 对结果进行排序；排序键相同的元素保持原有顺序
 func (qb *QueryBuilder) sortResults(results []Node) {
-	sort.Slice(results, func(i, j int) bool {
+	sort.SliceStable(results, func(i, j int) bool {
 		for _, sortField := range qb.sortFields {
-			iVal := qb.getNodeValue(results[i].Get(sortField.Field))
-			jVal := qb.getNodeValue(results[j].Get(sortField.Field))
-
-			cmp := qb.compareValues(iVal, jVal)
+			cmp := qb.compareSortField(sortField, results[i].Get(sortField.Field), results[j].Get(sortField.Field))
 			if cmp != 0 {
-				if sortField.Order == "desc" {
-					return cmp > 0
-				}
 				return cmp < 0
 			}
 		}
@@ -392,6 +1010,32 @@ func (qb *QueryBuilder) sortResults(results []Node) {
 	})
 }
 
+// compareSortField 按排序字段比较两个字段值，结果已考虑排序方向与 null 的位置
+func (qb *QueryBuilder) compareSortField(sortField SortField, iNode, jNode Node) int {
+	// null 与缺失值的位置不受排序方向影响
+	iNull, jNull := isNullOrMissing(iNode), isNullOrMissing(jNode)
+	if iNull || jNull {
+		switch {
+		case iNull == jNull:
+			return 0
+		case iNull == (sortField.Nulls == "first"):
+			return -1
+		}
+		return 1
+	}
+
+	var cmp int
+	if sortField.hasCollation() && iNode.typ == 's' && jNode.typ == 's' {
+		cmp = sortField.compareStrings(iNode, jNode)
+	} else {
+		cmp = qb.compareValues(qb.getNodeValue(iNode), qb.getNodeValue(jNode))
+	}
+	if sortField.Order == "desc" {
+		return -cmp
+	}
+	return cmp
+}
+
 // Aggregate 创建聚合器
 func (n Node) Aggregate() *Aggregator {
 	return &Aggregator{
@@ -449,6 +1093,74 @@ func (agg *Aggregator) Min(field, alias string) *Aggregator {
 	return agg
 }
 
+// Histogram 直方图聚合，按 edges 划分的区间统计字段值的数量，超出范围的值不计入
+func (agg *Aggregator) Histogram(field string, edges []float64, alias string) *Aggregator {
+	agg.operations = append(agg.operations, AggOperation{
+		Type:  "histogram",
+		Field: field,
+		Alias: alias,
+		Edges: append([]float64(nil), edges...),
+	})
+	return agg
+}
+
+// WeightedAvg 加权平均聚合：sum(value*weight) / sum(weight)
+func (agg *Aggregator) WeightedAvg(valueField, weightField, alias string) *Aggregator {
+	agg.operations = append(agg.operations, AggOperation{
+		Type:   "weighted_avg",
+		Field:  valueField,
+		Alias:  alias,
+		Weight: weightField,
+	})
+	return agg
+}
+
+// SumIf 条件求和聚合，仅累加满足条件的元素
+func (agg *Aggregator) SumIf(field string, cond Condition, alias string) *Aggregator {
+	agg.operations = append(agg.operations, AggOperation{
+		Type:      "sum_if",
+		Field:     field,
+		Alias:     alias,
+		Condition: &cond,
+	})
+	return agg
+}
+
+// CountIf 条件计数聚合
+func (agg *Aggregator) CountIf(cond Condition, alias string) *Aggregator {
+	agg.operations = append(agg.operations, AggOperation{
+		Type:      "count_if",
+		Alias:     alias,
+		Condition: &cond,
+	})
+	return agg
+}
+
+// TopN 按数值字段取最大的 n 个元素（分组时每组各取 n 个），结果为按字段降序排列的 []Node；
+// 结果中的节点是元素的副本，不引用输入数据
+func (agg *Aggregator) TopN(field string, n int, alias string) *Aggregator {
+	agg.operations = append(agg.operations, AggOperation{
+		Type:  "top_n",
+		Field: field,
+		Alias: alias,
+		Limit: n,
+	})
+	return agg
+}
+
+// PreserveIntegers 条件聚合（SumIf / CountIf）按整数精确比较，语义同 QueryBuilder.PreserveIntegers；
+// 同时 GroupBy 以数字、布尔等非字符串值的原始 JSON 作为分组键，默认这些值都归入 "null" 分组
+func (agg *Aggregator) PreserveIntegers() *Aggregator {
+	agg.preserveInts = true
+	return agg
+}
+
+// WithContext 设置聚合的 ctx，Execute 等执行方法在遍历与分组计算过程中检查取消并返回 ctx.Err()
+func (agg *Aggregator) WithContext(ctx context.Context) *Aggregator {
+	agg.ctx = ctx
+	return agg
+}
+
 // GroupBy 分组
 func (agg *Aggregator) GroupBy(fields ...string) *Aggregator {
 	agg.groupBy = append(agg.groupBy, fields...)
@@ -456,7 +1168,9 @@ func (agg *Aggregator) GroupBy(fields ...string) *Aggregator {
 }
 
 // Execute 执行聚合操作
-func (agg *Aggregator) Execute(node Node) (map[string]interface{}, error) {
+func (agg *Aggregator) Execute(node Node) (_ map[string]interface{}, err error) {
+	defer recoverPanic(&err, "Aggregate", node)
+
 	if node.Type() != 'a' {
 		return nil, fmt.Errorf("node must be an array for aggregation")
 	}
@@ -470,15 +1184,31 @@ func (agg *Aggregator) Execute(node Node) (map[string]interface{}, error) {
 
 	// 分组聚合
 	groups := make(map[string][]Node)
+	groupBytes := 0
 
 	for i := 0; i < node.Len(); i++ {
+		if err := checkCtx(agg.ctx, i); err != nil {
+			return nil, err
+		}
 		item := node.Index(i)
 		groupKey := agg.buildGroupKey(item)
+		if _, ok := groups[groupKey]; !ok {
+			groupBytes += len(groupKey) + groupEntrySize + len(agg.operations)*accumulatorSize
+		}
 		groups[groupKey] = append(groups[groupKey], item)
+		groupBytes += resultNodeSize
+		if err := agg.limits.checkGroups(len(groups), groupBytes); err != nil {
+			return nil, err
+		}
 	}
 
 	// 对每个分组执行聚合
 	for groupKey, groupItems := range groups {
+		if agg.ctx != nil {
+			if err := agg.ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
 		groupResult := make(map[string]interface{})
 
 		for _, op := range agg.operations {
@@ -495,17 +1225,53 @@ func (agg *Aggregator) Execute(node Node) (map[string]interface{}, error) {
 	return result, nil
 }
 
+// ExecuteJSON 执行聚合并使用内部序列化器输出 JSON（键按字典序排列）
+func (agg *Aggregator) ExecuteJSON(node Node) ([]byte, error) {
+	result, err := agg.Execute(node)
+	if err != nil {
+		return nil, err
+	}
+	return marshalAggResult(result)
+}
+
+// ExecuteNode 执行聚合并以 Node 形式返回结果，分组键等字符串不会被展开为嵌套 JSON
+func (agg *Aggregator) ExecuteNode(node Node) (Node, error) {
+	data, err := agg.ExecuteJSON(node)
+	if err != nil {
+		return Node{}, err
+	}
+	return parseUnexpanded(data)
+}
+
+// marshalAggResult 序列化聚合结果，排序键以保证输出稳定
+func marshalAggResult(result map[string]interface{}) ([]byte, error) {
+	opts := DefaultSerializeOptions
+	opts.SortKeys = true
+	return MarshalWithOptions(result, opts)
+}
+
 // executeSimpleAggregation 执行简单聚合（无分组）
 func (agg *Aggregator) executeSimpleAggregation(node Node) (map[string]interface{}, error) {
 	result := make(map[string]interface{})
 
 	// 转换为Node切片
+	if err := agg.limits.checkMemory(node.Len() * resultNodeSize); err != nil {
+		return nil, err
+	}
 	items := make([]Node, node.Len())
 	for i := 0; i < node.Len(); i++ {
+		if err := checkCtx(agg.ctx, i); err != nil {
+			return nil, err
+		}
 		items[i] = node.Index(i)
 	}
 
 	for _, op := range agg.operations {
+		if agg.ctx != nil {
+			if err := agg.ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
 		value, err := agg.executeOperation(op, items)
 		if err != nil {
 			return nil, err
@@ -521,7 +1287,10 @@ func (agg *Aggregator) buildGroupKey(item Node) string {
 	var keyParts []string
 	for _, field := range agg.groupBy {
 		value := item.Get(field)
-		if valueStr, err := value.String(); err == nil {
+		if agg.preserveInts {
+			// 数字使用原始字面量，避免大整数 ID 经 float64 转换后合并到同一分组
+			keyParts = append(keyParts, pivotKey(value))
+		} else if valueStr, err := value.String(); err == nil {
 			keyParts = append(keyParts, valueStr)
 		} else {
 			keyParts = append(keyParts, "null")
@@ -532,68 +1301,65 @@ func (agg *Aggregator) buildGroupKey(item Node) string {
 
 // executeOperation 执行单个聚合操作
 func (agg *Aggregator) executeOperation(op AggOperation, items []Node) (interface{}, error) {
-	switch op.Type {
-	case "count":
-		return len(items), nil
-
-	case "sum":
-		var sum float64
-		for _, item := range items {
-			if val, err := item.Get(op.Field).Float(); err == nil {
-				sum += val
-			}
-		}
-		return sum, nil
+	acc, err := newAggAccumulator(op, agg.preserveInts, len(items))
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		acc.add(item)
+	}
+	return acc.result(), nil
+}
 
-	case "avg":
-		var sum float64
-		var count int
-		for _, item := range items {
-			if val, err := item.Get(op.Field).Float(); err == nil {
-				sum += val
-				count++
-			}
-		}
-		if count == 0 {
-			return 0, nil
-		}
-		return sum / float64(count), nil
+// Pivot 透视聚合：按 rowField × colField 交叉分组，对 valueField 执行 aggType（count/sum/avg/max/min）
+// 返回 行值 -> 列值 -> 聚合结果 的嵌套映射，缺失的分组字段记为 "null"
+func (n Node) Pivot(rowField, colField, valueField, aggType string) (map[string]map[string]interface{}, error) {
+	if n.Type() != 'a' {
+		return nil, fmt.Errorf("node must be an array for pivot")
+	}
+	if aggType == "histogram" {
+		return nil, fmt.Errorf("unsupported pivot aggregation: %s", aggType)
+	}
 
-	case "max":
-		var max float64
-		var hasValue bool
-		for _, item := range items {
-			if val, err := item.Get(op.Field).Float(); err == nil {
-				if !hasValue || val > max {
-					max = val
-					hasValue = true
-				}
-			}
-		}
-		if !hasValue {
-			return nil, nil
+	cells := make(map[string]map[string][]Node)
+	n.ArrayForEach(func(_ int, item Node) bool {
+		row := pivotKey(item.Get(rowField))
+		col := pivotKey(item.Get(colField))
+		cols, ok := cells[row]
+		if !ok {
+			cols = make(map[string][]Node)
+			cells[row] = cols
 		}
-		return max, nil
+		cols[col] = append(cols[col], item)
+		return true
+	})
 
-	case "min":
-		var min float64
-		var hasValue bool
-		for _, item := range items {
-			if val, err := item.Get(op.Field).Float(); err == nil {
-				if !hasValue || val < min {
-					min = val
-					hasValue = true
-				}
+	agg := &Aggregator{}
+	op := AggOperation{Type: aggType, Field: valueField, Alias: aggType}
+	result := make(map[string]map[string]interface{}, len(cells))
+	for row, cols := range cells {
+		rowResult := make(map[string]interface{}, len(cols))
+		for col, items := range cols {
+			value, err := agg.executeOperation(op, items)
+			if err != nil {
+				return nil, err
 			}
+			rowResult[col] = value
 		}
-		if !hasValue {
-			return nil, nil
-		}
-		return min, nil
+		result[row] = rowResult
+	}
+	return result, nil
+}
 
-	default:
-		return nil, fmt.Errorf("unknown aggregation operation: %s", op.Type)
+// pivotKey 将分组字段值转换为键：字符串取其值，其他类型取原始 JSON
+func pivotKey(value Node) string {
+	if !value.Exists() {
+		return "null"
+	}
+	if str, err := value.String(); err == nil {
+		return str
 	}
+	return string(value.Raw())
 }
 
 // Validate 数据验证
@@ -640,6 +1406,12 @@ func (n Node) Validate(validator *DataValidator) (map[string]interface{}, []erro
 
 // validateAndConvertField 验证和转换字段值
 func validateAndConvertField(node Node, rule ValidationRule) (interface{}, error) {
+	if len(rule.Enum) > 0 || rule.Const != nil {
+		if err := checkEnumRule(node, rule); err != nil {
+			return nil, err
+		}
+	}
+
 	switch rule.Type {
 	case "string":
 		value, err := node.String()
@@ -655,6 +1427,16 @@ func validateAndConvertField(node Node, rule ValidationRule) (interface{}, error
 			return nil, fmt.Errorf("string too long, maximum length is %d", rule.MaxLength)
 		}
 
+		if rule.Pattern != "" {
+			re, err := compilePattern(rule.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern %q: %w", rule.Pattern, err)
+			}
+			if !re.MatchString(value) {
+				return nil, fmt.Errorf("string does not match pattern %q", rule.Pattern)
+			}
+		}
+
 		return value, nil
 
 	case "number":
@@ -663,12 +1445,8 @@ func validateAndConvertField(node Node, rule ValidationRule) (interface{}, error
 			return nil, err
 		}
 
-		if rule.Min != 0 && value < rule.Min {
-			return nil, fmt.Errorf("number too small, minimum is %f", rule.Min)
-		}
-
-		if rule.Max != 0 && value > rule.Max {
-			return nil, fmt.Errorf("number too large, maximum is %f", rule.Max)
+		if err := checkNumberRule(value, rule); err != nil {
+			return nil, err
 		}
 
 		return value, nil
@@ -676,6 +1454,12 @@ func validateAndConvertField(node Node, rule ValidationRule) (interface{}, error
 	case "boolean":
 		return node.Bool()
 
+	case "array":
+		if err := checkArrayRule(node, rule); err != nil {
+			return nil, err
+		}
+		return node.Raw(), nil
+
 	default:
 		// 原样返回
 		switch node.Type() {
@@ -691,6 +1475,131 @@ func validateAndConvertField(node Node, rule ValidationRule) (interface{}, error
 	}
 }
 
+// checkNumberRule 检查数值的范围、整数与倍数约束
+func checkNumberRule(value float64, rule ValidationRule) error {
+	if rule.hasMin() {
+		if rule.ExclusiveMin && value <= rule.Min {
+			return fmt.Errorf("number too small, must be greater than %v", rule.Min)
+		}
+		if value < rule.Min {
+			return fmt.Errorf("number too small, minimum is %f", rule.Min)
+		}
+	}
+
+	if rule.hasMax() {
+		if rule.ExclusiveMax && value >= rule.Max {
+			return fmt.Errorf("number too large, must be less than %v", rule.Max)
+		}
+		if value > rule.Max {
+			return fmt.Errorf("number too large, maximum is %f", rule.Max)
+		}
+	}
+
+	if rule.Integer && (math.IsInf(value, 0) || value != math.Trunc(value)) {
+		return fmt.Errorf("number %v is not an integer", value)
+	}
+
+	if rule.MultipleOf > 0 {
+		// 按商与最近整数的相对误差判断，避免 0.3/0.1 之类的浮点误差
+		q := value / rule.MultipleOf
+		if math.IsInf(q, 0) || math.Abs(q-math.Round(q)) > 1e-9*math.Max(1, math.Abs(q)) {
+			return fmt.Errorf("number %v is not a multiple of %v", value, rule.MultipleOf)
+		}
+	}
+	return nil
+}
+
+// checkArrayRule 检查数组的元素数量、唯一性，并对每个元素应用 Items 规则
+func checkArrayRule(node Node, rule ValidationRule) error {
+	if node.typ != 'a' {
+		return fmt.Errorf("expected array, got %s", node.Kind())
+	}
+
+	count := node.Len()
+	if count < rule.MinItems {
+		return fmt.Errorf("array too short, minimum items is %d", rule.MinItems)
+	}
+	if rule.MaxItems > 0 && count > rule.MaxItems {
+		return fmt.Errorf("array too long, maximum items is %d", rule.MaxItems)
+	}
+
+	var seen map[string]int
+	if rule.UniqueItems {
+		seen = make(map[string]int, count)
+	}
+	var err error
+	node.ArrayForEach(func(index int, item Node) bool {
+		if seen != nil {
+			key, ok := uniqueItemKey(item)
+			if prev, dup := seen[key]; ok && dup {
+				err = fmt.Errorf("item %d duplicates item %d", index, prev)
+				return false
+			}
+			seen[key] = index
+		}
+		if rule.Items != nil {
+			if _, itemErr := validateAndConvertField(item, *rule.Items); itemErr != nil {
+				err = fmt.Errorf("item %d: %w", index, itemErr)
+				return false
+			}
+		}
+		return true
+	})
+	return err
+}
+
+// checkEnumRule 检查值是否等于 Const 且属于 Enum
+func checkEnumRule(node Node, rule ValidationRule) error {
+	key, _ := uniqueItemKey(node)
+	if rule.Const != nil {
+		want, text, err := enumValueKey(rule.Const)
+		if err != nil {
+			return fmt.Errorf("invalid const: %w", err)
+		}
+		if key != want {
+			return fmt.Errorf("value %s does not equal const %s", node.Raw(), text)
+		}
+	}
+	if len(rule.Enum) == 0 {
+		return nil
+	}
+
+	allowed := make([]string, 0, len(rule.Enum))
+	for _, v := range rule.Enum {
+		want, text, err := enumValueKey(v)
+		if err != nil {
+			return fmt.Errorf("invalid enum value: %w", err)
+		}
+		if key == want {
+			return nil
+		}
+		allowed = append(allowed, string(text))
+	}
+	return fmt.Errorf("value %s is not one of [%s]", node.Raw(), strings.Join(allowed, ", "))
+}
+
+// enumValueKey 返回 Go 值按 JSON 编码后的比较键与文本
+func enumValueKey(v interface{}) (string, []byte, error) {
+	text, err := Marshal(v)
+	if err != nil {
+		return "", nil, err
+	}
+	key, ok := uniqueItemKey(parseRootNode(text))
+	if !ok {
+		return "", nil, fmt.Errorf("cannot compare %s", text)
+	}
+	return key, text, nil
+}
+
+// uniqueItemKey 数组元素的去重键，容器按规范化形式比较
+func uniqueItemKey(item Node) (string, bool) {
+	if item.IsContainer() {
+		c, err := item.Canonical()
+		return "c" + string(c), err == nil
+	}
+	return canonicalValueKey(item)
+}
+
 // Stream 流式处理
 func (n Node) Stream(processor func(Node, int) bool) error {
 	if n.Type() != 'a' {
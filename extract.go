@@ -0,0 +1,55 @@
+package fxjson
+
+import (
+	"fmt"
+	"sort"
+)
+
+// rawValue 原样写出的 JSON 值字节，用于 pathTree 叶子节点
+type rawValue []byte
+
+// Extract 按映射（目标路径 -> 源路径）从文档中抽取多个子树，组装为新的 JSON 对象
+//
+// 源路径语法同 GetPath，目标路径为点分路径，会自动创建中间对象。值的字节从原文档原样复制，
+// 不重新解析、不规范化数字与字符串（保留原始精度、转义、键顺序与内部空白），
+// 适合只做字段裁剪的网关转发；需要类型转换或默认值时使用 TransformToJSON。
+// 源路径不存在的映射被忽略，输出按目标路径排序
+func Extract(doc []byte, mapping map[string]string) ([]byte, error) {
+	if err := validateJSON(doc, DefaultParseOptions); err != nil {
+		return nil, err
+	}
+	root := parseRootNode(doc)
+	if !root.Exists() {
+		return nil, fmt.Errorf("invalid JSON data")
+	}
+
+	targets := make([]string, 0, len(mapping))
+	for target := range mapping {
+		targets = append(targets, target)
+	}
+	sort.Strings(targets)
+
+	tree := newPathTree()
+	for _, target := range targets {
+		source := mapping[target]
+		v := root
+		if source != "" {
+			v = root.GetPath(source)
+		}
+		if !v.Exists() {
+			continue
+		}
+		if err := tree.insert(target, rawValue(doc[v.start:v.end])); err != nil {
+			return nil, fmt.Errorf("target %q: %w", target, err)
+		}
+	}
+
+	buf := getBuffer()
+	defer putBuffer(buf)
+	if err := tree.writeTo(buf, DefaultSerializeOptions, 0); err != nil {
+		return nil, err
+	}
+	result := make([]byte, len(buf.buf))
+	copy(result, buf.buf)
+	return result, nil
+}
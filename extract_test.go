@@ -0,0 +1,44 @@
+package fxjson
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestExtract 测试按路径抽取原始子树
+func TestExtract(t *testing.T) {
+	doc := []byte(`{
+  "order": {"id": 12345678901234567890, "total": 1.50, "note": "café"},
+  "items": [{"sku": "A", "qty": 2}, {"sku": "B", "qty": 1}],
+  "customer": {"name": "Bob", "z": 1, "a": 2}
+}`)
+	got, err := Extract(doc, map[string]string{
+		"id":             "order.id",
+		"amount.total":   "order.total",
+		"amount.note":    "order.note",
+		"first":          "items[0]",
+		"customer":       "customer",
+		"missing.ignore": "order.nope",
+	})
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	want := `{"amount":{"note":"café","total":1.50},"customer":{"name": "Bob", "z": 1, "a": 2},"first":{"sku": "A", "qty": 2},"id":12345678901234567890}`
+	if string(got) != want {
+		t.Errorf("Extract =\n%s\nwant\n%s", got, want)
+	}
+	if !ValidateJSON(got) {
+		t.Errorf("Extract produced invalid JSON: %s", got)
+	}
+
+	if got, err := Extract(doc, nil); err != nil || string(got) != `{}` {
+		t.Errorf("empty mapping = %s, %v", got, err)
+	}
+	if _, err := Extract([]byte(`{"a":`), map[string]string{"a": "a"}); err == nil {
+		t.Error("expected error for invalid document")
+	}
+	_, err = Extract(doc, map[string]string{"a": "order.id", "a.b": "order.total"})
+	if err == nil || !strings.Contains(err.Error(), `target "a.b"`) {
+		t.Errorf("conflicting targets error = %v", err)
+	}
+}
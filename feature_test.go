@@ -2,6 +2,7 @@ package fxjson
 
 import (
 	"fmt"
+	"math"
 	"strings"
 	"testing"
 	"time"
@@ -207,6 +208,116 @@ func TestDataTransformation(t *testing.T) {
 	}
 }
 
+// TestTransformToJSON 测试变换结果直接输出为嵌套JSON
+func TestTransformToJSON(t *testing.T) {
+	node := FromBytes([]byte(testComplexJSON))
+
+	mapper := FieldMapper{
+		Rules: map[string]string{
+			"data.user_profile.user_id":  "user.id",
+			"data.user_profile.nickname": "user.profile.name",
+			"data.user_profile.level":    "user.profile.level",
+			"data.notes[0].tags":         "tags",
+			"data.notes[0].revenue":      "revenue",
+		},
+		DefaultValues: map[string]interface{}{
+			"status": "active",
+		},
+		TypeCast: map[string]string{
+			"user.profile.level": "float",
+		},
+	}
+
+	out, err := node.TransformToJSON(mapper)
+	if err != nil {
+		t.Fatalf("TransformToJSON failed: %v", err)
+	}
+
+	result := FromBytes(out)
+	if !result.Exists() {
+		t.Fatalf("TransformToJSON produced invalid JSON: %s", out)
+	}
+	if v := result.GetPath("user.profile.name").StringOr(""); v != "创作者小王" {
+		t.Errorf("nested target mismatch: got %q", v)
+	}
+	if v := result.GetPath("user.id").StringOr(""); v != "user_123" {
+		t.Errorf("user.id mismatch: got %q", v)
+	}
+	if v := result.Get("tags").Len(); v != 3 {
+		t.Errorf("array value should be preserved as array, got len %d (%s)", v, out)
+	}
+	if v, _ := result.Get("revenue").NumStr(); v != "156.80" {
+		t.Errorf("number should keep original form, got %q", v)
+	}
+	if v := result.Get("status").StringOr(""); v != "active" {
+		t.Errorf("default value mismatch: got %q", v)
+	}
+
+	// 目标路径冲突
+	conflict := FieldMapper{
+		Rules: map[string]string{
+			"data.user_profile.user_id":  "user",
+			"data.user_profile.nickname": "user.name",
+		},
+	}
+	if _, err := node.TransformToJSON(conflict); err == nil {
+		t.Error("expected error for conflicting target paths")
+	}
+}
+
+// TestTransformArrayAndInvert 测试数组批量变换与反向映射
+func TestTransformArrayAndInvert(t *testing.T) {
+	node := FromBytes([]byte(testComplexJSON))
+	notes := node.GetPath("data.notes")
+
+	mapper := FieldMapper{
+		Rules: map[string]string{
+			"id":         "note.id",
+			"view_count": "stats.views",
+		},
+		TypeCast: map[string]string{"stats.views": "int"},
+	}
+
+	out, err := notes.TransformArray(mapper)
+	if err != nil {
+		t.Fatalf("TransformArray failed: %v", err)
+	}
+	arr := FromBytes(out)
+	if arr.Len() != notes.Len() {
+		t.Fatalf("TransformArray length mismatch: got %d, want %d", arr.Len(), notes.Len())
+	}
+	if v := arr.Index(1).GetPath("note.id").StringOr(""); v != "note_002" {
+		t.Errorf("element 1 note.id mismatch: got %q", v)
+	}
+
+	// 反向映射还原原始字段
+	inverted, err := mapper.Invert()
+	if err != nil {
+		t.Fatalf("Invert failed: %v", err)
+	}
+	back, err := arr.Index(0).TransformToJSON(inverted)
+	if err != nil {
+		t.Fatalf("inverted transform failed: %v", err)
+	}
+	restored := FromBytes(back)
+	if v := restored.Get("view_count").IntOr(0); v != 12580 {
+		t.Errorf("restored view_count mismatch: got %d", v)
+	}
+	if inverted.TypeCast["view_count"] != "int" {
+		t.Errorf("type cast should follow inverted target, got %v", inverted.TypeCast)
+	}
+
+	// 非单射映射无法反转
+	ambiguous := FieldMapper{Rules: map[string]string{"a": "x", "b": "x"}}
+	if _, err := ambiguous.Invert(); err == nil {
+		t.Error("expected error when inverting non-injective rules")
+	}
+
+	if _, err := node.TransformArray(mapper); err == nil {
+		t.Error("expected error for non-array node")
+	}
+}
+
 // TestConditionalQueries 测试条件查询功能
 func TestConditionalQueries(t *testing.T) {
 	fmt.Println("\n🔍 测试条件查询功能")
@@ -353,6 +464,499 @@ func TestDataAggregation(t *testing.T) {
 	}
 }
 
+// TestHistogramAggregation 测试直方图聚合
+func TestHistogramAggregation(t *testing.T) {
+	notesList := FromBytes([]byte(testComplexJSON)).Get("data.notes")
+
+	stats, err := notesList.Aggregate().
+		Histogram("engagement_rate", []float64{0, 5, 7, 10}, "engagement_hist").
+		Execute(notesList)
+	if err != nil {
+		t.Fatalf("直方图聚合失败: %v", err)
+	}
+
+	buckets, ok := stats["engagement_hist"].([]HistogramBucket)
+	if !ok {
+		t.Fatalf("结果类型错误: %T", stats["engagement_hist"])
+	}
+	want := []HistogramBucket{{0, 5, 1}, {5, 7, 2}, {7, 10, 2}}
+	if len(buckets) != len(want) {
+		t.Fatalf("分桶数量错误: %+v", buckets)
+	}
+	for i := range want {
+		if buckets[i] != want[i] {
+			t.Errorf("分桶 %d: 期望 %+v, 实际 %+v", i, want[i], buckets[i])
+		}
+	}
+
+	// 分组后每组独立统计，上边界包含在最后一个桶
+	grouped, err := notesList.Aggregate().
+		GroupBy("status").
+		Histogram("engagement_rate", []float64{4.2, 8.9}, "hist").
+		Execute(notesList)
+	if err != nil {
+		t.Fatalf("分组直方图聚合失败: %v", err)
+	}
+	published := grouped["published"].(map[string]interface{})["hist"].([]HistogramBucket)
+	if published[0].Count != 4 {
+		t.Errorf("published 分桶计数错误: %+v", published)
+	}
+
+	if _, err := notesList.Aggregate().Histogram("engagement_rate", []float64{5, 1}, "bad").Execute(notesList); err == nil {
+		t.Error("非递增边界应返回错误")
+	}
+}
+
+// TestPivotAggregation 测试透视聚合
+func TestPivotAggregation(t *testing.T) {
+	notesList := FromBytes([]byte(testComplexJSON)).Get("data.notes")
+
+	pivot, err := notesList.Pivot("category", "status", "revenue", "sum")
+	if err != nil {
+		t.Fatalf("透视聚合失败: %v", err)
+	}
+	if len(pivot) != 5 {
+		t.Errorf("行数量错误: 期望 5, 实际 %d", len(pivot))
+	}
+	if v := pivot["food"]["published"]; v != 156.80 {
+		t.Errorf("food×published 期望 156.80, 实际 %v", v)
+	}
+	if v := pivot["fitness"]["draft"]; v != 67.20 {
+		t.Errorf("fitness×draft 期望 67.20, 实际 %v", v)
+	}
+	if _, ok := pivot["fitness"]["published"]; ok {
+		t.Error("不存在的组合不应出现")
+	}
+
+	counts, err := notesList.Pivot("status", "category", "", "count")
+	if err != nil {
+		t.Fatalf("计数透视失败: %v", err)
+	}
+	if len(counts["published"]) != 4 || counts["draft"]["fitness"] != 1 {
+		t.Errorf("计数透视结果错误: %v", counts)
+	}
+
+	out, err := MarshalWithOptions(pivot, PrettySerializeOptions)
+	if err != nil || !FromBytes(out).Get("travel").Get("published").Exists() {
+		t.Errorf("透视结果序列化失败: %v", err)
+	}
+
+	if _, err := notesList.Pivot("category", "status", "revenue", "median"); err == nil {
+		t.Error("未知聚合类型应返回错误")
+	}
+}
+
+// TestConditionalAggregation 测试加权平均与条件聚合
+func TestConditionalAggregation(t *testing.T) {
+	items := FromString(`[
+		{"status":"published","views":100,"rate":2,"revenue":10},
+		{"status":"published","views":300,"rate":4,"revenue":20},
+		{"status":"draft","views":600,"rate":1,"revenue":5}
+	]`)
+
+	stats, err := items.Aggregate().
+		WeightedAvg("rate", "views", "weighted_rate").
+		SumIf("revenue", Condition{Field: "status", Operator: "=", Value: "published"}, "published_revenue").
+		CountIf(Condition{Field: "views", Operator: ">", Value: 200}, "popular").
+		Execute(items)
+	if err != nil {
+		t.Fatalf("条件聚合失败: %v", err)
+	}
+
+	// (2*100 + 4*300 + 1*600) / 1000 = 2.0
+	if v := stats["weighted_rate"]; v != 2.0 {
+		t.Errorf("加权平均 期望 2.0, 实际 %v", v)
+	}
+	if v := stats["published_revenue"]; v != 30.0 {
+		t.Errorf("条件求和 期望 30, 实际 %v", v)
+	}
+	if v := stats["popular"]; v != 2 {
+		t.Errorf("条件计数 期望 2, 实际 %v", v)
+	}
+
+	grouped, err := items.Aggregate().
+		GroupBy("status").
+		CountIf(Condition{Field: "rate", Operator: ">=", Value: 2}, "high_rate").
+		Execute(items)
+	if err != nil {
+		t.Fatalf("分组条件聚合失败: %v", err)
+	}
+	if v := grouped["draft"].(map[string]interface{})["high_rate"]; v != 0 {
+		t.Errorf("draft 分组条件计数 期望 0, 实际 %v", v)
+	}
+}
+
+// TestTopNAggregation 测试分组 TopN 聚合
+func TestTopNAggregation(t *testing.T) {
+	items := FromString(`[
+		{"id":1,"category":"food","views":50},
+		{"id":2,"category":"food","views":300},
+		{"id":3,"category":"travel","views":90},
+		{"id":4,"category":"food","views":120},
+		{"id":5,"category":"food","views":300},
+		{"id":6,"category":"travel","views":10},
+		{"id":7,"category":"food"}
+	]`)
+
+	ids := func(nodes []Node) []int64 {
+		var out []int64
+		for _, n := range nodes {
+			out = append(out, n.Get("id").IntOr(0))
+		}
+		return out
+	}
+
+	grouped, err := items.Aggregate().
+		GroupBy("category").
+		TopN("views", 3, "top").
+		Execute(items)
+	if err != nil {
+		t.Fatalf("TopN 聚合失败: %v", err)
+	}
+
+	food := ids(grouped["food"].(map[string]interface{})["top"].([]Node))
+	if fmt.Sprint(food) != "[2 5 4]" {
+		t.Errorf("food TopN 期望 [2 5 4], 实际 %v", food)
+	}
+	travel := ids(grouped["travel"].(map[string]interface{})["top"].([]Node))
+	if fmt.Sprint(travel) != "[3 6]" {
+		t.Errorf("travel TopN 期望 [3 6], 实际 %v", travel)
+	}
+
+	global, err := items.Aggregate().TopN("views", 1, "best").Execute(items)
+	if err != nil {
+		t.Fatalf("全局 TopN 失败: %v", err)
+	}
+	if best := ids(global["best"].([]Node)); fmt.Sprint(best) != "[2]" {
+		t.Errorf("全局 TopN 期望 [2], 实际 %v", best)
+	}
+
+	// 极大的 N 不按 N 预分配，批量与增量聚合都按分组大小增长
+	huge, err := items.Aggregate().GroupBy("category").TopN("views", math.MaxInt, "top").Execute(items)
+	if err != nil {
+		t.Fatalf("极大 N 的 TopN 失败: %v", err)
+	}
+	if travel := ids(huge["travel"].(map[string]interface{})["top"].([]Node)); fmt.Sprint(travel) != "[3 6]" {
+		t.Errorf("极大 N 的 travel TopN 期望 [3 6], 实际 %v", travel)
+	}
+	stream := items.Aggregate().TopN("views", math.MaxInt, "top")
+	if err := stream.Feed(items); err != nil {
+		t.Fatalf("增量 TopN 失败: %v", err)
+	}
+	if res, err := stream.Result(); err != nil || len(res["top"].([]Node)) != 6 {
+		t.Errorf("增量 TopN 期望 6 个元素, 实际 %v, %v", res["top"], err)
+	}
+}
+
+// TestStreamingAggregation 测试增量聚合
+func TestStreamingAggregation(t *testing.T) {
+	lines := []string{
+		`{"category":"food","views":100}`,
+		`{"category":"travel","views":40}`,
+		`[{"category":"food","views":300},{"category":"food","views":20}]`,
+	}
+
+	agg := FromString(`[]`).Aggregate().
+		GroupBy("category").
+		Count("n").
+		Sum("views", "total").
+		Max("views", "max").
+		TopN("views", 2, "top")
+
+	for _, line := range lines {
+		if err := agg.Feed(FromString(line)); err != nil {
+			t.Fatalf("Feed 失败: %v", err)
+		}
+	}
+
+	result, err := agg.Result()
+	if err != nil {
+		t.Fatalf("Result 失败: %v", err)
+	}
+	food := result["food"].(map[string]interface{})
+	if food["n"] != 3 || food["total"] != 420.0 || food["max"] != 300.0 {
+		t.Errorf("food 结果错误: %v", food)
+	}
+	if top := food["top"].([]Node); len(top) != 2 || top[0].Get("views").IntOr(0) != 300 {
+		t.Errorf("food TopN 错误: %v", top)
+	}
+	if travel := result["travel"].(map[string]interface{}); travel["n"] != 1 {
+		t.Errorf("travel 结果错误: %v", travel)
+	}
+
+	// 与一次性 Execute 结果一致
+	all := FromString(`[{"category":"food","views":100},{"category":"travel","views":40},{"category":"food","views":300},{"category":"food","views":20}]`)
+	batch, err := all.Aggregate().GroupBy("category").Count("n").Sum("views", "total").Execute(all)
+	if err != nil {
+		t.Fatalf("Execute 失败: %v", err)
+	}
+	if batch["food"].(map[string]interface{})["total"] != food["total"] {
+		t.Errorf("增量与批量结果不一致")
+	}
+
+	agg.Reset()
+	empty, _ := FromString(`[]`).Aggregate().Count("n").Avg("views", "avg").Result()
+	if empty["n"] != 0 || empty["avg"] != 0 {
+		t.Errorf("空聚合结果错误: %v", empty)
+	}
+
+	// 复用同一行缓冲区输入时，TopN 保留的元素不受后续输入影响
+	reuse := FromString(`[]`).Aggregate().TopN("views", 1, "top")
+	buf := make([]byte, 0, 64)
+	for _, line := range []string{`{"id":"a","views":9}`, `{"id":"b","views":1}`, `{"id":"c","views":2}`} {
+		buf = append(buf[:0], line...)
+		if err := reuse.Feed(FromBytes(buf)); err != nil {
+			t.Fatalf("Feed 失败: %v", err)
+		}
+	}
+	reused, _ := reuse.Result()
+	if top := reused["top"].([]Node); len(top) != 1 || string(top[0].Raw()) != `{"id":"a","views":9}` {
+		t.Errorf("复用缓冲区后 TopN 错误: %v", top)
+	}
+
+	bad := FromString(`[]`).Aggregate().Histogram("views", []float64{1}, "h")
+	if err := bad.Feed(FromString(`{"views":1}`)); err == nil {
+		t.Error("无效的直方图边界应返回错误")
+	}
+}
+
+// TestQueryAndAggregateAsJSON 测试查询与聚合结果直接输出为 JSON / Node
+func TestQueryAndAggregateAsJSON(t *testing.T) {
+	items := FromString(`[
+		{"id":1,"category":"food","views":100},
+		{"id":2,"category":"travel","views":40},
+		{"id":3,"category":"food","views":300}
+	]`)
+
+	out, err := items.Query().Where("category", "=", "food").SortBy("views", "desc").ToJSON()
+	if err != nil {
+		t.Fatalf("ToJSON 失败: %v", err)
+	}
+	if string(out) != `[{"id":3,"category":"food","views":300},{"id":1,"category":"food","views":100}]` {
+		t.Errorf("ToJSON 结果错误: %s", out)
+	}
+
+	// 查询结果可继续聚合
+	foodNode, err := items.Query().Where("category", "=", "food").ToNode()
+	if err != nil {
+		t.Fatalf("ToNode 失败: %v", err)
+	}
+	stats, err := foodNode.Aggregate().Count("n").Sum("views", "total").TopN("views", 1, "top").ExecuteJSON(foodNode)
+	if err != nil {
+		t.Fatalf("ExecuteJSON 失败: %v", err)
+	}
+	if string(stats) != `{"n":2,"top":[{"category":"food","id":3,"views":300}],"total":400}` {
+		t.Errorf("ExecuteJSON 结果错误: %s", stats)
+	}
+
+	grouped, err := items.Aggregate().GroupBy("category").Max("views", "max").ExecuteNode(items)
+	if err != nil {
+		t.Fatalf("ExecuteNode 失败: %v", err)
+	}
+	if v, _ := grouped.GetPath("travel.max").Int(); v != 40 {
+		t.Errorf("ExecuteNode travel.max 期望 40, 实际 %d", v)
+	}
+
+	// 内容为 JSON 的字符串在结果中保持为字符串
+	strs, err := ParseBytes([]byte(`[{"k":"[1]","meta":"{\"a\":1}"}]`), ParseOptions{LazyExpansion: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	strNode, err := strs.Query().ToNode()
+	if err != nil {
+		t.Fatalf("ToNode 失败: %v", err)
+	}
+	if meta, err := strNode.Index(0).Get("meta").String(); err != nil || meta != `{"a":1}` {
+		t.Errorf("ToNode meta 期望字符串, 实际 %s (%v)", strNode.Index(0).Get("meta").Raw(), err)
+	}
+	strGroups, err := strs.Aggregate().GroupBy("meta").Count("n").ExecuteNode(strs)
+	if err != nil {
+		t.Fatalf("ExecuteNode 失败: %v", err)
+	}
+	if raw := string(strGroups.Raw()); raw != `{"{\"a\":1}":{"n":1}}` {
+		t.Errorf("ExecuteNode 分组键错误: %s", raw)
+	}
+
+	agg := items.Aggregate().Count("n")
+	_ = agg.Feed(items)
+	if res, _ := agg.ResultJSON(); string(res) != `{"n":3}` {
+		t.Errorf("ResultJSON 结果错误: %s", res)
+	}
+}
+
+// TestQueryPreserveIntegers 测试超过 2^53 的整数在查询与聚合中保持精确
+func TestQueryPreserveIntegers(t *testing.T) {
+	data := []byte(`[
+		{"id": 9007199254740993, "owner": 12345678901234567891, "v": 1},
+		{"id": 9007199254740992, "owner": 12345678901234567890, "v": 2},
+		{"id": 9007199254740994, "owner": 12345678901234567891, "v": 4}
+	]`)
+	node := FromBytes(data)
+
+	// 默认按 float64 比较，两个相邻的大整数无法区分
+	loose, _ := node.Query().Where("id", "=", int64(9007199254740993)).Count()
+	if loose != 2 {
+		t.Errorf("float comparison should conflate adjacent ids, got %d", loose)
+	}
+
+	exact, err := node.Query().PreserveIntegers().Where("id", "=", int64(9007199254740993)).ToSlice()
+	if err != nil || len(exact) != 1 || exact[0].Get("v").IntOr(0) != 1 {
+		t.Errorf("exact equality got %d results, err %v", len(exact), err)
+	}
+
+	count, _ := node.Query().PreserveIntegers().Where("id", ">", "9007199254740992").Count()
+	if count != 2 {
+		t.Errorf("expected 2 ids above 2^53, got %d", count)
+	}
+
+	// 超出 int64 的整数按原文精确比较
+	count, _ = node.Query().PreserveIntegers().Where("owner", "=", uint64(12345678901234567891)).Count()
+	if count != 2 {
+		t.Errorf("expected 2 matches for uint64 owner, got %d", count)
+	}
+
+	sorted, _ := node.Query().PreserveIntegers().SortBy("id", "asc").ToSlice()
+	var order []int64
+	for _, item := range sorted {
+		order = append(order, item.Get("v").IntOr(0))
+	}
+	if len(order) != 3 || order[0] != 2 || order[1] != 1 || order[2] != 4 {
+		t.Errorf("unexpected sort order %v", order)
+	}
+
+	// 条件聚合与分组
+	result, err := node.Aggregate().PreserveIntegers().
+		CountIf(Condition{Field: "id", Operator: "=", Value: int64(9007199254740993)}, "hits").
+		Execute(node)
+	if err != nil || result["hits"] != 1 {
+		t.Errorf("CountIf got %v, err %v", result["hits"], err)
+	}
+
+	groups, err := node.Aggregate().PreserveIntegers().GroupBy("owner").Sum("v", "total").Execute(node)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	group, ok := groups["12345678901234567891"].(map[string]interface{})
+	if len(groups) != 2 || !ok || group["total"] != 5.0 {
+		t.Errorf("unexpected groups %v", groups)
+	}
+
+	// 默认模式下非字符串分组值仍归入 "null" 分组
+	groups, err = node.Aggregate().GroupBy("owner").Sum("v", "total").Execute(node)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if group, ok := groups["null"].(map[string]interface{}); len(groups) != 1 || !ok || group["total"] != 7.0 {
+		t.Errorf("unexpected default groups %v", groups)
+	}
+}
+
+// TestQueryArrayElements 测试 any/all 运算符匹配嵌套数组元素
+func TestQueryArrayElements(t *testing.T) {
+	node := FromBytes([]byte(`[
+		{"id": 1, "tags": ["admin", "ops"], "scores": [90, 85], "reviews": [{"rating": 5, "by": "a"}, {"rating": 2, "by": "b"}]},
+		{"id": 2, "tags": ["dev"], "scores": [70, 95], "reviews": [{"rating": 3, "by": "a"}]},
+		{"id": 3, "tags": [], "scores": [], "reviews": []},
+		{"id": 4, "tags": "admin", "scores": [{"v": 1}, null, 99]}
+	]`))
+
+	ids := func(qb *QueryBuilder) []int64 {
+		items, err := qb.ToSlice()
+		if err != nil {
+			t.Fatalf("ToSlice failed: %v", err)
+		}
+		var out []int64
+		for _, item := range items {
+			out = append(out, item.Get("id").IntOr(0))
+		}
+		return out
+	}
+
+	cases := []struct {
+		name string
+		qb   *QueryBuilder
+		want string
+	}{
+		{"any_eq", node.Query().Where("tags", "any_eq", "admin"), "[1]"},
+		{"any_in", node.Query().Where("tags", "any_in", []interface{}{"dev", "ops"}), "[1 2]"},
+		{"any_contains", node.Query().Where("tags", "any_contains", "dm"), "[1]"},
+		{"any_gte skips non-scalars", node.Query().Where("scores", "any_gte", 95), "[2 4]"},
+		{"all_gt", node.Query().Where("scores", "all_gt", 80), "[1 3]"},
+		{"all_ne", node.Query().Where("tags", "all_ne", "admin"), "[2 3]"},
+		{"any sub condition", node.Query().Where("reviews", "any", SubCond("rating", ">=", 4)), "[1]"},
+		{"all sub condition", node.Query().Where("reviews", "all", SubCond("rating", ">=", 3)), "[2 3]"},
+		{"multiple sub conditions", node.Query().Where("reviews", "any", []Condition{
+			SubCond("by", "=", "a"), SubCond("rating", "<", 4),
+		}), "[2]"},
+		{"element itself", node.Query().Where("scores", "any", SubCond("", "<", 75)), "[2]"},
+		{"decoded sub condition", node.Query().Where("reviews", "any",
+			map[string]interface{}{"field": "rating", "operator": "=", "value": 2.0}), "[1]"},
+		{"invalid sub condition", node.Query().Where("reviews", "any", "rating"), "[]"},
+	}
+	for _, c := range cases {
+		if got := fmt.Sprint(ids(c.qb)); got != c.want {
+			t.Errorf("%s: got %s, want %s", c.name, got, c.want)
+		}
+	}
+
+	plan, err := node.Query().Where("tags", "any_eq", "admin").Where("tags", "any_like", 1).Explain()
+	if err != nil || len(plan.Warnings) != 1 || !strings.Contains(plan.Warnings[0], `"any_like"`) {
+		t.Errorf("expected one unknown operator warning, got %v, %v", plan, err)
+	}
+}
+
+// TestQueryNullSemantics 测试 null 与缺失字段的条件与排序
+func TestQueryNullSemantics(t *testing.T) {
+	node := FromBytes([]byte(`[
+		{"id": 1, "score": 5},
+		{"id": 2, "score": null},
+		{"id": 3},
+		{"id": 4, "score": 2},
+		{"id": 5, "score": 5}
+	]`))
+
+	ids := func(qb *QueryBuilder) string {
+		items, err := qb.ToSlice()
+		if err != nil {
+			t.Fatalf("ToSlice failed: %v", err)
+		}
+		var out []int64
+		for _, item := range items {
+			out = append(out, item.Get("id").IntOr(0))
+		}
+		return fmt.Sprint(out)
+	}
+
+	cases := []struct {
+		name string
+		qb   *QueryBuilder
+		want string
+	}{
+		{"is_null", node.Query().Where("score", "is_null", nil), "[2 3]"},
+		{"not_null", node.Query().Where("score", "not_null", nil), "[1 4 5]"},
+		{"= nil", node.Query().Where("score", "=", nil), "[2 3]"},
+		{"!= nil", node.Query().Where("score", "!=", nil), "[1 4 5]"},
+		{"!= skips unknown", node.Query().Where("score", "!=", 5), "[4]"},
+		{"not_in skips unknown", node.Query().WhereNotIn("score", []interface{}{2}), "[1 5]"},
+		{"= skips null", node.Query().Where("score", "=", 0), "[]"},
+		{"< nil", node.Query().Where("score", "<", nil), "[]"},
+		{"asc nulls last", node.Query().SortBy("score", "asc"), "[4 1 5 2 3]"},
+		{"desc nulls last", node.Query().SortBy("score", "desc"), "[1 5 4 2 3]"},
+		{"desc nulls first", node.Query().SortByNulls("score", "desc", "first"), "[2 3 1 5 4]"},
+		{"secondary key", node.Query().SortByNulls("score", "asc", "first").SortBy("id", "desc"), "[3 2 4 5 1]"},
+	}
+	for _, c := range cases {
+		if got := ids(c.qb); got != c.want {
+			t.Errorf("%s: got %s, want %s", c.name, got, c.want)
+		}
+	}
+
+	result, err := node.Aggregate().CountIf(Condition{Field: "score", Operator: "is_null"}, "unscored").Execute(node)
+	if err != nil || result["unscored"] != 2 {
+		t.Errorf("CountIf(is_null) = %v, %v", result["unscored"], err)
+	}
+}
+
 // TestCachePerformance 测试缓存性能功能
 func TestCachePerformance(t *testing.T) {
 	fmt.Println("\n⚡ 测试缓存性能功能")
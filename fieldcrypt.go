@@ -0,0 +1,110 @@
+package fxjson
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// encryptedValuePrefix 加密信封字符串的前缀，后接 base64(nonce || 密文)
+const encryptedValuePrefix = "fxenc:v1:"
+
+// valueEdit 一次值替换：原始数据中值的范围与替换后的字节
+type valueEdit struct {
+	path       string
+	start, end int
+	value      []byte
+}
+
+// EncryptFields 将 paths 处的值（任意类型，路径同 GetPath）加密为字符串信封 "fxenc:v1:<base64>"，
+// 用于持久化前保护个人信息。明文为值的原始 JSON 字节，路径作为附加认证数据，
+// 因此密文只能在原路径解密。只替换被加密的值，文档其余部分保持不变
+func EncryptFields(doc []byte, paths []string, aead cipher.AEAD) ([]byte, error) {
+	return spliceValues(doc, paths, func(path string, value Node) ([]byte, error) {
+		nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(value.Raw())+aead.Overhead())
+		if _, err := rand.Read(nonce); err != nil {
+			return nil, fmt.Errorf("generate nonce: %w", err)
+		}
+		sealed := aead.Seal(nonce, nonce, value.Raw(), []byte(path))
+		return quoteKey(encryptedValuePrefix + base64.StdEncoding.EncodeToString(sealed)), nil
+	})
+}
+
+// DecryptFields 将 paths 处由 EncryptFields 生成的信封还原为原始值
+// 值不是信封、密文被篡改或被移动到其他路径时返回错误
+func DecryptFields(doc []byte, paths []string, aead cipher.AEAD) ([]byte, error) {
+	return spliceValues(doc, paths, func(path string, value Node) ([]byte, error) {
+		s, err := value.String()
+		if err != nil || !strings.HasPrefix(s, encryptedValuePrefix) {
+			return nil, fmt.Errorf("path %q is not an encrypted value", path)
+		}
+		sealed, err := base64.StdEncoding.DecodeString(s[len(encryptedValuePrefix):])
+		if err != nil {
+			return nil, fmt.Errorf("path %q has invalid ciphertext encoding: %w", path, err)
+		}
+		if len(sealed) < aead.NonceSize() {
+			return nil, fmt.Errorf("path %q has truncated ciphertext", path)
+		}
+		nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+		plain, err := aead.Open(nil, nonce, ciphertext, []byte(path))
+		if err != nil {
+			return nil, fmt.Errorf("decrypt path %q: %w", path, err)
+		}
+		return plain, nil
+	})
+}
+
+// spliceValues 将 paths 处的值替换为 replace 的返回值，路径均相对于原文档解析且不得重叠
+func spliceValues(doc []byte, paths []string, replace func(path string, value Node) ([]byte, error)) ([]byte, error) {
+	root := parseRootNode(doc)
+	if !root.Exists() {
+		return nil, fmt.Errorf("invalid JSON data")
+	}
+
+	edits := make([]valueEdit, 0, len(paths))
+	for _, path := range paths {
+		if path == "" {
+			return nil, fmt.Errorf("empty path")
+		}
+		value := root.GetPath(path)
+		if !value.Exists() {
+			return nil, fmt.Errorf("path %q not found", path)
+		}
+		replacement, err := replace(path, value)
+		if err != nil {
+			return nil, err
+		}
+		edits = append(edits, valueEdit{path: path, start: value.start, end: value.end, value: replacement})
+	}
+	if len(edits) == 0 {
+		return append([]byte(nil), doc...), nil
+	}
+
+	sort.Slice(edits, func(i, j int) bool { return edits[i].start < edits[j].start })
+	for i := 1; i < len(edits); i++ {
+		if edits[i].start < edits[i-1].end {
+			return nil, fmt.Errorf("paths %q and %q overlap", edits[i-1].path, edits[i].path)
+		}
+	}
+
+	return applyValueEdits(doc, edits), nil
+}
+
+// applyValueEdits 按顺序应用已排序且互不重叠的替换，返回新文档
+func applyValueEdits(doc []byte, edits []valueEdit) []byte {
+	size := len(doc)
+	for _, e := range edits {
+		size += len(e.value) - (e.end - e.start)
+	}
+	result := make([]byte, 0, size)
+	last := 0
+	for _, e := range edits {
+		result = append(result, doc[last:e.start]...)
+		result = append(result, e.value...)
+		last = e.end
+	}
+	return append(result, doc[last:]...)
+}
@@ -0,0 +1,56 @@
+package fxjson
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"strings"
+	"testing"
+)
+
+// TestEncryptFields 测试字段级加密与解密的往返及篡改检测
+func TestEncryptFields(t *testing.T) {
+	block, _ := aes.NewCipher(make([]byte, 32))
+	aead, _ := cipher.NewGCM(block)
+
+	doc := []byte(`{
+  "id": 7,
+  "user": {"email": "a@b.c", "ssn": "123"},
+  "cards": [{"pan": 4111}, {"pan": 5500}]
+}`)
+	paths := []string{"user.email", "cards[1].pan", "user.ssn"}
+
+	enc, err := EncryptFields(doc, paths, aead)
+	if err != nil {
+		t.Fatalf("EncryptFields error: %v", err)
+	}
+	node := FromBytes(enc)
+	if strings.Contains(string(enc), "a@b.c") || strings.Contains(string(enc), "5500") {
+		t.Errorf("plaintext left in output: %s", enc)
+	}
+	if s, _ := node.GetPath("user.email").String(); !strings.HasPrefix(s, encryptedValuePrefix) {
+		t.Errorf("user.email = %q", s)
+	}
+	if v, _ := node.GetPath("cards[0].pan").Int(); v != 4111 || !strings.HasPrefix(string(enc), "{\n  \"id\": 7,") {
+		t.Errorf("untouched parts changed: %s", enc)
+	}
+
+	dec, err := DecryptFields(enc, paths, aead)
+	if err != nil || string(dec) != string(doc) {
+		t.Errorf("DecryptFields = %s, %v", dec, err)
+	}
+
+	// 密文移动到其他路径后无法解密
+	swapped, _ := RenameKeys(enc, map[string]string{"user.email": "mail"})
+	if _, err := DecryptFields(swapped, []string{"user.mail"}, aead); err == nil {
+		t.Error("decrypting a moved ciphertext should fail")
+	}
+	if _, err := DecryptFields(doc, []string{"id"}, aead); err == nil || !strings.Contains(err.Error(), "not an encrypted value") {
+		t.Errorf("plain value: err = %v", err)
+	}
+	if _, err := EncryptFields(doc, []string{"user", "user.ssn"}, aead); err == nil || !strings.Contains(err.Error(), "overlap") {
+		t.Errorf("overlapping paths: err = %v", err)
+	}
+	if _, err := EncryptFields(doc, []string{"missing"}, aead); err == nil {
+		t.Error("missing path should fail")
+	}
+}
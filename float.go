@@ -0,0 +1,58 @@
+package fxjson
+
+import "strconv"
+
+// FloatMode 浮点数解析精度模式
+type FloatMode uint8
+
+const (
+	// FloatExact 结果与 strconv.ParseFloat 逐位一致（默认）。
+	// 尾数不超过 2^53 且十进制指数在 ±22 以内时一次乘除即可正确舍入，其余输入回退到 strconv（Eisel-Lemire）
+	FloatExact FloatMode = iota
+
+	// FloatFast 保留 19 位尾数并按 10 的幂逐级缩放，速度更快但部分输入会有若干 ULP 的误差
+	FloatFast
+)
+
+// exactPow10 可被 float64 精确表示的 10 的幂
+var exactPow10 = [...]float64{
+	1e0, 1e1, 1e2, 1e3, 1e4, 1e5, 1e6, 1e7, 1e8, 1e9, 1e10,
+	1e11, 1e12, 1e13, 1e14, 1e15, 1e16, 1e17, 1e18, 1e19, 1e20, 1e21, 1e22,
+}
+
+// composeFloat 由扫描得到的尾数与十进制指数构造浮点数
+// truncated 表示尾数超出 19 位后有非零数字被舍弃；data 为完整的数字字面量，供精确模式回退使用
+func composeFloat(data []byte, mant uint64, decExp int, neg, truncated bool, mode FloatMode) float64 {
+	if mode == FloatFast || (mant == 0 && !truncated) ||
+		(!truncated && mant <= 1<<53 && decExp >= -22 && decExp <= 22) {
+		f := float64(mant)
+		switch {
+		case mant == 0:
+		case mode == FloatFast:
+			if decExp != 0 {
+				f = scaleByPow10(f, decExp)
+			}
+		case decExp > 0:
+			f *= exactPow10[decExp]
+		case decExp < 0:
+			f /= exactPow10[-decExp]
+		}
+		if neg {
+			f = -f
+		}
+		return f
+	}
+
+	f, err := strconv.ParseFloat(bytesToString(data), 64)
+	if err != nil && !isRangeError(err) {
+		// 非法字面量（调用方未校验时）退回近似结果
+		return composeFloat(data, mant, decExp, neg, truncated, FloatFast)
+	}
+	return f
+}
+
+// isRangeError 是否为 strconv 的溢出错误（此时结果为 ±Inf 或 0，与快速模式一致）
+func isRangeError(err error) bool {
+	ne, ok := err.(*strconv.NumError)
+	return ok && ne.Err == strconv.ErrRange
+}
@@ -0,0 +1,74 @@
+package fxjson
+
+import (
+	"math"
+	"strconv"
+	"testing"
+)
+
+// TestFloatExactParity 测试默认精确模式与 strconv.ParseFloat 逐位一致
+func TestFloatExactParity(t *testing.T) {
+	inputs := []string{
+		"0", "-0", "0.0", "1", "-1", "0.1", "0.3", "1.5e-5", "123456.789",
+		"9007199254740993",
+		"9007199254740992.5",
+		"2.2250738585072014e-308",
+		"2.2250738585072011e-308",
+		"4.9406564584124654e-324",
+		"1.7976931348623157e308",
+		"1e23",
+		"8.41e21",
+		"3.0000000000000001",
+		"0.30000000000000004",
+		"123456789012345678901234567890",
+		"1.00000000000000011102230246251565404236316680908203125",
+		"7.038531e-26",
+		"1e400", "-1e400", "1e-400",
+		"0e500",
+		"0.00000000000000000000000123",
+	}
+	for _, in := range inputs {
+		want, _ := strconv.ParseFloat(in, 64)
+		got, err := FromString(in).Float()
+		if err != nil {
+			t.Errorf("Float(%s) error: %v", in, err)
+			continue
+		}
+		if math.Float64bits(got) != math.Float64bits(want) {
+			t.Errorf("Float(%s) = %v (%#x), want %v (%#x)", in, got, math.Float64bits(got), want, math.Float64bits(want))
+		}
+
+		var v struct{ F float64 }
+		if err := FromString(`{"F":` + in + `}`).Decode(&v); err != nil {
+			t.Errorf("Decode(%s) error: %v", in, err)
+		} else if math.Float64bits(v.F) != math.Float64bits(want) {
+			t.Errorf("Decode(%s) = %v, want %v", in, v.F, want)
+		}
+	}
+}
+
+// TestFloatFastMode 测试按调用选择快速模式
+func TestFloatFastMode(t *testing.T) {
+	node := FromString("1.5")
+	if f, err := node.FloatWith(FloatFast); err != nil || f != 1.5 {
+		t.Errorf("FloatWith(FloatFast) = %v, %v", f, err)
+	}
+
+	// 截断 19 位尾数的近似结果与精确结果不同
+	const in = "1.00000000000000011102230246251565404236316680908203125"
+	exact, _ := FromString(in).FloatWith(FloatExact)
+	fast, _ := FromString(in).FloatWith(FloatFast)
+	if want, _ := strconv.ParseFloat(in, 64); exact != want {
+		t.Errorf("FloatWith(FloatExact) = %v, want %v", exact, want)
+	}
+	if fast == exact {
+		t.Errorf("FloatWith(FloatFast) = %v, expected the truncated approximation", fast)
+	}
+
+	var v struct{ F float64 }
+	opts := DefaultDecodeOptions
+	opts.FloatMode = FloatFast
+	if err := FromString(`{"F":`+in+`}`).DecodeWithOptions(&v, opts); err != nil || v.F != fast {
+		t.Errorf("DecodeWithOptions(FloatFast) = %v, %v; want %v", v.F, err, fast)
+	}
+}
@@ -1,12 +1,15 @@
 package fxjson
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"math"
 	"reflect"
+	"strconv"
 	"strings"
 	"sync"
-	"unsafe"
+	"unicode/utf8"
 )
 
 const (
@@ -20,8 +23,9 @@ type Node struct {
 	raw      []byte
 	start    int
 	end      int
-	typ      byte   // 'o' 'a' 's' 'n' 'b' 'l'
-	expanded []byte // 存储展开后的JSON数据
+	typ      byte           // 'o' 'a' 's' 'n' 'b' 'l'
+	expanded []byte         // 存储展开后的JSON数据
+	lazy     *lazyExpansion // 延迟展开时各副本共享的展开缓存
 }
 
 // JsonParam 用于控制 JSON 输出的格式化参数
@@ -38,6 +42,31 @@ type ParseOptions struct {
 	MaxObjectKeys int  // 最大对象键数量，0 表示无限制
 	MaxArrayItems int  // 最大数组项数量，0 表示无限制
 	StrictMode    bool // 严格模式：拒绝格式错误的 JSON
+
+	MaxExpansionDepth int // 嵌套 JSON 字符串的最大展开层数，0 表示无限制
+	MaxExpandedBytes  int // 展开过程中解转义的嵌套 JSON 累计字节数上限，0 表示无限制
+
+	LazyExpansion bool // 解析时不展开嵌套 JSON 字符串，改由 Expand/ExpandAll 按需展开并缓存
+
+	// LenientNumbers 接受嵌入式设备与 JavaScript 序列化器产生的非标准数字（0x1F、+5、.5、5.、Infinity、NaN），
+	// 解析前改写为标准 JSON：十六进制转为十进制整数，补全省略的整数位或小数位，Infinity 与 NaN 按 NonFinite 规则转换
+	LenientNumbers bool
+	NonFinite      NonFiniteRule // LenientNumbers 下 Infinity 与 NaN 的转换规则，默认转为 null
+
+	AllowSingleQuotes bool // 接受单引号字符串（'a "b"' 与 'it\'s'），解析前改写为双引号字符串
+	AllowUnquotedKeys bool // 接受未加引号的标识符键（{name: 1}），解析前补上引号
+
+	// 输入开头的 UTF-8 BOM 总是被跳过
+	TranscodeUTF16     bool // 将 UTF-16LE/BE 输入（按 BOM 或零字节位置识别）转码为 UTF-8 后解析
+	ReplaceInvalidUTF8 bool // 解析前将非法 UTF-8 序列替换为 U+FFFD
+
+	// ValidateUTF8 安全检查时拒绝字符串中的非法 UTF-8 序列；
+	// 同时开启 ReplaceInvalidUTF8 时非法序列先被替换为 U+FFFD，不再报错
+	ValidateUTF8 bool
+
+	// DetectBinary 解析前检查 NUL 字节与字符串外的控制字符，发现时返回 *BinaryDataError（含偏移量），
+	// 避免误投递的二进制负载产生难以理解的无效节点
+	DetectBinary bool
 }
 
 // DefaultParseOptions 默认解析选项
@@ -47,6 +76,9 @@ var DefaultParseOptions = ParseOptions{
 	MaxObjectKeys: 10000,       // 默认最大10000个键
 	MaxArrayItems: 100000,      // 默认最大100000个数组项
 	StrictMode:    false,       // 默认非严格模式
+
+	MaxExpansionDepth: 64,               // 默认最多展开64层嵌套JSON字符串
+	MaxExpandedBytes:  64 * 1024 * 1024, // 默认展开累计最多64MB
 }
 
 type NodeType byte
@@ -70,13 +102,6 @@ type arrKey struct {
 
 var arrIdxCache sync.Map // map[arrKey][]int
 
-func dataPtr(b []byte) uintptr {
-	if len(b) == 0 {
-		return 0
-	}
-	return uintptr(unsafe.Pointer(unsafe.SliceData(b)))
-}
-
 func buildArrOffsetsCached(n Node) []int {
 	if n.typ != 'a' || n.start >= n.end {
 		return nil
@@ -85,7 +110,9 @@ func buildArrOffsetsCached(n Node) []int {
 	// 使用展开后的数据
 	data := n.getWorkingData()
 	key := arrKey{data: dataPtr(data), s: n.start, e: n.end}
-	if v, ok := arrIdxCache.Load(key); ok {
+	v, ok := arrIdxCache.Load(key)
+	arrIdxCacheCounter.record(ok)
+	if ok {
 		return v.([]int)
 	}
 
@@ -234,6 +261,7 @@ func skipValueSimple(data []byte, pos int, end int) int {
 		return pos
 	}
 
+	start := pos
 	switch data[pos] {
 	case '{':
 		pos++
@@ -251,6 +279,9 @@ func skipValueSimple(data []byte, pos int, end int) int {
 				pos++
 			}
 		}
+		if depth > 0 {
+			return start // 未闭合
+		}
 		return pos
 	case '[':
 		pos++
@@ -268,9 +299,15 @@ func skipValueSimple(data []byte, pos int, end int) int {
 				pos++
 			}
 		}
+		if depth > 0 {
+			return start // 未闭合
+		}
 		return pos
 	case '"':
-		return skipStringSimple(data, pos, end)
+		if next, closed := skipStringClosed(data, pos, end); closed {
+			return next
+		}
+		return start // 未闭合，如 `"` 或 `"\"`
 	case 't':
 		if pos+4 <= end && string(data[pos:pos+4]) == "true" {
 			return pos + 4
@@ -299,6 +336,22 @@ func skipValueSimple(data []byte, pos int, end int) int {
 	}
 }
 
+// skipStringClosed 跳过字符串并报告是否遇到了结束引号
+func skipStringClosed(data []byte, pos int, end int) (int, bool) {
+	pos++ // 跳过开始引号
+	for pos < end {
+		switch data[pos] {
+		case '"':
+			return pos + 1, true
+		case '\\':
+			pos += 2
+		default:
+			pos++
+		}
+	}
+	return end, false
+}
+
 // skipStringSimple 简化的字符串跳过
 func skipStringSimple(data []byte, pos int, end int) int {
 	if pos >= end || data[pos] != '"' {
@@ -320,17 +373,89 @@ func skipStringSimple(data []byte, pos int, end int) int {
 
 // expandNestedJSON 迭代展开嵌套的JSON字符串，避免栈溢出
 func expandNestedJSON(data []byte) []byte {
+	expanded, _ := expandNestedJSONWithBudget(data, nil)
+	return expanded
+}
+
+// expandBudget 嵌套JSON展开预算，防止恶意构造的多层转义JSON放大内存
+type expandBudget struct {
+	maxDepth int
+	maxBytes int
+	depth    int
+	bytes    int
+	err      error
+	ctx      context.Context // 非 nil 时每次展开前检查取消
+}
+
+// newExpandBudget 根据解析选项创建展开预算，无限制时返回 nil
+func newExpandBudget(opts ParseOptions) *expandBudget {
+	if opts.MaxExpansionDepth <= 0 && opts.MaxExpandedBytes <= 0 {
+		return nil
+	}
+	return &expandBudget{maxDepth: opts.MaxExpansionDepth, maxBytes: opts.MaxExpandedBytes}
+}
+
+// enter 进入一层嵌套JSON字符串展开，超出预算时记录错误并返回 false
+func (b *expandBudget) enter(size int) bool {
+	if b == nil {
+		return true
+	}
+	if b.err != nil {
+		return false
+	}
+	if b.ctx != nil {
+		if err := b.ctx.Err(); err != nil {
+			b.err = err
+			return false
+		}
+	}
+	if b.maxDepth > 0 && b.depth+1 > b.maxDepth {
+		b.err = &FxJSONError{
+			Type:    ErrorTypeDepthLimit,
+			Message: fmt.Sprintf("nested JSON expansion depth exceeds limit %d", b.maxDepth),
+		}
+		return false
+	}
+	if b.maxBytes > 0 && b.bytes+size > b.maxBytes {
+		b.err = &FxJSONError{
+			Type:    ErrorTypeMemoryLimit,
+			Message: fmt.Sprintf("nested JSON expansion exceeds byte budget %d, requested: %d", b.maxBytes, b.bytes+size),
+		}
+		return false
+	}
+	b.depth++
+	b.bytes += size
+	return true
+}
+
+// leave 离开一层嵌套JSON字符串展开
+func (b *expandBudget) leave() {
+	if b != nil {
+		b.depth--
+	}
+}
+
+// exhausted 预算是否已耗尽
+func (b *expandBudget) exhausted() bool {
+	return b != nil && b.err != nil
+}
+
+// expandNestedJSONWithBudget 在预算内展开嵌套的JSON字符串，超出预算时中止并返回错误
+func expandNestedJSONWithBudget(data []byte, budget *expandBudget) ([]byte, error) {
 	node := parseRootNode(data)
 	if !node.Exists() {
-		return data
+		return data, nil
 	}
 
-	expanded, changed := expandNodeIterative(node)
+	expanded, changed := expandNodeIterative(node, budget)
+	if budget.exhausted() {
+		return data, budget.err
+	}
 	if !changed {
-		return data
+		return data, nil
 	}
 
-	return expanded
+	return expanded, nil
 }
 
 // expandNode 展开单个节点
@@ -367,7 +492,7 @@ type expandTask struct {
 }
 
 // expandNodeIterative 使用迭代方式展开节点，避免栈溢出
-func expandNodeIterative(rootNode Node) ([]byte, bool) {
+func expandNodeIterative(rootNode Node, budget *expandBudget) ([]byte, bool) {
 	// 使用栈来管理展开任务
 	stack := make([]expandTask, 0, 64) // 预分配容量避免频繁扩容
 
@@ -394,17 +519,17 @@ func expandNodeIterative(rootNode Node) ([]byte, bool) {
 
 			switch task.node.typ {
 			case 'o':
-				expandedObj, objChanged := expandObjectIterative(task.node, data)
+				expandedObj, objChanged := expandObjectIterative(task.node, data, budget)
 				*task.result = expandedObj
 				*task.changed = objChanged
 
 			case 'a':
-				expandedArr, arrChanged := expandArrayIterative(task.node, data)
+				expandedArr, arrChanged := expandArrayIterative(task.node, data, budget)
 				*task.result = expandedArr
 				*task.changed = arrChanged
 
 			case 's':
-				expandedStr, strChanged := expandStringIterative(task.node, data)
+				expandedStr, strChanged := expandStringIterative(task.node, data, budget)
 				*task.result = expandedStr
 				*task.changed = strChanged
 
@@ -419,7 +544,7 @@ func expandNodeIterative(rootNode Node) ([]byte, bool) {
 }
 
 // expandStringIterative 迭代展开字符串，避免栈溢出
-func expandStringIterative(n Node, data []byte) ([]byte, bool) {
+func expandStringIterative(n Node, data []byte, budget *expandBudget) ([]byte, bool) {
 	if n.start+1 >= n.end {
 		return data[n.start:n.end], false
 	}
@@ -432,6 +557,11 @@ func expandStringIterative(n Node, data []byte) ([]byte, bool) {
 
 	// 检查是否为有效的JSON
 	if isValidJSON(unescaped) {
+		if !budget.enter(len(unescaped)) {
+			return data[n.start:n.end], false
+		}
+		defer budget.leave()
+
 		// 使用迭代方式展开嵌套的JSON，避免递归调用expandNestedJSON
 		nestedNode := parseRootNode([]byte(unescaped))
 		if !nestedNode.Exists() {
@@ -439,7 +569,7 @@ func expandStringIterative(n Node, data []byte) ([]byte, bool) {
 		}
 
 		// 直接调用迭代版本，避免递归
-		nestedExpanded, _ := expandNodeIterative(nestedNode)
+		nestedExpanded, _ := expandNodeIterative(nestedNode, budget)
 		return nestedExpanded, true
 	}
 
@@ -447,7 +577,7 @@ func expandStringIterative(n Node, data []byte) ([]byte, bool) {
 }
 
 // expandObjectIterative 迭代展开对象
-func expandObjectIterative(n Node, data []byte) ([]byte, bool) {
+func expandObjectIterative(n Node, data []byte, budget *expandBudget) ([]byte, bool) {
 	var result strings.Builder
 	result.WriteByte('{')
 
@@ -500,9 +630,16 @@ func expandObjectIterative(n Node, data []byte) ([]byte, bool) {
 
 		// 解析值
 		valueNode := parseValueAt(data, pos, n.end)
+		if valueNode.end <= pos {
+			// 格式错误的值：保留容器原样，避免回退到起点后无限递归
+			return data[n.start:n.end], false
+		}
 
 		// 使用迭代方式展开值
-		expandedValue, valueChanged := expandNodeIterative(valueNode)
+		expandedValue, valueChanged := expandNodeIterative(valueNode, budget)
+		if budget.exhausted() {
+			return data[n.start:n.end], false
+		}
 		result.Write(expandedValue)
 
 		if valueChanged {
@@ -525,7 +662,7 @@ func expandObjectIterative(n Node, data []byte) ([]byte, bool) {
 }
 
 // expandArrayIterative 迭代展开数组
-func expandArrayIterative(n Node, data []byte) ([]byte, bool) {
+func expandArrayIterative(n Node, data []byte, budget *expandBudget) ([]byte, bool) {
 	var result strings.Builder
 	result.WriteByte('[')
 
@@ -549,9 +686,16 @@ func expandArrayIterative(n Node, data []byte) ([]byte, bool) {
 
 		// 解析值
 		valueNode := parseValueAt(data, pos, n.end)
+		if valueNode.end <= pos {
+			// 格式错误的值：保留容器原样，避免回退到起点后无限递归
+			return data[n.start:n.end], false
+		}
 
 		// 使用迭代方式展开值
-		expandedValue, valueChanged := expandNodeIterative(valueNode)
+		expandedValue, valueChanged := expandNodeIterative(valueNode, budget)
+		if budget.exhausted() {
+			return data[n.start:n.end], false
+		}
 		result.Write(expandedValue)
 
 		if valueChanged {
@@ -627,6 +771,10 @@ func expandObject(n Node, data []byte) ([]byte, bool) {
 
 		// 解析值
 		valueNode := parseValueAt(data, pos, n.end)
+		if valueNode.end <= pos {
+			// 格式错误的值：保留容器原样，避免回退到起点后无限递归
+			return data[n.start:n.end], false
+		}
 		expandedValue, valueChanged := expandNode(valueNode)
 		result.Write(expandedValue)
 
@@ -674,6 +822,10 @@ func expandArray(n Node, data []byte) ([]byte, bool) {
 
 		// 解析值
 		valueNode := parseValueAt(data, pos, n.end)
+		if valueNode.end <= pos {
+			// 格式错误的值：保留容器原样，避免回退到起点后无限递归
+			return data[n.start:n.end], false
+		}
 		expandedValue, valueChanged := expandNode(valueNode)
 		result.Write(expandedValue)
 
@@ -720,16 +872,26 @@ func expandString(n Node, data []byte) ([]byte, bool) {
 
 // parseRootNode 解析根节点
 func parseRootNode(data []byte) Node {
-	if len(data) == 0 {
+	node, next := parseLeadingValue(data)
+	if !node.Exists() {
 		return Node{}
 	}
 
+	// 检查是否有多余的字符（除了空白）
+	if next < len(data) {
+		return Node{} // 有多余的非空白字符
+	}
+	return node
+}
+
+// parseLeadingValue 解析 data 开头的第一个 JSON 值（不展开），返回节点与其后首个非空白字符的位置
+func parseLeadingValue(data []byte) (Node, int) {
 	start, end := 0, len(data)
 	for start < end && data[start] <= ' ' {
 		start++
 	}
 	if start >= end {
-		return Node{}
+		return Node{}, end
 	}
 
 	var typ byte
@@ -748,7 +910,7 @@ func parseRootNode(data []byte) Node {
 		if data[start] == '-' || (data[start] >= '0' && data[start] <= '9') {
 			typ = 'n'
 		} else {
-			return Node{} // 无效的开始字符
+			return Node{}, start // 无效的开始字符
 		}
 	}
 
@@ -756,31 +918,26 @@ func parseRootNode(data []byte) Node {
 
 	// 验证JSON是否完整
 	if valueEnd == start {
-		return Node{} // skipValueFast没有前进，说明格式错误
+		return Node{}, start // skipValueFast没有前进，说明格式错误
 	}
 
 	// 对于对象和数组，需要特别检查是否真正完整
 	if typ == 'o' {
 		if valueEnd > end || (valueEnd > 0 && data[valueEnd-1] != '}') {
-			return Node{} // 对象不完整
+			return Node{}, start // 对象不完整
 		}
 	}
 	if typ == 'a' {
 		if valueEnd > end || (valueEnd > 0 && data[valueEnd-1] != ']') {
-			return Node{} // 数组不完整
+			return Node{}, start // 数组不完整
 		}
 	}
 
-	// 检查是否有多余的字符（除了空白）
 	pos := valueEnd
 	for pos < end && data[pos] <= ' ' {
 		pos++
 	}
-	if pos < end {
-		return Node{} // 有多余的非空白字符
-	}
-
-	return Node{raw: data, start: start, end: valueEnd, typ: typ}
+	return Node{raw: data, start: start, end: valueEnd, typ: typ}, pos
 }
 
 // ===== From / 基本访问 =====
@@ -802,32 +959,109 @@ func FromBytes(b []byte) Node {
 
 // FromBytesWithOptions 使用指定选项解析 JSON
 func FromBytesWithOptions(b []byte, opts ParseOptions) Node {
+	node, _ := ParseBytes(b, opts)
+	return node
+}
+
+// ParseBytes 使用指定选项解析 JSON，并返回安全检查或嵌套展开预算超限的错误
+func ParseBytes(b []byte, opts ParseOptions) (Node, error) {
+	return parseBytes(context.Background(), b, opts)
+}
+
+// parseUnexpanded 按默认选项解析 b 但不展开嵌套 JSON 字符串，
+// 用于需要原样保留字符串值的内部重解析（如合并、引用展开的中间结果）
+func parseUnexpanded(b []byte) (Node, error) {
+	opts := DefaultParseOptions
+	opts.LazyExpansion = true
+	return ParseBytes(b, opts)
+}
+
+// parseBytes ParseBytes 的实现，在安全检查后与每次嵌套展开前检查 ctx 是否已取消
+func parseBytes(ctx context.Context, b []byte, opts ParseOptions) (node Node, err error) {
+	if done := slowOpStart(); done != nil {
+		size := len(b)
+		defer func() { done(SlowOp{Op: OpParse, InputBytes: size, Err: err}) }()
+	}
+	defer recoverPanic(&err, "ParseBytes", Node{})
+
+	if b, err = decodeInputEncoding(b, opts); err != nil {
+		return Node{typ: byte(TypeInvalid)}, err
+	}
 	if len(b) == 0 {
-		return Node{}
+		return Node{}, &FxJSONError{Type: ErrorTypeInvalidJSON, Message: "empty JSON data"}
+	}
+	if opts.DetectBinary {
+		if err := detectBinaryData(b); err != nil {
+			return Node{typ: byte(TypeInvalid)}, err
+		}
+	}
+
+	if opts.LenientNumbers || opts.AllowSingleQuotes || opts.AllowUnquotedKeys {
+		if b, err = rewriteLenientJSON(b, opts); err != nil {
+			return Node{typ: byte(TypeInvalid)}, err
+		}
 	}
 
 	// 安全检查
 	if err := validateJSON(b, opts); err != nil {
-		return Node{typ: byte(TypeInvalid)}
+		return Node{typ: byte(TypeInvalid)}, err
+	}
+	if err := ctx.Err(); err != nil {
+		return Node{}, err
 	}
 
 	// 首先创建原始节点
 	originalNode := parseRootNode(b)
 	if !originalNode.Exists() {
-		return originalNode
+		return originalNode, &FxJSONError{Type: ErrorTypeInvalidJSON, Message: "invalid JSON data"}
+	}
+	if opts.LazyExpansion {
+		originalNode.lazy = newLazyExpansion(opts, 0)
+		return originalNode, nil
 	}
 
 	// 尝试展开嵌套的JSON
-	expanded := expandNestedJSON(b)
+	budget := newExpandBudget(opts)
+	if ctx.Done() != nil {
+		if budget == nil {
+			budget = &expandBudget{}
+		}
+		budget.ctx = ctx
+	}
+	expanded, err := expandNestedJSONWithBudget(b, budget)
+	if err != nil {
+		return Node{typ: byte(TypeInvalid)}, err
+	}
 
 	// 如果展开后有变化，重新解析
 	if len(expanded) != len(b) || string(expanded) != string(b) {
 		expandedNode := parseRootNode(expanded)
 		expandedNode.expanded = expanded
-		return expandedNode
+		return expandedNode, nil
+	}
+
+	return originalNode, nil
+}
+
+// ParseFirst 解析 b 开头的第一个 JSON 值，返回该节点与其后剩余的字节（已去除前导空白）
+// 可用于逐个读取拼接的多文档数据（如 "{}{}{}"）；rest 为空表示没有尾随数据，
+// 非空则可视为尾随垃圾或下一个文档。b 中只有空白时返回错误
+func ParseFirst(b []byte) (node Node, rest []byte, err error) {
+	defer recoverPanic(&err, "ParseFirst", Node{})
+
+	first, next := parseLeadingValue(b)
+	if !first.Exists() {
+		if next >= len(b) {
+			return Node{}, nil, &FxJSONError{Type: ErrorTypeInvalidJSON, Message: "empty JSON data"}
+		}
+		return Node{}, nil, &FxJSONError{Type: ErrorTypeInvalidJSON, Message: "invalid JSON value", Pos: next}
 	}
 
-	return originalNode
+	node, err = ParseBytes(b[first.start:first.end], DefaultParseOptions)
+	if err != nil {
+		return Node{}, nil, err
+	}
+	return node, b[next:], nil
 }
 
 // validateJSON 验证 JSON 数据的安全性
@@ -863,6 +1097,13 @@ func validateJSON(data []byte, opts ParseOptions) error {
 					return fmt.Errorf("string too long: %d > %d", stringLen, opts.MaxStringLen)
 				}
 				stringLen = 0
+			} else if c >= utf8.RuneSelf && opts.ValidateUTF8 {
+				r, size := utf8.DecodeRune(data[i:])
+				if r == utf8.RuneError && size == 1 {
+					return fmt.Errorf("invalid UTF-8 byte 0x%02x in string at position %d", c, i)
+				}
+				i += size - 1
+				stringLen += size
 			} else {
 				stringLen++
 			}
@@ -943,16 +1184,91 @@ func (n Node) Get(path string) Node {
 	if len(path) == 0 || len(data) == 0 {
 		return Node{}
 	}
-	keyData := unsafe.StringData(path)
-	keyLen := len(path)
-	pos := findObjectField(data, n.start+1, n.end, keyData, 0, keyLen)
+	pos := findObjectField(data, n.start+1, n.end, path)
 	if pos < 0 {
 		return Node{}
 	}
-	return parseValueAtWithData(data, pos, n.end, n.expanded)
+	return parseValueAtWithData(data, pos, n.end, n)
 }
 
+// GetPath 按路径取值，如 "a.b[0].c"；起点可以是数组（"[0].name"），负数下标从末尾计数（"items[-1]"）
 func (n Node) GetPath(path string) Node {
+	return n.getPathSep(path, '.')
+}
+
+// GetPathSep 使用自定义分隔符（如 '/'）解析路径，适用于键名中包含 '.' 的文档，数组下标仍使用 [n]
+func (n Node) GetPathSep(path string, sep byte) Node {
+	if sep == '[' || sep == ']' {
+		return Node{}
+	}
+	return n.getPathSep(path, sep)
+}
+
+// GetSegments 按字面键逐级查找，不解析 '.' 或 '[]'；当前节点为数组时，纯数字段作为下标
+func (n Node) GetSegments(segments ...string) Node {
+	return n.getSegments(parseSegmentIndex, segments)
+}
+
+// getSegments GetSegments 的实现，parseIndex 解析数组下标段，负数下标从末尾计数
+func (n Node) getSegments(parseIndex func(string) (int, bool), segments []string) Node {
+	if len(segments) == 0 {
+		return n
+	}
+
+	current := n
+	for _, seg := range segments {
+		switch current.typ {
+		case 'o':
+			data := current.getWorkingData()
+			pos := findObjectField(data, current.start+1, current.end, seg)
+			if pos < 0 {
+				return Node{}
+			}
+			current = parseValueAtWithData(data, pos, current.end, current)
+		case 'a':
+			idx, ok := parseIndex(seg)
+			if !ok {
+				return Node{}
+			}
+			if idx < 0 {
+				if idx += current.Len(); idx < 0 {
+					return Node{}
+				}
+			}
+			current = current.Index(idx)
+		default:
+			return Node{}
+		}
+		if !current.Exists() {
+			return Node{}
+		}
+	}
+	return current
+}
+
+// parseSegmentIndex 解析纯数字的数组下标段
+func parseSegmentIndex(seg string) (int, bool) {
+	if len(seg) == 0 {
+		return 0, false
+	}
+	idx := 0
+	for i := 0; i < len(seg); i++ {
+		c := seg[i]
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		if idx > (int(^uint(0)>>1)-int(c-'0'))/10 {
+			return 0, false
+		}
+		idx = idx*10 + int(c-'0')
+	}
+	return idx, true
+}
+
+// getPathSep 按指定分隔符解析路径
+// 按当前值的类型解析每一段：键段要求当前值为对象，[n] 要求为数组（负数下标从末尾计数），
+// 因此数组与标量也可以作为起点，如 "[0].name"、"[-1]"；格式错误的路径返回不存在的节点
+func (n Node) getPathSep(path string, sep byte) Node {
 	if len(n.raw) == 0 || len(path) == 0 {
 		return Node{}
 	}
@@ -963,52 +1279,37 @@ func (n Node) GetPath(path string) Node {
 	for pos < end && (data[pos] == ' ' || data[pos] == '\t' || data[pos] == '\n' || data[pos] == '\r') {
 		pos++
 	}
-	if pos < end && data[pos] == '{' {
-		pos++
-	}
 
-	pathData := unsafe.StringData(path)
 	pathLen := len(path)
 	pathPos := 0
 
 	for pathPos < pathLen {
 		segStart := pathPos
-		segLen := 0
-		for pathPos < pathLen {
-			c := *(*byte)(unsafe.Add(unsafe.Pointer(pathData), pathPos))
-			if c == '.' || c == '[' {
-				break
-			}
-			segLen++
+		for pathPos < pathLen && path[pathPos] != sep && path[pathPos] != '[' {
 			pathPos++
 		}
 
-		if segLen > 0 {
-			pos = findObjectField(data, pos, end, pathData, segStart, segLen)
+		if pathPos > segStart {
+			if pos >= end || data[pos] != '{' {
+				return Node{}
+			}
+			pos = findObjectField(data, pos+1, end, path[segStart:pathPos])
 			if pos < 0 {
 				return Node{}
 			}
 		}
 
-		for pathPos < pathLen && *(*byte)(unsafe.Add(unsafe.Pointer(pathData), pathPos)) == '[' {
-			pathPos++
-			idx := 0
-			for pathPos < pathLen {
-				c := *(*byte)(unsafe.Add(unsafe.Pointer(pathData), pathPos))
-				if c == ']' {
-					pathPos++
-					break
-				}
-				// 安全检查：确保字符是数字
-				if c < '0' || c > '9' {
-					return Node{} // 无效的数组索引格式
-				}
-				// 防止整数溢出
-				if idx > (int(^uint(0)>>1)-int(c-'0'))/10 {
-					return Node{} // 索引过大，防止溢出
+		for pathPos < pathLen && path[pathPos] == '[' {
+			idx, next, ok := parsePathIndex(path, pathPos)
+			if !ok || pos >= end || data[pos] != '[' {
+				return Node{}
+			}
+			pathPos = next
+			if idx < 0 {
+				idx += parseValueAtWithData(data, pos, end, n).Len()
+				if idx < 0 {
+					return Node{}
 				}
-				idx = idx*10 + int(c-'0')
-				pathPos++
 			}
 			pos = findArrayElement(data, pos, end, idx)
 			if pos < 0 {
@@ -1016,28 +1317,54 @@ func (n Node) GetPath(path string) Node {
 			}
 		}
 
-		if pathPos < pathLen && *(*byte)(unsafe.Add(unsafe.Pointer(pathData), pathPos)) == '.' {
-			pathPos++
-			if pos < end && data[pos] == '{' {
-				pos++
+		if pathPos < pathLen {
+			// 下标之后只能是分隔符，分隔符之后必须还有路径段
+			if path[pathPos] != sep || pathPos+1 == pathLen {
+				return Node{}
 			}
+			pathPos++
 		}
 	}
 
-	return parseValueAtWithData(data, pos, end, n.expanded)
+	return parseValueAtWithData(data, pos, end, n)
+}
+
+// parsePathIndex 解析 path[start:] 处的 [n] 或 [-n]，返回下标与 ']' 之后的位置
+func parsePathIndex(path string, start int) (idx, next int, ok bool) {
+	closing := strings.IndexByte(path[start:], ']')
+	if closing < 0 {
+		return 0, 0, false
+	}
+	idx, ok = parseSignedIndex(path[start+1 : start+closing])
+	return idx, start + closing + 1, ok
+}
+
+// parseSignedIndex 解析可带 '-' 前缀的数组下标段，负数表示从末尾计数，"-0" 无效
+func parseSignedIndex(seg string) (int, bool) {
+	digits, negative := strings.CutPrefix(seg, "-")
+	idx, ok := parseSegmentIndex(digits)
+	if !negative {
+		return idx, ok
+	}
+	if !ok || idx == 0 {
+		return 0, false
+	}
+	return -idx, true
 }
 
-// parseValueAtWithData 解析指定位置的值，保持expanded数据
-func parseValueAtWithData(data []byte, pos int, end int, expanded []byte) Node {
+// parseValueAtWithData 解析指定位置的值，保持父节点的expanded数据与延迟展开缓存
+func parseValueAtWithData(data []byte, pos int, end int, parent Node) Node {
 	node := parseValueAt(data, pos, end)
-	if len(expanded) > 0 {
-		node.expanded = expanded
+	if len(parent.expanded) > 0 {
+		node.expanded = parent.expanded
 	}
+	node.lazy = parent.lazy
 	return node
 }
 
 // ===== 对象/数组定位 =====
-func findObjectField(data []byte, start int, end int, keyData *byte, keyStart int, keyLen int) int {
+func findObjectField(data []byte, start int, end int, key string) int {
+	keyLen := len(key)
 	pos := start
 	for pos < end {
 		for pos < end && (data[pos] <= ' ') {
@@ -1051,63 +1378,38 @@ func findObjectField(data []byte, start int, end int, keyData *byte, keyStart in
 		}
 		pos++
 		fieldStart := pos
-		match := true
-		if pos+keyLen <= end && data[pos+keyLen] == '"' {
-			// 优化：使用更高效的字节比较
-			if keyLen > 0 {
-				fieldBytes := data[fieldStart : fieldStart+keyLen]
-				keyBytes := unsafe.Slice((*byte)(unsafe.Add(unsafe.Pointer(keyData), keyStart)), keyLen)
-
-				// 对于较长的键，使用8字节块比较
-				if keyLen >= 8 {
-					// 比较前8字节
-					fieldPtr := *(*uint64)(unsafe.Pointer(&fieldBytes[0]))
-					keyPtr := *(*uint64)(unsafe.Pointer(&keyBytes[0]))
-					if fieldPtr == keyPtr {
-						// 比较剩余字节
-						match = true
-						for i := 8; i < keyLen; i++ {
-							if fieldBytes[i] != keyBytes[i] {
-								match = false
-								break
-							}
-						}
-					} else {
-						match = false
-					}
-				} else {
-					// 短键使用逐字节比较
-					match = true
-					for i := 0; i < keyLen; i++ {
-						if fieldBytes[i] != keyBytes[i] {
-							match = false
-							break
-						}
-					}
-				}
-			}
-			if match {
-				pos += keyLen + 1
-				for pos < end && data[pos] <= ' ' {
+		match := false
+		// string(bytes) == key 由编译器优化为无分配的内存比较
+		if pos+keyLen < end && data[pos+keyLen] == '"' && string(data[fieldStart:fieldStart+keyLen]) == key &&
+			bytes.IndexByte(data[fieldStart:fieldStart+keyLen], '\\') < 0 {
+			pos += keyLen
+			match = true
+		} else {
+			hasEscape := false
+			for pos < end && data[pos] != '"' {
+				if data[pos] == '\\' {
+					hasEscape = true
 					pos++
 				}
-				if pos >= end || data[pos] != ':' {
-					return -1
-				}
 				pos++
-				for pos < end && data[pos] <= ' ' {
-					pos++
-				}
-				return pos
 			}
+			// 含转义的键按解转义后的形式比较
+			match = hasEscape && pos < end && keyEqualsEscaped(data[fieldStart:pos], key)
 		}
-		for pos < end && data[pos] != '"' {
-			if data[pos] == '\\' {
+		pos++ // skip closing quote
+		if match {
+			for pos < end && data[pos] <= ' ' {
 				pos++
 			}
+			if pos >= end || data[pos] != ':' {
+				return -1
+			}
 			pos++
+			for pos < end && data[pos] <= ' ' {
+				pos++
+			}
+			return pos
 		}
-		pos++
 		for pos < end && data[pos] != ':' {
 			pos++
 		}
@@ -1168,6 +1470,7 @@ func (n Node) Index(i int) Node {
 	if len(n.expanded) > 0 {
 		node.expanded = n.expanded
 	}
+	node.lazy = n.lazy
 	return node
 }
 
@@ -1459,7 +1762,7 @@ func (n Node) String() (string, error) {
 		return "", nil // 空字符串正常返回
 	}
 
-	str := unsafe.String(&bytes[0], len(bytes))
+	str := bytesToString(bytes)
 	// 如果包含转义字符，需要解转义
 	if strings.Contains(str, "\\") {
 		return unescapeJSON(str), nil
@@ -1468,6 +1771,16 @@ func (n Node) String() (string, error) {
 	return str, nil
 }
 
+// SanitizedString 与 String 相同，但将非法 UTF-8 序列替换为 U+FFFD，
+// 适用于下游（搜索索引、protobuf 等）要求合法 UTF-8 的场景
+func (n Node) SanitizedString() (string, error) {
+	s, err := n.String()
+	if err != nil || utf8.ValidString(s) {
+		return s, err
+	}
+	return strings.ToValidUTF8(s, string(utf8.RuneError)), nil
+}
+
 // Int 返回节点的 int64 整数值
 // 如果节点类型不是 JSON 数字、为空、包含非整数字符，或超出 int64 范围，则返回错误
 func (n Node) Int() (int64, error) {
@@ -1592,8 +1905,13 @@ func (n Node) Uint() (uint64, error) {
 	return val, nil
 }
 
-// Float 返回节点的 float64 浮点值
+// Float 返回节点的 float64 浮点值，结果与 strconv.ParseFloat 逐位一致
 func (n Node) Float() (float64, error) {
+	return n.FloatWith(FloatExact)
+}
+
+// FloatWith 按指定精度模式返回节点的 float64 浮点值
+func (n Node) FloatWith(mode FloatMode) (float64, error) {
 	if n.typ != 'n' || n.start >= n.end {
 		return 0, fmt.Errorf("not a number: got type=%q at range [%d:%d] (len=%d)", n.Kind(), n.start, n.end, n.end-n.start)
 	}
@@ -1614,6 +1932,8 @@ func (n Node) Float() (float64, error) {
 	sawDigit := false
 	const maxMantDigits = 19
 	digits := 0
+	decExp := 0
+	truncated := false
 	for i < len(data) {
 		c := data[i]
 		if c < '0' || c > '9' {
@@ -1623,10 +1943,12 @@ func (n Node) Float() (float64, error) {
 		if digits < maxMantDigits {
 			mant = mant*10 + uint64(c-'0')
 			digits++
+		} else {
+			decExp++ // 超出尾数精度的整数位只影响数量级
+			truncated = truncated || c != '0'
 		}
 		i++
 	}
-	decExp := 0
 	if i < len(data) && data[i] == '.' {
 		i++
 		for i < len(data) {
@@ -1635,12 +1957,13 @@ func (n Node) Float() (float64, error) {
 				break
 			}
 			sawDigit = true
+			// 超出尾数精度的小数位直接舍弃
 			if digits < maxMantDigits {
 				mant = mant*10 + uint64(c-'0')
 				digits++
 				decExp--
 			} else {
-				decExp--
+				truncated = truncated || c != '0'
 			}
 			i++
 		}
@@ -1683,14 +2006,7 @@ func (n Node) Float() (float64, error) {
 	if !sawDigit {
 		return 0, fmt.Errorf("invalid number: no digits found at range [%d:%d] (type=%q)", n.start, n.end, n.Kind())
 	}
-	f := float64(mant)
-	if decExp != 0 {
-		f = scaleByPow10(f, decExp)
-	}
-	if neg {
-		f = -f
-	}
-	return f, nil
+	return composeFloat(data, mant, decExp, neg, truncated, mode), nil
 }
 
 func scaleByPow10(x float64, k int) float64 {
@@ -1743,7 +2059,7 @@ func (n Node) Bool() (bool, error) {
 		return false, nil
 	}
 	return false, fmt.Errorf("invalid bool: value=%q at range [%d:%d] (type=%q)",
-		unsafe.String(&data[0], len(data)), n.start, n.end, n.Kind())
+		bytesToString(data), n.start, n.end, n.Kind())
 }
 
 // NumStr 返回节点的数字原始字符串表示
@@ -1752,7 +2068,7 @@ func (n Node) NumStr() (string, error) {
 		return "", fmt.Errorf("not a number: got type=%q at range [%d:%d]", n.Kind(), n.start, n.end)
 	}
 	data := n.getWorkingData()
-	return unsafe.String(&data[n.start], n.end-n.start), nil
+	return bytesToString(data[n.start:n.end]), nil
 }
 
 // FloatString 返回数字的字符串表示，保持原始JSON格式的精度
@@ -1787,7 +2103,7 @@ func (n Node) Json() (string, error) {
 	if n.end > len(data) {
 		return "", fmt.Errorf("invalid range: end=%d > len(data)=%d", n.end, len(data))
 	}
-	return unsafe.String(&data[n.start], n.end-n.start), nil
+	return bytesToString(data[n.start:n.end]), nil
 }
 
 // ToJSON 将节点序列化为JSON字符串（压缩模式）
@@ -1803,7 +2119,10 @@ func (n Node) ToJSONIndent(prefix, indent string) (string, error) {
 }
 
 // ToJSONWithOptions 使用指定选项将节点序列化为JSON字符串
-func (n Node) ToJSONWithOptions(opts SerializeOptions) (string, error) {
+func (n Node) ToJSONWithOptions(opts SerializeOptions) (out string, err error) {
+	if done := slowOpStart(); done != nil {
+		defer func() { done(SlowOp{Op: OpSerialize, InputBytes: n.end - n.start, OutputBytes: len(out), Err: err}) }()
+	}
 	if !n.Exists() {
 		return "null", nil
 	}
@@ -1824,7 +2143,12 @@ func (n Node) ToJSONBytes() ([]byte, error) {
 }
 
 // ToJSONBytesWithOptions 使用指定选项将节点序列化为JSON字节切片
-func (n Node) ToJSONBytesWithOptions(opts SerializeOptions) ([]byte, error) {
+func (n Node) ToJSONBytesWithOptions(opts SerializeOptions) (out []byte, err error) {
+	if done := slowOpStart(); done != nil {
+		defer func() { done(SlowOp{Op: OpSerialize, InputBytes: n.end - n.start, OutputBytes: len(out), Err: err}) }()
+	}
+	defer recoverPanic(&err, "ToJSONBytes", n)
+
 	if !n.Exists() {
 		return []byte("null"), nil
 	}
@@ -1841,6 +2165,41 @@ func (n Node) ToJSONBytesWithOptions(opts SerializeOptions) ([]byte, error) {
 	return result, nil
 }
 
+// MarshalInto 使用指定选项将节点序列化并追加到 buf，不产生中间副本；失败时 buf 恢复到调用前的内容
+func (n Node) MarshalInto(buf *Buffer, opts SerializeOptions) (err error) {
+	mark := len(buf.buf)
+	defer func() {
+		if err != nil {
+			buf.buf = buf.buf[:mark]
+		}
+	}()
+	defer recoverPanic(&err, "MarshalInto", n)
+
+	return n.marshalNode(buf, opts, 0)
+}
+
+// ToJSONBytesNoCopy 将节点序列化为JSON（压缩模式），返回从池中获取的缓冲区，不复制结果
+// 通过 BytesView 或 WriteTo 读取内容（如立即写入 socket），用完后调用 Release 归还；
+// Release 之后不得再使用缓冲区及其内容。需要长期保留结果时请使用 ToJSONBytes
+//
+//	buf, err := node.ToJSONBytesNoCopy()
+//	if err == nil {
+//		_, err = buf.WriteTo(conn)
+//		buf.Release()
+//	}
+func (n Node) ToJSONBytesNoCopy() (*Buffer, error) {
+	buf := getBuffer()
+	if !n.Exists() {
+		buf.WriteString("null")
+		return buf, nil
+	}
+	if err := n.MarshalInto(buf, DefaultSerializeOptions); err != nil {
+		putBuffer(buf)
+		return nil, err
+	}
+	return buf, nil
+}
+
 // ToJSONFast 快速序列化节点为JSON字符串（最小开销）
 func (n Node) ToJSONFast() string {
 	if !n.Exists() {
@@ -1945,7 +2304,7 @@ func (n Node) marshalObject(buf *Buffer, opts SerializeOptions, depth int) error
 		pairs = append(pairs, struct {
 			key   string
 			value Node
-		}{key, value})
+		}{opts.outputKey(key), value})
 		return true
 	})
 
@@ -2296,13 +2655,20 @@ func (n Node) KeysBytes() [][]byte {
 func (n Node) RawString() (string, error) {
 	data := n.getWorkingData()
 	if n.start >= 0 && n.end <= len(data) && n.start < n.end {
-		return unsafe.String(&data[n.start], n.end-n.start), nil
+		return bytesToString(data[n.start:n.end]), nil
 	}
 	return "", fmt.Errorf("invalid node range: start=%d, end=%d, len(data)=%d, type=%q", n.start, n.end, len(data), n.Kind())
 }
 
 // Decode 将节点的 JSON 值解码到提供的变量 v 中
 func (n Node) Decode(v any) error {
+	return n.DecodeWithOptions(v, DefaultDecodeOptions)
+}
+
+// DecodeWithOptions 使用指定解码选项将节点的 JSON 值解码到 v 中
+func (n Node) DecodeWithOptions(v any, opts DecodeOptions) (err error) {
+	defer recoverPanic(&err, "Decode", n)
+
 	if !n.Exists() {
 		return fmt.Errorf("node does not exist: start=%d, end=%d, type=%q", n.start, n.end, n.Kind())
 	}
@@ -2314,37 +2680,44 @@ func (n Node) Decode(v any) error {
 		return fmt.Errorf("v must be a non-nil pointer: type=%T", v)
 	}
 
-	return n.decodeValueFast(rv.Elem())
+	return n.decodeValueFast(rv.Elem(), &opts)
 }
 
 // decodeValueFast 高性能解码实现
-func (n Node) decodeValueFast(rv reflect.Value) error {
+func (n Node) decodeValueFast(rv reflect.Value, opts *DecodeOptions) error {
 	if !rv.CanSet() {
 		return fmt.Errorf("cannot set value of type %s", rv.Type())
 	}
 
+	if handled, err := n.decodeCustom(rv); handled {
+		return err
+	}
+	if n.decodeRaw(rv) {
+		return nil
+	}
+
 	// 快速路径：直接处理常见类型，避免反射开销
 	switch n.typ {
 	case 'l': // null
 		rv.Set(reflect.Zero(rv.Type()))
 		return nil
 	case 's': // string
-		return n.decodeStringFast(rv)
+		return n.decodeStringFast(rv, opts)
 	case 'n': // number
-		return n.decodeNumberFast(rv)
+		return n.decodeNumberFast(rv, opts)
 	case 'b': // bool
-		return n.decodeBoolFast(rv)
+		return n.decodeBoolFast(rv, opts)
 	case 'a': // array
-		return n.decodeArrayFast(rv)
+		return n.decodeArrayFast(rv, opts)
 	case 'o': // object
-		return n.decodeObjectFast(rv)
+		return n.decodeObjectFast(rv, opts)
 	default:
 		return fmt.Errorf("unknown JSON type: %d", n.Kind())
 	}
 }
 
 // decodeStringFast 快速字符串解码
-func (n Node) decodeStringFast(rv reflect.Value) error {
+func (n Node) decodeStringFast(rv reflect.Value, opts *DecodeOptions) error {
 	data := n.getWorkingData()
 	if n.start+1 >= n.end {
 		return fmt.Errorf("invalid string bounds")
@@ -2356,7 +2729,7 @@ func (n Node) decodeStringFast(rv reflect.Value) error {
 	if len(strBytes) == 0 {
 		str = "" // 安全处理空字符串
 	} else {
-		str = unsafe.String(&strBytes[0], len(strBytes))
+		str = bytesToString(strBytes)
 	}
 
 	// 仅在需要时进行转义处理
@@ -2377,13 +2750,13 @@ func (n Node) decodeStringFast(rv reflect.Value) error {
 }
 
 // decodeNumberFast 快速数字解码
-func (n Node) decodeNumberFast(rv reflect.Value) error {
+func (n Node) decodeNumberFast(rv reflect.Value, opts *DecodeOptions) error {
 	data := n.getWorkingData()
 	numBytes := data[n.start:n.end]
 
 	switch rv.Kind() {
 	case reflect.String:
-		rv.SetString(unsafe.String(&numBytes[0], len(numBytes)))
+		rv.SetString(bytesToString(numBytes))
 		return nil
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		i, err := parseIntFast(numBytes)
@@ -2403,7 +2776,7 @@ func (n Node) decodeNumberFast(rv reflect.Value) error {
 		rv.SetUint(uint64(i))
 		return nil
 	case reflect.Float32, reflect.Float64:
-		f := parseFloatFast(numBytes)
+		f := parseFloatMode(numBytes, opts.FloatMode)
 		rv.SetFloat(f)
 		return nil
 	case reflect.Interface:
@@ -2413,8 +2786,17 @@ func (n Node) decodeNumberFast(rv reflect.Value) error {
 				rv.Set(reflect.ValueOf(i))
 				return nil
 			}
+			if opts.Int64Mode {
+				// 超出 int64 的整数不降级为 float64
+				u, err := strconv.ParseUint(string(numBytes), 10, 64)
+				if err != nil {
+					return fmt.Errorf("integer %s cannot be represented exactly", numBytes)
+				}
+				rv.Set(reflect.ValueOf(u))
+				return nil
+			}
 		}
-		f := parseFloatFast(numBytes)
+		f := parseFloatMode(numBytes, opts.FloatMode)
 		rv.Set(reflect.ValueOf(f))
 		return nil
 	default:
@@ -2423,7 +2805,7 @@ func (n Node) decodeNumberFast(rv reflect.Value) error {
 }
 
 // decodeBoolFast 快速布尔解码
-func (n Node) decodeBoolFast(rv reflect.Value) error {
+func (n Node) decodeBoolFast(rv reflect.Value, opts *DecodeOptions) error {
 	data := n.getWorkingData()
 	boolBytes := data[n.start:n.end]
 
@@ -2452,22 +2834,27 @@ func (n Node) decodeBoolFast(rv reflect.Value) error {
 }
 
 // decodeArrayFast 快速数组解码
-func (n Node) decodeArrayFast(rv reflect.Value) error {
+func (n Node) decodeArrayFast(rv reflect.Value, opts *DecodeOptions) error {
 	switch rv.Kind() {
 	case reflect.Slice:
-		return n.decodeSliceFast(rv)
+		return n.decodeSliceFast(rv, opts)
 	case reflect.Array:
-		return n.decodeArrayFixedFast(rv)
+		return n.decodeArrayFixedFast(rv, opts)
 	case reflect.Interface:
 		// 使用预分配容量避免扩容
 		length := n.Len()
-		slice := make([]interface{}, 0, length)
+		var slice []interface{}
+		if opts.Pool != nil {
+			slice = opts.Pool.getSlice(length)
+		} else {
+			slice = make([]interface{}, 0, length)
+		}
 
 		var decodeErr error
 		n.ArrayForEach(func(i int, child Node) bool {
 			var elem interface{}
 			elemRV := reflect.ValueOf(&elem).Elem()
-			if err := child.decodeValueFast(elemRV); err != nil {
+			if err := child.decodeValueFast(elemRV, opts); err != nil {
 				decodeErr = err
 				return false
 			}
@@ -2486,7 +2873,7 @@ func (n Node) decodeArrayFast(rv reflect.Value) error {
 }
 
 // decodeSliceFast 快速slice解码
-func (n Node) decodeSliceFast(rv reflect.Value) error {
+func (n Node) decodeSliceFast(rv reflect.Value, opts *DecodeOptions) error {
 	length := n.Len()
 	slice := reflect.MakeSlice(rv.Type(), length, length)
 
@@ -2496,7 +2883,7 @@ func (n Node) decodeSliceFast(rv reflect.Value) error {
 			return false
 		}
 		if i < length {
-			decodeErr = child.decodeValueFast(slice.Index(i))
+			decodeErr = child.decodeValueFast(slice.Index(i), opts)
 		}
 		return decodeErr == nil
 	})
@@ -2510,7 +2897,7 @@ func (n Node) decodeSliceFast(rv reflect.Value) error {
 }
 
 // decodeArrayFixedFast 快速固定数组解码
-func (n Node) decodeArrayFixedFast(rv reflect.Value) error {
+func (n Node) decodeArrayFixedFast(rv reflect.Value, opts *DecodeOptions) error {
 	length := rv.Len()
 
 	var decodeErr error
@@ -2519,7 +2906,7 @@ func (n Node) decodeArrayFixedFast(rv reflect.Value) error {
 			return false
 		}
 		if i < length {
-			decodeErr = child.decodeValueFast(rv.Index(i))
+			decodeErr = child.decodeValueFast(rv.Index(i), opts)
 		}
 		return decodeErr == nil
 	})
@@ -2528,15 +2915,33 @@ func (n Node) decodeArrayFixedFast(rv reflect.Value) error {
 }
 
 // decodeObjectFast 快速对象解码
-func (n Node) decodeObjectFast(rv reflect.Value) error {
+func (n Node) decodeObjectFast(rv reflect.Value, opts *DecodeOptions) error {
 	switch rv.Kind() {
 	case reflect.Struct:
-		return n.decodeStructFast(rv)
+		if rv.Type() == syncMapType {
+			return n.decodeSyncMap(rv, func(child Node, v reflect.Value) error {
+				return child.decodeValueFast(v, opts)
+			})
+		}
+		return n.decodeStructFast(rv, opts)
 	case reflect.Map:
-		return n.decodeMapFast(rv)
+		return n.decodeMapFast(rv, opts)
 	case reflect.Interface:
+		if opts.TypeField != "" {
+			if handled, err := n.decodeRegisteredType(rv, opts); handled {
+				return err
+			}
+		}
+		if rv.NumMethod() > 0 {
+			return fmt.Errorf("cannot decode object into non-empty interface %s; set DecodeOptions.TypeField and RegisterType", rv.Type())
+		}
 		// 使用预估容量减少map扩容
-		m := make(map[string]interface{}, n.Len())
+		var m map[string]interface{}
+		if opts.Pool != nil {
+			m = opts.Pool.getMap(n.Len())
+		} else {
+			m = make(map[string]interface{}, n.Len())
+		}
 
 		var decodeErr error
 		n.ForEach(func(key string, child Node) bool {
@@ -2545,7 +2950,7 @@ func (n Node) decodeObjectFast(rv reflect.Value) error {
 			}
 			var val interface{}
 			valRV := reflect.ValueOf(&val).Elem()
-			if err := child.decodeValueFast(valRV); err != nil {
+			if err := child.decodeValueFast(valRV, opts); err != nil {
 				decodeErr = err
 				return false
 			}
@@ -2564,40 +2969,67 @@ func (n Node) decodeObjectFast(rv reflect.Value) error {
 }
 
 // decodeStructFast 快速结构体解码（缓存优化版本）
-func (n Node) decodeStructFast(rv reflect.Value) error {
+func (n Node) decodeStructFast(rv reflect.Value, opts *DecodeOptions) error {
 	structType := rv.Type()
-	fieldMap := getStructFieldMapFast(structType)
+	fieldMap := getStructFieldMapFast(structType, opts)
+
+	// 仅在存在默认值时记录已出现的字段
+	defaults := getStructDefaults(structType)
+	var seen []bool
+	if len(defaults) > 0 {
+		seen = make([]bool, structType.NumField())
+	}
 
+	remain := getStructRemainField(structType)
+
+	var aliases aliasTracker
 	var decodeErr error
 	n.ForEach(func(key string, child Node) bool {
 		if decodeErr != nil {
 			return false
 		}
 
-		if fieldInfo, exists := fieldMap[key]; exists {
+		if fieldInfo, exists := lookupStructField(fieldMap, structType, key, opts); exists {
 			fieldValue := rv.Field(fieldInfo.Index)
-			if fieldValue.CanSet() {
-				decodeErr = child.decodeValueFast(fieldValue)
+			if fieldValue.CanSet() && aliases.allow(fieldInfo, fieldValue) {
+				decodeErr = child.decodeValueFast(fieldValue, opts)
+				if seen != nil && child.typ != 'l' {
+					seen[fieldInfo.Index] = true
+				}
 			}
+		} else if remain >= 0 {
+			decodeErr = decodeRemainField(rv.Field(remain), key, child, opts)
 		}
 		return decodeErr == nil
 	})
 
-	return decodeErr
+	if decodeErr != nil || seen == nil {
+		return decodeErr
+	}
+	return applyStructDefaults(rv, defaults, seen)
 }
 
+// anyMapType map[string]any 的反射类型
+var anyMapType = reflect.TypeOf(map[string]interface{}(nil))
+
 // decodeMapFast 快速map解码
-func (n Node) decodeMapFast(rv reflect.Value) error {
+func (n Node) decodeMapFast(rv reflect.Value, opts *DecodeOptions) error {
 	mapType := rv.Type()
 	keyType := mapType.Key()
 	valueType := mapType.Elem()
 
-	if keyType.Kind() != reflect.String {
-		return fmt.Errorf("map key must be string, got %s", keyType)
+	decodeKey, err := mapKeyDecoder(keyType)
+	if err != nil {
+		return err
 	}
 
-	// 预分配容量
-	m := reflect.MakeMapWithSize(mapType, n.Len())
+	// 预分配容量，map[string]any 可从池中获取
+	var m reflect.Value
+	if opts.Pool != nil && mapType == anyMapType {
+		m = reflect.ValueOf(opts.Pool.getMap(n.Len()))
+	} else {
+		m = reflect.MakeMapWithSize(mapType, n.Len())
+	}
 
 	var decodeErr error
 	n.ForEach(func(key string, child Node) bool {
@@ -2605,10 +3037,14 @@ func (n Node) decodeMapFast(rv reflect.Value) error {
 			return false
 		}
 
-		keyVal := reflect.ValueOf(key)
+		keyVal, err := decodeKey(key)
+		if err != nil {
+			decodeErr = err
+			return false
+		}
 		valueVal := reflect.New(valueType).Elem()
 
-		if err := child.decodeValueFast(valueVal); err != nil {
+		if err := child.decodeValueFast(valueVal, opts); err != nil {
 			decodeErr = err
 			return false
 		}
@@ -2626,9 +3062,12 @@ func (n Node) decodeMapFast(rv reflect.Value) error {
 }
 
 // getStructFieldMapFast 快速结构体字段映射（优化版本）
-func getStructFieldMapFast(t reflect.Type) map[string]structFieldInfo {
-	if cached, ok := structFieldCache.Load(t); ok {
-		return cached.(map[string]structFieldInfo)
+func getStructFieldMapFast(t reflect.Type, opts *DecodeOptions) map[string]structFieldInfo {
+	if opts.TagName != "" {
+		return getStructFieldMapWithTag(t, opts.TagName)
+	}
+	if cached, ok := loadStructFieldMap(t); ok {
+		return cached
 	}
 
 	fieldMap := make(map[string]structFieldInfo, t.NumField())
@@ -2642,7 +3081,7 @@ func getStructFieldMapFast(t reflect.Type) map[string]structFieldInfo {
 		}
 
 		jsonName := getJSONFieldNameFast(field)
-		if jsonName == "-" {
+		if jsonName == "-" || isRemainField(field) {
 			continue
 		}
 
@@ -2650,6 +3089,7 @@ func getStructFieldMapFast(t reflect.Type) map[string]structFieldInfo {
 			Index:    i,
 			JSONName: jsonName,
 		}
+		addStructFieldAliases(fieldMap, field, i, jsonName)
 	}
 
 	structFieldCache.Store(t, fieldMap)
@@ -2682,6 +3122,13 @@ func (n Node) decodeValue(rv reflect.Value) error {
 		return fmt.Errorf("cannot set value of type %s", rv.Type())
 	}
 
+	if handled, err := n.decodeCustom(rv); handled {
+		return err
+	}
+	if n.decodeRaw(rv) {
+		return nil
+	}
+
 	switch n.Kind() {
 	case TypeNull:
 		rv.Set(reflect.Zero(rv.Type()))
@@ -2872,6 +3319,9 @@ func (n Node) decodeArrayFixed(rv reflect.Value) error {
 func (n Node) decodeObject(rv reflect.Value) error {
 	switch rv.Kind() {
 	case reflect.Struct:
+		if rv.Type() == syncMapType {
+			return n.decodeSyncMap(rv, Node.decodeValue)
+		}
 		return n.decodeStruct(rv)
 	case reflect.Map:
 		return n.decodeMap(rv)
@@ -2898,6 +3348,7 @@ func (n Node) decodeStruct(rv reflect.Value) error {
 	structType := rv.Type()
 	fieldMap := getStructFieldMap(structType)
 
+	var aliases aliasTracker
 	var decodeErr error
 	n.ForEach(func(key string, child Node) bool {
 		if decodeErr != nil {
@@ -2906,7 +3357,7 @@ func (n Node) decodeStruct(rv reflect.Value) error {
 
 		if fieldInfo, exists := fieldMap[key]; exists {
 			fieldValue := rv.Field(fieldInfo.Index)
-			if fieldValue.CanSet() {
+			if fieldValue.CanSet() && aliases.allow(fieldInfo, fieldValue) {
 				decodeErr = child.decodeValue(fieldValue)
 			}
 		}
@@ -2922,8 +3373,9 @@ func (n Node) decodeMap(rv reflect.Value) error {
 	keyType := mapType.Key()
 	valueType := mapType.Elem()
 
-	if keyType.Kind() != reflect.String {
-		return fmt.Errorf("map key must be string, got %s", keyType)
+	decodeKey, err := mapKeyDecoder(keyType)
+	if err != nil {
+		return err
 	}
 
 	m := reflect.MakeMap(mapType)
@@ -2934,7 +3386,11 @@ func (n Node) decodeMap(rv reflect.Value) error {
 			return false
 		}
 
-		keyVal := reflect.ValueOf(key)
+		keyVal, err := decodeKey(key)
+		if err != nil {
+			decodeErr = err
+			return false
+		}
 		valueVal := reflect.New(valueType).Elem()
 
 		if err := child.decodeValue(valueVal); err != nil {
@@ -2956,8 +3412,10 @@ func (n Node) decodeMap(rv reflect.Value) error {
 
 // structFieldInfo 存储结构体字段信息
 type structFieldInfo struct {
-	Index    int    // 字段在结构体中的索引
-	JSONName string // JSON标签名或字段名
+	Index      int    // 字段在结构体中的索引
+	JSONName   string // JSON标签名或字段名
+	Alias      bool   // 该条目是 fxalias 标签中的旧名称
+	HasAliases bool   // 字段声明了 fxalias 别名
 }
 
 // structFieldMap 缓存结构体字段映射
@@ -2965,8 +3423,8 @@ var structFieldCache = sync.Map{}
 
 // getStructFieldMap 获取结构体字段映射
 func getStructFieldMap(t reflect.Type) map[string]structFieldInfo {
-	if cached, ok := structFieldCache.Load(t); ok {
-		return cached.(map[string]structFieldInfo)
+	if cached, ok := loadStructFieldMap(t); ok {
+		return cached
 	}
 
 	fieldMap := make(map[string]structFieldInfo)
@@ -2980,7 +3438,7 @@ func getStructFieldMap(t reflect.Type) map[string]structFieldInfo {
 		}
 
 		jsonName := getJSONFieldName(field)
-		if jsonName == "-" {
+		if jsonName == "-" || isRemainField(field) {
 			// json:"-" 表示忽略此字段
 			continue
 		}
@@ -2989,6 +3447,7 @@ func getStructFieldMap(t reflect.Type) map[string]structFieldInfo {
 			Index:    i,
 			JSONName: jsonName,
 		}
+		addStructFieldAliases(fieldMap, field, i, jsonName)
 	}
 
 	structFieldCache.Store(t, fieldMap)
@@ -3018,7 +3477,9 @@ func getJSONFieldName(field reflect.StructField) string {
 
 // DecodeStruct 是一个优化版本的Decode方法，专门用于结构体解码
 // 避免创建Node的开销，直接使用字节切片
-func DecodeStruct(data []byte, v any) error {
+func DecodeStruct(data []byte, v any) (err error) {
+	defer recoverPanic(&err, "DecodeStruct", Node{})
+
 	rv := reflect.ValueOf(v)
 	if rv.Kind() != reflect.Ptr {
 		return fmt.Errorf("v must be a pointer: got kind=%s, type=%T", rv.Kind(), v)
@@ -3028,11 +3489,11 @@ func DecodeStruct(data []byte, v any) error {
 	}
 
 	// 直接解析，避免FromBytes的额外开销
-	return decodeStructFromBytes(data, rv.Elem())
+	return decodeStructFromBytes(data, rv.Elem(), &DefaultDecodeOptions)
 }
 
 // decodeStructFromBytes 直接从字节切片解码到结构体，避免Node创建开销
-func decodeStructFromBytes(data []byte, rv reflect.Value) error {
+func decodeStructFromBytes(data []byte, rv reflect.Value, opts *DecodeOptions) error {
 	if len(data) == 0 {
 		return fmt.Errorf("empty data")
 	}
@@ -3048,11 +3509,13 @@ func decodeStructFromBytes(data []byte, rv reflect.Value) error {
 
 	end := len(data)
 	node := Node{raw: data, start: start, end: end, typ: 'o'}
-	return node.decodeStructFast(rv)
+	return node.decodeStructFast(rv, opts)
 }
 
 // DecodeStructFast 极致优化的结构体解码函数
-func DecodeStructFast(data []byte, v any) error {
+func DecodeStructFast(data []byte, v any) (err error) {
+	defer recoverPanic(&err, "DecodeStructFast", Node{})
+
 	rv := reflect.ValueOf(v)
 	if rv.Kind() != reflect.Ptr {
 		return fmt.Errorf("v must be a pointer: got kind=%s, type=%T", rv.Kind(), v)
@@ -3066,18 +3529,29 @@ func DecodeStructFast(data []byte, v any) error {
 		return fmt.Errorf("v must point to a struct, got %s", elem.Kind())
 	}
 
-	return decodeStructDirectly(data, elem)
+	return decodeStructDirectly(data, elem, &DefaultDecodeOptions)
 }
 
 // decodeStructDirectly 直接解码结构体，跳过所有中间步骤
-func decodeStructDirectly(data []byte, rv reflect.Value) error {
+func decodeStructDirectly(data []byte, rv reflect.Value, opts *DecodeOptions) error {
 	if len(data) == 0 {
 		return fmt.Errorf("empty JSON data")
 	}
 
 	// 获取结构体类型信息
 	structType := rv.Type()
-	fieldMap := getStructFieldMapFast(structType)
+	fieldMap := getStructFieldMapFast(structType, opts)
+	remain := getStructRemainField(structType)
+
+	// 仅在存在默认值时记录已出现的字段
+	defaults := getStructDefaults(structType)
+	var seen []bool
+	if len(defaults) > 0 {
+		seen = make([]bool, structType.NumField())
+	}
+
+	var keyScratch []byte // 含转义键的解转义缓冲区
+	var aliases aliasTracker
 
 	// 快速扫描JSON对象
 	pos := 0
@@ -3117,7 +3591,7 @@ func decodeStructDirectly(data []byte, rv reflect.Value) error {
 		pos++ // skip closing quote
 
 		// 零拷贝键提取
-		key := unsafe.String(&data[keyStart], keyEnd-keyStart)
+		key := keyString(data[keyStart:keyEnd], &keyScratch)
 
 		// 跳过冒号
 		for pos < len(data) && data[pos] <= ' ' {
@@ -3132,9 +3606,9 @@ func decodeStructDirectly(data []byte, rv reflect.Value) error {
 		}
 
 		// 查找对应的结构体字段
-		if fieldInfo, exists := fieldMap[key]; exists {
+		if fieldInfo, exists := lookupStructField(fieldMap, structType, key, opts); exists {
 			fieldValue := rv.Field(fieldInfo.Index)
-			if fieldValue.CanSet() {
+			if fieldValue.CanSet() && aliases.allow(fieldInfo, fieldValue) {
 				// 解析值并直接设置到字段
 				valueEnd := skipValueFast(data, pos, len(data))
 				if valueEnd <= pos {
@@ -3149,15 +3623,29 @@ func decodeStructDirectly(data []byte, rv reflect.Value) error {
 					typ:   detectType(data[pos]),
 				}
 
-				if err := valueNode.decodeValueFast(fieldValue); err != nil {
+				if err := valueNode.decodeValueFast(fieldValue, opts); err != nil {
 					return fmt.Errorf("failed to decode field %s: %v", key, err)
 				}
+				if seen != nil && valueNode.typ != 'l' {
+					seen[fieldInfo.Index] = true
+				}
 
 				pos = valueEnd
 			} else {
 				// 跳过无法设置的字段
 				pos = skipValueFast(data, pos, len(data))
 			}
+		} else if remain >= 0 {
+			// 未匹配的字段收集到 remain 字段中
+			valueEnd := skipValueFast(data, pos, len(data))
+			if valueEnd <= pos {
+				return fmt.Errorf("invalid value at position %d", pos)
+			}
+			valueNode := Node{raw: data, start: pos, end: valueEnd, typ: detectType(data[pos])}
+			if err := decodeRemainField(rv.Field(remain), key, valueNode, opts); err != nil {
+				return err
+			}
+			pos = valueEnd
 		} else {
 			// 跳过未匹配的字段
 			pos = skipValueFast(data, pos, len(data))
@@ -3172,6 +3660,9 @@ func decodeStructDirectly(data []byte, rv reflect.Value) error {
 		}
 	}
 
+	if seen != nil {
+		return applyStructDefaults(rv, defaults, seen)
+	}
 	return nil
 }
 
@@ -3189,6 +3680,7 @@ func fastDecode(buf []byte, start, end int) (any, int, error) {
 	switch buf[start] {
 	case '{':
 		m := make(map[string]any)
+		var keyScratch []byte
 		i := start + 1
 		for i < valueEnd-1 {
 			// 跳过空白
@@ -3204,7 +3696,7 @@ func fastDecode(buf []byte, start, end int) (any, int, error) {
 				return nil, i, fmt.Errorf("expected key at position %d", i)
 			}
 			keyEnd := skipValueFast(buf, i, valueEnd)
-			key := unsafe.String(&buf[i+1], keyEnd-i-2)
+			key := keyString(buf[i+1:keyEnd-1], &keyScratch)
 			i = keyEnd
 
 			// 跳过空白和冒号
@@ -3255,7 +3747,7 @@ func fastDecode(buf []byte, start, end int) (any, int, error) {
 		return arr, valueEnd, nil
 
 	case '"':
-		str := unsafe.String(&buf[start+1], valueEnd-start-2)
+		str := bytesToString(buf[start+1 : valueEnd-1])
 		return str, valueEnd, nil
 
 	case 't':
@@ -3269,7 +3761,7 @@ func fastDecode(buf []byte, start, end int) (any, int, error) {
 
 	default:
 		// 数字
-		fv := parseFloatFast(buf[start:valueEnd])
+		fv := parseFloatMode(buf[start:valueEnd], FloatExact)
 		return fv, valueEnd, nil
 	}
 }
@@ -3311,7 +3803,8 @@ func parseIntFast(data []byte) (int64, error) {
 	return int64(val), nil
 }
 
-func parseFloatFast(data []byte) float64 {
+// parseFloatMode 解析已知为数字的字节序列，不做语法校验
+func parseFloatMode(data []byte, mode FloatMode) float64 {
 	i := 0
 	neg := false
 	if len(data) > 0 && data[i] == '-' {
@@ -3322,6 +3815,8 @@ func parseFloatFast(data []byte) float64 {
 	sawDigit := false
 	const maxMantDigits = 19
 	digits := 0
+	decExp := 0
+	truncated := false
 	for i < len(data) {
 		c := data[i]
 		if c < '0' || c > '9' {
@@ -3331,10 +3826,12 @@ func parseFloatFast(data []byte) float64 {
 		if digits < maxMantDigits {
 			mant = mant*10 + uint64(c-'0')
 			digits++
+		} else {
+			decExp++ // 超出尾数精度的整数位只影响数量级
+			truncated = truncated || c != '0'
 		}
 		i++
 	}
-	decExp := 0
 	if i < len(data) && data[i] == '.' {
 		i++
 		for i < len(data) {
@@ -3343,12 +3840,13 @@ func parseFloatFast(data []byte) float64 {
 				break
 			}
 			sawDigit = true
+			// 超出尾数精度的小数位直接舍弃
 			if digits < maxMantDigits {
 				mant = mant*10 + uint64(c-'0')
 				digits++
 				decExp--
 			} else {
-				decExp--
+				truncated = truncated || c != '0'
 			}
 			i++
 		}
@@ -3378,14 +3876,7 @@ func parseFloatFast(data []byte) float64 {
 	if !sawDigit {
 		return 0
 	}
-	f := float64(mant)
-	if decExp != 0 {
-		f = scaleByPow10(f, decExp)
-	}
-	if neg {
-		f = -f
-	}
-	return f
+	return composeFloat(data, mant, decExp, neg, truncated, mode)
 }
 
 // ===== TypeInfo =====
@@ -3455,7 +3946,8 @@ func (n Node) ForEach(fn ForEachFunc) {
 	data := n.getWorkingData()
 	pos := n.start + 1 // 直接跳过 '{'
 	end := n.end
-	endMinus1 := end - 1 // 预计算边界
+	endMinus1 := end - 1  // 预计算边界
+	var keyScratch []byte // 含转义键的解转义缓冲区
 
 	// 批处理优化：预分配键值对缓冲区
 	type keyValuePair struct {
@@ -3539,8 +4031,8 @@ func (n Node) ForEach(fn ForEachFunc) {
 	for i := 0; i < pairCount; i++ {
 		pair := pairs[i]
 
-		// 创建键字符串（零拷贝）
-		key := unsafe.String(&data[pair.keyStart], pair.keyEnd-pair.keyStart)
+		// 创建键字符串（无转义时零拷贝）
+		key := keyString(data[pair.keyStart:pair.keyEnd], &keyScratch)
 
 		// 创建值节点
 		valueNode := Node{
@@ -3549,6 +4041,7 @@ func (n Node) ForEach(fn ForEachFunc) {
 			end:      pair.valueEnd,
 			typ:      pair.valueType,
 			expanded: n.expanded,
+			lazy:     n.lazy,
 		}
 
 		if !fn(key, valueNode) {
@@ -3607,9 +4100,10 @@ func (n Node) ForEach(fn ForEachFunc) {
 				end:      valueEnd,
 				typ:      detectType(data[valueStart]),
 				expanded: n.expanded,
+				lazy:     n.lazy,
 			}
 
-			key := unsafe.String(&data[keyStart], keyEnd-keyStart)
+			key := keyString(data[keyStart:keyEnd], &keyScratch)
 			if !fn(key, valueNode) {
 				break
 			}
@@ -3738,6 +4232,7 @@ func (n Node) ArrayForEach(fn ArrayForEachFunc) {
 				end:      valueEnd,
 				typ:      detectType(data[offset]),
 				expanded: n.expanded,
+				lazy:     n.lazy,
 			}
 
 			if !fn(i, valueNode) {
@@ -3798,6 +4293,7 @@ func (n Node) ArrayForEach(fn ArrayForEachFunc) {
 			end:      valueEnd,
 			typ:      detectType(data[valueStart]),
 			expanded: n.expanded,
+			lazy:     n.lazy,
 		}
 
 		if !fn(index, valueNode) {
@@ -3969,6 +4465,7 @@ func (n Node) Walk(fn WalkFunc) {
 
 	// 复用字节缓冲区避免重复分配
 	var pathBytes [512]byte
+	var keyScratch []byte
 
 	for len(stack) > 0 {
 		// 出栈
@@ -4022,7 +4519,7 @@ func (n Node) Walk(fn WalkFunc) {
 				keyEnd := pos
 				pos++ // skip closing quote
 
-				key := unsafe.String(&data[keyStart], keyEnd-keyStart)
+				key := keyString(data[keyStart:keyEnd], &keyScratch)
 
 				// 跳过冒号
 				for pos < end && data[pos] <= ' ' {
@@ -4048,6 +4545,7 @@ func (n Node) Walk(fn WalkFunc) {
 					end:      valueEnd,
 					typ:      detectType(data[valueStart]),
 					expanded: n.expanded,
+					lazy:     n.lazy,
 				}
 
 				pairs = append(pairs, keyValue{key: key, value: value})
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"math"
 	"reflect"
+	"sync"
 	"testing"
 )
 
@@ -127,6 +128,57 @@ func TestGetPath(t *testing.T) {
 	}
 }
 
+// TestGetPathRoots 测试以数组与标量为起点的路径解析
+func TestGetPathRoots(t *testing.T) {
+	root := FromString(` [ {"name":"a","tags":["x","y"]}, {"name":"b"}, [1, [2, 3]] ] `)
+	scalar := FromString(`"name"`)
+	sub := FromString(`{"list":[10,20,30],"s":"k"}`)
+
+	tests := []struct {
+		node Node
+		path string
+		want string // 空表示不存在
+	}{
+		{root, "[0].name", `"a"`},
+		{root, "[1].name", `"b"`},
+		{root, "[-1]", `[1, [2, 3]]`},
+		{root, "[-1][1][-2]", `2`},
+		{root, "[-3].tags[-1]", `"y"`},
+		{root, "[2][1][1]", `3`},
+		{root, "[-4]", ``},
+		{root, "[3]", ``},
+		{root, "name", ``},
+		{root, "[0]name", ``},
+		{root, "[0].", ``},
+		{root, "[2].name", ``},
+		{root, "[0].name[0]", ``},
+		{root, "[", ``},
+		{root, "[]", ``},
+		{root, "[0", ``},
+		{root, "[-0]", ``},
+		{root, "[x]", ``},
+		{sub.Get("list"), "[-1]", `30`},
+		{sub.Get("list"), "[0]", `10`},
+		{sub, "list[-2]", `20`},
+		{sub.Get("s"), "k", ``},
+		{scalar, "name", ``},
+		{scalar, "[0]", ``},
+		{FromString(`42`), "[0]", ``},
+	}
+	for _, tt := range tests {
+		if got := string(tt.node.GetPath(tt.path).Raw()); got != tt.want {
+			t.Errorf("GetPath(%q) on %s = %q, want %q", tt.path, tt.node.Raw(), got, tt.want)
+		}
+	}
+
+	if got := root.GetPathSep("[0]/tags[-1]", '/').Raw(); string(got) != `"y"` {
+		t.Errorf("GetPathSep = %s", got)
+	}
+	if got := root.Get("[1].name").Raw(); string(got) != `"b"` {
+		t.Errorf("Get = %s", got)
+	}
+}
+
 func TestIndex(t *testing.T) {
 	node := FromBytes(testJSON).Get("array")
 
@@ -1653,3 +1705,588 @@ func TestTypeConversionBoundaries(t *testing.T) {
 		}
 	})
 }
+
+// TestEscapedObjectKeys 测试含转义字符的对象键
+func TestEscapedObjectKeys(t *testing.T) {
+	data := []byte(`{"a\"b":1,"caf\u00e9":2,"tab\tkey":3,"\ud83d\ude00":4,"plain":5,"nested\/x":{"k\\v":6}}`)
+	node := FromBytes(data)
+
+	tests := map[string]int64{
+		`a"b`:      1,
+		"café":     2,
+		"tab\tkey": 3,
+		"😀":        4,
+		"plain":    5,
+	}
+	for key, want := range tests {
+		if got, err := node.Get(key).Int(); err != nil || got != want {
+			t.Errorf("Get(%q) = %d, %v; want %d", key, got, err, want)
+		}
+	}
+
+	if got, _ := node.Get("nested/x").Get(`k\v`).Int(); got != 6 {
+		t.Errorf("nested escaped key lookup got %d", got)
+	}
+	if node.Get(`a\"b`).Exists() {
+		t.Error("raw escaped form should not match")
+	}
+	if node.Get(`a\`).Exists() {
+		t.Error("prefix of escaped key should not match")
+	}
+
+	keys := node.Keys()
+	wantKeys := []string{`a"b`, "café", "tab\tkey", "😀", "plain", "nested/x"}
+	if len(keys) != len(wantKeys) {
+		t.Fatalf("Keys() = %q, want %q", keys, wantKeys)
+	}
+	for i, k := range wantKeys {
+		if keys[i] != k {
+			t.Errorf("Keys()[%d] = %q, want %q", i, keys[i], k)
+		}
+	}
+
+	var m map[string]int
+	if err := FromBytes([]byte(`{"x\ny":1}`)).Decode(&m); err != nil || m["x\ny"] != 1 {
+		t.Errorf("Decode map with escaped key: %v, %v", m, err)
+	}
+}
+
+// TestGetSegmentsAndPathSep 测试字面键路径与自定义路径分隔符
+func TestGetSegmentsAndPathSep(t *testing.T) {
+	node := FromString(`{"example.com":{"a.b":[{"c":1},{"c":2}],"":"empty"},"x":{"y":3}}`)
+
+	if v, _ := node.GetSegments("example.com", "a.b", "1", "c").Int(); v != 2 {
+		t.Errorf("GetSegments got %d, want 2", v)
+	}
+	if s, _ := node.GetSegments("example.com", "").String(); s != "empty" {
+		t.Errorf("GetSegments with empty key got %q", s)
+	}
+	if node.GetSegments("example.com", "a.b", "c").Exists() {
+		t.Error("non-numeric segment on array should not exist")
+	}
+	if node.GetSegments("x.y").Exists() {
+		t.Error("segments must not interpret dots")
+	}
+	if !node.GetSegments().Exists() {
+		t.Error("no segments should return the node itself")
+	}
+
+	if v, _ := node.GetPathSep("example.com/a.b[0]/c", '/').Int(); v != 1 {
+		t.Errorf("GetPathSep got %d, want 1", v)
+	}
+	if v, _ := node.GetPathSep("x/y", '/').Int(); v != 3 {
+		t.Errorf("GetPathSep got %d, want 3", v)
+	}
+	if node.GetPathSep("x[y", '[').Exists() {
+		t.Error("'[' must be rejected as separator")
+	}
+
+	results := node.GetMultipleSep('/', "x/y", "example.com/a.b[1]/c", "missing")
+	if v, _ := results[0].Int(); v != 3 {
+		t.Errorf("GetMultipleSep[0] got %d", v)
+	}
+	if v, _ := results[1].Int(); v != 2 {
+		t.Errorf("GetMultipleSep[1] got %d", v)
+	}
+	if results[2].Exists() {
+		t.Error("GetMultipleSep[2] should not exist")
+	}
+}
+
+// TestCursorPauseResume 测试游标暂停、序列化位置后恢复遍历
+func TestCursorPauseResume(t *testing.T) {
+	data := []byte(`{"users":[{"name":"Alice","tags":["a","b"]},{"name":"Bob","tags":[]}],"café":{"open":true},"count":2}`)
+	node := FromBytes(data)
+
+	var walked []string
+	node.Walk(func(path string, _ Node) bool {
+		walked = append(walked, path)
+		return true
+	})
+
+	cursor := node.NewCursor()
+	var visited []string
+	for i := 0; i < 5; i++ {
+		path, _, ok := cursor.Next()
+		if !ok {
+			t.Fatalf("cursor ended early at %d", i)
+		}
+		visited = append(visited, path)
+	}
+
+	saved, err := json.Marshal(cursor.State())
+	if err != nil {
+		t.Fatalf("marshal state failed: %v", err)
+	}
+
+	var state CursorState
+	if err := json.Unmarshal(saved, &state); err != nil {
+		t.Fatalf("unmarshal state failed: %v", err)
+	}
+	resumed, err := FromBytes(data).ResumeCursor(state)
+	if err != nil {
+		t.Fatalf("ResumeCursor failed: %v", err)
+	}
+	for {
+		path, _, ok := resumed.Next()
+		if !ok {
+			break
+		}
+		visited = append(visited, path)
+	}
+
+	if !reflect.DeepEqual(visited, walked) {
+		t.Errorf("resumed sequence mismatch:\n got %v\nwant %v", visited, walked)
+	}
+
+	// 初始状态恢复等价于从头遍历
+	fresh, err := node.ResumeCursor(node.NewCursor().State())
+	if err != nil {
+		t.Fatalf("ResumeCursor from initial state failed: %v", err)
+	}
+	if path, _, ok := fresh.Next(); !ok || path != "" {
+		t.Errorf("fresh cursor should start at root, got %q %v", path, ok)
+	}
+
+	// 文档变化后位置不再匹配
+	if _, err := FromBytes([]byte(`{"other":[1,2,3,4,5,6,7,8,9,10,11,12,13,14,15,16,17,18,19,20]}`)).ResumeCursor(state); err == nil {
+		t.Error("expected error when resuming against a different document")
+	}
+}
+
+// TestCursorSkipChildren 测试游标跳过子节点并在恢复后保持跳过
+func TestCursorSkipChildren(t *testing.T) {
+	data := []byte(`{"a":{"x":1,"y":2},"b":[1,2],"c":3}`)
+	node := FromBytes(data)
+
+	cursor := node.NewCursor()
+	var paths []string
+	for {
+		path, _, ok := cursor.Next()
+		if !ok {
+			break
+		}
+		paths = append(paths, path)
+		if path == "a" {
+			cursor.SkipChildren()
+		}
+	}
+	want := []string{"", "a", "b", "b[0]", "b[1]", "c"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("got %v, want %v", paths, want)
+	}
+
+	cursor = node.NewCursor()
+	cursor.Next()
+	cursor.Next()
+	cursor.SkipChildren()
+	resumed, err := node.ResumeCursor(cursor.State())
+	if err != nil {
+		t.Fatalf("ResumeCursor failed: %v", err)
+	}
+	if path, _, ok := resumed.Next(); !ok || path != "b" {
+		t.Errorf("expected to resume at b, got %q %v", path, ok)
+	}
+}
+
+// TestRenameKeys 测试按路径重命名对象键
+func TestRenameKeys(t *testing.T) {
+	doc := []byte(`{
+  "user": {"first_name": "Ada", "tags": ["x"]},
+  "ver": 1
+}`)
+
+	out, err := RenameKeys(doc, map[string]string{
+		"user.first_name": "firstName",
+		"user":            "account",
+		"ver":             "schema\"v",
+	})
+	if err != nil {
+		t.Fatalf("RenameKeys failed: %v", err)
+	}
+	want := `{
+  "account": {"firstName": "Ada", "tags": ["x"]},
+  "schema\"v": 1
+}`
+	if string(out) != want {
+		t.Errorf("got %s, want %s", out, want)
+	}
+	if got := FromBytes(out).Get("schema\"v").IntOr(0); got != 1 {
+		t.Errorf("renamed key lookup got %d", got)
+	}
+
+	// 交换键名不视为冲突
+	swapped, err := RenameKeys([]byte(`{"a":1,"b":2}`), map[string]string{"a": "b", "b": "a"})
+	if err != nil || string(swapped) != `{"b":1,"a":2}` {
+		t.Errorf("swap got %s, err %v", swapped, err)
+	}
+
+	if _, err := RenameKeys(doc, map[string]string{"ver": "user"}); err == nil {
+		t.Error("expected duplicate key error")
+	}
+	if _, err := RenameKeys(doc, map[string]string{"missing": "x"}); err == nil {
+		t.Error("expected error for missing path")
+	}
+	if _, err := RenameKeys(doc, map[string]string{"user.tags[0]": "x"}); err == nil {
+		t.Error("expected error for array element path")
+	}
+}
+
+// TestMovePath 测试在文档内移动字段
+func TestMovePath(t *testing.T) {
+	doc := []byte(`{
+  "name": "Ada",
+  "meta": {
+    "created": 1
+  },
+  "settings": {}
+}`)
+
+	out, err := MovePath(doc, "name", "meta.name")
+	if err != nil {
+		t.Fatalf("MovePath failed: %v", err)
+	}
+	want := `{
+  "meta": {
+    "created": 1,
+    "name": "Ada"
+  },
+  "settings": {}
+}`
+	if string(out) != want {
+		t.Errorf("got %s, want %s", out, want)
+	}
+
+	out, err = MovePath(out, "settings", "meta.settings")
+	if err != nil {
+		t.Fatalf("MovePath failed: %v", err)
+	}
+	node := FromBytes(out)
+	if !node.Get("meta.settings").IsObject() || node.Get("settings").Exists() {
+		t.Errorf("unexpected result %s", out)
+	}
+
+	out, err = MovePath([]byte(`{"a":{"b":1}}`), "a.b", "c")
+	if err != nil || string(out) != `{"a":{},"c":1}` {
+		t.Errorf("got %s, err %v", out, err)
+	}
+
+	if _, err := MovePath(doc, "meta", "meta.inner"); err == nil {
+		t.Error("expected error when moving into itself")
+	}
+	if _, err := MovePath(doc, "name", "meta"); err == nil {
+		t.Error("expected error when target exists")
+	}
+	if _, err := MovePath(doc, "missing", "x"); err == nil {
+		t.Error("expected error for missing source")
+	}
+	if _, err := MovePath(doc, "name", "nowhere.name"); err == nil {
+		t.Error("expected error for missing target parent")
+	}
+}
+
+// TestToOrderedMap 测试保持键顺序的对象视图
+func TestToOrderedMap(t *testing.T) {
+	node := FromBytes([]byte(`{"z":1, "a":{"x":[1, 2]}, "m":"s", "a":2}`))
+
+	m := node.ToOrderedMap()
+	if m.Len() != 4 {
+		t.Fatalf("Len = %d, want 4", m.Len())
+	}
+	if got := m.Keys(); !reflect.DeepEqual(got, []string{"z", "a", "m", "a"}) {
+		t.Errorf("Keys = %v", got)
+	}
+
+	// 重复键取最后一次出现的值，与 ToMap 一致
+	if v, ok := m.Get("a"); !ok || v.IntOr(0) != 2 {
+		t.Errorf("Get(a) = %s, %v", v.Raw(), ok)
+	}
+	if _, ok := m.Get("missing"); ok {
+		t.Error("Get(missing) should not exist")
+	}
+
+	var visited []string
+	m.Range(func(key string, _ Node) bool {
+		visited = append(visited, key)
+		return len(visited) < 2
+	})
+	if !reflect.DeepEqual(visited, []string{"z", "a"}) {
+		t.Errorf("Range visited %v", visited)
+	}
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("json.Marshal failed: %v", err)
+	}
+	if want := `{"z":1,"a":{"x":[1,2]},"m":"s","a":2}`; string(data) != want {
+		t.Errorf("MarshalJSON = %s, want %s", data, want)
+	}
+
+	if FromBytes([]byte(`[1]`)).ToOrderedMap() != nil {
+		t.Error("ToOrderedMap on array should return nil")
+	}
+	var empty *OrderedMap
+	if empty.Len() != 0 || empty.Keys() != nil {
+		t.Error("nil OrderedMap should be empty")
+	}
+}
+
+// TestParseFirst 测试逐个解析拼接的多文档数据
+func TestParseFirst(t *testing.T) {
+	stream := []byte(" {\"a\":1}{\"b\":[2]}\n[3] \"s\" 4 true null ")
+
+	var got []string
+	rest := stream
+	for len(rest) > 0 {
+		node, next, err := ParseFirst(rest)
+		if err != nil {
+			t.Fatalf("ParseFirst(%q) failed: %v", rest, err)
+		}
+		got = append(got, string(node.Raw()))
+		rest = next
+	}
+	want := []string{`{"a":1}`, `{"b":[2]}`, `[3]`, `"s"`, `4`, `true`, `null`}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("documents = %q, want %q", got, want)
+	}
+
+	// 尾随垃圾：值本身可用，rest 非空
+	node, rest, err := ParseFirst([]byte(`{"a":1} garbage`))
+	if err != nil || node.Get("a").IntOr(0) != 1 || string(rest) != "garbage" {
+		t.Errorf("trailing garbage: node=%s rest=%q err=%v", node.Raw(), rest, err)
+	}
+
+	// 解析出的节点与 FromBytes 行为一致（含嵌套 JSON 展开）
+	node, _, err = ParseFirst([]byte(`{"inner":"{\"x\":5}"}{}`))
+	if err != nil || node.GetPath("inner.x").IntOr(0) != 5 {
+		t.Errorf("nested expansion: node=%s err=%v", node.Raw(), err)
+	}
+
+	if _, _, err := ParseFirst([]byte("  \n")); err == nil {
+		t.Error("expected error for whitespace-only input")
+	}
+	if _, _, err := ParseFirst([]byte(`{"a":1`)); err == nil {
+		t.Error("expected error for incomplete object")
+	}
+	if _, _, err := ParseFirst([]byte(`}{}`)); err == nil {
+		t.Error("expected error for invalid leading value")
+	}
+}
+
+// TestLazyExpansion 测试延迟展开的按需计算、缓存与并发安全
+func TestLazyExpansion(t *testing.T) {
+	data := []byte(`{"payload":"{\"user\":{\"name\":\"bob\"},\"inner\":\"[1,2]\"}","plain":"text"}`)
+
+	lazy, err := ParseBytes(data, ParseOptions{LazyExpansion: true})
+	if err != nil {
+		t.Fatalf("ParseBytes failed: %v", err)
+	}
+	payload := lazy.Get("payload")
+	if payload.Kind() != TypeString {
+		t.Fatalf("lazy payload should stay a string, got %s", payload.Kind())
+	}
+	if lazy.GetPath("payload.user.name").Exists() {
+		t.Error("lazy node should not resolve into unexpanded strings")
+	}
+
+	// 并发展开同一节点，结果只计算一次
+	const workers = 16
+	results := make([]Node, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = lazy.Get("payload").Expand()
+		}(i)
+	}
+	wg.Wait()
+	for i, r := range results {
+		if r.GetPath("user.name").StringOr("") != "bob" {
+			t.Fatalf("worker %d: expanded payload = %s", i, r.Raw())
+		}
+		if &r.raw[0] != &results[0].raw[0] {
+			t.Fatalf("worker %d: expansion was not shared", i)
+		}
+	}
+
+	// 展开结果仍是延迟的，可逐层继续展开
+	inner := results[0].Get("inner")
+	if inner.Kind() != TypeString || inner.Expand().Index(1).IntOr(0) != 2 {
+		t.Errorf("nested Expand failed: %s", inner.Expand().Raw())
+	}
+	if got := lazy.Get("plain").Expand(); got.StringOr("") != "text" {
+		t.Errorf("non-JSON string should be returned unchanged, got %s", got.Raw())
+	}
+
+	// ExpandAll 与 FromBytes 结果一致
+	all, err := lazy.ExpandAll()
+	if err != nil {
+		t.Fatalf("ExpandAll failed: %v", err)
+	}
+	if want := FromBytes(data); string(all.Raw()) != string(want.Raw()) {
+		t.Errorf("ExpandAll = %s, want %s", all.Raw(), want.Raw())
+	}
+	again, _ := lazy.ExpandAll()
+	if &again.raw[0] != &all.raw[0] {
+		t.Error("ExpandAll result should be cached")
+	}
+
+	// 展开层数预算
+	limited, _ := ParseBytes(data, ParseOptions{LazyExpansion: true, MaxExpansionDepth: 1})
+	first := limited.Get("payload").Expand()
+	if first.Kind() != TypeObject || first.Get("inner").Expand().Kind() != TypeString {
+		t.Error("Expand should respect MaxExpansionDepth")
+	}
+}
+
+// TestNodeRoot 测试从任意节点回到文档根
+func TestNodeRoot(t *testing.T) {
+	data := []byte(` {"defs":{"user":{"type":"object"}},"items":[{"$ref":"defs.user"}],"nested":"{\"k\":1}"}
+`)
+	root := FromBytes(data)
+	if !root.IsRoot() {
+		t.Error("document root should report IsRoot")
+	}
+
+	ref := root.GetPath("items[0].$ref")
+	if ref.IsRoot() {
+		t.Error("child should not report IsRoot")
+	}
+	target := ref.Root().GetPath(ref.StringOr(""))
+	if got := target.Get("type").StringOr(""); got != "object" {
+		t.Errorf("resolved $ref type = %q, want object", got)
+	}
+
+	// 展开后的文档：根为展开后的数据
+	if got := root.GetPath("nested.k").Root().GetPath("nested.k").IntOr(0); got != 1 {
+		t.Errorf("root of expanded node = %d, want 1", got)
+	}
+
+	var missing Node
+	if missing.Root().Exists() || missing.IsRoot() {
+		t.Error("missing node should have no root")
+	}
+}
+
+// TestNodeParentAndSibling 测试父节点与兄弟节点导航
+func TestNodeParentAndSibling(t *testing.T) {
+	root := FromBytes([]byte(`{"a":{"x":1,"y":[10,20,30]},"b":true}`))
+
+	y1 := root.GetPath("a.y[1]")
+	if got := string(y1.Parent().Raw()); got != "[10,20,30]" {
+		t.Errorf("Parent of a.y[1] = %s", got)
+	}
+	if got := string(y1.Parent().Parent().Raw()); got != `{"x":1,"y":[10,20,30]}` {
+		t.Errorf("grandparent = %s", got)
+	}
+	if !y1.Parent().Parent().Parent().IsRoot() {
+		t.Error("great-grandparent should be the root")
+	}
+	if root.Parent().Exists() {
+		t.Error("root should have no parent")
+	}
+
+	if got := y1.NextSibling().IntOr(0); got != 30 {
+		t.Errorf("NextSibling of a.y[1] = %d, want 30", got)
+	}
+	if root.GetPath("a.y[2]").NextSibling().Exists() {
+		t.Error("last element should have no next sibling")
+	}
+	if got := root.Get("a").NextSibling().BoolOr(false); !got {
+		t.Error("NextSibling of a should be b")
+	}
+
+	// ForEach 得到的节点同样可以导航
+	var visited []string
+	root.Get("a").ForEach(func(key string, value Node) bool {
+		visited = append(visited, key+"->"+string(value.Parent().Get("x").Raw()))
+		return true
+	})
+	if !reflect.DeepEqual(visited, []string{"x->1", "y->1"}) {
+		t.Errorf("ForEach navigation = %v", visited)
+	}
+}
+
+// countingVisitor 统计每个容器内的标量数量，并记录回调顺序
+type countingVisitor struct {
+	BaseVisitor
+	events []string
+	counts *NodeTable[int]
+	open   []Node
+}
+
+func (v *countingVisitor) EnterObject(path string, node Node) bool {
+	v.events = append(v.events, "{"+path)
+	v.open = append(v.open, node)
+	return path != "skip"
+}
+
+func (v *countingVisitor) LeaveObject(path string, node Node) {
+	v.events = append(v.events, "}"+path)
+	v.open = v.open[:len(v.open)-1]
+}
+
+func (v *countingVisitor) EnterArray(path string, node Node) bool {
+	v.events = append(v.events, "["+path)
+	v.open = append(v.open, node)
+	return true
+}
+
+func (v *countingVisitor) LeaveArray(path string, node Node) {
+	v.events = append(v.events, "]"+path)
+	v.open = v.open[:len(v.open)-1]
+}
+
+func (v *countingVisitor) Value(path string, node Node) {
+	v.events = append(v.events, path)
+	for _, c := range v.open {
+		n, _ := v.counts.Get(c)
+		v.counts.Set(c, n+1)
+	}
+}
+
+// TestVisit 测试 Visitor 回调顺序与节点数据表
+func TestVisit(t *testing.T) {
+	root := FromBytes([]byte(`{"a":1,"b":[true,{"c":null}],"skip":{"x":1}}`))
+
+	v := &countingVisitor{counts: NewNodeTable[int]()}
+	root.Visit(v)
+
+	want := []string{"{", "a", "[b", "b[0]", "{b[1]", "b[1].c", "}b[1]", "]b", "{skip", "}"}
+	if !reflect.DeepEqual(v.events, want) {
+		t.Errorf("events = %v, want %v", v.events, want)
+	}
+
+	// 第二阶段：按收集到的数据读取
+	if n, _ := v.counts.Get(root); n != 3 {
+		t.Errorf("root scalar count = %d, want 3", n)
+	}
+	if n, _ := v.counts.Get(root.Get("b")); n != 2 {
+		t.Errorf("b scalar count = %d, want 2", n)
+	}
+	if _, ok := v.counts.Get(root.Get("skip")); ok {
+		t.Error("skipped object should have no entry")
+	}
+	if v.counts.Len() != 3 {
+		t.Errorf("table size = %d, want 3", v.counts.Len())
+	}
+	v.counts.Delete(root)
+	if _, ok := v.counts.Get(root); ok {
+		t.Error("Delete should remove the entry")
+	}
+
+	var scalars []string
+	FromBytes([]byte(`[1,"s"]`)).Visit(valueCollector{paths: &scalars})
+	if !reflect.DeepEqual(scalars, []string{"[0]", "[1]"}) {
+		t.Errorf("BaseVisitor embedding: %v", scalars)
+	}
+}
+
+// valueCollector 只实现 Value 的 Visitor
+type valueCollector struct {
+	BaseVisitor
+	paths *[]string
+}
+
+func (c valueCollector) Value(path string, _ Node) { *c.paths = append(*c.paths, path) }
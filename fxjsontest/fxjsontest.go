@@ -0,0 +1,82 @@
+// Package fxjsontest provides test helpers that assert fxjson's
+// zero-allocation guarantees, so users can pin them in their own CI
+// against their own documents. Property-based helpers live in fxtest.
+package fxjsontest
+
+import (
+	"testing"
+
+	"github.com/icloudza/fxjson"
+)
+
+// allocRuns 每项测量的运行次数
+const allocRuns = 100
+
+// AllocsPerOp 返回 fn 每次调用的平均堆分配次数
+func AllocsPerOp(fn func()) float64 {
+	return testing.AllocsPerRun(allocRuns, fn)
+}
+
+// AssertZeroAllocs 断言 fn 不产生堆分配
+func AssertZeroAllocs(tb testing.TB, name string, fn func()) {
+	tb.Helper()
+	if allocs := AllocsPerOp(fn); allocs != 0 {
+		tb.Errorf("%s: expected 0 allocs/op, got %.1f", name, allocs)
+	}
+}
+
+// AssertNodeZeroAllocs 对文档中的每个对象与数组断言 Get、GetPath、Index、Len、
+// ForEach 与 ArrayForEach 均为零分配；使用 fxjson_safe 构建标签时零分配不成立，断言会失败
+func AssertNodeZeroAllocs(tb testing.TB, root fxjson.Node) {
+	tb.Helper()
+	if !root.Exists() {
+		tb.Fatalf("node does not exist")
+	}
+
+	type container struct {
+		path string
+		node fxjson.Node
+	}
+	var containers []container
+	root.Walk(func(path string, node fxjson.Node) bool {
+		if node.IsObject() || node.IsArray() {
+			containers = append(containers, container{path, node})
+		}
+		return true
+	})
+
+	for _, c := range containers {
+		n := c.node
+		name := c.path
+		if name == "" {
+			name = "<root>"
+		}
+
+		if c.path != "" && root.GetPath(c.path).Exists() {
+			AssertZeroAllocs(tb, name+": GetPath", func() { _ = root.GetPath(c.path) })
+		}
+		AssertZeroAllocs(tb, name+": Len", func() { _ = n.Len() })
+
+		if n.IsObject() {
+			var firstKey string
+			n.ForEach(func(key string, _ fxjson.Node) bool {
+				firstKey = key
+				return false
+			})
+			if firstKey != "" {
+				AssertZeroAllocs(tb, name+": Get", func() { _ = n.Get(firstKey) })
+			}
+			AssertZeroAllocs(tb, name+": ForEach", func() {
+				n.ForEach(func(string, fxjson.Node) bool { return true })
+			})
+			continue
+		}
+
+		if length := n.Len(); length > 0 {
+			AssertZeroAllocs(tb, name+": Index", func() { _ = n.Index(length - 1) })
+		}
+		AssertZeroAllocs(tb, name+": ArrayForEach", func() {
+			n.ArrayForEach(func(int, fxjson.Node) bool { return true })
+		})
+	}
+}
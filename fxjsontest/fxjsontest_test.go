@@ -0,0 +1,24 @@
+package fxjsontest
+
+import "testing"
+
+// recordingTB 记录失败而不终止测试的 testing.TB
+type recordingTB struct {
+	testing.TB
+	failed bool
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(string, ...any) { r.failed = true }
+
+// TestAssertZeroAllocsReportsFailure 测试分配时断言失败
+func TestAssertZeroAllocsReportsFailure(t *testing.T) {
+	var sink []byte
+	tb := &recordingTB{TB: t}
+	AssertZeroAllocs(tb, "alloc", func() { sink = make([]byte, 64) })
+	if !tb.failed {
+		t.Error("expected AssertZeroAllocs to fail for an allocating function")
+	}
+	_ = sink
+}
@@ -0,0 +1,21 @@
+//go:build !fxjson_safe
+
+package fxjsontest
+
+import (
+	"testing"
+
+	"github.com/icloudza/fxjson"
+)
+
+// TestAssertNodeZeroAllocs 测试对整篇文档断言零分配
+func TestAssertNodeZeroAllocs(t *testing.T) {
+	docs := []string{
+		`{"user":{"name":"Alice","tags":["x","y"]},"list":[1,2,3],"empty":{}}`,
+		`{"café":{"k\"q":[{"a":1}]}}`,
+		`{"payload":"{\"nested\":{\"ids\":[1,2]}}"}`,
+	}
+	for _, doc := range docs {
+		AssertNodeZeroAllocs(t, fxjson.FromBytes([]byte(doc)))
+	}
+}
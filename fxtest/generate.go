@@ -0,0 +1,208 @@
+// Package fxtest provides property-based test helpers for code that
+// parses JSON with fxjson: seeded generators of valid and malformed JSON,
+// fuzz corpus replay and an input shrinker for minimizing failures.
+package fxtest
+
+import (
+	"encoding/json"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+// GeneratorOptions 控制生成文档的规模
+type GeneratorOptions struct {
+	MaxDepth     int // 最大嵌套深度，默认 8
+	MaxWidth     int // 对象键与数组元素的最大数量，默认 5
+	MaxStringLen int // 字符串的最大字符数（不含转义），默认 12
+}
+
+// Generator 可复现的随机 JSON 生成器，相同种子生成相同的序列
+// 生成的文档覆盖转义序列（含代理对）、深层嵌套与超出 float64/int64 范围的数字
+type Generator struct {
+	rng  *rand.Rand
+	opts GeneratorOptions
+}
+
+// NewGenerator 以 seed 创建生成器
+func NewGenerator(seed int64, opts GeneratorOptions) *Generator {
+	if opts.MaxDepth <= 0 {
+		opts.MaxDepth = 8
+	}
+	if opts.MaxWidth <= 0 {
+		opts.MaxWidth = 5
+	}
+	if opts.MaxStringLen <= 0 {
+		opts.MaxStringLen = 12
+	}
+	return &Generator{rng: rand.New(rand.NewSource(seed)), opts: opts}
+}
+
+// 生成数字与字符串时使用的特殊样本
+var (
+	edgeNumbers = []string{
+		"0", "-0", "1", "-1", "0.5", "1e308", "-1e308", "1e-400", "5e-324", "2.2250738585072014e-308",
+		"9007199254740993", "-9223372036854775808", "18446744073709551616",
+		"123456789012345678901234567890", "1.000000000000000000001", "1E+2", "3.14e-10",
+	}
+	edgeEscapes = []string{
+		`\"`, `\\`, `\/`, `\b`, `\f`, `\n`, `\r`, `\t`, `\u0000`, `\u001f`, `\u00e9`, `\u4e2d`,
+		`\ud83d\ude00`, `\ud834\udd1e`, `\uffff`,
+	}
+	plainRunes = []rune("abcXYZ019 _-.é中😀")
+)
+
+// Valid 生成一个合法的 JSON 文档
+func (g *Generator) Valid() []byte {
+	var sb strings.Builder
+	g.value(&sb, 0)
+	return []byte(sb.String())
+}
+
+// Deep 生成嵌套 depth 层的合法文档，对象与数组交替出现
+func (g *Generator) Deep(depth int) []byte {
+	var sb strings.Builder
+	for i := 0; i < depth; i++ {
+		if i%2 == 0 {
+			sb.WriteString(`{"k":`)
+		} else {
+			sb.WriteByte('[')
+		}
+	}
+	g.scalar(&sb)
+	for i := depth - 1; i >= 0; i-- {
+		if i%2 == 0 {
+			sb.WriteByte('}')
+		} else {
+			sb.WriteByte(']')
+		}
+	}
+	return []byte(sb.String())
+}
+
+// Invalid 对合法文档做一次随机破坏，生成不合法的 JSON（以 encoding/json 的判断为准）
+// 破坏方式包括截断、插入多余字符、删除括号、非法转义、尾随逗号与前导零等
+func (g *Generator) Invalid() []byte {
+	for {
+		doc := g.mutate(g.Valid())
+		if !json.Valid(doc) {
+			return doc
+		}
+	}
+}
+
+// mutate 对文档做一次随机破坏
+func (g *Generator) mutate(doc []byte) []byte {
+	pos := 0
+	if len(doc) > 0 {
+		pos = g.rng.Intn(len(doc))
+	}
+	insert := func(s string) []byte {
+		return append(append(append([]byte(nil), doc[:pos]...), s...), doc[pos:]...)
+	}
+	switch g.rng.Intn(8) {
+	case 0:
+		return append([]byte(nil), doc[:pos]...) // 截断
+	case 1:
+		return insert([]string{",", ":", "}", "]", "\"", "{", "["}[g.rng.Intn(7)])
+	case 2:
+		if i := strings.LastIndexAny(string(doc), "}]"); i >= 0 {
+			return append(append([]byte(nil), doc[:i]...), doc[i+1:]...) // 缺少结束括号
+		}
+	case 3:
+		return insert(`"\x"`) // 非法转义
+	case 4:
+		return insert(`"\ud800`) // 未闭合的字符串
+	case 5:
+		return []byte(`[` + string(doc) + `,]`) // 尾随逗号
+	case 6:
+		return []byte(`[01,` + string(doc) + `]`) // 前导零
+	case 7:
+		return insert(string([]byte{0x00, 0x1f}))
+	}
+	return insert("}")
+}
+
+// value 写入一个值，depth 达到上限后只写标量
+func (g *Generator) value(sb *strings.Builder, depth int) {
+	if depth >= g.opts.MaxDepth {
+		g.scalar(sb)
+		return
+	}
+	switch g.rng.Intn(4) {
+	case 0:
+		sb.WriteByte('{')
+		n := g.rng.Intn(g.opts.MaxWidth + 1)
+		seen := make(map[string]bool, n)
+		for i := 0; i < n; i++ {
+			key := g.string()
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			if len(seen) > 1 {
+				sb.WriteByte(',')
+			}
+			g.whitespace(sb)
+			sb.WriteString(key)
+			g.whitespace(sb)
+			sb.WriteByte(':')
+			g.value(sb, depth+1)
+		}
+		sb.WriteByte('}')
+	case 1:
+		sb.WriteByte('[')
+		n := g.rng.Intn(g.opts.MaxWidth + 1)
+		for i := 0; i < n; i++ {
+			if i > 0 {
+				sb.WriteByte(',')
+			}
+			g.whitespace(sb)
+			g.value(sb, depth+1)
+		}
+		sb.WriteByte(']')
+	default:
+		g.scalar(sb)
+	}
+}
+
+// scalar 写入一个标量值
+func (g *Generator) scalar(sb *strings.Builder) {
+	switch g.rng.Intn(6) {
+	case 0:
+		sb.WriteString(g.string())
+	case 1:
+		sb.WriteString(edgeNumbers[g.rng.Intn(len(edgeNumbers))])
+	case 2:
+		sb.WriteString(strconv.FormatFloat(g.rng.NormFloat64()*1e6, 'g', -1, 64))
+	case 3:
+		sb.WriteString(strconv.FormatInt(g.rng.Int63()-g.rng.Int63(), 10))
+	case 4:
+		sb.WriteString([]string{"true", "false"}[g.rng.Intn(2)])
+	default:
+		sb.WriteString("null")
+	}
+}
+
+// string 生成带引号的字符串，随机混入转义序列
+func (g *Generator) string() string {
+	var sb strings.Builder
+	sb.WriteByte('"')
+	n := g.rng.Intn(g.opts.MaxStringLen + 1)
+	for i := 0; i < n; i++ {
+		if g.rng.Intn(4) == 0 {
+			sb.WriteString(edgeEscapes[g.rng.Intn(len(edgeEscapes))])
+		} else {
+			sb.WriteRune(plainRunes[g.rng.Intn(len(plainRunes))])
+		}
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
+
+// whitespace 随机写入合法的空白
+func (g *Generator) whitespace(sb *strings.Builder) {
+	if g.rng.Intn(4) == 0 {
+		sb.WriteString([]string{" ", "\n", "\t", "\r\n  "}[g.rng.Intn(4)])
+	}
+}
@@ -0,0 +1,84 @@
+package fxtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/icloudza/fxjson"
+)
+
+// TestGenerator 测试生成器的合法性、可复现性与非法输入
+func TestGenerator(t *testing.T) {
+	a := NewGenerator(42, GeneratorOptions{})
+	b := NewGenerator(42, GeneratorOptions{})
+	for i := 0; i < 500; i++ {
+		doc := a.Valid()
+		if !json.Valid(doc) {
+			t.Fatalf("Valid() produced invalid JSON: %s", doc)
+		}
+		if !bytes.Equal(doc, b.Valid()) {
+			t.Fatalf("same seed produced different documents")
+		}
+		if !fxjson.ValidateJSON(doc) {
+			t.Errorf("fxjson rejected valid document: %s", doc)
+		}
+	}
+
+	for i := 0; i < 500; i++ {
+		if doc := a.Invalid(); json.Valid(doc) {
+			t.Fatalf("Invalid() produced valid JSON: %s", doc)
+		}
+	}
+
+	deep := a.Deep(1000)
+	if !json.Valid(deep) {
+		t.Fatalf("Deep() produced invalid JSON")
+	}
+}
+
+// TestShrink 测试最小化失败输入
+func TestShrink(t *testing.T) {
+	input := []byte(`{"a":[1,2,3],"bad":"\x","z":true}`)
+	failing := func(data []byte) bool { return bytes.Contains(data, []byte(`\x`)) }
+	got := Shrink(input, failing)
+	if string(got) != `\x` {
+		t.Errorf("Shrink = %q, want %q", got, `\x`)
+	}
+	if string(input) != `{"a":[1,2,3],"bad":"\x","z":true}` {
+		t.Errorf("Shrink modified its input")
+	}
+
+	// 对原始输入不失败时原样返回
+	if got := Shrink([]byte("abc"), func([]byte) bool { return false }); string(got) != "abc" {
+		t.Errorf("Shrink of passing input = %q", got)
+	}
+}
+
+// TestReplayCorpus 测试原始文件与 go 模糊测试语料格式
+func TestReplayCorpus(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.json": `{"a":1}`,
+		"b":      "go test fuzz v1\n[]byte(\"[1,\\\"x\\\"]\")\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var got []string
+	ReplayCorpus(t, dir, func(t *testing.T, data []byte) {
+		got = append(got, string(data))
+	})
+	if len(got) != 2 || got[0] != `{"a":1}` || got[1] != `[1,"x"]` {
+		t.Errorf("replayed %q", got)
+	}
+
+	if _, err := parseCorpusFile([]byte("go test fuzz v1\nstring(\"x\")\n")); err == nil {
+		t.Errorf("expected error for non-[]byte corpus entry")
+	}
+}
@@ -0,0 +1,124 @@
+package fxtest
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// maxShrinkSteps Shrink 调用 failing 的次数上限
+const maxShrinkSteps = 10000
+
+// Shrink 在保持 failing(data) 为 true 的前提下尽量缩小输入，返回最小化后的副本
+// 先按块删除（delta debugging），再逐字节删除；failing 对原始输入应返回 true，否则原样返回副本
+func Shrink(data []byte, failing func([]byte) bool) []byte {
+	cur := append([]byte(nil), data...)
+	steps := 0
+	try := func(candidate []byte) bool {
+		if steps >= maxShrinkSteps {
+			return false
+		}
+		steps++
+		return failing(candidate)
+	}
+	if !try(cur) {
+		return cur
+	}
+
+	for chunk := len(cur) / 2; chunk >= 1 && steps < maxShrinkSteps; {
+		removed := false
+		for start := 0; start+chunk <= len(cur); {
+			candidate := make([]byte, 0, len(cur)-chunk)
+			candidate = append(append(candidate, cur[:start]...), cur[start+chunk:]...)
+			if try(candidate) {
+				cur = candidate
+				removed = true
+				continue // 同一位置继续尝试
+			}
+			start += chunk
+		}
+		if !removed {
+			chunk /= 2
+		} else if chunk > len(cur)/2 {
+			chunk = max(len(cur)/2, 1)
+		}
+		if len(cur) == 0 {
+			break
+		}
+	}
+	return cur
+}
+
+// fuzzCorpusHeader go test 模糊测试语料文件的首行
+const fuzzCorpusHeader = "go test fuzz v1"
+
+// ReplayCorpus 对 dir 下的每个文件运行一个子测试（按文件名排序），fn 接收文件内容
+// 支持原始 JSON 文件与 go 模糊测试语料格式（testdata/fuzz/FuzzXxx 下的 []byte("...") 条目）
+// 目录不存在时跳过测试
+func ReplayCorpus(t *testing.T, dir string, fn func(t *testing.T, data []byte)) {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		t.Skipf("corpus directory %s does not exist", dir)
+	}
+	if err != nil {
+		t.Fatalf("read corpus: %v", err)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		raw, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			t.Fatalf("read corpus file %s: %v", entry.Name(), err)
+		}
+		data, err := parseCorpusFile(raw)
+		if err != nil {
+			t.Fatalf("corpus file %s: %v", entry.Name(), err)
+		}
+		t.Run(entry.Name(), func(t *testing.T) {
+			fn(t, data)
+		})
+	}
+}
+
+// parseCorpusFile 解析语料文件；非 go 模糊测试格式时原样返回
+func parseCorpusFile(raw []byte) ([]byte, error) {
+	if !bytes.HasPrefix(raw, []byte(fuzzCorpusHeader)) {
+		return raw, nil
+	}
+	for _, line := range strings.Split(string(raw), "\n")[1:] {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "[]byte(") || !strings.HasSuffix(line, ")") {
+			return nil, &corpusError{line: line}
+		}
+		s, err := strconv.Unquote(line[len("[]byte(") : len(line)-1])
+		if err != nil {
+			return nil, &corpusError{line: line}
+		}
+		return []byte(s), nil
+	}
+	return nil, &corpusError{}
+}
+
+// corpusError 语料文件中没有可用的 []byte 条目
+type corpusError struct {
+	line string
+}
+
+// Error 实现 error 接口
+func (e *corpusError) Error() string {
+	if e.line == "" {
+		return "fuzz corpus file has no []byte entry"
+	}
+	return "unsupported fuzz corpus entry " + strconv.Quote(e.line) + ", expected []byte(...)"
+}
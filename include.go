@@ -0,0 +1,291 @@
+package fxjson
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// IncludeOptions ResolveIncludes 的选项
+type IncludeOptions struct {
+	IncludeKey string // 引用指令的键，默认 "$include"
+	MergeKey   string // 合并指令的键，默认 "$merge"
+	MaxDepth   int    // 引用嵌套的最大层数，默认 16
+	MaxBytes   int    // 被引用文档与最终结果展开后的最大字节数，默认 64MB
+}
+
+// DefaultIncludeOptions 默认的引用与合并指令选项
+var DefaultIncludeOptions = IncludeOptions{IncludeKey: "$include", MergeKey: "$merge", MaxDepth: 16, MaxBytes: 64 << 20}
+
+// ResolveIncludes 展开配置文档中的引用与合并指令，返回合并后的单个节点
+//
+// 对象中的指令：
+//
+//	{"$include": "base.json"}                    替换为 resolve("base.json") 返回的文档（递归展开）
+//	{"$include": ["a.json", "b.json"], "k": 1}   按顺序深度合并各文档，再合并对象自身的其余字段
+//	{"$merge": [{...}, {"$include": "c.json"}]}  按顺序深度合并数组中的值，再合并对象自身的其余字段
+//
+// 深度合并时对象按键递归合并，其他类型由后者覆盖；同时使用两种指令时先合并 $include 再合并 $merge。
+// 引用形成环、嵌套超过 MaxDepth 或展开结果超过 MaxBytes 时返回错误，错误信息包含引用链与出错的路径。
+// 同名文档只加载并展开一次，菱形引用（多处引用同一文档）不会重复解析。
+// 内容为 JSON 的字符串值保持为字符串，不会展开后参与合并
+func ResolveIncludes(doc []byte, resolve func(name string) ([]byte, error), opts IncludeOptions) (Node, error) {
+	if opts.IncludeKey == "" {
+		opts.IncludeKey = DefaultIncludeOptions.IncludeKey
+	}
+	if opts.MergeKey == "" {
+		opts.MergeKey = DefaultIncludeOptions.MergeKey
+	}
+	if opts.MaxDepth <= 0 {
+		opts.MaxDepth = DefaultIncludeOptions.MaxDepth
+	}
+	if opts.MaxBytes <= 0 {
+		opts.MaxBytes = DefaultIncludeOptions.MaxBytes
+	}
+
+	root, err := parseUnexpanded(doc)
+	if err != nil {
+		return Node{}, err
+	}
+	e := &includeExpander{resolve: resolve, opts: opts, done: make(map[string]expandedInclude)}
+	expanded, err := e.expand(root, "")
+	if err != nil {
+		return Node{}, err
+	}
+	if len(expanded) > opts.MaxBytes {
+		return Node{}, fmt.Errorf("expanded document exceeds limit of %d bytes", opts.MaxBytes)
+	}
+	return parseUnexpanded(expanded)
+}
+
+// includeExpander 展开指令的状态，chain 为当前的引用链，done 缓存已展开的文档
+type includeExpander struct {
+	resolve func(name string) ([]byte, error)
+	opts    IncludeOptions
+	chain   []string
+	done    map[string]expandedInclude
+	height  int // 当前文档内已展开引用的最大嵌套层数
+}
+
+// expandedInclude 已展开的被引用文档，height 为其自身占用的引用层数（含自身）
+type expandedInclude struct {
+	data   []byte
+	height int
+}
+
+// expand 返回展开指令后的紧凑 JSON
+func (e *includeExpander) expand(n Node, path string) ([]byte, error) {
+	switch n.typ {
+	case 'a':
+		out := []byte{'['}
+		var err error
+		n.ArrayForEach(func(i int, item Node) bool {
+			var v []byte
+			if v, err = e.expand(item, path+"["+strconv.Itoa(i)+"]"); err != nil {
+				return false
+			}
+			if i > 0 {
+				out = append(out, ',')
+			}
+			out = append(out, v...)
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+		return append(out, ']'), nil
+	case 'o':
+		return e.expandObject(n, path)
+	default:
+		return n.Raw(), nil
+	}
+}
+
+// expandObject 展开对象的字段与指令
+func (e *includeExpander) expandObject(n Node, path string) ([]byte, error) {
+	var includes, merges Node
+	own := []byte{'{'}
+	fields := 0
+	var err error
+	n.ForEach(func(key string, value Node) bool {
+		switch key {
+		case e.opts.IncludeKey:
+			includes = value
+		case e.opts.MergeKey:
+			merges = value
+		default:
+			var v []byte
+			if v, err = e.expand(value, joinIncludePath(path, key)); err != nil {
+				return false
+			}
+			if fields > 0 {
+				own = append(own, ',')
+			}
+			own = append(append(append(own, quoteKey(key)...), ':'), v...)
+			fields++
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	own = append(own, '}')
+
+	var base []byte
+	if includes.Exists() {
+		var names []string
+		switch includes.typ {
+		case 's':
+			name, _ := includes.String()
+			names = []string{name}
+		case 'a':
+			includes.ArrayForEach(func(_ int, item Node) bool {
+				name, strErr := item.String()
+				if strErr != nil {
+					err = fmt.Errorf("%s: %s must be a string or an array of strings", displayIncludePath(path), e.opts.IncludeKey)
+					return false
+				}
+				names = append(names, name)
+				return true
+			})
+		default:
+			err = fmt.Errorf("%s: %s must be a string or an array of strings", displayIncludePath(path), e.opts.IncludeKey)
+		}
+		for _, name := range names {
+			if err != nil {
+				break
+			}
+			var v []byte
+			if v, err = e.include(name, path); err == nil {
+				base = mergeJSONValues(base, v)
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if merges.Exists() {
+		if merges.typ != 'a' {
+			return nil, fmt.Errorf("%s: %s must be an array", displayIncludePath(path), e.opts.MergeKey)
+		}
+		merges.ArrayForEach(func(i int, item Node) bool {
+			var v []byte
+			if v, err = e.expand(item, joinIncludePath(path, e.opts.MergeKey)+"["+strconv.Itoa(i)+"]"); err != nil {
+				return false
+			}
+			base = mergeJSONValues(base, v)
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case base == nil:
+		return own, nil
+	case fields == 0:
+		return base, nil
+	case parseRootNode(base).typ != 'o':
+		return nil, fmt.Errorf("%s: cannot merge fields into included %s", displayIncludePath(path), parseRootNode(base).Kind())
+	}
+	return mergeJSONValues(base, own), nil
+}
+
+// include 加载并展开被引用的文档
+func (e *includeExpander) include(name, path string) ([]byte, error) {
+	for i, c := range e.chain {
+		if c == name {
+			cycle := append(append([]string(nil), e.chain[i:]...), name)
+			return nil, fmt.Errorf("%s: include cycle %s", displayIncludePath(path), strings.Join(cycle, " -> "))
+		}
+	}
+	// 已展开的文档直接复用；展开成功说明其引用闭包中没有环，只需按其层数重新检查深度
+	if d, ok := e.done[name]; ok {
+		if len(e.chain)+d.height > e.opts.MaxDepth {
+			return nil, fmt.Errorf("%s: include depth exceeds limit %d", displayIncludePath(path), e.opts.MaxDepth)
+		}
+		e.height = max(e.height, d.height)
+		return d.data, nil
+	}
+	if len(e.chain) >= e.opts.MaxDepth {
+		return nil, fmt.Errorf("%s: include depth exceeds limit %d", displayIncludePath(path), e.opts.MaxDepth)
+	}
+
+	data, err := e.resolve(name)
+	if err != nil {
+		return nil, fmt.Errorf("%s: include %q: %w", displayIncludePath(path), name, err)
+	}
+	node, err := parseUnexpanded(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: include %q: %w", displayIncludePath(path), name, err)
+	}
+
+	outer := e.height
+	e.chain, e.height = append(e.chain, name), 0
+	v, err := e.expand(node, "")
+	e.chain = e.chain[:len(e.chain)-1]
+	if err != nil {
+		return nil, fmt.Errorf("include %q: %w", name, err)
+	}
+	if len(v) > e.opts.MaxBytes {
+		return nil, fmt.Errorf("%s: include %q expands to more than %d bytes", displayIncludePath(path), name, e.opts.MaxBytes)
+	}
+	d := expandedInclude{data: v, height: e.height + 1}
+	e.done[name] = d
+	e.height = max(outer, d.height)
+	return v, nil
+}
+
+// mergeJSONValues 深度合并两个紧凑 JSON 值：都是对象时按键递归合并（保留 a 的键顺序，新键追加在后），否则返回 b
+func mergeJSONValues(a, b []byte) []byte {
+	if a == nil {
+		return b
+	}
+	an, bn := parseRootNode(a), parseRootNode(b)
+	if an.typ != 'o' || bn.typ != 'o' {
+		return b
+	}
+
+	out := []byte{'{'}
+	seen := make(map[string]bool)
+	add := func(key string, value []byte) {
+		if len(out) > 1 {
+			out = append(out, ',')
+		}
+		out = append(append(append(out, quoteKey(key)...), ':'), value...)
+	}
+	an.ForEach(func(key string, value Node) bool {
+		seen[key] = true
+		if other := bn.GetSegments(key); other.Exists() {
+			add(key, mergeJSONValues(value.Raw(), other.Raw()))
+		} else {
+			add(key, value.Raw())
+		}
+		return true
+	})
+	bn.ForEach(func(key string, value Node) bool {
+		if !seen[key] {
+			add(key, value.Raw())
+		}
+		return true
+	})
+	return append(out, '}')
+}
+
+// joinIncludePath 拼接错误信息中的路径
+func joinIncludePath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// displayIncludePath 错误信息中显示的路径
+func displayIncludePath(path string) string {
+	if path == "" {
+		return "root"
+	}
+	return "path " + strconv.Quote(path)
+}
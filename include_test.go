@@ -0,0 +1,146 @@
+package fxjson
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// mapResolver 从内存中的文件表加载引用
+func mapResolver(files map[string]string) func(string) ([]byte, error) {
+	return func(name string) ([]byte, error) {
+		data, ok := files[name]
+		if !ok {
+			return nil, errors.New("file not found")
+		}
+		return []byte(data), nil
+	}
+}
+
+// TestResolveIncludes 测试引用与合并指令的展开
+func TestResolveIncludes(t *testing.T) {
+	files := map[string]string{
+		"base.json":   `{"server":{"host":"0.0.0.0","port":80},"log":{"level":"info"}}`,
+		"prod.json":   `{"$include":"base.json","server":{"port":443}}`,
+		"tls.json":    `{"cert":"/etc/cert.pem"}`,
+		"names.json":  `["a","b"]`,
+		"nested.json": `{"server":{"tls":{"$include":"tls.json"}}}`,
+	}
+
+	doc := []byte(`{
+  "$include": ["prod.json", "nested.json"],
+  "$merge": [{"log":{"level":"warn"}}, {"log":{"format":"json"}}],
+  "users": {"$include": "names.json"},
+  "server": {"port": 8443}
+}`)
+	node, err := ResolveIncludes(doc, mapResolver(files), IncludeOptions{})
+	if err != nil {
+		t.Fatalf("ResolveIncludes failed: %v", err)
+	}
+
+	want := `{"server":{"host":"0.0.0.0","port":8443,"tls":{"cert":"/etc/cert.pem"}},"log":{"level":"warn","format":"json"},"users":["a","b"]}`
+	if string(node.Raw()) != want {
+		t.Errorf("merged = %s\nwant     %s", node.Raw(), want)
+	}
+
+	// 没有指令时原样返回（去除空白）
+	plain, err := ResolveIncludes([]byte(`{"a": [1, {"b": 2}]}`), mapResolver(nil), DefaultIncludeOptions)
+	if err != nil || string(plain.Raw()) != `{"a":[1,{"b":2}]}` {
+		t.Errorf("plain = %s, %v", plain.Raw(), err)
+	}
+
+	// 内容为 JSON 的字符串值保持为字符串，不与对象合并
+	files["payload.json"] = `{"payload":"{\"a\":1}","list":"[1,2]"}`
+	strs, err := ResolveIncludes([]byte(`{"$include":"payload.json","payload":"{\"b\":2}","note":"{}"}`), mapResolver(files), IncludeOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"payload":"{\"b\":2}","list":"[1,2]","note":"{}"}`; string(strs.Raw()) != want {
+		t.Errorf("string values = %s\nwant           %s", strs.Raw(), want)
+	}
+	if s, err := strs.Get("list").String(); err != nil || s != "[1,2]" {
+		t.Errorf("list = %q, %v", s, err)
+	}
+}
+
+// TestResolveIncludesErrors 测试环、深度限制与格式错误
+func TestResolveIncludesErrors(t *testing.T) {
+	files := map[string]string{
+		"a.json":      `{"x":{"$include":"b.json"}}`,
+		"b.json":      `{"$include":"a.json"}`,
+		"array.json":  `[1]`,
+		"broken.json": `{"a":`,
+	}
+	chain := map[string]string{}
+	for i := 0; i < 20; i++ {
+		chain["c"+string(rune('a'+i))] = `{"$include":"c` + string(rune('a'+i+1)) + `"}`
+	}
+
+	cases := []struct {
+		doc   string
+		files map[string]string
+		want  string
+	}{
+		{`{"$include":"a.json"}`, files, `include cycle a.json -> b.json -> a.json`},
+		{`{"$include":"missing.json"}`, files, `root: include "missing.json": file not found`},
+		{`{"cfg":{"$include":"broken.json"}}`, files, `path "cfg": include "broken.json"`},
+		{`{"$include":"array.json","k":1}`, files, "cannot merge fields into included array"},
+		{`{"$include":1}`, files, "must be a string or an array of strings"},
+		{`{"a":{"$merge":{}}}`, files, `path "a": $merge must be an array`},
+		{`{"$include":"ca"}`, chain, "include depth exceeds limit 16"},
+	}
+	for _, c := range cases {
+		_, err := ResolveIncludes([]byte(c.doc), mapResolver(c.files), IncludeOptions{})
+		if err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Errorf("ResolveIncludes(%s) error = %v, want %q", c.doc, err, c.want)
+		}
+	}
+
+	// 自定义指令键
+	opts := IncludeOptions{IncludeKey: "@import"}
+	node, err := ResolveIncludes([]byte(`{"@import":"x","$include":"kept"}`), mapResolver(map[string]string{"x": `{"v":1}`}), opts)
+	if err != nil || string(node.Raw()) != `{"v":1,"$include":"kept"}` {
+		t.Errorf("custom key = %s, %v", node.Raw(), err)
+	}
+}
+
+// TestResolveIncludesDiamond 测试菱形引用只加载一次，且复用时仍检查深度与大小限制
+func TestResolveIncludesDiamond(t *testing.T) {
+	// d0 引用两次 d1，d1 引用两次 d2……逐层展开时解析次数随层数指数增长
+	files := map[string]string{"d12": `{"leaf":1}`}
+	for i := 0; i < 12; i++ {
+		files["d"+strconv.Itoa(i)] = `{"l":{"$include":"d` + strconv.Itoa(i+1) + `"},"r":{"$include":"d` + strconv.Itoa(i+1) + `"}}`
+	}
+	calls := 0
+	resolve := func(name string) ([]byte, error) {
+		calls++
+		return mapResolver(files)(name)
+	}
+	node, err := ResolveIncludes([]byte(`{"$include":"d0"}`), resolve, IncludeOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 13 {
+		t.Errorf("resolve called %d times, want 13", calls)
+	}
+	if v, _ := node.GetPath("l.r.l.r.l.r.l.r.l.r.l.r.leaf").Int(); v != 1 {
+		t.Errorf("leaf = %d, want 1", v)
+	}
+
+	// 先在浅处展开 d10（共 3 层），再在深处复用时仍超出 MaxDepth
+	doc := `{"a":{"$include":"d10"},"b":{"$include":"d0"}}`
+	if _, err := ResolveIncludes([]byte(doc), mapResolver(files), IncludeOptions{MaxDepth: 5}); err == nil || !strings.Contains(err.Error(), "include depth exceeds limit 5") {
+		t.Errorf("reused include depth error = %v", err)
+	}
+
+	// 展开结果超过 MaxBytes
+	_, err = ResolveIncludes([]byte(`{"$include":"d0"}`), mapResolver(files), IncludeOptions{MaxBytes: 1 << 10})
+	if err == nil || !strings.Contains(err.Error(), "expands to more than 1024 bytes") {
+		t.Errorf("MaxBytes error = %v", err)
+	}
+	_, err = ResolveIncludes([]byte(`[{"$include":"d7"},{"$include":"d7"},{"$include":"d7"}]`), mapResolver(files), IncludeOptions{MaxBytes: 1 << 10})
+	if err == nil || !strings.Contains(err.Error(), "expanded document exceeds limit of 1024 bytes") {
+		t.Errorf("MaxBytes error = %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+// Package apicheck lists the exported API surface of a package directory so
+// that tests can pin it against a golden file, in the spirit of Go's api/
+// checks: removing or changing an exported identifier in a facade package
+// fails the build until the golden file is updated deliberately.
+package apicheck
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Surface 返回目录中包（不含测试文件）的导出声明，每行一个，已排序：
+//
+//	func From(n fxjson.Node) *Builder
+//	type Builder = fxjson.QueryBuilder
+//	method (*Builder) Run() error
+func Surface(dir string) ([]string, error) {
+	fset := token.NewFileSet()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		for _, decl := range file.Decls {
+			declLines, err := declSurface(fset, decl)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			lines = append(lines, declLines...)
+		}
+	}
+	sort.Strings(lines)
+	return lines, nil
+}
+
+// declSurface 返回单个声明中的导出部分
+func declSurface(fset *token.FileSet, decl ast.Decl) ([]string, error) {
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		if !d.Name.IsExported() {
+			return nil, nil
+		}
+		sig, err := render(fset, d.Type)
+		if err != nil {
+			return nil, err
+		}
+		sig = strings.TrimPrefix(sig, "func")
+		if d.Recv == nil {
+			return []string{"func " + d.Name.Name + sig}, nil
+		}
+		recv, err := render(fset, d.Recv.List[0].Type)
+		if err != nil {
+			return nil, err
+		}
+		return []string{"method (" + recv + ") " + d.Name.Name + sig}, nil
+
+	case *ast.GenDecl:
+		var lines []string
+		for _, spec := range d.Specs {
+			switch s := spec.(type) {
+			case *ast.TypeSpec:
+				if !s.Name.IsExported() {
+					continue
+				}
+				typ, err := render(fset, s.Type)
+				if err != nil {
+					return nil, err
+				}
+				sep := " "
+				if s.Assign.IsValid() {
+					sep = " = "
+				}
+				lines = append(lines, "type "+s.Name.Name+sep+typ)
+			case *ast.ValueSpec:
+				kind := "var"
+				if d.Tok == token.CONST {
+					kind = "const"
+				}
+				for _, name := range s.Names {
+					if name.IsExported() {
+						lines = append(lines, kind+" "+name.Name)
+					}
+				}
+			}
+		}
+		return lines, nil
+	}
+	return nil, nil
+}
+
+// render 将语法节点格式化为单行文本
+func render(fset *token.FileSet, node ast.Node) (string, error) {
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, fset, node); err != nil {
+		return "", err
+	}
+	return strings.Join(strings.Fields(buf.String()), " "), nil
+}
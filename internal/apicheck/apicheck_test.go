@@ -0,0 +1,41 @@
+package apicheck
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden API files")
+
+// checkedPackages 导出接口受检查的子包（相对仓库根目录）
+var checkedPackages = []string{"query", "validate", "debugx", "cache"}
+
+// TestSurface 子包的导出接口必须与 testdata 中的记录一致，
+// 有意修改接口时使用 go test ./internal/apicheck -update 更新记录
+func TestSurface(t *testing.T) {
+	for _, pkg := range checkedPackages {
+		lines, err := Surface(filepath.Join("..", "..", pkg))
+		if err != nil {
+			t.Fatalf("%s: %v", pkg, err)
+		}
+		got := strings.Join(lines, "\n") + "\n"
+		golden := filepath.Join("testdata", pkg+".txt")
+
+		if *update {
+			if err := os.WriteFile(golden, []byte(got), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		want, err := os.ReadFile(golden)
+		if err != nil {
+			t.Fatalf("%s: %v (run with -update to create it)", pkg, err)
+		}
+		if got != string(want) {
+			t.Errorf("%s: exported API changed (run with -update if intended)\ngot:\n%swant:\n%s", pkg, got, want)
+		}
+	}
+}
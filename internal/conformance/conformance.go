@@ -0,0 +1,345 @@
+// Package conformance cross-checks fxjson against encoding/json.
+//
+// Each case is run through three checks: whether the document is accepted
+// (parse), what Decode into any produces (decode), and what Marshal emits
+// for the encoding/json decoding of the document (marshal). Case names
+// follow JSONTestSuite conventions: y_ files must be accepted, n_ files must
+// be rejected, and i_ files may go either way; any other name is expected
+// to match json.Valid. RunDir can be pointed at a checkout of
+// JSONTestSuite's test_parsing directory.
+package conformance
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/icloudza/fxjson"
+)
+
+// Check 检查项
+type Check string
+
+const (
+	CheckParse   Check = "parse"   // 是否接受文档
+	CheckDecode  Check = "decode"  // Decode 到 any 的结果
+	CheckMarshal Check = "marshal" // Marshal 输出的语义
+)
+
+// Case 一个测试用例
+type Case struct {
+	Name string
+	Data []byte
+}
+
+// Divergence 一处与 encoding/json（或 JSONTestSuite 预期）不一致的结果
+type Divergence struct {
+	Case   string
+	Check  Check
+	Expect string
+	Got    string
+}
+
+// String 返回可读的差异描述
+func (d Divergence) String() string {
+	return fmt.Sprintf("%s [%s]: expected %s, got %s", d.Case, d.Check, d.Expect, d.Got)
+}
+
+// ConformanceReport 一次运行的汇总结果
+type ConformanceReport struct {
+	Total       int          // 用例数
+	Checks      int          // 执行的检查项数
+	Divergences []Divergence // 按用例名与检查项排序
+}
+
+// OK 是否没有任何差异
+func (r *ConformanceReport) OK() bool {
+	return len(r.Divergences) == 0
+}
+
+// ByCheck 返回指定检查项的差异
+func (r *ConformanceReport) ByCheck(check Check) []Divergence {
+	var out []Divergence
+	for _, d := range r.Divergences {
+		if d.Check == check {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// Find 返回指定用例的差异
+func (r *ConformanceReport) Find(name string) []Divergence {
+	var out []Divergence
+	for _, d := range r.Divergences {
+		if d.Case == name {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// String 返回汇总与逐条差异
+func (r *ConformanceReport) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d cases, %d checks, %d divergences\n", r.Total, r.Checks, len(r.Divergences))
+	for _, d := range r.Divergences {
+		sb.WriteString("  ")
+		sb.WriteString(d.String())
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// LoadDir 读取目录下所有 .json 文件作为用例
+func LoadDir(dir string) ([]Case, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var cases []Case
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".json" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		cases = append(cases, Case{Name: e.Name(), Data: data})
+	}
+	return cases, nil
+}
+
+// RunDir 对目录下的所有 .json 文件运行检查
+func RunDir(dir string) (*ConformanceReport, error) {
+	cases, err := LoadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	return Run(cases), nil
+}
+
+// Run 对用例运行全部检查
+func Run(cases []Case) *ConformanceReport {
+	report := &ConformanceReport{Total: len(cases)}
+	for _, c := range cases {
+		runCase(report, c)
+	}
+
+	sort.SliceStable(report.Divergences, func(i, j int) bool {
+		a, b := report.Divergences[i], report.Divergences[j]
+		if a.Case != b.Case {
+			return a.Case < b.Case
+		}
+		return a.Check < b.Check
+	})
+	return report
+}
+
+// runCase 运行单个用例的检查
+func runCase(report *ConformanceReport, c Case) {
+	valid := json.Valid(c.Data)
+
+	report.Checks++
+	checkParse(report, c, valid)
+
+	// 解码与编码只比较双方都应接受的文档
+	if !valid {
+		return
+	}
+	report.Checks += 2
+	checkDecode(report, c)
+	checkMarshal(report, c)
+}
+
+// checkParse 检查是否接受文档
+func checkParse(report *ConformanceReport, c Case, valid bool) {
+	var expect bool
+	switch {
+	case strings.HasPrefix(c.Name, "y_"):
+		expect = true
+	case strings.HasPrefix(c.Name, "n_"):
+		expect = false
+	case strings.HasPrefix(c.Name, "i_"):
+		return // 实现自定义，不视为差异
+	default:
+		expect = valid
+	}
+
+	accepted, err := protect(func() error {
+		_, err := fxjson.ParseBytes(c.Data, fxjson.ParseOptions{StrictMode: true})
+		return err
+	})
+	if accepted != expect {
+		report.add(c.Name, CheckParse, acceptance(expect), acceptance(accepted)+errorSuffix(err))
+	}
+}
+
+// checkDecode 比较 Decode 到 any 的结果
+func checkDecode(report *ConformanceReport, c Case) {
+	dec := json.NewDecoder(bytes.NewReader(c.Data))
+	dec.UseNumber()
+	var want any
+	if err := dec.Decode(&want); err != nil {
+		return
+	}
+
+	var got any
+	ok, err := protect(func() error {
+		return fxjson.FromBytes(c.Data).Decode(&got)
+	})
+	if !ok {
+		report.add(c.Name, CheckDecode, describe(want), "error: "+err.Error())
+		return
+	}
+	if !equivalent(want, got) {
+		report.add(c.Name, CheckDecode, describe(want), describe(got))
+	}
+}
+
+// checkMarshal 比较 Marshal 输出的语义：同一个 Go 值分别由两者编码后再由 encoding/json 解析比较
+func checkMarshal(report *ConformanceReport, c Case) {
+	var value any
+	if err := json.Unmarshal(c.Data, &value); err != nil {
+		return
+	}
+
+	want, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+
+	var got []byte
+	ok, err := protect(func() error {
+		var err error
+		got, err = fxjson.Marshal(value)
+		return err
+	})
+	if !ok {
+		report.add(c.Name, CheckMarshal, string(want), "error: "+err.Error())
+		return
+	}
+
+	wantValue, _ := decodeNumbers(want)
+	gotValue, err := decodeNumbers(got)
+	if err != nil || !equivalent(wantValue, gotValue) {
+		report.add(c.Name, CheckMarshal, string(want), string(got))
+	}
+}
+
+// add 记录一处差异
+func (r *ConformanceReport) add(name string, check Check, expect, got string) {
+	r.Divergences = append(r.Divergences, Divergence{Case: name, Check: check, Expect: expect, Got: got})
+}
+
+// protect 运行 fn 并把 panic 转为错误，返回是否成功
+func protect(fn func() error) (ok bool, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			ok, err = false, fmt.Errorf("panic: %v", r)
+		}
+	}()
+	err = fn()
+	return err == nil, err
+}
+
+// decodeNumbers 使用 json.Number 解析 JSON
+func decodeNumbers(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	err := dec.Decode(&v)
+	return v, err
+}
+
+// equivalent 比较 encoding/json（json.Number 形式）与 fxjson 的解码结果
+// 数字按数值比较：fxjson 将整数解码为 int64、其余解码为 float64
+func equivalent(want, got any) bool {
+	switch w := want.(type) {
+	case json.Number:
+		return numberEquivalent(w, got)
+	case map[string]any:
+		g, ok := got.(map[string]any)
+		if !ok || len(g) != len(w) {
+			return false
+		}
+		for k, wv := range w {
+			gv, ok := g[k]
+			if !ok || !equivalent(wv, gv) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		g, ok := got.([]any)
+		if !ok || len(g) != len(w) {
+			return false
+		}
+		for i := range w {
+			if !equivalent(w[i], g[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(want, got)
+	}
+}
+
+// numberEquivalent 比较数字
+func numberEquivalent(want json.Number, got any) bool {
+	switch g := got.(type) {
+	case json.Number:
+		if g == want {
+			return true
+		}
+		gf, err := g.Float64()
+		if err != nil {
+			return false
+		}
+		return numberEquivalent(want, gf)
+	case int64:
+		if i, err := want.Int64(); err == nil {
+			return i == g
+		}
+		return false
+	case float64:
+		f, err := strconv.ParseFloat(want.String(), 64)
+		if err != nil && !math.IsInf(f, 0) {
+			return false
+		}
+		return f == g || (math.IsNaN(f) && math.IsNaN(g))
+	default:
+		return false
+	}
+}
+
+// acceptance 返回接受状态的描述
+func acceptance(accepted bool) string {
+	if accepted {
+		return "accept"
+	}
+	return "reject"
+}
+
+// errorSuffix 返回附加的错误信息
+func errorSuffix(err error) string {
+	if err == nil {
+		return ""
+	}
+	return " (" + err.Error() + ")"
+}
+
+// describe 返回值的可读形式（含 Go 类型，便于定位数字语义差异）
+func describe(v any) string {
+	return fmt.Sprintf("%#v", v)
+}
@@ -0,0 +1,97 @@
+package conformance
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// loadKnownDivergences 读取已知差异列表
+func loadKnownDivergences(t *testing.T) map[string]bool {
+	t.Helper()
+	f, err := os.Open(filepath.Join("testdata", "known_divergences.txt"))
+	if err != nil {
+		t.Fatalf("open known divergences: %v", err)
+	}
+	defer f.Close()
+
+	known := make(map[string]bool)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		known[line] = true
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("read known divergences: %v", err)
+	}
+	return known
+}
+
+// TestCorpus 对内置语料运行检查，差异必须与已知列表一致
+func TestCorpus(t *testing.T) {
+	report, err := RunDir("testdata")
+	if err != nil {
+		t.Fatalf("RunDir failed: %v", err)
+	}
+	if report.Total == 0 {
+		t.Fatal("empty corpus")
+	}
+
+	known := loadKnownDivergences(t)
+	for _, d := range report.Divergences {
+		key := d.Case + " " + string(d.Check)
+		if !known[key] {
+			t.Errorf("new divergence: %s", d)
+		}
+		delete(known, key)
+	}
+	for key := range known {
+		t.Errorf("known divergence %q no longer occurs; remove it from known_divergences.txt", key)
+	}
+}
+
+// TestRunReport 测试报告的汇总与筛选
+func TestRunReport(t *testing.T) {
+	report := Run([]Case{
+		{Name: "y_ok.json", Data: []byte(`{"a":[1,2.5,"x",true,null]}`)},
+		{Name: "n_trailing_garbage.json", Data: []byte(`{"a":1}}`)},
+		{Name: "plain.json", Data: []byte(`[1,2]`)},
+	})
+
+	if report.Total != 3 {
+		t.Errorf("Total = %d, want 3", report.Total)
+	}
+	if report.Checks != 7 {
+		t.Errorf("Checks = %d, want 7", report.Checks)
+	}
+	if len(report.Find("y_ok.json")) != 0 || len(report.Find("plain.json")) != 0 {
+		t.Errorf("unexpected divergences:\n%s", report)
+	}
+	for _, d := range report.ByCheck(CheckDecode) {
+		t.Errorf("unexpected decode divergence: %s", d)
+	}
+	if report.OK() != (len(report.Divergences) == 0) {
+		t.Error("OK should reflect divergences")
+	}
+	if !strings.HasPrefix(report.String(), "3 cases, 7 checks") {
+		t.Errorf("unexpected summary %q", report.String())
+	}
+}
+
+// TestJSONTestSuite 对完整的 JSONTestSuite 运行检查（设置 FXJSON_JSONTESTSUITE 为 test_parsing 目录）
+func TestJSONTestSuite(t *testing.T) {
+	dir := os.Getenv("FXJSON_JSONTESTSUITE")
+	if dir == "" {
+		t.Skip("FXJSON_JSONTESTSUITE not set")
+	}
+	report, err := RunDir(dir)
+	if err != nil {
+		t.Fatalf("RunDir failed: %v", err)
+	}
+	t.Log(report)
+}
@@ -0,0 +1,86 @@
+package fxjson
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Interpolate 替换字符串值中的 ${VAR} 占位符，用于加载配置时注入环境变量或密钥
+//
+// 占位符规则：
+//
+//	${VAR}           lookup 返回的值，变量不存在时返回错误
+//	${VAR:-default}  变量不存在或为空时使用 default
+//	$${VAR}          转义，输出字面量 ${VAR}
+//
+// 只处理字符串值，不处理对象键；替换在解码后的文本上进行，结果重新编码为 JSON 字符串，
+// 因此变量值中的引号、反斜杠与换行会被正确转义。不含占位符的字符串与文档其余部分原样保留
+func Interpolate(doc []byte, lookup func(key string) (string, bool)) ([]byte, error) {
+	root := parseRootNode(doc)
+	if !root.Exists() {
+		return nil, fmt.Errorf("invalid JSON data")
+	}
+
+	var edits []valueEdit
+	var scratch []byte
+	var walkErr error
+	root.Walk(func(path string, node Node) bool {
+		if node.typ != 's' {
+			return true
+		}
+		raw := doc[node.start+1 : node.end-1]
+		if !strings.Contains(string(raw), "${") {
+			return true
+		}
+		scratch = appendUnescaped(scratch[:0], raw)
+		text, err := interpolateString(string(scratch), lookup)
+		if err != nil {
+			walkErr = fmt.Errorf("path %q: %w", path, err)
+			return false
+		}
+		edits = append(edits, valueEdit{path: path, start: node.start, end: node.end, value: quoteKey(text)})
+		return true
+	})
+	if walkErr != nil {
+		return nil, walkErr
+	}
+	return applyValueEdits(doc, edits), nil
+}
+
+// interpolateString 替换单个字符串中的占位符
+func interpolateString(s string, lookup func(key string) (string, bool)) (string, error) {
+	var sb strings.Builder
+	sb.Grow(len(s))
+	for i := 0; i < len(s); {
+		if strings.HasPrefix(s[i:], "$${") {
+			sb.WriteString("${")
+			i += 3
+			continue
+		}
+		if !strings.HasPrefix(s[i:], "${") {
+			sb.WriteByte(s[i])
+			i++
+			continue
+		}
+
+		closeIdx := strings.IndexByte(s[i+2:], '}')
+		if closeIdx < 0 {
+			return "", fmt.Errorf("unclosed placeholder at offset %d", i)
+		}
+		expr := s[i+2 : i+2+closeIdx]
+		name, def, hasDefault := strings.Cut(expr, ":-")
+		if name == "" {
+			return "", fmt.Errorf("empty placeholder at offset %d", i)
+		}
+		value, ok := lookup(name)
+		switch {
+		case hasDefault && (!ok || value == ""):
+			value = def
+		case !ok:
+			return "", fmt.Errorf("undefined variable %q", name)
+		}
+		sb.WriteString(value)
+		i += 2 + closeIdx + 1
+	}
+	return sb.String(), nil
+}
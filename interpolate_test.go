@@ -0,0 +1,71 @@
+package fxjson
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestInterpolate 测试字符串值中的占位符替换
+func TestInterpolate(t *testing.T) {
+	env := map[string]string{
+		"HOST":  "db.local",
+		"PASS":  `p"a\ss`,
+		"EMPTY": "",
+	}
+	lookup := func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+
+	doc := []byte(`{
+  "dsn": "postgres://${HOST}:5432",
+  "password": "${PASS}",
+  "${HOST}": "keys are untouched",
+  "list": ["${MISSING:-fallback}", "${EMPTY:-default}", "$${HOST}", 1],
+  "plain": "aA"
+}`)
+	got, err := Interpolate(doc, lookup)
+	if err != nil {
+		t.Fatalf("Interpolate failed: %v", err)
+	}
+
+	node := FromBytes(got)
+	checks := map[string]string{
+		"dsn":      "postgres://db.local:5432",
+		"password": `p"a\ss`,
+		"${HOST}":  "keys are untouched",
+		"list[0]":  "fallback",
+		"list[1]":  "default",
+		"list[2]":  "${HOST}",
+	}
+	for path, want := range checks {
+		var v string
+		if strings.HasPrefix(path, "$") {
+			v, _ = node.Get(path).String()
+		} else {
+			v, _ = node.GetPath(path).String()
+		}
+		if v != want {
+			t.Errorf("%s = %q, want %q", path, v, want)
+		}
+	}
+	if !strings.Contains(string(got), `"plain": "aA"`) {
+		t.Errorf("strings without placeholders should be kept verbatim: %s", got)
+	}
+}
+
+// TestInterpolateErrors 测试未定义变量与格式错误的占位符
+func TestInterpolateErrors(t *testing.T) {
+	lookup := func(string) (string, bool) { return "", false }
+	cases := map[string]string{
+		`{"a":{"b":"${NOPE}"}}`: `path "a.b": undefined variable "NOPE"`,
+		`["x ${OPEN"]`:          "unclosed placeholder",
+		`{"a":"${}"}`:           "empty placeholder",
+		`{"a":`:                 "invalid JSON",
+	}
+	for doc, want := range cases {
+		if _, err := Interpolate([]byte(doc), lookup); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Interpolate(%s) error = %v, want %q", doc, err, want)
+		}
+	}
+}
@@ -0,0 +1,1814 @@
+package fxjson
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// JQProgram 编译后的 jq 程序，可在多个节点上重复执行，并发安全
+//
+// 支持的 jq 子集：
+//   - 路径：. .foo ."key" .[n] .[expr] .[m:n] .[] .. 以及后缀 ?
+//   - 组合：| , // 括号
+//   - 运算：+ - * / % == != < <= > >= and or，一元负号
+//   - 构造：字面量、[...]、{...}（含 {name} 简写与 (expr) 键）、字符串插值 "\(expr)"
+//   - 条件：if ... then ... elif ... else ... end
+//   - 函数：map select has join split startswith endswith sort_by length keys add
+//     tostring tonumber tojson type not empty first last reverse sort unique min max
+//     ascii_downcase ascii_upcase to_entries from_entries
+//
+// 选取的值直接引用输入文档（零拷贝），运算与构造产生的值使用新的小缓冲区。
+type JQProgram struct {
+	src  string
+	root jqExpr
+}
+
+// CompileJQ 编译 jq 程序，括号、数组、对象等嵌套超过 1000 层时返回错误
+//
+// Deprecated: 新代码请使用 query.CompileJQ，该函数仅为兼容保留
+func CompileJQ(prog string) (_ *JQProgram, err error) {
+	defer recoverPanic(&err, "CompileJQ", Node{})
+
+	root, err := parseJQ(prog, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &JQProgram{src: prog, root: root}, nil
+}
+
+// Run 在节点上执行程序，返回全部输出
+func (p *JQProgram) Run(n Node) (out []Node, err error) {
+	if done := slowOpStart(); done != nil {
+		defer func() {
+			done(SlowOp{Op: OpJQ, Path: p.src, InputBytes: n.end - n.start, OutputBytes: nodesBytes(out), Err: err})
+		}()
+	}
+	defer recoverPanic(&err, "JQProgram.Run", n)
+
+	if !n.Exists() {
+		n = jqNull
+	}
+	return p.root.eval(n)
+}
+
+// String 返回程序源码
+func (p *JQProgram) String() string {
+	return p.src
+}
+
+// ===== 值构造 =====
+
+var (
+	jqNull  = parseRootNode([]byte("null"))
+	jqTrue  = parseRootNode([]byte("true"))
+	jqFalse = parseRootNode([]byte("false"))
+)
+
+// jqBool 返回布尔节点
+func jqBool(b bool) Node {
+	if b {
+		return jqTrue
+	}
+	return jqFalse
+}
+
+// jqNumber 返回数字节点
+func jqNumber(f float64) Node {
+	return parseRootNode(AppendNumber(nil, f, 64))
+}
+
+// jqString 返回字符串节点
+func jqString(s string) Node {
+	return parseRootNode(quoteKey(s))
+}
+
+// jqArray 由元素构造数组节点
+func jqArray(items []Node) Node {
+	buf := []byte{'['}
+	for i, item := range items {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = append(buf, item.Raw()...)
+	}
+	return parseRootNode(append(buf, ']'))
+}
+
+// jqObject 由键值对构造对象节点，重复的键保留首次出现的位置与最后一次的值
+func jqObject(pairs []KeyValue) Node {
+	index := make(map[string]int, len(pairs))
+	merged := make([]KeyValue, 0, len(pairs))
+	for _, kv := range pairs {
+		if i, ok := index[kv.Key]; ok {
+			merged[i].Value = kv.Value
+			continue
+		}
+		index[kv.Key] = len(merged)
+		merged = append(merged, kv)
+	}
+
+	buf := []byte{'{'}
+	for i, kv := range merged {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = append(buf, quoteKey(kv.Key)...)
+		buf = append(buf, ':')
+		buf = append(buf, kv.Value.Raw()...)
+	}
+	return parseRootNode(append(buf, '}'))
+}
+
+// jqTruthy 除 false 与 null 外均为真
+func jqTruthy(n Node) bool {
+	switch n.typ {
+	case 'l':
+		return false
+	case 'b':
+		b, _ := n.Bool()
+		return b
+	default:
+		return true
+	}
+}
+
+// jqTypeName 返回 jq 的类型名
+func jqTypeName(n Node) string {
+	switch n.typ {
+	case 'o':
+		return "object"
+	case 'a':
+		return "array"
+	case 's':
+		return "string"
+	case 'n':
+		return "number"
+	case 'b':
+		return "boolean"
+	default:
+		return "null"
+	}
+}
+
+// jqStr 返回字符串节点的内容
+func jqStr(n Node) string {
+	s, _ := n.String()
+	return s
+}
+
+// jqNum 返回数字节点的值
+func jqNum(n Node) float64 {
+	f, _ := n.Float()
+	return f
+}
+
+// jqToString 字符串原样返回，其他值返回紧凑 JSON
+func jqToString(n Node) string {
+	if n.typ == 's' {
+		return jqStr(n)
+	}
+	return string(CompactJSON(n.Raw()))
+}
+
+// jqElements 返回数组元素
+func jqElements(n Node) []Node {
+	var items []Node
+	n.ArrayForEach(func(_ int, v Node) bool {
+		items = append(items, v)
+		return true
+	})
+	return items
+}
+
+// jqEntries 返回对象的键值对（按原始顺序）
+func jqEntries(n Node) []KeyValue {
+	var pairs []KeyValue
+	n.ForEach(func(key string, v Node) bool {
+		pairs = append(pairs, KeyValue{Key: key, Value: v})
+		return true
+	})
+	return pairs
+}
+
+// jqRank 返回 jq 排序中的类型次序：null < false < true < 数字 < 字符串 < 数组 < 对象
+func jqRank(n Node) int {
+	switch n.typ {
+	case 'l':
+		return 0
+	case 'b':
+		if jqTruthy(n) {
+			return 2
+		}
+		return 1
+	case 'n':
+		return 3
+	case 's':
+		return 4
+	case 'a':
+		return 5
+	default:
+		return 6
+	}
+}
+
+// jqCompare 按 jq 的规则比较两个值
+func jqCompare(a, b Node) int {
+	ra, rb := jqRank(a), jqRank(b)
+	if ra != rb {
+		return ra - rb
+	}
+
+	switch a.typ {
+	case 'n':
+		fa, fb := jqNum(a), jqNum(b)
+		switch {
+		case fa < fb:
+			return -1
+		case fa > fb:
+			return 1
+		}
+		return 0
+	case 's':
+		return strings.Compare(jqStr(a), jqStr(b))
+	case 'a':
+		ea, eb := jqElements(a), jqElements(b)
+		for i := 0; i < len(ea) && i < len(eb); i++ {
+			if c := jqCompare(ea[i], eb[i]); c != 0 {
+				return c
+			}
+		}
+		return len(ea) - len(eb)
+	case 'o':
+		// 先比较排序后的键集合，再按键顺序比较值
+		ka, kb := jqSortedKeys(a), jqSortedKeys(b)
+		for i := 0; i < len(ka) && i < len(kb); i++ {
+			if c := strings.Compare(ka[i], kb[i]); c != 0 {
+				return c
+			}
+		}
+		if len(ka) != len(kb) {
+			return len(ka) - len(kb)
+		}
+		for _, k := range ka {
+			if c := jqCompare(a.GetSegments(k), b.GetSegments(k)); c != 0 {
+				return c
+			}
+		}
+		return 0
+	default:
+		return 0
+	}
+}
+
+// jqSortedKeys 返回排序后的对象键
+func jqSortedKeys(n Node) []string {
+	keys := n.Keys()
+	sort.Strings(keys)
+	return keys
+}
+
+// jqSort 按 jq 规则稳定排序
+func jqSort(items []Node) {
+	sort.SliceStable(items, func(i, j int) bool { return jqCompare(items[i], items[j]) < 0 })
+}
+
+// ===== 表达式 =====
+
+// jqExpr jq 表达式，eval 返回对输入的全部输出
+type jqExpr interface {
+	eval(in Node) ([]Node, error)
+}
+
+type (
+	jqIdentity struct{}
+	jqRecurse  struct{}
+	jqLiteral  struct{ value Node }
+	jqIndex    struct{ target, index jqExpr }
+	jqSlice    struct{ target, from, to jqExpr }
+	jqIterate  struct{ target jqExpr }
+	jqOptional struct{ inner jqExpr }
+	jqPipe     struct{ left, right jqExpr }
+	jqComma    struct{ left, right jqExpr }
+	jqAlt      struct{ left, right jqExpr }
+	jqNeg      struct{ inner jqExpr }
+	jqArrayOf  struct{ inner jqExpr }
+	jqIf       struct{ cond, then, els jqExpr }
+	jqBinary   struct {
+		op          string
+		left, right jqExpr
+	}
+	jqObjectOf struct{ entries []jqObjectEntry }
+	jqInterp   struct{ parts []jqStringPart }
+	jqCall     struct {
+		name string
+		args []jqExpr
+	}
+)
+
+// jqObjectEntry 对象构造中的一个键值
+type jqObjectEntry struct {
+	key, value jqExpr
+}
+
+// jqStringPart 字符串插值的一段：字面量或表达式
+type jqStringPart struct {
+	lit  string
+	expr jqExpr
+}
+
+func (jqIdentity) eval(in Node) ([]Node, error) { return []Node{in}, nil }
+
+func (jqRecurse) eval(in Node) ([]Node, error) {
+	var out []Node
+	stack := []Node{in}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		out = append(out, n)
+
+		var children []Node
+		switch n.typ {
+		case 'a':
+			children = jqElements(n)
+		case 'o':
+			for _, kv := range jqEntries(n) {
+				children = append(children, kv.Value)
+			}
+		}
+		for i := len(children) - 1; i >= 0; i-- {
+			stack = append(stack, children[i])
+		}
+	}
+	return out, nil
+}
+
+func (e jqLiteral) eval(Node) ([]Node, error) { return []Node{e.value}, nil }
+
+func (e jqIndex) eval(in Node) ([]Node, error) {
+	targets, err := e.target.eval(in)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := e.index.eval(in)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []Node
+	for _, t := range targets {
+		for _, k := range keys {
+			v, err := jqIndexValue(t, k)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, v)
+		}
+	}
+	return out, nil
+}
+
+// jqIndexValue 对象按键、数组按下标取值，null 上取值得到 null
+func jqIndexValue(t, k Node) (Node, error) {
+	switch {
+	case t.typ == 'l':
+		return jqNull, nil
+	case t.typ == 'o' && k.typ == 's':
+		if v := t.GetSegments(jqStr(k)); v.Exists() {
+			return v, nil
+		}
+		return jqNull, nil
+	case t.typ == 'a' && k.typ == 'n':
+		idx := int(math.Floor(jqNum(k)))
+		if idx < 0 {
+			idx += t.Len()
+		}
+		if v := t.Index(idx); v.Exists() {
+			return v, nil
+		}
+		return jqNull, nil
+	default:
+		return Node{}, fmt.Errorf("jq: cannot index %s with %s", jqTypeName(t), jqTypeName(k))
+	}
+}
+
+func (e jqSlice) eval(in Node) ([]Node, error) {
+	targets, err := e.target.eval(in)
+	if err != nil {
+		return nil, err
+	}
+	froms, tos := []Node{jqNull}, []Node{jqNull}
+	if e.from != nil {
+		if froms, err = e.from.eval(in); err != nil {
+			return nil, err
+		}
+	}
+	if e.to != nil {
+		if tos, err = e.to.eval(in); err != nil {
+			return nil, err
+		}
+	}
+
+	var out []Node
+	for _, t := range targets {
+		for _, to := range tos {
+			for _, from := range froms {
+				v, err := jqSliceValue(t, from, to)
+				if err != nil {
+					return nil, err
+				}
+				out = append(out, v)
+			}
+		}
+	}
+	return out, nil
+}
+
+// jqSliceValue 截取数组或字符串，下标可为负数
+func jqSliceValue(t, from, to Node) (Node, error) {
+	var length int
+	switch t.typ {
+	case 'l':
+		return jqNull, nil
+	case 'a':
+		length = t.Len()
+	case 's':
+		length = utf8.RuneCountInString(jqStr(t))
+	default:
+		return Node{}, fmt.Errorf("jq: cannot slice %s", jqTypeName(t))
+	}
+
+	bound := func(n Node, def int) (int, error) {
+		switch n.typ {
+		case 'l':
+			return def, nil
+		case 'n':
+			i := int(math.Floor(jqNum(n)))
+			if i < 0 {
+				i += length
+			}
+			return min(max(i, 0), length), nil
+		default:
+			return 0, fmt.Errorf("jq: slice indices must be numbers, got %s", jqTypeName(n))
+		}
+	}
+	start, err := bound(from, 0)
+	if err != nil {
+		return Node{}, err
+	}
+	end, err := bound(to, length)
+	if err != nil {
+		return Node{}, err
+	}
+	end = max(end, start)
+
+	if t.typ == 'a' {
+		return jqArray(jqElements(t)[start:end]), nil
+	}
+	runes := []rune(jqStr(t))
+	return jqString(string(runes[start:end])), nil
+}
+
+func (e jqIterate) eval(in Node) ([]Node, error) {
+	targets, err := e.target.eval(in)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []Node
+	for _, t := range targets {
+		switch t.typ {
+		case 'a':
+			out = append(out, jqElements(t)...)
+		case 'o':
+			for _, kv := range jqEntries(t) {
+				out = append(out, kv.Value)
+			}
+		default:
+			return nil, fmt.Errorf("jq: cannot iterate over %s", jqTypeName(t))
+		}
+	}
+	return out, nil
+}
+
+func (e jqOptional) eval(in Node) ([]Node, error) {
+	out, err := e.inner.eval(in)
+	if err != nil {
+		return nil, nil
+	}
+	return out, nil
+}
+
+func (e jqPipe) eval(in Node) ([]Node, error) {
+	lefts, err := e.left.eval(in)
+	if err != nil {
+		return nil, err
+	}
+	var out []Node
+	for _, l := range lefts {
+		rights, err := e.right.eval(l)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, rights...)
+	}
+	return out, nil
+}
+
+func (e jqComma) eval(in Node) ([]Node, error) {
+	lefts, err := e.left.eval(in)
+	if err != nil {
+		return nil, err
+	}
+	rights, err := e.right.eval(in)
+	if err != nil {
+		return nil, err
+	}
+	return append(lefts, rights...), nil
+}
+
+func (e jqAlt) eval(in Node) ([]Node, error) {
+	lefts, _ := e.left.eval(in)
+	var out []Node
+	for _, l := range lefts {
+		if jqTruthy(l) {
+			out = append(out, l)
+		}
+	}
+	if len(out) > 0 {
+		return out, nil
+	}
+	return e.right.eval(in)
+}
+
+func (e jqNeg) eval(in Node) ([]Node, error) {
+	vals, err := e.inner.eval(in)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Node, 0, len(vals))
+	for _, v := range vals {
+		if v.typ != 'n' {
+			return nil, fmt.Errorf("jq: cannot negate %s", jqTypeName(v))
+		}
+		out = append(out, jqNumber(-jqNum(v)))
+	}
+	return out, nil
+}
+
+func (e jqArrayOf) eval(in Node) ([]Node, error) {
+	if e.inner == nil {
+		return []Node{jqArray(nil)}, nil
+	}
+	items, err := e.inner.eval(in)
+	if err != nil {
+		return nil, err
+	}
+	return []Node{jqArray(items)}, nil
+}
+
+func (e jqIf) eval(in Node) ([]Node, error) {
+	conds, err := e.cond.eval(in)
+	if err != nil {
+		return nil, err
+	}
+	var out []Node
+	for _, c := range conds {
+		branch := e.els
+		if jqTruthy(c) {
+			branch = e.then
+		}
+		if branch == nil {
+			out = append(out, in)
+			continue
+		}
+		vals, err := branch.eval(in)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, vals...)
+	}
+	return out, nil
+}
+
+func (e jqBinary) eval(in Node) ([]Node, error) {
+	if e.op == "and" || e.op == "or" {
+		return e.evalLogic(in)
+	}
+
+	rights, err := e.right.eval(in)
+	if err != nil {
+		return nil, err
+	}
+	lefts, err := e.left.eval(in)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []Node
+	for _, r := range rights {
+		for _, l := range lefts {
+			v, err := jqApply(e.op, l, r)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, v)
+		}
+	}
+	return out, nil
+}
+
+// evalLogic 短路求值 and/or
+func (e jqBinary) evalLogic(in Node) ([]Node, error) {
+	lefts, err := e.left.eval(in)
+	if err != nil {
+		return nil, err
+	}
+	var out []Node
+	for _, l := range lefts {
+		lt := jqTruthy(l)
+		if (e.op == "and" && !lt) || (e.op == "or" && lt) {
+			out = append(out, jqBool(lt))
+			continue
+		}
+		rights, err := e.right.eval(in)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range rights {
+			out = append(out, jqBool(jqTruthy(r)))
+		}
+	}
+	return out, nil
+}
+
+// jqApply 计算二元运算
+func jqApply(op string, l, r Node) (Node, error) {
+	switch op {
+	case "==":
+		return jqBool(jqCompare(l, r) == 0), nil
+	case "!=":
+		return jqBool(jqCompare(l, r) != 0), nil
+	case "<":
+		return jqBool(jqCompare(l, r) < 0), nil
+	case "<=":
+		return jqBool(jqCompare(l, r) <= 0), nil
+	case ">":
+		return jqBool(jqCompare(l, r) > 0), nil
+	case ">=":
+		return jqBool(jqCompare(l, r) >= 0), nil
+	case "+":
+		return jqAdd(l, r)
+	}
+
+	switch {
+	case l.typ == 'n' && r.typ == 'n':
+		a, b := jqNum(l), jqNum(r)
+		switch op {
+		case "-":
+			return jqNumber(a - b), nil
+		case "*":
+			return jqNumber(a * b), nil
+		case "/":
+			if b == 0 {
+				return Node{}, fmt.Errorf("jq: %s and %s cannot be divided because the divisor is zero", l.Raw(), r.Raw())
+			}
+			return jqNumber(a / b), nil
+		case "%":
+			ai, bi := int64(a), int64(b)
+			if bi == 0 {
+				return Node{}, fmt.Errorf("jq: %s and %s cannot be divided because the divisor is zero", l.Raw(), r.Raw())
+			}
+			return jqNumber(float64(ai % bi)), nil
+		}
+	case op == "-" && l.typ == 'a' && r.typ == 'a':
+		remove := jqElements(r)
+		var kept []Node
+		for _, item := range jqElements(l) {
+			found := false
+			for _, x := range remove {
+				if jqCompare(item, x) == 0 {
+					found = true
+					break
+				}
+			}
+			if !found {
+				kept = append(kept, item)
+			}
+		}
+		return jqArray(kept), nil
+	case op == "/" && l.typ == 's' && r.typ == 's':
+		return jqSplit(jqStr(l), jqStr(r)), nil
+	}
+	return Node{}, fmt.Errorf("jq: %s (%s) and %s (%s) cannot be combined with %q", jqTypeName(l), l.Raw(), jqTypeName(r), r.Raw(), op)
+}
+
+// jqAdd 加法：数字相加、字符串与数组拼接、对象合并，null 为单位元
+func jqAdd(l, r Node) (Node, error) {
+	switch {
+	case l.typ == 'l':
+		return r, nil
+	case r.typ == 'l':
+		return l, nil
+	case l.typ == 'n' && r.typ == 'n':
+		return jqNumber(jqNum(l) + jqNum(r)), nil
+	case l.typ == 's' && r.typ == 's':
+		return jqString(jqStr(l) + jqStr(r)), nil
+	case l.typ == 'a' && r.typ == 'a':
+		return jqArray(append(jqElements(l), jqElements(r)...)), nil
+	case l.typ == 'o' && r.typ == 'o':
+		return jqObject(append(jqEntries(l), jqEntries(r)...)), nil
+	}
+	return Node{}, fmt.Errorf("jq: %s (%s) and %s (%s) cannot be added", jqTypeName(l), l.Raw(), jqTypeName(r), r.Raw())
+}
+
+// jqSplit 按分隔符拆分字符串为数组
+func jqSplit(s, sep string) Node {
+	var items []Node
+	if s != "" {
+		for _, part := range strings.Split(s, sep) {
+			items = append(items, jqString(part))
+		}
+	}
+	return jqArray(items)
+}
+
+func (e jqObjectOf) eval(in Node) ([]Node, error) {
+	partials := [][]KeyValue{nil}
+	for _, entry := range e.entries {
+		keys, err := entry.key.eval(in)
+		if err != nil {
+			return nil, err
+		}
+		values, err := entry.value.eval(in)
+		if err != nil {
+			return nil, err
+		}
+
+		next := make([][]KeyValue, 0, len(partials)*len(keys)*len(values))
+		for _, p := range partials {
+			for _, k := range keys {
+				if k.typ != 's' {
+					return nil, fmt.Errorf("jq: object keys must be strings, got %s", jqTypeName(k))
+				}
+				for _, v := range values {
+					pairs := append(append([]KeyValue(nil), p...), KeyValue{Key: jqStr(k), Value: v})
+					next = append(next, pairs)
+				}
+			}
+		}
+		partials = next
+	}
+
+	out := make([]Node, 0, len(partials))
+	for _, p := range partials {
+		out = append(out, jqObject(p))
+	}
+	return out, nil
+}
+
+func (e jqInterp) eval(in Node) ([]Node, error) {
+	partials := []string{""}
+	for _, part := range e.parts {
+		if part.expr == nil {
+			for i := range partials {
+				partials[i] += part.lit
+			}
+			continue
+		}
+		vals, err := part.expr.eval(in)
+		if err != nil {
+			return nil, err
+		}
+		next := make([]string, 0, len(partials)*len(vals))
+		for _, p := range partials {
+			for _, v := range vals {
+				next = append(next, p+jqToString(v))
+			}
+		}
+		partials = next
+	}
+
+	out := make([]Node, 0, len(partials))
+	for _, s := range partials {
+		out = append(out, jqString(s))
+	}
+	return out, nil
+}
+
+// jqBuiltinArity 内置函数及其参数个数
+var jqBuiltinArity = map[string]int{
+	"length": 0, "keys": 0, "add": 0, "tostring": 0, "tonumber": 0, "tojson": 0,
+	"type": 0, "not": 0, "empty": 0, "first": 0, "last": 0, "reverse": 0,
+	"sort": 0, "unique": 0, "min": 0, "max": 0, "ascii_downcase": 0, "ascii_upcase": 0,
+	"to_entries": 0, "from_entries": 0,
+	"map": 1, "select": 1, "has": 1, "join": 1, "split": 1, "startswith": 1,
+	"endswith": 1, "sort_by": 1,
+}
+
+func (e jqCall) eval(in Node) ([]Node, error) {
+	switch e.name {
+	case "empty":
+		return nil, nil
+	case "select":
+		conds, err := e.args[0].eval(in)
+		if err != nil {
+			return nil, err
+		}
+		var out []Node
+		for _, c := range conds {
+			if jqTruthy(c) {
+				out = append(out, in)
+			}
+		}
+		return out, nil
+	case "map":
+		return jqArrayOf{inner: jqPipe{left: jqIterate{target: jqIdentity{}}, right: e.args[0]}}.eval(in)
+	case "sort_by":
+		if in.typ != 'a' {
+			return nil, fmt.Errorf("jq: %s cannot be sorted, as it is not an array", jqTypeName(in))
+		}
+		items := jqElements(in)
+		keys := make([]Node, len(items))
+		for i, item := range items {
+			vals, err := e.args[0].eval(item)
+			if err != nil {
+				return nil, err
+			}
+			keys[i] = jqArray(vals)
+		}
+		idx := make([]int, len(items))
+		for i := range idx {
+			idx[i] = i
+		}
+		sort.SliceStable(idx, func(i, j int) bool { return jqCompare(keys[idx[i]], keys[idx[j]]) < 0 })
+		sorted := make([]Node, len(items))
+		for i, j := range idx {
+			sorted[i] = items[j]
+		}
+		return []Node{jqArray(sorted)}, nil
+	}
+
+	if len(e.args) == 1 {
+		args, err := e.args[0].eval(in)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]Node, 0, len(args))
+		for _, arg := range args {
+			v, err := jqCall1(e.name, in, arg)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, v)
+		}
+		return out, nil
+	}
+
+	v, err := jqCall0(e.name, in)
+	if err != nil {
+		return nil, err
+	}
+	return []Node{v}, nil
+}
+
+// jqCall1 执行单参数内置函数
+func jqCall1(name string, in, arg Node) (Node, error) {
+	switch name {
+	case "has":
+		switch {
+		case in.typ == 'o' && arg.typ == 's':
+			return jqBool(in.GetSegments(jqStr(arg)).Exists()), nil
+		case in.typ == 'a' && arg.typ == 'n':
+			i := jqNum(arg)
+			return jqBool(i >= 0 && int(i) < in.Len()), nil
+		}
+		return Node{}, fmt.Errorf("jq: cannot check whether %s has a %s key", jqTypeName(in), jqTypeName(arg))
+	case "join":
+		if in.typ != 'a' || arg.typ != 's' {
+			return Node{}, fmt.Errorf("jq: cannot join %s with %s", jqTypeName(in), jqTypeName(arg))
+		}
+		var sb strings.Builder
+		for i, item := range jqElements(in) {
+			if i > 0 {
+				sb.WriteString(jqStr(arg))
+			}
+			switch item.typ {
+			case 'l':
+			case 's', 'n', 'b':
+				sb.WriteString(jqToString(item))
+			default:
+				return Node{}, fmt.Errorf("jq: cannot join with %s", jqTypeName(item))
+			}
+		}
+		return jqString(sb.String()), nil
+	}
+
+	if in.typ != 's' || arg.typ != 's' {
+		return Node{}, fmt.Errorf("jq: %s requires string input and argument, got %s and %s", name, jqTypeName(in), jqTypeName(arg))
+	}
+	s, x := jqStr(in), jqStr(arg)
+	switch name {
+	case "split":
+		return jqSplit(s, x), nil
+	case "startswith":
+		return jqBool(strings.HasPrefix(s, x)), nil
+	default: // endswith
+		return jqBool(strings.HasSuffix(s, x)), nil
+	}
+}
+
+// jqCall0 执行无参数内置函数
+func jqCall0(name string, in Node) (Node, error) {
+	switch name {
+	case "type":
+		return jqString(jqTypeName(in)), nil
+	case "not":
+		return jqBool(!jqTruthy(in)), nil
+	case "tostring":
+		if in.typ == 's' {
+			return in, nil
+		}
+		return jqString(jqToString(in)), nil
+	case "tojson":
+		return jqString(string(CompactJSON(in.Raw()))), nil
+	case "tonumber":
+		switch in.typ {
+		case 'n':
+			return in, nil
+		case 's':
+			f, err := strconv.ParseFloat(strings.TrimSpace(jqStr(in)), 64)
+			if err != nil {
+				return Node{}, fmt.Errorf("jq: cannot parse %s as number", in.Raw())
+			}
+			return jqNumber(f), nil
+		}
+		return Node{}, fmt.Errorf("jq: %s cannot be parsed as a number", jqTypeName(in))
+	case "length":
+		switch in.typ {
+		case 'l':
+			return jqNumber(0), nil
+		case 'n':
+			return jqNumber(math.Abs(jqNum(in))), nil
+		case 's':
+			return jqNumber(float64(utf8.RuneCountInString(jqStr(in)))), nil
+		case 'a':
+			return jqNumber(float64(in.Len())), nil
+		case 'o':
+			return jqNumber(float64(len(jqEntries(in)))), nil
+		}
+		return Node{}, fmt.Errorf("jq: %s has no length", jqTypeName(in))
+	case "ascii_downcase", "ascii_upcase":
+		if in.typ != 's' {
+			return Node{}, fmt.Errorf("jq: %s cannot be case-converted", jqTypeName(in))
+		}
+		lower := name == "ascii_downcase"
+		return jqString(strings.Map(func(r rune) rune {
+			switch {
+			case lower && r >= 'A' && r <= 'Z':
+				return r + 'a' - 'A'
+			case !lower && r >= 'a' && r <= 'z':
+				return r - 'a' + 'A'
+			}
+			return r
+		}, jqStr(in))), nil
+	case "keys":
+		switch in.typ {
+		case 'o':
+			keys := jqSortedKeys(in)
+			items := make([]Node, len(keys))
+			for i, k := range keys {
+				items[i] = jqString(k)
+			}
+			return jqArray(items), nil
+		case 'a':
+			items := make([]Node, in.Len())
+			for i := range items {
+				items[i] = jqNumber(float64(i))
+			}
+			return jqArray(items), nil
+		}
+		return Node{}, fmt.Errorf("jq: %s has no keys", jqTypeName(in))
+	case "to_entries":
+		if in.typ != 'o' {
+			return Node{}, fmt.Errorf("jq: %s has no keys", jqTypeName(in))
+		}
+		var items []Node
+		for _, kv := range jqEntries(in) {
+			items = append(items, jqObject([]KeyValue{{Key: "key", Value: jqString(kv.Key)}, {Key: "value", Value: kv.Value}}))
+		}
+		return jqArray(items), nil
+	case "from_entries":
+		if in.typ != 'a' {
+			return Node{}, fmt.Errorf("jq: cannot use %s as object entries", jqTypeName(in))
+		}
+		var pairs []KeyValue
+		for _, item := range jqElements(in) {
+			k := jqFirstField(item, "key", "k", "name")
+			v := jqFirstField(item, "value", "v")
+			switch k.typ {
+			case 's':
+				pairs = append(pairs, KeyValue{Key: jqStr(k), Value: v})
+			case 'n', 'b':
+				pairs = append(pairs, KeyValue{Key: jqToString(k), Value: v})
+			default:
+				return Node{}, fmt.Errorf("jq: cannot use %s as object key", jqTypeName(k))
+			}
+		}
+		return jqObject(pairs), nil
+	}
+
+	// 以下函数要求数组输入
+	if in.typ != 'a' {
+		if name == "reverse" && in.typ == 'l' {
+			return jqArray(nil), nil
+		}
+		return Node{}, fmt.Errorf("jq: %s requires an array, got %s", name, jqTypeName(in))
+	}
+	items := jqElements(in)
+	switch name {
+	case "first":
+		if len(items) == 0 {
+			return jqNull, nil
+		}
+		return items[0], nil
+	case "last":
+		if len(items) == 0 {
+			return jqNull, nil
+		}
+		return items[len(items)-1], nil
+	case "reverse":
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+		return jqArray(items), nil
+	case "sort":
+		jqSort(items)
+		return jqArray(items), nil
+	case "unique":
+		jqSort(items)
+		var out []Node
+		for i, item := range items {
+			if i == 0 || jqCompare(items[i-1], item) != 0 {
+				out = append(out, item)
+			}
+		}
+		return jqArray(out), nil
+	case "min", "max":
+		if len(items) == 0 {
+			return jqNull, nil
+		}
+		best := items[0]
+		for _, item := range items[1:] {
+			c := jqCompare(item, best)
+			if (name == "min" && c < 0) || (name == "max" && c >= 0) {
+				best = item
+			}
+		}
+		return best, nil
+	default: // add
+		acc := jqNull
+		for _, item := range items {
+			var err error
+			if acc, err = jqAdd(acc, item); err != nil {
+				return Node{}, err
+			}
+		}
+		return acc, nil
+	}
+}
+
+// jqFirstField 返回对象中第一个存在的字段，都不存在时返回 null
+func jqFirstField(n Node, names ...string) Node {
+	if n.typ == 'o' {
+		for _, name := range names {
+			if v := n.GetSegments(name); v.Exists() {
+				return v
+			}
+		}
+	}
+	return jqNull
+}
+
+// ===== 词法分析 =====
+
+// jqTokenKind 词法单元类型
+type jqTokenKind int
+
+const (
+	jqTokEOF    jqTokenKind = iota
+	jqTokDot                // .
+	jqTokDotDot             // ..
+	jqTokField              // .name
+	jqTokIdent              // 标识符与关键字
+	jqTokNumber             // 数字
+	jqTokString             // 字符串（text 为引号内的原始内容）
+	jqTokOp                 // 运算符与标点
+)
+
+// jqToken 词法单元
+type jqToken struct {
+	kind jqTokenKind
+	text string
+	pos  int
+}
+
+// jqOperators 运算符，长的在前
+var jqOperators = []string{"//", "==", "!=", "<=", ">=", "|", ",", "<", ">", "+", "-", "*", "/", "%", "(", ")", "[", "]", "{", "}", ":", ";", "?"}
+
+// lexJQ 将程序切分为词法单元
+func lexJQ(src string) ([]jqToken, error) {
+	var tokens []jqToken
+	i := 0
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '#':
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case c == '.':
+			switch {
+			case i+1 < len(src) && src[i+1] == '.':
+				tokens = append(tokens, jqToken{kind: jqTokDotDot, text: "..", pos: i})
+				i += 2
+			case i+1 < len(src) && isJQIdentStart(src[i+1]):
+				j := i + 2
+				for j < len(src) && isJQIdentChar(src[j]) {
+					j++
+				}
+				tokens = append(tokens, jqToken{kind: jqTokField, text: src[i+1 : j], pos: i})
+				i = j
+			default:
+				tokens = append(tokens, jqToken{kind: jqTokDot, text: ".", pos: i})
+				i++
+			}
+		case isJQIdentStart(c):
+			j := i + 1
+			for j < len(src) && isJQIdentChar(src[j]) {
+				j++
+			}
+			tokens = append(tokens, jqToken{kind: jqTokIdent, text: src[i:j], pos: i})
+			i = j
+		case c >= '0' && c <= '9':
+			j := i
+			for j < len(src) && (src[j] >= '0' && src[j] <= '9' || src[j] == '.') {
+				j++
+			}
+			if j < len(src) && (src[j] == 'e' || src[j] == 'E') {
+				j++
+				if j < len(src) && (src[j] == '+' || src[j] == '-') {
+					j++
+				}
+				for j < len(src) && src[j] >= '0' && src[j] <= '9' {
+					j++
+				}
+			}
+			tokens = append(tokens, jqToken{kind: jqTokNumber, text: src[i:j], pos: i})
+			i = j
+		case c == '"':
+			end, err := scanJQString(src, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, jqToken{kind: jqTokString, text: src[i+1 : end-1], pos: i})
+			i = end
+		default:
+			matched := false
+			for _, op := range jqOperators {
+				if strings.HasPrefix(src[i:], op) {
+					tokens = append(tokens, jqToken{kind: jqTokOp, text: op, pos: i})
+					i += len(op)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("jq: unexpected character %q at position %d", c, i)
+			}
+		}
+	}
+	return append(tokens, jqToken{kind: jqTokEOF, pos: len(src)}), nil
+}
+
+// scanJQString 扫描从 start 处开始的字符串字面量（含插值），返回结束引号之后的位置
+func scanJQString(src string, start int) (int, error) {
+	i := start + 1
+	for i < len(src) {
+		switch src[i] {
+		case '"':
+			return i + 1, nil
+		case '\\':
+			if i+1 < len(src) && src[i+1] == '(' {
+				end, err := scanJQInterpolation(src, i+1)
+				if err != nil {
+					return 0, err
+				}
+				i = end
+				continue
+			}
+			i += 2
+		default:
+			i++
+		}
+	}
+	return 0, fmt.Errorf("jq: unterminated string at position %d", start)
+}
+
+// scanJQInterpolation 扫描从 open 处 '(' 开始的插值表达式，返回匹配的 ')' 之后的位置
+func scanJQInterpolation(src string, open int) (int, error) {
+	depth := 0
+	for i := open; i < len(src); i++ {
+		switch src[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i + 1, nil
+			}
+		case '"':
+			end, err := scanJQString(src, i)
+			if err != nil {
+				return 0, err
+			}
+			i = end - 1
+		}
+	}
+	return 0, fmt.Errorf("jq: unterminated string interpolation at position %d", open)
+}
+
+// isJQIdentStart 标识符首字符
+func isJQIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// isJQIdentChar 标识符字符
+func isJQIdentChar(c byte) bool {
+	return isJQIdentStart(c) || (c >= '0' && c <= '9')
+}
+
+// ===== 语法分析 =====
+
+// maxJQDepth jq 程序的最大嵌套深度，与 DefaultParseOptions.MaxDepth 的默认值一致
+const maxJQDepth = 1000
+
+// jqParser 递归下降语法分析器
+type jqParser struct {
+	tokens []jqToken
+	pos    int
+	depth  int // 当前嵌套深度，含外层插值所在程序的深度
+}
+
+// parseJQ 解析完整程序，depth 为外层程序的嵌套深度（字符串插值时非零）
+func parseJQ(src string, depth int) (jqExpr, error) {
+	tokens, err := lexJQ(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &jqParser{tokens: tokens, depth: depth}
+	if p.peek().kind == jqTokEOF {
+		return nil, fmt.Errorf("jq: empty program")
+	}
+	expr, err := p.parsePipe()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != jqTokEOF {
+		return nil, p.unexpected(t)
+	}
+	return expr, nil
+}
+
+func (p *jqParser) peek() jqToken { return p.tokens[p.pos] }
+
+func (p *jqParser) next() jqToken {
+	t := p.tokens[p.pos]
+	if t.kind != jqTokEOF {
+		p.pos++
+	}
+	return t
+}
+
+// enter 进入一层嵌套，超过 maxJQDepth 时返回错误
+func (p *jqParser) enter() error {
+	p.depth++
+	if p.depth > maxJQDepth {
+		return fmt.Errorf("jq: nesting too deep at position %d: exceeds %d levels", p.peek().pos, maxJQDepth)
+	}
+	return nil
+}
+
+// leave 退出一层嵌套
+func (p *jqParser) leave() { p.depth-- }
+
+// isOp 当前词法单元是否为指定运算符
+func (p *jqParser) isOp(op string) bool {
+	t := p.peek()
+	return t.kind == jqTokOp && t.text == op
+}
+
+// isKeyword 当前词法单元是否为指定关键字
+func (p *jqParser) isKeyword(kw string) bool {
+	t := p.peek()
+	return t.kind == jqTokIdent && t.text == kw
+}
+
+// expect 消费指定的运算符或关键字
+func (p *jqParser) expect(text string) error {
+	t := p.peek()
+	if (t.kind == jqTokOp || t.kind == jqTokIdent) && t.text == text {
+		p.next()
+		return nil
+	}
+	if t.kind == jqTokEOF {
+		return fmt.Errorf("jq: expected %q at end of program", text)
+	}
+	return fmt.Errorf("jq: expected %q at position %d, got %q", text, t.pos, t.text)
+}
+
+// unexpected 返回意外词法单元的错误
+func (p *jqParser) unexpected(t jqToken) error {
+	if t.kind == jqTokEOF {
+		return fmt.Errorf("jq: unexpected end of program")
+	}
+	return fmt.Errorf("jq: unexpected %q at position %d", t.text, t.pos)
+}
+
+// parsePipe pipe := comma ('|' pipe)?
+func (p *jqParser) parsePipe() (jqExpr, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+
+	left, err := p.parseComma()
+	if err != nil {
+		return nil, err
+	}
+	if p.isOp("|") {
+		p.next()
+		right, err := p.parsePipe()
+		if err != nil {
+			return nil, err
+		}
+		return jqPipe{left: left, right: right}, nil
+	}
+	return left, nil
+}
+
+// parseComma comma := alt (',' alt)*
+func (p *jqParser) parseComma() (jqExpr, error) {
+	left, err := p.parseAlt()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp(",") {
+		p.next()
+		right, err := p.parseAlt()
+		if err != nil {
+			return nil, err
+		}
+		left = jqComma{left: left, right: right}
+	}
+	return left, nil
+}
+
+// parseAlt alt := or ('//' alt)?
+func (p *jqParser) parseAlt() (jqExpr, error) {
+	left, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.isOp("//") {
+		p.next()
+		right, err := p.parseAlt()
+		if err != nil {
+			return nil, err
+		}
+		return jqAlt{left: left, right: right}, nil
+	}
+	return left, nil
+}
+
+// parseOr or := and ('or' and)*
+func (p *jqParser) parseOr() (jqExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = jqBinary{op: "or", left: left, right: right}
+	}
+	return left, nil
+}
+
+// parseAnd and := compare ('and' compare)*
+func (p *jqParser) parseAnd() (jqExpr, error) {
+	left, err := p.parseCompare()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("and") {
+		p.next()
+		right, err := p.parseCompare()
+		if err != nil {
+			return nil, err
+		}
+		left = jqBinary{op: "and", left: left, right: right}
+	}
+	return left, nil
+}
+
+// parseCompare compare := additive (cmpop additive)?
+func (p *jqParser) parseCompare() (jqExpr, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if p.isOp(op) {
+			p.next()
+			right, err := p.parseAdditive()
+			if err != nil {
+				return nil, err
+			}
+			return jqBinary{op: op, left: left, right: right}, nil
+		}
+	}
+	return left, nil
+}
+
+// parseAdditive additive := multiplicative (('+'|'-') multiplicative)*
+func (p *jqParser) parseAdditive() (jqExpr, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("+") || p.isOp("-") {
+		op := p.next().text
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = jqBinary{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+// parseMultiplicative multiplicative := unary (('*'|'/'|'%') unary)*
+func (p *jqParser) parseMultiplicative() (jqExpr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("*") || p.isOp("/") || p.isOp("%") {
+		op := p.next().text
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = jqBinary{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+// parseUnary unary := '-' unary | postfix
+func (p *jqParser) parseUnary() (jqExpr, error) {
+	if p.isOp("-") {
+		p.next()
+		if err := p.enter(); err != nil {
+			return nil, err
+		}
+		defer p.leave()
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return jqNeg{inner: inner}, nil
+	}
+	return p.parsePostfix()
+}
+
+// parsePostfix postfix := primary (field | '[' ... ']' | '?')*
+func (p *jqParser) parsePostfix() (jqExpr, error) {
+	expr, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		t := p.peek()
+		switch {
+		case t.kind == jqTokField:
+			p.next()
+			expr = jqIndex{target: expr, index: jqLiteral{value: jqString(t.text)}}
+		case t.kind == jqTokDot && p.tokens[p.pos+1].kind == jqTokString:
+			p.next()
+			key, err := p.parseStringLiteral(p.next())
+			if err != nil {
+				return nil, err
+			}
+			expr = jqIndex{target: expr, index: key}
+		case t.kind == jqTokDot && p.tokens[p.pos+1].kind == jqTokOp && p.tokens[p.pos+1].text == "[":
+			p.next()
+		case p.isOp("["):
+			if expr, err = p.parseBracketSuffix(expr); err != nil {
+				return nil, err
+			}
+		case p.isOp("?"):
+			p.next()
+			expr = jqOptional{inner: expr}
+		default:
+			return expr, nil
+		}
+	}
+}
+
+// parseBracketSuffix 解析 [] [expr] [from:to]
+func (p *jqParser) parseBracketSuffix(target jqExpr) (jqExpr, error) {
+	p.next() // '['
+	if p.isOp("]") {
+		p.next()
+		return jqIterate{target: target}, nil
+	}
+
+	var from jqExpr
+	if !p.isOp(":") {
+		var err error
+		if from, err = p.parsePipe(); err != nil {
+			return nil, err
+		}
+	}
+	if p.isOp(":") {
+		p.next()
+		var to jqExpr
+		if !p.isOp("]") {
+			var err error
+			if to, err = p.parsePipe(); err != nil {
+				return nil, err
+			}
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+		return jqSlice{target: target, from: from, to: to}, nil
+	}
+	if err := p.expect("]"); err != nil {
+		return nil, err
+	}
+	return jqIndex{target: target, index: from}, nil
+}
+
+// parsePrimary 解析基本表达式
+func (p *jqParser) parsePrimary() (jqExpr, error) {
+	t := p.next()
+	switch t.kind {
+	case jqTokDot:
+		if p.peek().kind == jqTokString {
+			key, err := p.parseStringLiteral(p.next())
+			if err != nil {
+				return nil, err
+			}
+			return jqIndex{target: jqIdentity{}, index: key}, nil
+		}
+		return jqIdentity{}, nil
+	case jqTokDotDot:
+		return jqRecurse{}, nil
+	case jqTokField:
+		return jqIndex{target: jqIdentity{}, index: jqLiteral{value: jqString(t.text)}}, nil
+	case jqTokNumber:
+		f, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("jq: invalid number %q at position %d", t.text, t.pos)
+		}
+		return jqLiteral{value: jqNumber(f)}, nil
+	case jqTokString:
+		return p.parseStringLiteral(t)
+	case jqTokIdent:
+		return p.parseIdent(t)
+	case jqTokOp:
+		switch t.text {
+		case "(":
+			expr, err := p.parsePipe()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return expr, nil
+		case "[":
+			if p.isOp("]") {
+				p.next()
+				return jqArrayOf{}, nil
+			}
+			inner, err := p.parsePipe()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			return jqArrayOf{inner: inner}, nil
+		case "{":
+			return p.parseObject()
+		}
+	}
+	return nil, p.unexpected(t)
+}
+
+// parseIdent 解析字面量关键字、if 表达式与函数调用
+func (p *jqParser) parseIdent(t jqToken) (jqExpr, error) {
+	switch t.text {
+	case "true":
+		return jqLiteral{value: jqTrue}, nil
+	case "false":
+		return jqLiteral{value: jqFalse}, nil
+	case "null":
+		return jqLiteral{value: jqNull}, nil
+	case "if":
+		return p.parseIfRest()
+	}
+
+	arity, ok := jqBuiltinArity[t.text]
+	if !ok {
+		return nil, fmt.Errorf("jq: unknown function %q at position %d", t.text, t.pos)
+	}
+	var args []jqExpr
+	if p.isOp("(") {
+		p.next()
+		for {
+			arg, err := p.parsePipe()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if !p.isOp(";") {
+				break
+			}
+			p.next()
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+	}
+	if len(args) != arity {
+		return nil, fmt.Errorf("jq: %s/%d is not defined (position %d)", t.text, len(args), t.pos)
+	}
+	return jqCall{name: t.text, args: args}, nil
+}
+
+// parseIfRest 解析 if 之后的部分：cond then a (elif ...)* (else b)? end
+func (p *jqParser) parseIfRest() (jqExpr, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+
+	cond, err := p.parsePipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect("then"); err != nil {
+		return nil, err
+	}
+	then, err := p.parsePipe()
+	if err != nil {
+		return nil, err
+	}
+
+	var els jqExpr
+	switch {
+	case p.isKeyword("elif"):
+		// elif 链解析为嵌套的 if，共用最外层的 end
+		p.next()
+		if els, err = p.parseIfRest(); err != nil {
+			return nil, err
+		}
+		return jqIf{cond: cond, then: then, els: els}, nil
+	case p.isKeyword("else"):
+		p.next()
+		if els, err = p.parsePipe(); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.expect("end"); err != nil {
+		return nil, err
+	}
+	return jqIf{cond: cond, then: then, els: els}, nil
+}
+
+// parseObject 解析对象构造 { key: value, name, "key": value, (expr): value }
+func (p *jqParser) parseObject() (jqExpr, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+
+	var entries []jqObjectEntry
+	if p.isOp("}") {
+		p.next()
+		return jqObjectOf{}, nil
+	}
+
+	for {
+		t := p.next()
+		var key jqExpr
+		shorthand := true
+		switch {
+		case t.kind == jqTokIdent:
+			key = jqLiteral{value: jqString(t.text)}
+		case t.kind == jqTokString:
+			var err error
+			if key, err = p.parseStringLiteral(t); err != nil {
+				return nil, err
+			}
+		case t.kind == jqTokOp && t.text == "(":
+			var err error
+			if key, err = p.parsePipe(); err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			shorthand = false
+		default:
+			return nil, p.unexpected(t)
+		}
+
+		var value jqExpr
+		if p.isOp(":") {
+			p.next()
+			var err error
+			if value, err = p.parseAlt(); err != nil {
+				return nil, err
+			}
+		} else if shorthand {
+			value = jqIndex{target: jqIdentity{}, index: key}
+		} else {
+			return nil, p.unexpected(p.peek())
+		}
+		entries = append(entries, jqObjectEntry{key: key, value: value})
+
+		if p.isOp(",") {
+			p.next()
+			continue
+		}
+		if err := p.expect("}"); err != nil {
+			return nil, err
+		}
+		return jqObjectOf{entries: entries}, nil
+	}
+}
+
+// parseStringLiteral 将字符串词法单元解析为字面量或插值表达式
+func (p *jqParser) parseStringLiteral(t jqToken) (jqExpr, error) {
+	text := t.text
+	var parts []jqStringPart
+	lit := 0
+	for i := 0; i < len(text); i++ {
+		if text[i] != '\\' {
+			continue
+		}
+		if i+1 < len(text) && text[i+1] == '(' {
+			if lit < i {
+				parts = append(parts, jqStringPart{lit: unescapeJSON(text[lit:i])})
+			}
+			end, err := scanJQInterpolation(text, i+1)
+			if err != nil {
+				return nil, err
+			}
+			expr, err := parseJQ(text[i+2:end-1], p.depth)
+			if err != nil {
+				return nil, err
+			}
+			parts = append(parts, jqStringPart{expr: expr})
+			lit = end
+			i = end - 1
+			continue
+		}
+		i++ // 跳过被转义的字符
+	}
+	if lit < len(text) {
+		parts = append(parts, jqStringPart{lit: unescapeJSON(text[lit:])})
+	}
+
+	if len(parts) == 0 {
+		return jqLiteral{value: jqString("")}, nil
+	}
+	if len(parts) == 1 && parts[0].expr == nil {
+		return jqLiteral{value: jqString(parts[0].lit)}, nil
+	}
+	return jqInterp{parts: parts}, nil
+}
@@ -0,0 +1,178 @@
+package fxjson
+
+import (
+	"strings"
+	"testing"
+)
+
+const jqTestDoc = `{
+  "name": "shop",
+  "owner": {"first": "Ada", "last": "Lovelace"},
+  "items": [
+    {"id": 1, "title": "Book", "price": 12.5, "tags": ["paper"], "stock": 3},
+    {"id": 2, "title": "Pen", "price": 1.25, "tags": [], "stock": 0},
+    {"id": 3, "title": "Lamp", "price": 30, "tags": ["light", "home"], "stock": 7}
+  ],
+  "a.b": "dotted"
+}`
+
+// runJQ 执行程序并以换行连接各输出的紧凑 JSON
+func runJQ(t *testing.T, prog string, doc Node) string {
+	t.Helper()
+	p, err := CompileJQ(prog)
+	if err != nil {
+		t.Fatalf("CompileJQ(%q) failed: %v", prog, err)
+	}
+	out, err := p.Run(doc)
+	if err != nil {
+		t.Fatalf("Run(%q) failed: %v", prog, err)
+	}
+	lines := make([]string, len(out))
+	for i, n := range out {
+		lines[i] = string(CompactJSON(n.Raw()))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// TestJQPrograms 测试 jq 子集的执行结果
+func TestJQPrograms(t *testing.T) {
+	doc := FromString(jqTestDoc)
+
+	tests := []struct {
+		prog string
+		want string
+	}{
+		{`.`, string(CompactJSON([]byte(jqTestDoc)))},
+		{`.name`, `"shop"`},
+		{`.owner.first`, `"Ada"`},
+		{`."a.b"`, `"dotted"`},
+		{`.missing`, `null`},
+		{`.items[1].title`, `"Pen"`},
+		{`.items[-1].id`, `3`},
+		{`.items[].id`, "1\n2\n3"},
+		{`.items | length`, `3`},
+		{`.items[0:2] | map(.id)`, `[1,2]`},
+		{`[.items[] | select(.stock > 0) | .title]`, `["Book","Lamp"]`},
+		{`.items | map(.price * .stock) | add`, `247.5`},
+		{`.items | map(.price) | max`, `30`},
+		{`.items | sort_by(.price) | map(.title)`, `["Pen","Book","Lamp"]`},
+		{`.items | map(.tags[]) | unique`, `["home","light","paper"]`},
+		{`"\(.owner.first) \(.owner.last | ascii_upcase)"`, `"Ada LOVELACE"`},
+		{`{name, count: (.items | length), (.owner.first): true}`, `{"name":"shop","count":3,"Ada":true}`},
+		{`.items[] | {id, cheap: (.price < 10)}`, "{\"id\":1,\"cheap\":false}\n{\"id\":2,\"cheap\":true}\n{\"id\":3,\"cheap\":false}"},
+		{`.items[] | if .stock == 0 then "out" elif .stock < 5 then "low" else "ok" end`, "\"low\"\n\"out\"\n\"ok\""},
+		{`.nope // "default"`, `"default"`},
+		{`.items[0].tags | join(", ")`, `"paper"`},
+		{`.owner | keys`, `["first","last"]`},
+		{`.owner | to_entries | map(.value) | join(" ")`, `"Ada Lovelace"`},
+		{`[.owner | to_entries[] | {key: (.key | ascii_upcase), value}] | from_entries`, `{"FIRST":"Ada","LAST":"Lovelace"}`},
+		{`(1, 2) + (10, 20)`, "11\n12\n21\n22"},
+		{`[1, 2, 3] - [2]`, `[1,3]`},
+		{`{"a": 1} + {"b": 2}`, `{"a":1,"b":2}`},
+		{`10 % 3, 7 / 2, -(.items[0].id)`, "1\n3.5\n-1"},
+		{`(.name | type), (.items | type), (null | not)`, "\"string\"\n\"array\"\ntrue"},
+		{`"a,b,c" | split(",")`, `["a","b","c"]`},
+		{`.title? // "none"`, `"none"`},
+		{`[.[]?]`, `["shop",{"first":"Ada","last":"Lovelace"},[{"id":1,"title":"Book","price":12.5,"tags":["paper"],"stock":3},{"id":2,"title":"Pen","price":1.25,"tags":[],"stock":0},{"id":3,"title":"Lamp","price":30,"tags":["light","home"],"stock":7}],"dotted"]`},
+		{`[.. | select(type == "number")] | add`, `59.75`},
+		{`.items[0] | .price | tostring`, `"12.5"`},
+		{`"42" | tonumber + 1`, `43`},
+		{`.owner | tojson`, `"{\"first\":\"Ada\",\"last\":\"Lovelace\"}"`},
+		{`.items | first.id, last.id`, "1\n3"},
+		{`[.items[] | .title | startswith("L")]`, `[false,false,true]`},
+		{`.owner.first and .missing, (.missing or true)`, "false\ntrue"},
+		{`[empty, 1]`, `[1]`},
+	}
+
+	for _, tt := range tests {
+		if got := runJQ(t, tt.prog, doc); got != tt.want {
+			t.Errorf("%s\n got: %s\nwant: %s", tt.prog, got, tt.want)
+		}
+	}
+}
+
+// TestJQZeroCopy 选取的值直接引用输入文档
+func TestJQZeroCopy(t *testing.T) {
+	data := []byte(`{"user":{"name":"bob"}}`)
+	doc := FromBytes(data)
+
+	p, err := CompileJQ(`.user.name`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := p.Run(doc)
+	if err != nil || len(out) != 1 {
+		t.Fatalf("Run = %v, %v", out, err)
+	}
+	if &out[0].Raw()[0] != &data[16] {
+		t.Error("selected value should reference the input buffer")
+	}
+	if p.String() != `.user.name` {
+		t.Errorf("String() = %q", p.String())
+	}
+}
+
+// TestJQErrors 测试编译与运行错误
+func TestJQErrors(t *testing.T) {
+	compileErrors := []string{
+		``,
+		`.a |`,
+		`.items[`,
+		`numbers`,
+		`map`,
+		`map(.a; .b)`,
+		`if . then 1`,
+		`{a: 1`,
+		`"unterminated`,
+		`"\(.a"`,
+		`.a @ .b`,
+	}
+	for _, prog := range compileErrors {
+		if _, err := CompileJQ(prog); err == nil {
+			t.Errorf("CompileJQ(%q) should fail", prog)
+		}
+	}
+
+	// 嵌套深度受 maxJQDepth 限制，字符串插值计入外层深度
+	deep := func(open, mid, close string, n int) string {
+		return strings.Repeat(open, n) + mid + strings.Repeat(close, n)
+	}
+	if _, err := CompileJQ(deep("(", ".", ")", maxJQDepth/2)); err != nil {
+		t.Errorf("CompileJQ(%d nested parens) failed: %v", maxJQDepth/2, err)
+	}
+	tooDeep := []string{
+		deep("(", ".", ")", maxJQDepth),
+		deep("[", ".", "]", maxJQDepth*10),
+		deep("{a:", "1", "}", maxJQDepth*10),
+		strings.Repeat("-", maxJQDepth*10) + "1",
+		strings.Repeat(". | ", maxJQDepth*10) + ".",
+		deep("(", `"\(`+deep("(", ".", ")", maxJQDepth/2)+`)"`, ")", maxJQDepth/2),
+	}
+	for _, prog := range tooDeep {
+		if _, err := CompileJQ(prog); err == nil || !strings.Contains(err.Error(), "nesting too deep") {
+			t.Errorf("CompileJQ(%.20q...) = %v, want nesting error", prog, err)
+		}
+	}
+
+	doc := FromString(`{"n": 1, "s": "x", "a": [1]}`)
+	runtimeErrors := []string{
+		`.n.x`,
+		`.s[]`,
+		`.n + .s`,
+		`.n / 0`,
+		`.a | join(",") | .x`,
+		`{(.n): 1}`,
+		`.s | keys`,
+		`"x" | tonumber`,
+	}
+	for _, prog := range runtimeErrors {
+		p, err := CompileJQ(prog)
+		if err != nil {
+			t.Errorf("CompileJQ(%q) failed: %v", prog, err)
+			continue
+		}
+		if _, err := p.Run(doc); err == nil {
+			t.Errorf("Run(%q) should fail", prog)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package fxjson
+
+import (
+	"bytes"
+	"sync"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// nextUnescapedRune 解码 raw[i:] 处的一个（可能转义的）字符，返回其 UTF-8 编码与下一个位置
+func nextUnescapedRune(raw []byte, i int, scratch *[utf8.UTFMax]byte) ([]byte, int) {
+	if raw[i] != '\\' || i+1 >= len(raw) {
+		return raw[i : i+1], i + 1
+	}
+
+	switch raw[i+1] {
+	case '"', '\\', '/':
+		return raw[i+1 : i+2], i + 2
+	case 'b':
+		scratch[0] = '\b'
+	case 'f':
+		scratch[0] = '\f'
+	case 'n':
+		scratch[0] = '\n'
+	case 'r':
+		scratch[0] = '\r'
+	case 't':
+		scratch[0] = '\t'
+	case 'u':
+		r, next, ok := decodeUnicodeEscape(raw, i)
+		if !ok {
+			return raw[i : i+1], i + 1
+		}
+		n := utf8.EncodeRune(scratch[:], r)
+		return scratch[:n], next
+	default:
+		return raw[i : i+1], i + 1
+	}
+	return scratch[:1], i + 2
+}
+
+// decodeUnicodeEscape 解析 raw[i:] 处的 \uXXXX（含代理对）转义
+func decodeUnicodeEscape(raw []byte, i int) (rune, int, bool) {
+	r, ok := parseHex4(raw, i+2)
+	if !ok {
+		return 0, i, false
+	}
+	next := i + 6
+	if utf16.IsSurrogate(r) {
+		if next+1 < len(raw) && raw[next] == '\\' && raw[next+1] == 'u' {
+			if r2, ok := parseHex4(raw, next+2); ok {
+				if combined := utf16.DecodeRune(r, r2); combined != utf8.RuneError {
+					return combined, next + 6, true
+				}
+			}
+		}
+		return utf8.RuneError, next, true
+	}
+	return r, next, true
+}
+
+// parseHex4 解析 4 位十六进制数
+func parseHex4(raw []byte, i int) (rune, bool) {
+	if i+4 > len(raw) {
+		return 0, false
+	}
+	var r rune
+	for _, c := range raw[i : i+4] {
+		switch {
+		case c >= '0' && c <= '9':
+			c -= '0'
+		case c >= 'a' && c <= 'f':
+			c = c - 'a' + 10
+		case c >= 'A' && c <= 'F':
+			c = c - 'A' + 10
+		default:
+			return 0, false
+		}
+		r = r<<4 | rune(c)
+	}
+	return r, true
+}
+
+// keyEqualsEscaped 无分配地比较含转义的原始键与未转义的键
+func keyEqualsEscaped(raw []byte, key string) bool {
+	var scratch [utf8.UTFMax]byte
+	k := 0
+	for i := 0; i < len(raw); {
+		var chunk []byte
+		chunk, i = nextUnescapedRune(raw, i, &scratch)
+		if k+len(chunk) > len(key) || key[k:k+len(chunk)] != string(chunk) {
+			return false
+		}
+		k += len(chunk)
+	}
+	return k == len(key)
+}
+
+// appendUnescaped 将原始键解转义后追加到 dst
+func appendUnescaped(dst []byte, raw []byte) []byte {
+	var scratch [utf8.UTFMax]byte
+	for i := 0; i < len(raw); {
+		var chunk []byte
+		chunk, i = nextUnescapedRune(raw, i, &scratch)
+		dst = append(dst, chunk...)
+	}
+	return dst
+}
+
+// maxEscapedKeyCache 含转义键缓存的最大条目数，写满后清空重新开始，
+// 因此大量不同的键（如不可信输入）只会暂时挤占缓存，不会使常用键永久失去缓存
+const maxEscapedKeyCache = 4096
+
+// escapedKeyCache 含转义键（原始字节 -> 解转义结果）的进程级缓存，使重复出现的键不再分配；
+// 命中时只持有读锁，未命中时短暂持有写锁
+var escapedKeyCache = struct {
+	sync.RWMutex
+	m map[string]string
+}{m: make(map[string]string)}
+
+// keyString 返回对象键的字符串形式：无转义时零拷贝，含转义时优先复用缓存的解转义结果
+// 未命中缓存时借助 scratch 解转义后复制，返回的字符串可以安全持有
+func keyString(raw []byte, scratch *[]byte) string {
+	if bytes.IndexByte(raw, '\\') < 0 {
+		return bytesToString(raw)
+	}
+
+	escapedKeyCache.RLock()
+	key, ok := escapedKeyCache.m[string(raw)] // map 查找中的 string(raw) 不分配
+	escapedKeyCache.RUnlock()
+	if ok {
+		return key
+	}
+
+	*scratch = appendUnescaped((*scratch)[:0], raw)
+	key = string(*scratch)
+
+	escapedKeyCache.Lock()
+	if len(escapedKeyCache.m) >= maxEscapedKeyCache {
+		clear(escapedKeyCache.m)
+	}
+	escapedKeyCache.m[string(raw)] = key
+	escapedKeyCache.Unlock()
+	return key
+}
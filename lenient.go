@@ -0,0 +1,194 @@
+package fxjson
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// NonFiniteRule 宽松数字模式下 Infinity 与 NaN 的转换规则
+type NonFiniteRule uint8
+
+const (
+	NonFiniteNull   NonFiniteRule = iota // 转为 null（默认）
+	NonFiniteString                      // 转为字符串 "Infinity"、"-Infinity"、"NaN"
+	NonFiniteClamp                       // ±Infinity 转为 ±math.MaxFloat64，NaN 转为 null
+	NonFiniteError                       // 返回解析错误
+)
+
+// rewriteLenientJSON 在解析前将宽松语法改写为标准 JSON，双引号字符串内容保持不变；
+// 没有需要改写的内容时直接返回 b
+func rewriteLenientJSON(b []byte, opts ParseOptions) ([]byte, error) {
+	var out []byte
+	last := 0 // b[:last] 已写入 out
+	replace := func(from, to int, repl []byte) {
+		if out == nil {
+			out = make([]byte, 0, len(b)+16)
+		}
+		out = append(out, b[last:from]...)
+		out = append(out, repl...)
+		last = to
+	}
+
+	for i := 0; i < len(b); {
+		c := b[i]
+		if c == '"' {
+			i = skipStringSimple(b, i, len(b))
+			continue
+		}
+		if c == '\'' && opts.AllowSingleQuotes {
+			repl, end, err := singleQuotedString(b, i)
+			if err != nil {
+				return nil, err
+			}
+			replace(i, end, repl)
+			i = end
+			continue
+		}
+		if !isLenientTokenByte(c) {
+			i++
+			continue
+		}
+
+		j := i
+		for j < len(b) && isLenientTokenByte(b[j]) {
+			j++
+		}
+		if opts.AllowUnquotedKeys && isIdentifier(b[i:j]) && followedByColon(b, j) {
+			replace(i, j, []byte(`"`+string(b[i:j])+`"`))
+		} else if opts.LenientNumbers {
+			repl, err := lenientNumber(b[i:j], opts.NonFinite)
+			if err != nil {
+				return nil, &FxJSONError{Type: ErrorTypeInvalidJSON, Message: err.Error(), Pos: i}
+			}
+			if repl != nil {
+				replace(i, j, repl)
+			}
+		}
+		i = j
+	}
+	if out == nil {
+		return b, nil
+	}
+	return append(out, b[last:]...), nil
+}
+
+// singleQuotedString 将 b[start] 处的单引号字符串改写为双引号字符串，返回结束引号之后的位置；
+// 内容中的 \' 还原为 '，未转义的 " 补上转义，其余转义序列原样保留
+func singleQuotedString(b []byte, start int) ([]byte, int, error) {
+	out := make([]byte, 0, 16)
+	out = append(out, '"')
+	for i := start + 1; i < len(b); i++ {
+		switch c := b[i]; c {
+		case '\'':
+			return append(out, '"'), i + 1, nil
+		case '"':
+			out = append(out, '\\', '"')
+		case '\\':
+			if i+1 >= len(b) {
+				break
+			}
+			i++
+			if b[i] == '\'' {
+				out = append(out, '\'')
+			} else {
+				out = append(out, '\\', b[i])
+			}
+		default:
+			out = append(out, c)
+		}
+	}
+	return nil, 0, &FxJSONError{Type: ErrorTypeInvalidJSON, Message: "unterminated single-quoted string", Pos: start}
+}
+
+// isIdentifier 是否为可作为未加引号键的标识符（字母、数字、_ 与 $，不以数字开头）
+func isIdentifier(tok []byte) bool {
+	if len(tok) == 0 || (tok[0] >= '0' && tok[0] <= '9') {
+		return false
+	}
+	for _, c := range tok {
+		if !(c >= '0' && c <= '9') && !(c >= 'a' && c <= 'z') && !(c >= 'A' && c <= 'Z') && c != '_' && c != '$' {
+			return false
+		}
+	}
+	return true
+}
+
+// followedByColon b[pos:] 跳过空白后是否为 ':'
+func followedByColon(b []byte, pos int) bool {
+	for pos < len(b) && (b[pos] == ' ' || b[pos] == '\t' || b[pos] == '\n' || b[pos] == '\r') {
+		pos++
+	}
+	return pos < len(b) && b[pos] == ':'
+}
+
+// isLenientTokenByte 是否可以出现在未加引号的字面量（数字、true/false/null、Infinity 等）中
+func isLenientTokenByte(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') ||
+		c == '+' || c == '-' || c == '.' || c == '_' || c == '$'
+}
+
+// lenientNumber 将非标准数字字面量（0x1F、+5、.5、5.、Infinity、NaN）改写为标准 JSON，
+// 已是标准字面量或不是数字时返回 nil
+func lenientNumber(tok []byte, rule NonFiniteRule) ([]byte, error) {
+	s := string(tok)
+	if s == "true" || s == "false" || s == "null" || isJSONNumberLiteral(s) {
+		return nil, nil
+	}
+
+	sign := ""
+	body := s
+	if body[0] == '+' || body[0] == '-' {
+		if body[0] == '-' {
+			sign = "-"
+		}
+		body = body[1:]
+	}
+
+	switch body {
+	case "Infinity", "NaN":
+		return nonFiniteLiteral(sign, body, rule)
+	}
+
+	if len(body) > 2 && body[0] == '0' && (body[1] == 'x' || body[1] == 'X') {
+		u, err := strconv.ParseUint(body[2:], 16, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hex number %s", s)
+		}
+		return []byte(sign + strconv.FormatUint(u, 10)), nil
+	}
+
+	// 补全省略的整数位或小数位：.5 → 0.5，5. → 5.0，5.e3 → 5.0e3
+	if body != "" && body[0] == '.' {
+		body = "0" + body
+	}
+	for k := 0; k < len(body); k++ {
+		if body[k] == '.' && (k+1 == len(body) || body[k+1] == 'e' || body[k+1] == 'E') {
+			body = body[:k+1] + "0" + body[k+1:]
+			break
+		}
+	}
+	if fixed := sign + body; isJSONNumberLiteral(fixed) {
+		return []byte(fixed), nil
+	}
+	return nil, nil
+}
+
+// nonFiniteLiteral 按规则转换 Infinity 与 NaN
+func nonFiniteLiteral(sign, body string, rule NonFiniteRule) ([]byte, error) {
+	switch rule {
+	case NonFiniteString:
+		if body == "NaN" {
+			return []byte(`"NaN"`), nil
+		}
+		return []byte(`"` + sign + body + `"`), nil
+	case NonFiniteClamp:
+		if body == "NaN" {
+			return []byte("null"), nil
+		}
+		return []byte(sign + "1.7976931348623157e308"), nil
+	case NonFiniteError:
+		return nil, fmt.Errorf("non-finite number %s%s", sign, body)
+	default:
+		return []byte("null"), nil
+	}
+}
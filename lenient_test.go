@@ -0,0 +1,130 @@
+package fxjson
+
+import (
+	"math"
+	"testing"
+)
+
+// TestLenientNumbers 测试宽松数字模式的改写规则
+func TestLenientNumbers(t *testing.T) {
+	opts := DefaultParseOptions
+	opts.LenientNumbers = true
+
+	input := `{"hex":0x1F,"neghex":-0xff,"plus":+5,"lead":.5,"neglead":-.25,"trail":5.,"exp":5.e2,` +
+		`"inf":Infinity,"ninf":-Infinity,"nan":NaN,"str":"0x1F +5 .5 NaN","ok":1.5e3,"b":true,"n":null}`
+	node, err := ParseBytes([]byte(input), opts)
+	if err != nil {
+		t.Fatalf("ParseBytes error: %v", err)
+	}
+
+	ints := map[string]int64{"hex": 31, "neghex": -255, "plus": 5}
+	for key, want := range ints {
+		if got, err := node.Get(key).Int(); err != nil || got != want {
+			t.Errorf("%s = %d, %v; want %d", key, got, err, want)
+		}
+	}
+	floats := map[string]float64{"lead": 0.5, "neglead": -0.25, "trail": 5, "exp": 500, "ok": 1500}
+	for key, want := range floats {
+		if got, err := node.Get(key).Float(); err != nil || got != want {
+			t.Errorf("%s = %v, %v; want %v", key, got, err, want)
+		}
+	}
+	for _, key := range []string{"inf", "ninf", "nan"} {
+		if !node.Get(key).IsNull() {
+			t.Errorf("%s = %s, want null", key, node.Get(key).Raw())
+		}
+	}
+	if s, _ := node.Get("str").String(); s != "0x1F +5 .5 NaN" {
+		t.Errorf("str = %q, string contents must not be rewritten", s)
+	}
+	if !node.Get("b").BoolOr(false) {
+		t.Errorf("b = %s", node.Get("b").Raw())
+	}
+
+	// 默认关闭时保持原有行为
+	if node, _ := ParseBytes([]byte(`[0x1F]`), DefaultParseOptions); string(node.Raw()) != `[0x1F]` {
+		t.Errorf("input rewritten without LenientNumbers: %s", node.Raw())
+	}
+}
+
+// TestLenientNonFiniteRules 测试 Infinity 与 NaN 的转换规则
+func TestLenientNonFiniteRules(t *testing.T) {
+	opts := DefaultParseOptions
+	opts.LenientNumbers = true
+
+	opts.NonFinite = NonFiniteString
+	node, err := ParseBytes([]byte(`[Infinity,-Infinity,NaN]`), opts)
+	if err != nil {
+		t.Fatalf("ParseBytes error: %v", err)
+	}
+	for i, want := range []string{"Infinity", "-Infinity", "NaN"} {
+		if got, _ := node.Index(i).String(); got != want {
+			t.Errorf("NonFiniteString [%d] = %q, want %q", i, got, want)
+		}
+	}
+
+	opts.NonFinite = NonFiniteClamp
+	node, err = ParseBytes([]byte(`[+Infinity,-Infinity,NaN]`), opts)
+	if err != nil {
+		t.Fatalf("ParseBytes error: %v", err)
+	}
+	if f, _ := node.Index(0).Float(); f != math.MaxFloat64 {
+		t.Errorf("NonFiniteClamp +Infinity = %v", f)
+	}
+	if f, _ := node.Index(1).Float(); f != -math.MaxFloat64 {
+		t.Errorf("NonFiniteClamp -Infinity = %v", f)
+	}
+	if !node.Index(2).IsNull() {
+		t.Errorf("NonFiniteClamp NaN = %s", node.Index(2).Raw())
+	}
+
+	opts.NonFinite = NonFiniteError
+	if _, err := ParseBytes([]byte(`{"a":NaN}`), opts); err == nil {
+		t.Errorf("NonFiniteError accepted NaN")
+	}
+	if _, err := ParseBytes([]byte(`[0x1FFFFFFFFFFFFFFFFF]`), opts); err == nil {
+		t.Errorf("overflowing hex accepted")
+	}
+}
+
+// TestLenientQuotesAndKeys 测试单引号字符串与未加引号的键
+func TestLenientQuotesAndKeys(t *testing.T) {
+	opts := DefaultParseOptions
+	opts.AllowSingleQuotes = true
+	opts.AllowUnquotedKeys = true
+
+	input := `{name: 'it\'s "quoted"', $id: 7, _tag: 'a\nb', "plain": "x: 'y'", nested: {ok_1 : true}, list: ['p', "q"]}`
+	node, err := ParseBytes([]byte(input), opts)
+	if err != nil {
+		t.Fatalf("ParseBytes error: %v", err)
+	}
+	if s, _ := node.Get("name").String(); s != `it's "quoted"` {
+		t.Errorf("name = %q", s)
+	}
+	if v, _ := node.Get("$id").Int(); v != 7 {
+		t.Errorf("$id = %d", v)
+	}
+	if s, _ := node.Get("_tag").String(); s != "a\nb" {
+		t.Errorf("_tag = %q", s)
+	}
+	if s, _ := node.Get("plain").String(); s != "x: 'y'" {
+		t.Errorf("plain = %q, double-quoted strings must not be rewritten", s)
+	}
+	if !node.GetPath("nested.ok_1").BoolOr(false) {
+		t.Errorf("nested.ok_1 = %s", node.GetPath("nested.ok_1").Raw())
+	}
+	if s, _ := node.GetPath("list[0]").String(); s != "p" {
+		t.Errorf("list[0] = %q", s)
+	}
+
+	if _, err := ParseBytes([]byte(`{'a: 1}`), opts); err == nil {
+		t.Errorf("unterminated single-quoted string accepted")
+	}
+
+	// 只开启单引号时不改写键
+	only := DefaultParseOptions
+	only.AllowSingleQuotes = true
+	if node, _ := ParseBytes([]byte(`{a: 'x'}`), only); string(node.Raw()) != `{a: "x"}` {
+		t.Errorf("AllowSingleQuotes only = %s", node.Raw())
+	}
+}
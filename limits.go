@@ -0,0 +1,59 @@
+package fxjson
+
+import "reflect"
+
+// ResultLimits 查询与聚合的结果规模上限，0 表示不限制
+// 对外提供查询能力、处理不可信文档时使用，防止结果集膨胀（如在百万元素上按唯一字段 GroupBy）耗尽内存；
+// 超限时返回 Type 为 ErrorTypeResultLimit 的 *FxJSONError
+type ResultLimits struct {
+	MaxResults int // 查询匹配的元素数量上限（在 Offset/Limit 之前计数）
+	MaxGroups  int // 聚合的分组数量上限
+
+	// MaxMemoryBytes 结果占用内存的估算上限：查询按每个匹配元素的 Node 大小加原始字节数
+	// （ToJSON/ToNode 会复制这些字节）估算，聚合按分组键、分组元素与累加器大小估算
+	MaxMemoryBytes int
+}
+
+const groupEntrySize = 64 // 分组 map 条目与切片头的估算开销
+
+// 结果与累加器的大小，通过反射获取以便在 fxjson_safe 构建标签下不依赖 unsafe
+var (
+	resultNodeSize  = int(reflect.TypeFor[Node]().Size())
+	accumulatorSize = int(reflect.TypeFor[aggAccumulator]().Size())
+)
+
+// WithLimits 设置查询的结果规模上限
+func (qb *QueryBuilder) WithLimits(limits ResultLimits) *QueryBuilder {
+	qb.limits = limits
+	return qb
+}
+
+// WithLimits 设置聚合的分组数量与内存估算上限（Execute 与 Feed 均生效）
+func (agg *Aggregator) WithLimits(limits ResultLimits) *Aggregator {
+	agg.limits = limits
+	return agg
+}
+
+// checkResults 检查查询结果数量与估算内存
+func (l ResultLimits) checkResults(count, bytes int) error {
+	if l.MaxResults > 0 && count > l.MaxResults {
+		return NewResultLimitError("MaxResults", l.MaxResults, count)
+	}
+	return l.checkMemory(bytes)
+}
+
+// checkGroups 检查分组数量与估算内存
+func (l ResultLimits) checkGroups(count, bytes int) error {
+	if l.MaxGroups > 0 && count > l.MaxGroups {
+		return NewResultLimitError("MaxGroups", l.MaxGroups, count)
+	}
+	return l.checkMemory(bytes)
+}
+
+// checkMemory 检查估算内存
+func (l ResultLimits) checkMemory(bytes int) error {
+	if l.MaxMemoryBytes > 0 && bytes > l.MaxMemoryBytes {
+		return NewResultLimitError("MaxMemoryBytes", l.MaxMemoryBytes, bytes)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package fxjson
+
+import (
+	"errors"
+	"testing"
+)
+
+// isResultLimitError 是否为结果规模超限错误
+func isResultLimitError(err error) bool {
+	var fe *FxJSONError
+	return errors.As(err, &fe) && fe.Type == ErrorTypeResultLimit
+}
+
+// TestResultLimits 测试查询与聚合的结果规模上限
+func TestResultLimits(t *testing.T) {
+	arr := contextTestArray(1000)
+
+	if _, err := arr.Query().WithLimits(ResultLimits{MaxResults: 100}).Where("id", ">=", 0).ToSlice(); !isResultLimitError(err) {
+		t.Errorf("MaxResults err = %v", err)
+	}
+	// Limit 不影响匹配数量的上限检查，条件过滤后未超限则正常返回
+	if res, err := arr.Query().WithLimits(ResultLimits{MaxResults: 100}).Where("id", "<", 100).ToSlice(); err != nil || len(res) != 100 {
+		t.Errorf("within MaxResults = %d, %v", len(res), err)
+	}
+	if _, err := arr.Query().WithLimits(ResultLimits{MaxMemoryBytes: 1024}).ToSlice(); !isResultLimitError(err) {
+		t.Errorf("query MaxMemoryBytes err = %v", err)
+	}
+
+	if _, err := arr.Aggregate().PreserveIntegers().WithLimits(ResultLimits{MaxGroups: 10}).GroupBy("id").Count("n").Execute(arr); !isResultLimitError(err) {
+		t.Errorf("MaxGroups err = %v", err)
+	}
+	if res, err := arr.Aggregate().WithLimits(ResultLimits{MaxGroups: 10}).GroupBy("g").Count("n").Execute(arr); err != nil || len(res) != 3 {
+		t.Errorf("within MaxGroups = %v, %v", res, err)
+	}
+	if _, err := arr.Aggregate().WithLimits(ResultLimits{MaxMemoryBytes: 4096}).Count("n").Execute(arr); !isResultLimitError(err) {
+		t.Errorf("aggregate MaxMemoryBytes err = %v", err)
+	}
+
+	agg := arr.Aggregate().PreserveIntegers().WithLimits(ResultLimits{MaxGroups: 500}).GroupBy("id").Count("n")
+	if err := agg.Feed(arr); !isResultLimitError(err) {
+		t.Errorf("Feed MaxGroups err = %v", err)
+	}
+	agg.Reset()
+	if err := agg.Feed(contextTestArray(10)); err != nil {
+		t.Errorf("Feed after Reset err = %v", err)
+	}
+
+	var fe *FxJSONError
+	_, err := arr.Query().WithLimits(ResultLimits{MaxResults: 1}).ToSlice()
+	if !errors.As(err, &fe) || fe.Error() != "[ResultLimit] MaxResults 1 exceeded: 2" {
+		t.Errorf("error message = %v", err)
+	}
+}
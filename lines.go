@@ -0,0 +1,78 @@
+package fxjson
+
+import (
+	"bytes"
+	"io"
+)
+
+// LinesWriter NDJSON（每行一个 JSON 值）输出编码器，适用于日志管道
+//
+// 每条记录压缩为单行并以 '\n' 结尾，通过一次 Write 调用写入底层 io.Writer，
+// 序列化使用池化缓冲区。LinesWriter 不是并发安全的。
+type LinesWriter struct {
+	w     io.Writer
+	opts  SerializeOptions
+	count int
+}
+
+// NewLinesWriter 使用默认序列化选项创建 NDJSON 编码器
+func NewLinesWriter(w io.Writer) *LinesWriter {
+	return NewLinesWriterWithOptions(w, DefaultSerializeOptions)
+}
+
+// NewLinesWriterWithOptions 使用指定序列化选项创建 NDJSON 编码器，Indent 会被忽略
+func NewLinesWriterWithOptions(w io.Writer, opts SerializeOptions) *LinesWriter {
+	opts.Indent = ""
+	return &LinesWriter{w: w, opts: opts}
+}
+
+// Write 序列化一条记录并写入一行，v 可以是 Node 或任意可序列化的值
+// Node 中原有的换行与缩进会被去除，序列化失败时不写入任何内容
+func (lw *LinesWriter) Write(v any) error {
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	if err := buf.EncodeValueWithOptions(v, lw.opts); err != nil {
+		return err
+	}
+	if bytes.ContainsAny(buf.buf, "\r\n") {
+		// 原样输出的 Node 可能带有格式化空白；JSON 字符串中的换行总是转义的，
+		// 因此出现裸换行时整体去除字符串外的空白
+		buf.buf = compactInPlace(buf.buf)
+	}
+	buf.buf = append(buf.buf, '\n')
+
+	if _, err := lw.w.Write(buf.buf); err != nil {
+		return err
+	}
+	lw.count++
+	return nil
+}
+
+// Count 返回已成功写入的记录数
+func (lw *LinesWriter) Count() int {
+	return lw.count
+}
+
+// compactInPlace 原地去除字符串外的空白（同 CompactJSON，但复用 b 的存储）
+func compactInPlace(b []byte) []byte {
+	out := b[:0]
+	inString, escaped := false, false
+	for _, c := range b {
+		if inString {
+			if escaped {
+				escaped = false
+			} else if c == '\\' {
+				escaped = true
+			} else if c == '"' {
+				inString = false
+			}
+		} else if c == ' ' || c == '\t' || c == '\n' || c == '\r' {
+			continue
+		} else if c == '"' {
+			inString = true
+		}
+		out = append(out, c)
+	}
+	return out
+}
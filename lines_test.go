@@ -0,0 +1,74 @@
+package fxjson
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestLinesWriter 测试 NDJSON 输出每行一条压缩记录
+func TestLinesWriter(t *testing.T) {
+	var out bytes.Buffer
+	lw := NewLinesWriter(&out)
+
+	pretty := FromBytes([]byte("{\n  \"msg\": \"a b\\nc\",\n  \"tags\": [ 1, 2 ]\n}"))
+	records := []any{
+		pretty,
+		map[string]any{"level": "info"},
+		struct {
+			ID int `json:"id"`
+		}{7},
+		"plain",
+	}
+	for _, r := range records {
+		if err := lw.Write(r); err != nil {
+			t.Fatalf("Write(%v): %v", r, err)
+		}
+	}
+	if lw.Count() != len(records) {
+		t.Errorf("Count = %d, want %d", lw.Count(), len(records))
+	}
+
+	want := []string{`{"msg":"a b\nc","tags":[1,2]}`, `{"level":"info"}`, `{"id":7}`, `"plain"`}
+	scanner := bufio.NewScanner(&out)
+	var lines []string
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if strings.Join(lines, "|") != strings.Join(want, "|") {
+		t.Errorf("lines = %q, want %q", lines, want)
+	}
+	for _, line := range lines {
+		if !FromBytes([]byte(line)).Exists() {
+			t.Errorf("line does not parse: %s", line)
+		}
+	}
+}
+
+// TestLinesWriterErrors 测试序列化失败与写入失败
+func TestLinesWriterErrors(t *testing.T) {
+	var out bytes.Buffer
+	lw := NewLinesWriterWithOptions(&out, SerializeOptions{Indent: "  "})
+	if err := lw.Write(json.RawMessage(`{bad`)); err == nil {
+		t.Error("expected error for invalid raw message")
+	}
+	if out.Len() != 0 || lw.Count() != 0 {
+		t.Errorf("failed record should not be written, got %q", out.String())
+	}
+	if err := lw.Write(map[string]int{"a": 1}); err != nil || out.String() != "{\"a\":1}\n" {
+		t.Errorf("Indent should be ignored, got %q, %v", out.String(), err)
+	}
+
+	failing := NewLinesWriter(errWriter{})
+	if err := failing.Write(1); err == nil || failing.Count() != 0 {
+		t.Errorf("expected writer error, got %v", err)
+	}
+}
+
+// errWriter 总是返回错误的 io.Writer
+type errWriter struct{}
+
+func (errWriter) Write([]byte) (int, error) { return 0, errors.New("write failed") }
@@ -1,10 +1,13 @@
 package fxjson
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
 	"reflect"
 	"strconv"
 	"sync"
-	"unsafe"
 )
 
 // SerializeOptions 序列化选项
@@ -15,6 +18,20 @@ type SerializeOptions struct {
 	OmitEmpty       bool   // 是否忽略空值
 	FloatPrecision  int    // 浮点数精度，-1表示默认
 	UseNumberString bool   // 大数字是否用字符串表示
+
+	FieldNaming NamingPolicy // 未通过 json 标签显式命名的结构体字段的输出命名风格
+
+	// KeyTransform 输出前对所有对象键的转换（结构体字段名、map 键与 Node 对象键），nil 表示不转换
+	// 内置风格可直接传入 ToSnakeCase、ToCamelCase、ToPascalCase 或 NamingPolicy.Apply
+	KeyTransform func(string) string
+}
+
+// outputKey 返回应用 KeyTransform 后的对象键
+func (o SerializeOptions) outputKey(key string) string {
+	if o.KeyTransform == nil {
+		return key
+	}
+	return o.KeyTransform(key)
 }
 
 // DefaultSerializeOptions 默认序列化选项（压缩模式）
@@ -37,7 +54,7 @@ var PrettySerializeOptions = SerializeOptions{
 	UseNumberString: false,
 }
 
-// Buffer 高性能字节缓冲区
+// Buffer 高性能字节缓冲区，可通过 NewBuffer 从池中获取，将多次序列化组合到同一输出中
 type Buffer struct {
 	buf []byte
 }
@@ -60,24 +77,86 @@ func putBuffer(buf *Buffer) {
 	bufferPool.Put(buf)
 }
 
+// NewBuffer 从池中获取一个空缓冲区，使用完毕后调用 Release 归还
+func NewBuffer() *Buffer {
+	return getBuffer()
+}
+
+// Release 将缓冲区归还到池中，之后不得再使用该缓冲区及 BytesView 返回的切片
+func (b *Buffer) Release() {
+	putBuffer(b)
+}
+
 // Reset 重置缓冲区
 func (b *Buffer) Reset() {
 	b.buf = b.buf[:0]
 }
 
+// Len 返回已写入的字节数
+func (b *Buffer) Len() int {
+	return len(b.buf)
+}
+
 // Bytes 返回缓冲区字节切片
 func (b *Buffer) Bytes() []byte {
 	return b.buf
 }
 
+// BytesView 返回引用缓冲区内部存储的切片，在下一次写入、Reset 或 Release 之前有效
+func (b *Buffer) BytesView() []byte {
+	return b.buf
+}
+
+// BytesCopy 返回缓冲区内容的副本
+func (b *Buffer) BytesCopy() []byte {
+	result := make([]byte, len(b.buf))
+	copy(result, b.buf)
+	return result
+}
+
+// WriteTo 将缓冲区内容写入 w（实现 io.WriterTo），不会清空缓冲区
+func (b *Buffer) WriteTo(w io.Writer) (int64, error) {
+	n, err := w.Write(b.buf)
+	return int64(n), err
+}
+
+// EncodeValue 使用默认选项将 v 序列化并追加到缓冲区
+func (b *Buffer) EncodeValue(v interface{}) error {
+	return b.EncodeValueWithOptions(v, DefaultSerializeOptions)
+}
+
+// EncodeValueWithOptions 使用指定选项将 v 序列化并追加到缓冲区，失败时缓冲区恢复到调用前的内容
+func (b *Buffer) EncodeValueWithOptions(v interface{}, opts SerializeOptions) (err error) {
+	mark := len(b.buf)
+	defer func() {
+		if err != nil {
+			b.buf = b.buf[:mark]
+		}
+	}()
+	defer recoverPanic(&err, "EncodeValue", Node{})
+
+	return marshalValue(b, reflect.ValueOf(v), opts, 0)
+}
+
+// EncodeNode 使用默认选项将节点序列化并追加到缓冲区
+func (b *Buffer) EncodeNode(n Node) error {
+	return b.EncodeNodeWithOptions(n, DefaultSerializeOptions)
+}
+
+// EncodeNodeWithOptions 使用指定选项将节点序列化并追加到缓冲区，失败时缓冲区恢复到调用前的内容
+func (b *Buffer) EncodeNodeWithOptions(n Node, opts SerializeOptions) error {
+	return n.MarshalInto(b, opts)
+}
+
 // String 返回缓冲区字符串
 func (b *Buffer) String() string {
-	return unsafe.String(unsafe.SliceData(b.buf), len(b.buf))
+	return bytesToString(b.buf)
 }
 
-// WriteByte 写入单个字节
-func (b *Buffer) WriteByte(c byte) {
+// WriteByte 写入单个字节（满足 io.ByteWriter，始终返回 nil）
+func (b *Buffer) WriteByte(c byte) error {
 	b.buf = append(b.buf, c)
+	return nil
 }
 
 // WriteString 写入字符串
@@ -90,6 +169,11 @@ func (b *Buffer) Write(p []byte) {
 	b.buf = append(b.buf, p...)
 }
 
+// WriteJSONString 写入带引号并按 JSON 规则转义的字符串
+func (b *Buffer) WriteJSONString(s string) {
+	writeString(b, s, false)
+}
+
 // Grow 扩展缓冲区容量
 func (b *Buffer) Grow(n int) {
 	if cap(b.buf)-len(b.buf) < n {
@@ -104,6 +188,8 @@ type fieldInfo struct {
 	index       int
 	name        string
 	jsonName    string
+	namedByTag  bool                      // 是否通过 json 标签显式命名
+	styledNames [namingPolicyCount]string // 各命名风格下的字段名（仅未显式命名时使用）
 	omitEmpty   bool
 	isPointer   bool
 	isInterface bool
@@ -120,7 +206,9 @@ var typeCache sync.Map
 
 // getTypeInfo 获取类型信息（带缓存）
 func getTypeInfo(t reflect.Type) *typeInfo {
-	if cached, ok := typeCache.Load(t); ok {
+	cached, ok := typeCache.Load(t)
+	typeCacheCounter.record(ok)
+	if ok {
 		return cached.(*typeInfo)
 	}
 
@@ -142,12 +230,14 @@ func getTypeInfo(t reflect.Type) *typeInfo {
 		}
 
 		jsonName := field.Name
+		namedByTag := false
 		omitEmpty := false
 
 		if jsonTag != "" {
 			parts := parseJSONTag(jsonTag)
 			if parts[0] != "" {
 				jsonName = parts[0]
+				namedByTag = true
 			}
 			for _, part := range parts[1:] {
 				if part == "omitempty" {
@@ -160,10 +250,19 @@ func getTypeInfo(t reflect.Type) *typeInfo {
 		isPointer := fieldType.Kind() == reflect.Ptr
 		isInterface := fieldType.Kind() == reflect.Interface
 
+		var styledNames [namingPolicyCount]string
+		if !namedByTag {
+			for p := NamingPolicy(0); p < namingPolicyCount; p++ {
+				styledNames[p] = p.Apply(field.Name)
+			}
+		}
+
 		info.fields = append(info.fields, fieldInfo{
 			index:       i,
 			name:        field.Name,
 			jsonName:    jsonName,
+			namedByTag:  namedByTag,
+			styledNames: styledNames,
 			omitEmpty:   omitEmpty,
 			isPointer:   isPointer,
 			isInterface: isInterface,
@@ -175,6 +274,14 @@ func getTypeInfo(t reflect.Type) *typeInfo {
 	return info
 }
 
+// outputName 返回按命名风格输出的字段名，显式命名的字段保持标签名
+func (f *fieldInfo) outputName(policy NamingPolicy) string {
+	if f.namedByTag || policy <= NamingAsIs || policy >= namingPolicyCount {
+		return f.jsonName
+	}
+	return f.styledNames[policy]
+}
+
 // parseJSONTag 解析JSON标签
 func parseJSONTag(tag string) []string {
 	var parts []string
@@ -209,9 +316,13 @@ func MarshalIndent(v interface{}, prefix, indent string) ([]byte, error) {
 }
 
 // MarshalWithOptions 使用指定选项序列化
-func MarshalWithOptions(v interface{}, opts SerializeOptions) ([]byte, error) {
+func MarshalWithOptions(v interface{}, opts SerializeOptions) (out []byte, err error) {
+	if done := slowOpStart(); done != nil {
+		defer func() { done(SlowOp{Op: OpSerialize, OutputBytes: len(out), Err: err}) }()
+	}
 	buf := getBuffer()
 	defer putBuffer(buf)
+	defer recoverPanic(&err, "Marshal", Node{})
 
 	if err := marshalValue(buf, reflect.ValueOf(v), opts, 0); err != nil {
 		return nil, err
@@ -228,9 +339,13 @@ func MarshalToString(v interface{}) (string, error) {
 }
 
 // MarshalToStringWithOptions 使用指定选项序列化为字符串
-func MarshalToStringWithOptions(v interface{}, opts SerializeOptions) (string, error) {
+func MarshalToStringWithOptions(v interface{}, opts SerializeOptions) (out string, err error) {
+	if done := slowOpStart(); done != nil {
+		defer func() { done(SlowOp{Op: OpSerialize, OutputBytes: len(out), Err: err}) }()
+	}
 	buf := getBuffer()
 	defer putBuffer(buf)
+	defer recoverPanic(&err, "MarshalToString", Node{})
 
 	if err := marshalValue(buf, reflect.ValueOf(v), opts, 0); err != nil {
 		return "", err
@@ -252,6 +367,39 @@ func FastMarshal(v interface{}) []byte {
 	return result
 }
 
+// nodeType Node 的反射类型，用于在序列化时识别 Node 值
+var nodeType = reflect.TypeOf(Node{})
+
+// rawMessageType json.RawMessage 的反射类型，其内容作为已编码的 JSON 输出
+var rawMessageType = reflect.TypeOf(json.RawMessage(nil))
+
+// rawPassthrough 选项是否允许原样输出已编码的 JSON（无需缩进、排序、过滤或改写键与字符串）
+func (o SerializeOptions) rawPassthrough() bool {
+	return o.Indent == "" && !o.SortKeys && !o.OmitEmpty && !o.EscapeHTML && o.KeyTransform == nil
+}
+
+// marshalRawNode 序列化 Node 值：选项允许时直接写入原始字节，否则按选项重新序列化
+func marshalRawNode(buf *Buffer, n Node, opts SerializeOptions, depth int) error {
+	if n.Exists() && opts.rawPassthrough() {
+		buf.Write(n.Raw())
+		return nil
+	}
+	return n.marshalNode(buf, opts, depth)
+}
+
+// marshalRawMessage 序列化 json.RawMessage，空值输出 null，内容不是合法 JSON 时返回错误
+func marshalRawMessage(buf *Buffer, raw []byte, opts SerializeOptions, depth int) error {
+	if len(raw) == 0 {
+		buf.WriteString("null")
+		return nil
+	}
+	n := parseRootNode(raw)
+	if !n.Exists() || !isValidJSONSimple(raw) {
+		return fmt.Errorf("invalid json.RawMessage: %.32q", raw)
+	}
+	return marshalRawNode(buf, n, opts, depth)
+}
+
 // marshalValue 序列化反射值
 func marshalValue(buf *Buffer, rv reflect.Value, opts SerializeOptions, depth int) error {
 	if !rv.IsValid() {
@@ -277,6 +425,11 @@ func marshalValue(buf *Buffer, rv reflect.Value, opts SerializeOptions, depth in
 		rv = rv.Elem()
 	}
 
+	// 已注册的自定义序列化函数优先
+	if handled, err := encodeCustom(buf, rv); handled {
+		return err
+	}
+
 	switch rv.Kind() {
 	case reflect.Bool:
 		if rv.Bool() {
@@ -292,18 +445,25 @@ func marshalValue(buf *Buffer, rv reflect.Value, opts SerializeOptions, depth in
 		writeUint(buf, rv.Uint())
 
 	case reflect.Float32, reflect.Float64:
-		writeFloat(buf, rv.Float(), opts.FloatPrecision)
+		writeFloat(buf, rv.Float(), rv.Type().Bits(), opts.FloatPrecision)
 
 	case reflect.String:
 		writeString(buf, rv.String(), opts.EscapeHTML)
 
 	case reflect.Slice, reflect.Array:
+		if rv.Type() == rawMessageType {
+			return marshalRawMessage(buf, rv.Bytes(), opts, depth)
+		}
 		return marshalSlice(buf, rv, opts, depth)
 
 	case reflect.Map:
 		return marshalMap(buf, rv, opts, depth)
 
 	case reflect.Struct:
+		// Node 按其 JSON 内容序列化
+		if rv.Type() == nodeType && rv.CanInterface() {
+			return marshalRawNode(buf, rv.Interface().(Node), opts, depth)
+		}
 		return marshalStruct(buf, rv, opts, depth)
 
 	default:
@@ -330,6 +490,14 @@ func fastMarshalValue(buf *Buffer, rv reflect.Value) {
 		rv = rv.Elem()
 	}
 
+	if fn, ok := lookupEncoder(rv.Type()); ok {
+		mark := len(buf.buf)
+		if fn(buf, rv) != nil {
+			buf.buf = append(buf.buf[:mark], "null"...)
+		}
+		return
+	}
+
 	switch rv.Kind() {
 	case reflect.Bool:
 		if rv.Bool() {
@@ -345,18 +513,33 @@ func fastMarshalValue(buf *Buffer, rv reflect.Value) {
 		writeUint(buf, rv.Uint())
 
 	case reflect.Float32, reflect.Float64:
-		writeFloat(buf, rv.Float(), -1)
+		writeFloat(buf, rv.Float(), rv.Type().Bits(), -1)
 
 	case reflect.String:
 		writeStringFast(buf, rv.String())
 
 	case reflect.Slice, reflect.Array:
+		if rv.Type() == rawMessageType {
+			mark := len(buf.buf)
+			if marshalRawMessage(buf, rv.Bytes(), DefaultSerializeOptions, 0) != nil {
+				buf.buf = append(buf.buf[:mark], "null"...)
+			}
+			return
+		}
 		fastMarshalSlice(buf, rv)
 
 	case reflect.Map:
 		fastMarshalMap(buf, rv)
 
 	case reflect.Struct:
+		if rv.Type() == nodeType && rv.CanInterface() {
+			if n := rv.Interface().(Node); n.Exists() {
+				buf.Write(n.Raw())
+			} else {
+				buf.WriteString("null")
+			}
+			return
+		}
 		fastMarshalStruct(buf, rv)
 
 	default:
@@ -423,22 +606,23 @@ func writeUint(buf *Buffer, n uint64) {
 	}
 }
 
-// writeFloat 写入浮点数
-func writeFloat(buf *Buffer, f float64, precision int) {
-	if f != f { // NaN
+// writeFloat 写入浮点数，bitSize 为源类型的位数（32 或 64），precision < 0 时使用最短往返表示
+func writeFloat(buf *Buffer, f float64, bitSize int, precision int) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
 		buf.WriteString("null")
 		return
 	}
 
+	if precision < 0 {
+		buf.buf = AppendNumber(buf.buf, f, bitSize)
+		return
+	}
+
 	if f > 1e20 || f < -1e20 {
 		// 使用科学计数法
-		buf.WriteString(strconv.FormatFloat(f, 'e', precision, 64))
+		buf.buf = strconv.AppendFloat(buf.buf, f, 'e', precision, bitSize)
 	} else {
-		if precision >= 0 {
-			buf.WriteString(strconv.FormatFloat(f, 'f', precision, 64))
-		} else {
-			buf.WriteString(strconv.FormatFloat(f, 'g', -1, 64))
-		}
+		buf.buf = strconv.AppendFloat(buf.buf, f, 'f', precision, bitSize)
 	}
 }
 
@@ -373,7 +373,7 @@ func (sm *StreamMarshaler) WriteField(key string, value interface{}) error {
 	buf := getBuffer()
 	defer putBuffer(buf)
 
-	writeString(buf, key, sm.opts.EscapeHTML)
+	writeString(buf, sm.opts.outputKey(key), sm.opts.EscapeHTML)
 	buf.WriteByte(':')
 
 	if sm.opts.Indent != "" {
@@ -1,7 +1,11 @@
 package fxjson
 
 import (
+	"encoding/json"
 	"fmt"
+	"math"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -235,6 +239,90 @@ func TestMarshalWithOptions(t *testing.T) {
 	}
 }
 
+// TestMarshalFieldNaming 测试字段命名风格
+func TestMarshalFieldNaming(t *testing.T) {
+	type Account struct {
+		UserName   string
+		UserID     int
+		HTTPServer string
+		Email      string `json:"mail"`
+	}
+	acc := Account{UserName: "bob", UserID: 7, HTTPServer: "s1", Email: "b@x.io"}
+
+	tests := []struct {
+		policy NamingPolicy
+		want   string
+	}{
+		{NamingAsIs, `{"UserName":"bob","UserID":7,"HTTPServer":"s1","mail":"b@x.io"}`},
+		{NamingSnakeCase, `{"user_name":"bob","user_id":7,"http_server":"s1","mail":"b@x.io"}`},
+		{NamingCamelCase, `{"userName":"bob","userID":7,"httpServer":"s1","mail":"b@x.io"}`},
+		{NamingPascalCase, `{"UserName":"bob","UserID":7,"HTTPServer":"s1","mail":"b@x.io"}`},
+	}
+
+	for _, tt := range tests {
+		opts := DefaultSerializeOptions
+		opts.FieldNaming = tt.policy
+		got, err := MarshalWithOptions(acc, opts)
+		if err != nil {
+			t.Fatalf("%s: MarshalWithOptions failed: %v", tt.policy, err)
+		}
+		if string(got) != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.policy, got, tt.want)
+		}
+	}
+
+	if got := ToPascalCase("user_name"); got != "UserName" {
+		t.Errorf("ToPascalCase: got %q", got)
+	}
+	if got := ToCamelCase("user-name"); got != "userName" {
+		t.Errorf("ToCamelCase: got %q", got)
+	}
+}
+
+// TestMarshalKeyTransform 测试对象键转换
+func TestMarshalKeyTransform(t *testing.T) {
+	type Event struct {
+		EventID   int               `json:"EventID"`
+		CreatedBy string            `json:"createdBy"`
+		Labels    map[string]string `json:"Labels"`
+		Payload   Node              `json:"Payload"`
+	}
+	ev := Event{
+		EventID:   9,
+		CreatedBy: "ops",
+		Labels:    map[string]string{"TeamName": "core", "AppID": "x1"},
+		Payload:   FromString(`{"retryCount":2,"lastError":{"errorCode":5}}`),
+	}
+
+	opts := DefaultSerializeOptions
+	opts.SortKeys = true
+	opts.KeyTransform = ToSnakeCase
+	got, err := MarshalWithOptions(ev, opts)
+	if err != nil {
+		t.Fatalf("MarshalWithOptions failed: %v", err)
+	}
+	want := `{"event_id":9,"created_by":"ops","labels":{"app_id":"x1","team_name":"core"},"payload":{"last_error":{"error_code":5},"retry_count":2}}`
+	if string(got) != want {
+		t.Errorf("snake: got %s, want %s", got, want)
+	}
+
+	opts.KeyTransform = NamingCamelCase.Apply
+	node, err := FromString(`{"user_name":"bob","home_address":{"zip_code":"1"}}`).ToJSONBytesWithOptions(opts)
+	if err != nil {
+		t.Fatalf("ToJSONBytesWithOptions failed: %v", err)
+	}
+	if want := `{"homeAddress":{"zipCode":"1"},"userName":"bob"}`; string(node) != want {
+		t.Errorf("camel: got %s, want %s", node, want)
+	}
+
+	opts = DefaultSerializeOptions
+	opts.KeyTransform = strings.ToUpper
+	got, err = MarshalWithOptions(map[string]int{"a": 1}, opts)
+	if err != nil || string(got) != `{"A":1}` {
+		t.Errorf("custom: got %s, %v", got, err)
+	}
+}
+
 // TestNodeToJSON 测试Node到JSON的序列化
 func TestNodeToJSON(t *testing.T) {
 	jsonStr := `{"name":"John","age":30,"tags":["a","b"],"address":{"city":"NYC"}}`
@@ -267,6 +355,114 @@ func TestNodeToJSON(t *testing.T) {
 	}
 }
 
+// TestRawFieldPassthrough 测试 Node 与 json.RawMessage 字段的原样输出与捕获
+func TestRawFieldPassthrough(t *testing.T) {
+	type Envelope struct {
+		ID      int             `json:"id"`
+		Payload Node            `json:"payload"`
+		Extra   json.RawMessage `json:"extra"`
+		Empty   json.RawMessage `json:"empty"`
+	}
+
+	input := []byte(`{"id":1,"payload":{"b": "é", "a": [1, 2]},"extra":[true, null],"empty":null}`)
+	var env Envelope
+	if err := DecodeStruct(input, &env); err != nil {
+		t.Fatalf("DecodeStruct failed: %v", err)
+	}
+	if string(env.Payload.Raw()) != `{"b": "é", "a": [1, 2]}` {
+		t.Errorf("Payload = %s", env.Payload.Raw())
+	}
+	if &env.Payload.Raw()[0] != &input[18] {
+		t.Error("Node field should reference the input buffer")
+	}
+	if string(env.Extra) != `[true, null]` || string(env.Empty) != "null" {
+		t.Errorf("Extra = %s, Empty = %s", env.Extra, env.Empty)
+	}
+	input[18] = 'X'
+	if string(env.Extra) != `[true, null]` {
+		t.Error("json.RawMessage field should own a copy")
+	}
+	input[18] = '{'
+
+	var viaNode Envelope
+	if err := FromBytes(input).Decode(&viaNode); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if string(viaNode.Extra) != `[true, null]` || viaNode.Payload.Get("a").Len() != 2 {
+		t.Errorf("Decode = %+v", viaNode)
+	}
+
+	got, err := Marshal(env)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	want := `{"id":1,"payload":{"b": "é", "a": [1, 2]},"extra":[true, null],"empty":null}`
+	if string(got) != want {
+		t.Errorf("Marshal = %s, want %s", got, want)
+	}
+	if fast := FastMarshal(env); string(fast) != want {
+		t.Errorf("FastMarshal = %s, want %s", fast, want)
+	}
+
+	// 需要改写输出的选项仍按选项重新序列化
+	opts := DefaultSerializeOptions
+	opts.SortKeys = true
+	got, err = MarshalWithOptions(env, opts)
+	if err != nil {
+		t.Fatalf("MarshalWithOptions failed: %v", err)
+	}
+	if want := `{"id":1,"payload":{"a":[1,2],"b":"é"},"extra":[true,null],"empty":null}`; string(got) != want {
+		t.Errorf("MarshalWithOptions = %s, want %s", got, want)
+	}
+
+	if _, err := Marshal(Envelope{Extra: json.RawMessage(`{"a":`)}); err == nil {
+		t.Error("invalid json.RawMessage should fail")
+	}
+	if got, _ := Marshal(Envelope{}); string(got) != `{"id":0,"payload":null,"extra":null,"empty":null}` {
+		t.Errorf("zero Envelope = %s", got)
+	}
+}
+
+// TestNodeMarshalInto 测试无副本的节点序列化
+func TestNodeMarshalInto(t *testing.T) {
+	node := FromString(`{"b": [1, 2], "a": "x"}`)
+
+	buf := NewBuffer()
+	defer buf.Release()
+	buf.WriteByte('[')
+	if err := node.MarshalInto(buf, PrettySerializeOptions); err != nil {
+		t.Fatalf("MarshalInto failed: %v", err)
+	}
+	buf.WriteByte(',')
+	if err := node.Get("b").MarshalInto(buf, DefaultSerializeOptions); err != nil {
+		t.Fatalf("MarshalInto failed: %v", err)
+	}
+	buf.WriteByte(']')
+	if want := "[{\n  \"a\": \"x\",\n  \"b\": [\n    1,\n    2\n  ]\n},[1,2]]"; buf.String() != want {
+		t.Errorf("MarshalInto = %q, want %q", buf.String(), want)
+	}
+
+	view, err := node.ToJSONBytesNoCopy()
+	if err != nil {
+		t.Fatalf("ToJSONBytesNoCopy failed: %v", err)
+	}
+	// 其他序列化调用不会覆盖未归还的缓冲区
+	for i := 0; i < 10; i++ {
+		if _, err := FromString(`{"name":"BOB!!"}`).ToJSONBytes(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if want := `{"b":[1,2],"a":"x"}`; string(view.BytesView()) != want {
+		t.Errorf("ToJSONBytesNoCopy = %s, want %s", view.BytesView(), want)
+	}
+	view.Release()
+	missing, err := node.Get("nope").ToJSONBytesNoCopy()
+	if err != nil || missing.String() != "null" {
+		t.Errorf("missing node = %v, %v", missing, err)
+	}
+	missing.Release()
+}
+
 // TestBatchMarshal 测试批量序列化
 func TestBatchMarshal(t *testing.T) {
 	persons := []interface{}{
@@ -330,6 +526,58 @@ func TestStreamMarshal(t *testing.T) {
 	}
 }
 
+// badMarshaler 注册为总是失败的自定义类型
+type badMarshaler struct{}
+
+// TestBufferEncode 测试公开的 Buffer 组合序列化
+func TestBufferEncode(t *testing.T) {
+	buf := NewBuffer()
+	defer buf.Release()
+
+	buf.WriteString(`{"meta":`)
+	if err := buf.EncodeValue(map[string]int{"v": 1}); err != nil {
+		t.Fatalf("EncodeValue failed: %v", err)
+	}
+	buf.WriteString(`,"data":`)
+	if err := buf.EncodeNode(FromString(`[1, {"a": "x"}]`)); err != nil {
+		t.Fatalf("EncodeNode failed: %v", err)
+	}
+	buf.WriteString(`,"name":`)
+	buf.WriteJSONString(`say "hi"`)
+	buf.WriteByte('}')
+
+	want := `{"meta":{"v":1},"data":[1,{"a":"x"}],"name":"say \"hi\""}`
+	if got := string(buf.BytesView()); got != want {
+		t.Errorf("BytesView = %s, want %s", got, want)
+	}
+	if buf.Len() != len(want) {
+		t.Errorf("Len = %d, want %d", buf.Len(), len(want))
+	}
+
+	copied := buf.BytesCopy()
+	var sb strings.Builder
+	if n, err := buf.WriteTo(&sb); err != nil || n != int64(len(want)) || sb.String() != want {
+		t.Errorf("WriteTo = %d, %v, %q", n, err, sb.String())
+	}
+
+	// 失败的编码不会留下部分输出
+	badType := reflect.TypeOf(badMarshaler{})
+	RegisterEncoder(badType, func(*Buffer, reflect.Value) error { return fmt.Errorf("boom") })
+	defer RegisterEncoder(badType, nil)
+	if err := buf.EncodeValue(map[string]any{"bad": badMarshaler{}}); err == nil {
+		t.Fatal("EncodeValue should fail")
+	}
+	if string(buf.BytesView()) != want {
+		t.Errorf("buffer changed after failed encode: %s", buf.BytesView())
+	}
+
+	buf.Reset()
+	buf.WriteString("reused")
+	if string(copied) != want {
+		t.Errorf("BytesCopy should not alias the buffer: %s", copied)
+	}
+}
+
 // TestPerformance 性能测试
 func TestPerformance(t *testing.T) {
 	if testing.Short() {
@@ -464,3 +712,44 @@ func BenchmarkStructMarshal(b *testing.B) {
 		}
 	}
 }
+
+// TestFormatNumber 测试最短往返数字格式化
+func TestFormatNumber(t *testing.T) {
+	tests := []struct {
+		in   float64
+		want string
+	}{
+		{0, "0"},
+		{1, "1"},
+		{-2.5, "-2.5"},
+		{1e20, "100000000000000000000"},
+		{1e21, "1e+21"},
+		{1.5e-7, "1.5e-7"},
+		{0.000001, "0.000001"},
+		{123456789.125, "123456789.125"},
+		{math.NaN(), "null"},
+		{math.Inf(-1), "null"},
+	}
+	for _, tt := range tests {
+		if got := FormatNumber(tt.in); got != tt.want {
+			t.Errorf("FormatNumber(%v) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	// float32 按其自身精度输出，不暴露 float64 展开后的尾数
+	data, err := Marshal(map[string]interface{}{"f32": float32(0.1), "inf": math.Inf(1)})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	node := FromBytes(data)
+	if raw := string(node.Get("f32").Raw()); raw != "0.1" {
+		t.Errorf("float32 got %s", raw)
+	}
+	if !node.Get("inf").IsNull() {
+		t.Errorf("Inf should marshal as null, got %s", data)
+	}
+
+	if got := AppendNumber([]byte("x="), 2.5, 64); string(got) != "x=2.5" {
+		t.Errorf("AppendNumber got %q", got)
+	}
+}
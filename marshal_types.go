@@ -43,7 +43,7 @@ func marshalStruct(buf *Buffer, rv reflect.Value, opts SerializeOptions, depth i
 		}
 
 		// 写入键
-		writeString(buf, field.jsonName, opts.EscapeHTML)
+		writeString(buf, opts.outputKey(field.outputName(opts.FieldNaming)), opts.EscapeHTML)
 		buf.WriteByte(':')
 
 		if hasIndent {
@@ -161,9 +161,22 @@ func marshalMap(buf *Buffer, rv reflect.Value, opts SerializeOptions, depth int)
 
 	keys := rv.MapKeys()
 
+	// 存在 KeyTransform 时预先计算输出键，排序也按转换后的键进行
+	var names []string
+	if opts.KeyTransform != nil {
+		names = make([]string, len(keys))
+		for i, key := range keys {
+			names[i] = opts.KeyTransform(getStringFromValue(key))
+		}
+	}
+
 	// 排序键（如果启用）
 	if opts.SortKeys {
-		sortMapKeys(keys)
+		if names != nil {
+			sort.Sort(mapKeysByName{keys, names})
+		} else {
+			sortMapKeys(keys)
+		}
 	}
 
 	buf.WriteByte('{')
@@ -176,7 +189,7 @@ func marshalMap(buf *Buffer, rv reflect.Value, opts SerializeOptions, depth int)
 		depth++
 	}
 
-	for _, key := range keys {
+	for i, key := range keys {
 		value := rv.MapIndex(key)
 
 		// 处理omitempty
@@ -194,7 +207,12 @@ func marshalMap(buf *Buffer, rv reflect.Value, opts SerializeOptions, depth int)
 		}
 
 		// 写入键（必须是字符串）
-		keyStr := getStringFromValue(key)
+		var keyStr string
+		if names != nil {
+			keyStr = names[i]
+		} else {
+			keyStr = getStringFromValue(key)
+		}
 		writeString(buf, keyStr, opts.EscapeHTML)
 		buf.WriteByte(':')
 
@@ -219,6 +237,19 @@ func marshalMap(buf *Buffer, rv reflect.Value, opts SerializeOptions, depth int)
 	return nil
 }
 
+// mapKeysByName 按输出键名排序 map 键
+type mapKeysByName struct {
+	keys  []reflect.Value
+	names []string
+}
+
+func (m mapKeysByName) Len() int           { return len(m.keys) }
+func (m mapKeysByName) Less(i, j int) bool { return m.names[i] < m.names[j] }
+func (m mapKeysByName) Swap(i, j int) {
+	m.keys[i], m.keys[j] = m.keys[j], m.keys[i]
+	m.names[i], m.names[j] = m.names[j], m.names[i]
+}
+
 // fastMarshalMap 快速序列化Map
 func fastMarshalMap(buf *Buffer, rv reflect.Value) {
 	if rv.IsNil() {
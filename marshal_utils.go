@@ -2,10 +2,48 @@ package fxjson
 
 import (
 	"fmt"
+	"math"
 	"reflect"
+	"strconv"
 	"time"
 )
 
+// FormatNumber 以最短往返精度格式化浮点数，与 encoding/json 的输出一致：
+// 1e-6 <= |f| < 1e21 使用定点表示（整数不带小数点），其余使用科学计数法（如 1e+21、1e-7）；
+// NaN 与 ±Inf 不是合法的 JSON 数字，返回 "null"
+func FormatNumber(f float64) string {
+	var scratch [32]byte
+	return string(AppendNumber(scratch[:0], f, 64))
+}
+
+// AppendNumber 按 FormatNumber 的格式将浮点数追加到 dst，bitSize 为 32 时按 float32 的最短表示输出
+func AppendNumber(dst []byte, f float64, bitSize int) []byte {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return append(dst, "null"...)
+	}
+
+	format := byte('f')
+	if abs := math.Abs(f); abs != 0 {
+		if bitSize == 32 {
+			abs = float64(float32(abs))
+		}
+		if abs < 1e-6 || abs >= 1e21 {
+			format = 'e'
+		}
+	}
+
+	dst = strconv.AppendFloat(dst, f, format, -1, bitSize)
+	if format == 'e' {
+		// 将两位负指数 e-07 规范为 e-7
+		n := len(dst)
+		if n >= 4 && dst[n-4] == 'e' && dst[n-3] == '-' && dst[n-2] == '0' {
+			dst[n-2] = dst[n-1]
+			dst = dst[:n-1]
+		}
+	}
+	return dst
+}
+
 // CompactJSON 压缩JSON字符串（移除空白字符）
 func CompactJSON(src []byte) []byte {
 	buf := getBuffer()
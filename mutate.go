@@ -0,0 +1,333 @@
+package fxjson
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// fieldSpan 对象字段在原始数据中的字节范围
+type fieldSpan struct {
+	keyStart   int // 键的起始引号
+	keyEnd     int // 键的结束引号之后
+	valueStart int
+	valueEnd   int
+}
+
+// forEachFieldSpan 遍历对象字段及其字节范围，键已解转义，fn 返回 false 时停止
+func (n Node) forEachFieldSpan(fn func(key string, span fieldSpan) bool) {
+	if n.typ != 'o' {
+		return
+	}
+	data := n.getWorkingData()
+	pos := n.start + 1
+	end := n.end - 1
+
+	var scratch []byte
+	for pos < end {
+		for pos < end && (data[pos] <= ' ' || data[pos] == ',') {
+			pos++
+		}
+		if pos >= end || data[pos] != '"' {
+			return
+		}
+
+		var span fieldSpan
+		span.keyStart = pos
+		span.keyEnd = skipStringSimple(data, pos, end)
+		if span.keyEnd < pos+2 || data[span.keyEnd-1] != '"' {
+			return
+		}
+		key := keyString(data[pos+1:span.keyEnd-1], &scratch)
+
+		pos = span.keyEnd
+		for pos < end && data[pos] <= ' ' {
+			pos++
+		}
+		if pos >= end || data[pos] != ':' {
+			return
+		}
+		pos++
+		for pos < end && data[pos] <= ' ' {
+			pos++
+		}
+
+		span.valueStart = pos
+		span.valueEnd = skipValueFast(data, pos, end)
+		if span.valueEnd <= pos {
+			return
+		}
+		if !fn(key, span) {
+			return
+		}
+		pos = span.valueEnd
+	}
+}
+
+// findFieldSpan 查找对象中指定键的字段范围
+func (n Node) findFieldSpan(key string) (fieldSpan, bool) {
+	var found fieldSpan
+	ok := false
+	n.forEachFieldSpan(func(k string, span fieldSpan) bool {
+		if k == key {
+			found, ok = span, true
+			return false
+		}
+		return true
+	})
+	return found, ok
+}
+
+// splitFieldPath 将路径拆分为父路径与最后一段对象键
+func splitFieldPath(path string) (parent, key string, err error) {
+	if path == "" {
+		return "", "", fmt.Errorf("empty path")
+	}
+	idx := strings.LastIndexByte(path, '.')
+	parent, key = path[:max(idx, 0)], path[idx+1:]
+	if key == "" || strings.ContainsAny(key, "[]") {
+		return "", "", fmt.Errorf("path %q must address an object field", path)
+	}
+	if idx >= 0 && parent == "" {
+		return "", "", fmt.Errorf("invalid path %q", path)
+	}
+	return parent, key, nil
+}
+
+// resolveParentObject 定位路径所在的父对象
+func resolveParentObject(root Node, path string) (Node, string, error) {
+	parentPath, key, err := splitFieldPath(path)
+	if err != nil {
+		return Node{}, "", err
+	}
+	parent := root
+	if parentPath != "" {
+		parent = root.GetPath(parentPath)
+	}
+	if !parent.Exists() {
+		return Node{}, "", fmt.Errorf("parent of path %q not found", path)
+	}
+	if parent.typ != 'o' {
+		return Node{}, "", fmt.Errorf("parent of path %q must be an object, got %s", path, parent.Kind())
+	}
+	return parent, key, nil
+}
+
+// quoteKey 将键编码为 JSON 字符串
+func quoteKey(key string) []byte {
+	var buf Buffer
+	writeString(&buf, key, false)
+	return buf.buf
+}
+
+// keyRename 一次键名替换：原始数据中键的范围与新的键名
+type keyRename struct {
+	path  string
+	span  fieldSpan
+	name  string
+	owner Node
+}
+
+// RenameKeys 按路径重命名对象键，renames 的键为字段路径（同 GetPath），值为新的键名
+// 仅替换键本身的字节，文档其余部分（包括格式与值）保持不变；路径均相对于原文档解析
+func RenameKeys(doc []byte, renames map[string]string) ([]byte, error) {
+	root := parseRootNode(doc)
+	if !root.Exists() {
+		return nil, fmt.Errorf("invalid JSON data")
+	}
+
+	edits := make([]keyRename, 0, len(renames))
+	for path, name := range renames {
+		parent, key, err := resolveParentObject(root, path)
+		if err != nil {
+			return nil, err
+		}
+		span, ok := parent.findFieldSpan(key)
+		if !ok {
+			return nil, fmt.Errorf("path %q not found", path)
+		}
+		edits = append(edits, keyRename{path: path, span: span, name: name, owner: parent})
+	}
+	if len(edits) == 0 {
+		return append([]byte(nil), doc...), nil
+	}
+
+	sort.Slice(edits, func(i, j int) bool { return edits[i].span.keyStart < edits[j].span.keyStart })
+	for i := 1; i < len(edits); i++ {
+		if edits[i].span.keyStart == edits[i-1].span.keyStart {
+			return nil, fmt.Errorf("paths %q and %q address the same key", edits[i-1].path, edits[i].path)
+		}
+	}
+
+	// 重命名后同一对象内不得出现重复键
+	renamed := make(map[int]string, len(edits))
+	for _, e := range edits {
+		renamed[e.span.keyStart] = e.name
+	}
+	checked := make(map[int]bool)
+	for _, e := range edits {
+		if checked[e.owner.start] {
+			continue
+		}
+		checked[e.owner.start] = true
+
+		names := make(map[string]struct{})
+		var conflict string
+		e.owner.forEachFieldSpan(func(key string, span fieldSpan) bool {
+			if name, ok := renamed[span.keyStart]; ok {
+				key = name
+			}
+			if _, dup := names[key]; dup {
+				conflict = key
+				return false
+			}
+			names[key] = struct{}{}
+			return true
+		})
+		if conflict != "" {
+			return nil, fmt.Errorf("renaming %q produces duplicate key %q", e.path, conflict)
+		}
+	}
+
+	result := make([]byte, 0, len(doc)+len(edits)*8)
+	last := 0
+	for _, e := range edits {
+		result = append(result, doc[last:e.span.keyStart]...)
+		result = append(result, quoteKey(e.name)...)
+		last = e.span.keyEnd
+	}
+	result = append(result, doc[last:]...)
+	return result, nil
+}
+
+// MovePath 将 from 处的字段移动到 to 处，to 的父对象必须已存在且不含同名键
+// 字段值的原始字节原样搬移，新字段追加在目标对象末尾并沿用其已有字段的缩进格式
+func MovePath(doc []byte, from, to string) ([]byte, error) {
+	root := parseRootNode(doc)
+	if !root.Exists() {
+		return nil, fmt.Errorf("invalid JSON data")
+	}
+	if _, _, err := splitFieldPath(to); err != nil {
+		return nil, err
+	}
+
+	parent, key, err := resolveParentObject(root, from)
+	if err != nil {
+		return nil, err
+	}
+	span, ok := parent.findFieldSpan(key)
+	if !ok {
+		return nil, fmt.Errorf("path %q not found", from)
+	}
+	if to == from || strings.HasPrefix(to, from+".") || strings.HasPrefix(to, from+"[") {
+		return nil, fmt.Errorf("cannot move %q into itself", from)
+	}
+
+	value := append([]byte(nil), doc[span.valueStart:span.valueEnd]...)
+	removed := removeField(doc, parent, span)
+
+	target, targetKey, err := resolveParentObject(parseRootNode(removed), to)
+	if err != nil {
+		return nil, err
+	}
+	if _, exists := target.findFieldSpan(targetKey); exists {
+		return nil, fmt.Errorf("target path %q already exists", to)
+	}
+	return insertField(removed, target, targetKey, value), nil
+}
+
+// removeField 从对象中删除字段，连同一个相邻的逗号
+func removeField(doc []byte, obj Node, span fieldSpan) []byte {
+	cutStart, cutEnd := removalRange(doc, obj, span.keyStart, span.valueEnd)
+	result := make([]byte, 0, len(doc)-(cutEnd-cutStart))
+	result = append(result, doc[:cutStart]...)
+	return append(result, doc[cutEnd:]...)
+}
+
+// removalRange 计算从容器中删除 [start, end) 处成员时需要剪掉的范围（含一个相邻的逗号）
+func removalRange(doc []byte, container Node, start, end int) (cutStart, cutEnd int) {
+	cutStart, cutEnd = start, end
+
+	pos := end
+	for pos < container.end-1 && doc[pos] <= ' ' {
+		pos++
+	}
+	if doc[pos] == ',' {
+		// 删除到下一个成员之前，保留其缩进
+		pos++
+		for pos < container.end-1 && doc[pos] <= ' ' {
+			pos++
+		}
+		return cutStart, pos
+	}
+
+	pos = start - 1
+	for pos > container.start && doc[pos] <= ' ' {
+		pos--
+	}
+	if doc[pos] == ',' {
+		// 最后一个成员：删除前面的逗号
+		return pos, cutEnd
+	}
+	// 唯一成员：清空容器
+	return container.start + 1, container.end - 1
+}
+
+// insertField 在对象末尾追加字段
+func insertField(doc []byte, obj Node, key string, value []byte) []byte {
+	pos, insert := fieldInsertion(doc, obj, key, value)
+	result := make([]byte, 0, len(doc)+len(insert))
+	result = append(result, doc[:pos]...)
+	result = append(result, insert...)
+	return append(result, doc[pos:]...)
+}
+
+// fieldInsertion 计算在对象末尾追加字段时的插入位置与插入内容
+func fieldInsertion(doc []byte, obj Node, key string, value []byte) (int, []byte) {
+	var lastSpan fieldSpan
+	count := 0
+	obj.forEachFieldSpan(func(_ string, span fieldSpan) bool {
+		lastSpan = span
+		count++
+		return true
+	})
+
+	field := quoteKey(key)
+	if count == 0 {
+		return obj.start + 1, append(append(field, ':'), value...)
+	}
+
+	// 沿用最后一个字段前的空白与键值分隔符
+	insert := []byte{','}
+	insert = append(insert, doc[leadingSpaceStart(doc, obj, lastSpan.keyStart):lastSpan.keyStart]...)
+	insert = append(insert, field...)
+	insert = append(insert, doc[lastSpan.keyEnd:lastSpan.valueStart]...)
+	insert = append(insert, value...)
+	return lastSpan.valueEnd, insert
+}
+
+// elementInsertion 计算在数组末尾追加元素时的插入位置与插入内容
+func elementInsertion(doc []byte, arr Node, value []byte) (int, []byte) {
+	lastStart, lastEnd := -1, -1
+	arr.arrayScan(func(_ int, _ []byte, start, end int) bool {
+		lastStart, lastEnd = start, end
+		return true
+	})
+	if lastStart < 0 {
+		return arr.start + 1, append([]byte(nil), value...)
+	}
+
+	// 沿用最后一个元素前的空白
+	insert := []byte{','}
+	insert = append(insert, doc[leadingSpaceStart(doc, arr, lastStart):lastStart]...)
+	insert = append(insert, value...)
+	return lastEnd, insert
+}
+
+// leadingSpaceStart 返回容器成员前连续空白的起始位置
+func leadingSpaceStart(doc []byte, container Node, pos int) int {
+	for pos > container.start+1 && doc[pos-1] <= ' ' {
+		pos--
+	}
+	return pos
+}
@@ -0,0 +1,139 @@
+package fxjson
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// NamingPolicy 字段命名风格
+type NamingPolicy int
+
+const (
+	NamingAsIs       NamingPolicy = iota // 保持原样（默认）
+	NamingSnakeCase                      // user_name
+	NamingCamelCase                      // userName
+	NamingPascalCase                     // UserName
+	namingPolicyCount
+)
+
+// Apply 按命名风格转换名称
+func (p NamingPolicy) Apply(name string) string {
+	switch p {
+	case NamingSnakeCase:
+		return ToSnakeCase(name)
+	case NamingCamelCase:
+		return ToCamelCase(name)
+	case NamingPascalCase:
+		return ToPascalCase(name)
+	default:
+		return name
+	}
+}
+
+// String 返回命名风格名称
+func (p NamingPolicy) String() string {
+	switch p {
+	case NamingAsIs:
+		return "as-is"
+	case NamingSnakeCase:
+		return "snake_case"
+	case NamingCamelCase:
+		return "camelCase"
+	case NamingPascalCase:
+		return "PascalCase"
+	default:
+		return "unknown"
+	}
+}
+
+// ToSnakeCase 转换为蛇形命名（UserID -> user_id）
+func ToSnakeCase(s string) string {
+	words := splitNameWords(s)
+	for i, w := range words {
+		words[i] = strings.ToLower(w)
+	}
+	return strings.Join(words, "_")
+}
+
+// ToCamelCase 转换为小驼峰命名（user_name -> userName）
+func ToCamelCase(s string) string {
+	words := splitNameWords(s)
+	if len(words) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.Grow(len(s))
+	b.WriteString(strings.ToLower(words[0]))
+	for _, w := range words[1:] {
+		b.WriteString(upperFirst(w))
+	}
+	return b.String()
+}
+
+// ToPascalCase 转换为大驼峰命名（user_name -> UserName）
+func ToPascalCase(s string) string {
+	words := splitNameWords(s)
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, w := range words {
+		b.WriteString(upperFirst(w))
+	}
+	return b.String()
+}
+
+// upperFirst 首字母大写
+func upperFirst(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError || unicode.IsUpper(r) {
+		return s
+	}
+	return string(unicode.ToUpper(r)) + s[size:]
+}
+
+// splitNameWords 按分隔符与大小写边界拆分单词（HTTPServer -> HTTP, Server）
+func splitNameWords(s string) []string {
+	var words []string
+	runes := []rune(s)
+	start := -1
+
+	for i, r := range runes {
+		if r == '_' || r == '-' || r == ' ' || r == '.' {
+			if start != -1 {
+				words = append(words, string(runes[start:i]))
+				start = -1
+			}
+			continue
+		}
+		if start == -1 {
+			start = i
+			continue
+		}
+		if unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			// aB 或 ABc 中的 B 开始新单词
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				words = append(words, string(runes[start:i]))
+				start = i
+			}
+		}
+	}
+	if start != -1 {
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
+
+// normalizeFieldName 规范化名称用于跨命名风格匹配（忽略大小写与分隔符）
+func normalizeFieldName(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		if r == '_' || r == '-' {
+			continue
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
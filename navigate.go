@@ -0,0 +1,90 @@
+package fxjson
+
+// Root 返回节点所在文档（同一缓冲区）的根节点，可用于在深层遍历中解析 $ref 等文档内引用
+// 延迟展开得到的节点，其根节点为所在嵌套 JSON 字符串的根
+func (n Node) Root() Node {
+	data := n.getWorkingData()
+	if !n.Exists() || len(data) == 0 {
+		return Node{}
+	}
+
+	start, end := 0, len(data)
+	for start < end && data[start] <= ' ' {
+		start++
+	}
+	for end > start && data[end-1] <= ' ' {
+		end--
+	}
+	if start >= end {
+		return Node{}
+	}
+	return Node{raw: n.raw, start: start, end: end, typ: detectType(data[start]), expanded: n.expanded, lazy: n.lazy}
+}
+
+// IsRoot 节点是否为所在文档的根节点
+func (n Node) IsRoot() bool {
+	root := n.Root()
+	return root.Exists() && root.start == n.start && root.end == n.end
+}
+
+// Parent 返回节点的父容器，根节点或不存在的节点返回空节点
+// 节点本身不记录上下文，父节点从根向下逐层定位，耗时与路径上各容器的大小成正比
+func (n Node) Parent() Node {
+	if !n.Exists() {
+		return Node{}
+	}
+
+	current := n.Root()
+	for current.Exists() && (current.start != n.start || current.end != n.end) {
+		var next Node
+		current.forEachMemberSpan(func(start, end int) bool {
+			if start <= n.start && n.end <= end {
+				next = current.nodeAt(current.getWorkingData(), start, end)
+				return false
+			}
+			return true
+		})
+		if !next.Exists() {
+			return Node{}
+		}
+		if next.start == n.start && next.end == n.end {
+			return current
+		}
+		current = next
+	}
+	return Node{}
+}
+
+// NextSibling 返回同一父容器中的下一个成员（对象字段的值或数组元素），没有时返回空节点
+func (n Node) NextSibling() Node {
+	parent := n.Parent()
+	if !parent.Exists() {
+		return Node{}
+	}
+
+	var sibling Node
+	found := false
+	parent.forEachMemberSpan(func(start, end int) bool {
+		if found {
+			sibling = parent.nodeAt(parent.getWorkingData(), start, end)
+			return false
+		}
+		found = start == n.start && end == n.end
+		return true
+	})
+	return sibling
+}
+
+// forEachMemberSpan 遍历对象字段值或数组元素的字节范围，fn 返回 false 时停止
+func (n Node) forEachMemberSpan(fn func(start, end int) bool) {
+	switch n.typ {
+	case 'o':
+		n.forEachFieldSpan(func(_ string, span fieldSpan) bool {
+			return fn(span.valueStart, span.valueEnd)
+		})
+	case 'a':
+		n.arrayScan(func(_ int, _ []byte, start, end int) bool {
+			return fn(start, end)
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package fxjson
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NormalizeOptions 文档清理选项，所有清理在一次遍历中完成
+type NormalizeOptions struct {
+	DropNulls            bool // 删除值为 null 的对象字段与数组元素
+	TrimStrings          bool // 去除字符串值首尾空白
+	LowercaseKeys        bool // 对象键转为小写，转换后重复的键返回错误
+	CoerceNumericStrings bool // 内容为合法 JSON 数字的字符串转为数字
+	RemoveEmpty          bool // 删除空对象与空数组（含清理后变空的容器），根节点除外
+}
+
+// Normalize 按选项清理文档并返回新的 JSON
+func (n Node) Normalize(opts NormalizeOptions) ([]byte, error) {
+	if !n.Exists() {
+		return nil, fmt.Errorf("node does not exist")
+	}
+
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	if _, err := n.normalizeValue(buf, &opts, true); err != nil {
+		return nil, err
+	}
+
+	result := make([]byte, len(buf.Bytes()))
+	copy(result, buf.Bytes())
+	return result, nil
+}
+
+// normalizeValue 写入清理后的值，返回 false 表示该值应被删除（已写入的内容由调用方回退）
+func (n Node) normalizeValue(buf *Buffer, opts *NormalizeOptions, root bool) (bool, error) {
+	data := n.getWorkingData()
+
+	switch n.typ {
+	case 'o':
+		return n.normalizeObject(buf, opts, root)
+	case 'a':
+		return n.normalizeArray(buf, opts, root)
+	case 's':
+		if !opts.TrimStrings && !opts.CoerceNumericStrings {
+			buf.Write(data[n.start:n.end])
+			return true, nil
+		}
+		str, err := n.String()
+		if err != nil {
+			return false, err
+		}
+		if opts.TrimStrings {
+			str = strings.TrimSpace(str)
+		}
+		// 只有开启 TrimStrings 时才忽略首尾空白，" 1" 否则保持为字符串
+		if opts.CoerceNumericStrings && isJSONNumberLiteral(str) {
+			buf.WriteString(str)
+			return true, nil
+		}
+		writeString(buf, str, false)
+		return true, nil
+	case 'l':
+		if opts.DropNulls && !root {
+			return false, nil
+		}
+		buf.WriteString("null")
+		return true, nil
+	default:
+		buf.Write(data[n.start:n.end])
+		return true, nil
+	}
+}
+
+// normalizeObject 写入清理后的对象
+func (n Node) normalizeObject(buf *Buffer, opts *NormalizeOptions, root bool) (bool, error) {
+	mark := len(buf.buf)
+	buf.WriteByte('{')
+
+	data := n.getWorkingData()
+	written := 0
+	var seen map[string]struct{}
+	if opts.LowercaseKeys {
+		seen = make(map[string]struct{})
+	}
+
+	var err error
+	n.forEachFieldSpan(func(key string, span fieldSpan) bool {
+		if opts.LowercaseKeys {
+			key = strings.ToLower(key)
+		}
+
+		fieldMark := len(buf.buf)
+		if written > 0 {
+			buf.WriteByte(',')
+		}
+		writeString(buf, key, false)
+		buf.WriteByte(':')
+
+		var keep bool
+		keep, err = n.nodeAt(data, span.valueStart, span.valueEnd).normalizeValue(buf, opts, false)
+		if err != nil {
+			err = fmt.Errorf("field %q: %w", key, err)
+			return false
+		}
+		if !keep {
+			buf.buf = buf.buf[:fieldMark]
+			return true
+		}
+		// 只检查保留下来的字段，被 DropNulls / RemoveEmpty 删除的字段不会造成冲突
+		if opts.LowercaseKeys {
+			if _, dup := seen[key]; dup {
+				err = fmt.Errorf("duplicate key %q after lowercasing", key)
+				return false
+			}
+			seen[key] = struct{}{}
+		}
+		written++
+		return true
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if written == 0 && opts.RemoveEmpty && !root {
+		buf.buf = buf.buf[:mark]
+		return false, nil
+	}
+	buf.WriteByte('}')
+	return true, nil
+}
+
+// normalizeArray 写入清理后的数组
+func (n Node) normalizeArray(buf *Buffer, opts *NormalizeOptions, root bool) (bool, error) {
+	mark := len(buf.buf)
+	buf.WriteByte('[')
+
+	written := 0
+	var err error
+	n.arrayScan(func(idx int, data []byte, start, end int) bool {
+		elemMark := len(buf.buf)
+		if written > 0 {
+			buf.WriteByte(',')
+		}
+
+		var keep bool
+		keep, err = n.nodeAt(data, start, end).normalizeValue(buf, opts, false)
+		if err != nil {
+			err = fmt.Errorf("element %d: %w", idx, err)
+			return false
+		}
+		if keep {
+			written++
+		} else {
+			buf.buf = buf.buf[:elemMark]
+		}
+		return true
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if written == 0 && opts.RemoveEmpty && !root {
+		buf.buf = buf.buf[:mark]
+		return false, nil
+	}
+	buf.WriteByte(']')
+	return true, nil
+}
+
+// isJSONNumberLiteral 检查字符串是否符合 JSON 数字语法
+func isJSONNumberLiteral(s string) bool {
+	i := 0
+	if i < len(s) && s[i] == '-' {
+		i++
+	}
+	if i >= len(s) {
+		return false
+	}
+
+	// 整数部分：0 或不以 0 开头的数字串
+	if s[i] == '0' {
+		i++
+	} else if s[i] >= '1' && s[i] <= '9' {
+		for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+			i++
+		}
+	} else {
+		return false
+	}
+
+	if i < len(s) && s[i] == '.' {
+		i++
+		digits := i
+		for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+			i++
+		}
+		if i == digits {
+			return false
+		}
+	}
+
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		i++
+		if i < len(s) && (s[i] == '+' || s[i] == '-') {
+			i++
+		}
+		digits := i
+		for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+			i++
+		}
+		if i == digits {
+			return false
+		}
+	}
+
+	return i == len(s)
+}
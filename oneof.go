@@ -0,0 +1,90 @@
+package fxjson
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// DecodeOneOf 依次尝试将对象 n 解码到候选结构体（均为指向结构体的非 nil 指针），返回第一个匹配的下标
+//
+// 候选匹配的条件：对象的每个键都对应结构体的字段（结构体含 remain 字段时不检查未知键），
+// 标签选项带 required 的字段（如 `json:"id,required"`）都存在，且解码没有错误。
+// 只有匹配的候选会写入解码结果；没有候选匹配时返回 -1 和列出各候选失败原因的错误
+//
+//	var created OrderCreated
+//	var refunded OrderRefunded
+//	switch i, err := fxjson.DecodeOneOf(node, &created, &refunded); i { ... }
+func DecodeOneOf(n Node, candidates ...any) (_ int, err error) {
+	defer recoverPanic(&err, "DecodeOneOf", n)
+
+	if n.typ != 'o' {
+		return -1, fmt.Errorf("DecodeOneOf requires an object, got %s", n.Kind())
+	}
+	if len(candidates) == 0 {
+		return -1, fmt.Errorf("DecodeOneOf requires at least one candidate")
+	}
+	for i, c := range candidates {
+		rv := reflect.ValueOf(c)
+		if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+			return -1, fmt.Errorf("candidate %d must be a non-nil pointer to a struct, got %T", i, c)
+		}
+	}
+
+	reasons := make([]string, 0, len(candidates))
+	for i, c := range candidates {
+		target := reflect.ValueOf(c).Elem()
+		t := target.Type()
+		if reason := n.oneOfMismatch(t); reason != "" {
+			reasons = append(reasons, fmt.Sprintf("%s: %s", t, reason))
+			continue
+		}
+		v := reflect.New(t).Elem()
+		if err := n.decodeValueFast(v, &DefaultDecodeOptions); err != nil {
+			reasons = append(reasons, fmt.Sprintf("%s: %v", t, err))
+			continue
+		}
+		target.Set(v)
+		return i, nil
+	}
+	return -1, fmt.Errorf("no candidate matched: %s", strings.Join(reasons, "; "))
+}
+
+// oneOfMismatch 按未知键与 required 字段检查对象是否匹配结构体类型 t，匹配时返回空字符串
+// 缺少多个 required 字段时报告声明顺序中的第一个
+func (n Node) oneOfMismatch(t reflect.Type) string {
+	opts := &DefaultDecodeOptions
+	fieldMap := getStructFieldMapFast(t, opts)
+	present := make(map[int]bool, len(fieldMap))
+	checkUnknown := getStructRemainField(t) < 0
+
+	var unknown string
+	n.ForEach(func(key string, _ Node) bool {
+		info, ok := lookupStructField(fieldMap, t, key, opts)
+		if ok {
+			present[info.Index] = true
+		} else if checkUnknown {
+			unknown = key
+			return false
+		}
+		return true
+	})
+	if unknown != "" {
+		return fmt.Sprintf("unknown field %q", unknown)
+	}
+
+	missing := -1
+	var name string
+	for _, info := range fieldMap {
+		if present[info.Index] || (missing >= 0 && info.Index > missing) {
+			continue
+		}
+		if hasTagOption(t.Field(info.Index), "required") {
+			missing, name = info.Index, info.JSONName
+		}
+	}
+	if missing >= 0 {
+		return fmt.Sprintf("missing required field %q", name)
+	}
+	return ""
+}
@@ -0,0 +1,68 @@
+package fxjson
+
+import (
+	"strings"
+	"testing"
+)
+
+type testOrderCreated struct {
+	ID    string  `json:"id,required"`
+	Total float64 `json:"total,required"`
+	Note  string  `json:"note"`
+}
+
+type testOrderRefunded struct {
+	ID     string `json:"id,required"`
+	Reason string `json:"reason,required"`
+}
+
+type testOrderAny struct {
+	ID    string          `json:"id"`
+	Extra map[string]Node `fx:",remain"`
+}
+
+// TestDecodeOneOf 测试按未知键与 required 字段选择候选结构体
+func TestDecodeOneOf(t *testing.T) {
+	var created testOrderCreated
+	var refunded testOrderRefunded
+
+	i, err := DecodeOneOf(FromString(`{"id":"r1","reason":"damaged"}`), &created, &refunded)
+	if err != nil || i != 1 || refunded.Reason != "damaged" || refunded.ID != "r1" {
+		t.Errorf("refund: i=%d err=%v %+v", i, err, refunded)
+	}
+	if created.ID != "" {
+		t.Errorf("non-matching candidate was written: %+v", created)
+	}
+
+	i, err = DecodeOneOf(FromString(`{"id":"c1","total":9.5}`), &created, &refunded)
+	if err != nil || i != 0 || created.Total != 9.5 {
+		t.Errorf("created: i=%d err=%v %+v", i, err, created)
+	}
+
+	// 解码失败的候选被跳过
+	var other testOrderAny
+	i, err = DecodeOneOf(FromString(`{"id":"x","total":"bad","flag":true}`), &created, &other)
+	if err != nil || i != 1 || other.ID != "x" || len(other.Extra) != 2 {
+		t.Errorf("remain: i=%d err=%v %+v", i, err, other)
+	}
+
+	i, err = DecodeOneOf(FromString(`{"id":"u1","status":"shipped"}`), &created, &refunded)
+	if i != -1 || err == nil {
+		t.Fatalf("no match: i=%d err=%v", i, err)
+	}
+	for _, want := range []string{`unknown field "status"`, "testOrderCreated", "testOrderRefunded"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("err = %v, want %q", err, want)
+		}
+	}
+	if _, err := DecodeOneOf(FromString(`{"id":"u1"}`), &created); err == nil || !strings.Contains(err.Error(), `missing required field "total"`) {
+		t.Errorf("missing required: err = %v", err)
+	}
+
+	if _, err := DecodeOneOf(FromString(`[1]`), &created); err == nil {
+		t.Error("array input should fail")
+	}
+	if _, err := DecodeOneOf(FromString(`{}`), created); err == nil || !strings.Contains(err.Error(), "pointer to a struct") {
+		t.Errorf("non-pointer candidate: err = %v", err)
+	}
+}
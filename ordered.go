@@ -0,0 +1,105 @@
+package fxjson
+
+// KeyValue 对象中的一个键值对
+type KeyValue struct {
+	Key   string
+	Value Node
+}
+
+// OrderedMap 保持原始键顺序的对象视图，重复的键按出现顺序全部保留，Get 返回最后一次出现的值（与 ToMap 一致）
+type OrderedMap struct {
+	pairs []KeyValue
+	index map[string]int // 键 -> pairs 中最后一次出现的下标
+}
+
+// ToOrderedMap 将对象节点转换为保持原始键顺序的 OrderedMap，非对象返回 nil
+func (n Node) ToOrderedMap() *OrderedMap {
+	if n.typ != 'o' {
+		return nil
+	}
+
+	m := &OrderedMap{index: make(map[string]int)}
+	n.ForEach(func(key string, value Node) bool {
+		m.index[key] = len(m.pairs)
+		m.pairs = append(m.pairs, KeyValue{Key: key, Value: value})
+		return true
+	})
+	return m
+}
+
+// Len 返回键值对数量（含重复键）
+func (m *OrderedMap) Len() int {
+	if m == nil {
+		return 0
+	}
+	return len(m.pairs)
+}
+
+// Get 获取键对应的值
+func (m *OrderedMap) Get(key string) (Node, bool) {
+	if m == nil {
+		return Node{}, false
+	}
+	i, ok := m.index[key]
+	if !ok {
+		return Node{}, false
+	}
+	return m.pairs[i].Value, true
+}
+
+// Keys 按原始顺序返回所有键
+func (m *OrderedMap) Keys() []string {
+	if m == nil {
+		return nil
+	}
+	keys := make([]string, len(m.pairs))
+	for i, kv := range m.pairs {
+		keys[i] = kv.Key
+	}
+	return keys
+}
+
+// Pairs 按原始顺序返回键值对（返回副本，修改不影响 OrderedMap）
+func (m *OrderedMap) Pairs() []KeyValue {
+	if m == nil {
+		return nil
+	}
+	return append([]KeyValue(nil), m.pairs...)
+}
+
+// Range 按原始顺序遍历键值对，fn 返回 false 时停止
+func (m *OrderedMap) Range(fn func(key string, value Node) bool) {
+	if m == nil || fn == nil {
+		return
+	}
+	for _, kv := range m.pairs {
+		if !fn(kv.Key, kv.Value) {
+			return
+		}
+	}
+}
+
+// MarshalJSON 按原始键顺序序列化为紧凑 JSON，值直接复用原始字节
+func (m *OrderedMap) MarshalJSON() ([]byte, error) {
+	if m == nil {
+		return []byte("null"), nil
+	}
+
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	buf.WriteByte('{')
+	for i, kv := range m.pairs {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		writeString(buf, kv.Key, false)
+		buf.WriteByte(':')
+		buf.Write(kv.Value.Raw())
+	}
+	buf.WriteByte('}')
+
+	result := make([]byte, len(buf.buf))
+	copy(result, buf.buf)
+	return result, nil
+}
@@ -0,0 +1,187 @@
+package fxjson
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// QueryPage Cursor 返回的一页结果
+type QueryPage struct {
+	Items []Node // 本页的元素
+	Next  string // 下一页的游标，没有更多元素时为空
+}
+
+// Cursor 按键集（keyset）分页执行查询：afterKey 为 nil 或空字符串时返回第一页，
+// 否则为上一页的 QueryPage.Next，返回排在其后的最多 pageSize 个元素
+//
+// 顺序由 SortBy 等排序字段决定，排序键相同的元素再按元素内容（Canonical 的摘要）排列，
+// 因此顺序不依赖元素在数组中的位置。游标只记录最后一个元素的排序键，
+// 对同一文档或重新获取的文档重复查询时，新增或删除的元素不会导致重复或遗漏已返回位置之后的元素。
+// 游标是不透明的字符串，只能用于排序字段相同的查询；Limit 与 Offset 在 Cursor 中不生效
+//
+//	page, err := node.Query().Where("status", "=", "open").SortBy("created", "desc").Cursor(nil, 50)
+//	for err == nil && page.Next != "" {
+//		page, err = node.Query().Where("status", "=", "open").SortBy("created", "desc").Cursor(page.Next, 50)
+//	}
+func (qb *QueryBuilder) Cursor(afterKey any, pageSize int) (*QueryPage, error) {
+	if pageSize <= 0 {
+		return nil, fmt.Errorf("page size must be positive, got %d", pageSize)
+	}
+	after, err := qb.decodeCursor(afterKey)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := *qb
+	filter.sortFields = nil
+	filter.limitCount = -1
+	filter.offsetVal = 0
+	items, err := filter.ToSlice()
+	if err != nil {
+		return nil, err
+	}
+
+	// 只保留游标之后的元素；与游标完全相同的元素已返回过 after.seen 个
+	rest := make([]cursorKey, 0, len(items))
+	skip := 0
+	for _, item := range items {
+		key, err := qb.cursorKeyOf(item)
+		if err != nil {
+			return nil, err
+		}
+		if after != nil {
+			cmp := qb.compareCursorKeys(key, *after)
+			if cmp < 0 {
+				continue
+			}
+			if cmp == 0 && skip < after.seen {
+				skip++
+				continue
+			}
+		}
+		rest = append(rest, key)
+	}
+	sort.SliceStable(rest, func(i, j int) bool {
+		return qb.compareCursorKeys(rest[i], rest[j]) < 0
+	})
+
+	page := &QueryPage{Items: make([]Node, 0, min(pageSize, len(rest)))}
+	for _, key := range rest[:min(pageSize, len(rest))] {
+		page.Items = append(page.Items, key.node)
+	}
+	if len(rest) > pageSize {
+		last := rest[pageSize-1]
+		last.seen = 0
+		for i := pageSize - 1; i >= 0 && qb.compareCursorKeys(rest[i], last) == 0; i-- {
+			last.seen++
+		}
+		if last.seen == pageSize && after != nil && qb.compareCursorKeys(last, *after) == 0 {
+			last.seen += after.seen
+		}
+		page.Next = qb.encodeCursor(last)
+	}
+	return page, nil
+}
+
+// cursorKey 元素在分页顺序中的位置
+type cursorKey struct {
+	node   Node   // 元素，解码得到的游标为空
+	fields []Node // 各排序字段的值
+	digest string // 元素 Canonical 结果的摘要，排序键相同时使用
+	seen   int    // 游标中：与该位置完全相同的元素已返回的数量
+}
+
+// cursorKeyOf 计算元素的分页位置
+func (qb *QueryBuilder) cursorKeyOf(item Node) (cursorKey, error) {
+	canonical, err := item.Canonical()
+	if err != nil {
+		return cursorKey{}, err
+	}
+	sum := sha256.Sum256(canonical)
+	key := cursorKey{node: item, fields: make([]Node, len(qb.sortFields)), digest: hex.EncodeToString(sum[:16])}
+	for i, sf := range qb.sortFields {
+		key.fields[i] = item.Get(sf.Field)
+	}
+	return key, nil
+}
+
+// compareCursorKeys 按排序字段与摘要比较两个位置
+func (qb *QueryBuilder) compareCursorKeys(a, b cursorKey) int {
+	for i, sf := range qb.sortFields {
+		if cmp := qb.compareSortField(sf, a.fields[i], b.fields[i]); cmp != 0 {
+			return cmp
+		}
+	}
+	return strings.Compare(a.digest, b.digest)
+}
+
+// cursorSignature 排序字段的描述，用于拒绝来自其他查询的游标
+func (qb *QueryBuilder) cursorSignature() string {
+	parts := make([]string, len(qb.sortFields))
+	for i, sf := range qb.sortFields {
+		parts[i] = fmt.Sprintf("%s %s %s", sf.Field, sf.Order, sf.Nulls)
+		if sf.hasCollation() {
+			parts[i] += fmt.Sprintf(" ci=%t natural=%t collator=%T", sf.CaseInsensitive, sf.Natural, sf.Collator)
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// encodeCursor 将位置编码为游标：{"q":签名,"k":[排序键],"h":摘要,"n":已返回数量} 的 base64url
+func (qb *QueryBuilder) encodeCursor(key cursorKey) string {
+	var buf bytes.Buffer
+	buf.WriteString(`{"q":`)
+	buf.WriteString(strconv.Quote(qb.cursorSignature()))
+	buf.WriteString(`,"k":[`)
+	for i, field := range key.fields {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if isNullOrMissing(field) {
+			buf.WriteString("null")
+		} else {
+			buf.Write(CompactJSON(field.Raw()))
+		}
+	}
+	fmt.Fprintf(&buf, `],"h":%q,"n":%d}`, key.digest, key.seen)
+	return base64.RawURLEncoding.EncodeToString(buf.Bytes())
+}
+
+// decodeCursor 解析 Cursor 的 afterKey，第一页返回 nil；
+// 游标不展开嵌套 JSON 字符串，内容为 JSON 的字符串排序键保持为字符串
+func (qb *QueryBuilder) decodeCursor(afterKey any) (*cursorKey, error) {
+	token, ok := afterKey.(string)
+	if !ok && afterKey != nil {
+		return nil, fmt.Errorf("unsupported cursor type %T", afterKey)
+	}
+	if token == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || validateJSON(data, DefaultParseOptions) != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	node := parseRootNode(data)
+	if sig, _ := node.Get("q").String(); sig != qb.cursorSignature() {
+		return nil, fmt.Errorf("cursor does not match the query sort order")
+	}
+	keys := node.Get("k")
+	seen, err := node.Get("n").Int()
+	if err != nil || seen < 0 || keys.Len() != len(qb.sortFields) {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	key := &cursorKey{fields: make([]Node, len(qb.sortFields)), seen: int(seen)}
+	key.digest, _ = node.Get("h").String()
+	for i := range key.fields {
+		key.fields[i] = keys.Index(i)
+	}
+	return key, nil
+}
@@ -0,0 +1,132 @@
+package fxjson
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// TestQueryCursor 测试键集分页遍历全部结果且不重复
+func TestQueryCursor(t *testing.T) {
+	node := FromBytes([]byte(`[
+		{"id": 1, "p": 3}, {"id": 2, "p": 1}, {"id": 3, "p": 3}, {"id": 4},
+		{"id": 5, "p": 2}, {"id": 6, "p": 1}, {"dup": true}, {"dup": true}, {"dup": true}
+	]`))
+	query := func() *QueryBuilder { return node.Query().SortBy("p", "desc") }
+
+	var seen []string
+	after := ""
+	for pages := 0; ; pages++ {
+		page, err := query().Cursor(after, 2)
+		if err != nil {
+			t.Fatalf("Cursor failed: %v", err)
+		}
+		if len(page.Items) == 0 || len(page.Items) > 2 || pages > 5 {
+			t.Fatalf("unexpected page %d with %d items", pages, len(page.Items))
+		}
+		for _, item := range page.Items {
+			seen = append(seen, string(item.Raw()))
+		}
+		if page.Next == "" {
+			break
+		}
+		after = page.Next
+	}
+
+	all, _ := query().ToSlice()
+	if len(seen) != len(all) {
+		t.Fatalf("paged %d items, want %d: %v", len(seen), len(all), seen)
+	}
+	for i, item := range all {
+		if got, want := FromString(seen[i]).Get("p").Raw(), item.Get("p").Raw(); string(got) != string(want) {
+			t.Errorf("item %d has p %s, want %s (sort order)", i, got, want)
+		}
+	}
+	if strings.Count(strings.Join(seen, ","), `{"dup": true}`) != 3 {
+		t.Errorf("identical elements should each be returned once: %v", seen)
+	}
+}
+
+// TestQueryCursorStable 测试重新获取的文档中元素位置变化时分页仍保持连续
+func TestQueryCursorStable(t *testing.T) {
+	first := FromBytes([]byte(`[{"id":1},{"id":2},{"id":3},{"id":4},{"id":5}]`))
+	page, err := first.Query().Where("id", ">", 0).SortBy("id", "asc").Cursor(nil, 2)
+	if err != nil || len(page.Items) != 2 || page.Next == "" {
+		t.Fatalf("first page = %v, %v", page, err)
+	}
+
+	// 重新获取：元素顺序改变、已返回的元素被删除、新增一个排在游标之前的元素
+	refetched := FromBytes([]byte(`[{"id":5},{"id":0},{"id":4},{"id":3}]`))
+	page, err = refetched.Query().Where("id", ">", 0).SortBy("id", "asc").Cursor(page.Next, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []int64
+	for _, item := range page.Items {
+		ids = append(ids, item.Get("id").IntOr(0))
+	}
+	if fmt.Sprint(ids) != "[3 4]" || page.Next == "" {
+		t.Errorf("second page = %v, next %q", ids, page.Next)
+	}
+}
+
+// TestQueryCursorErrors 测试无效参数与游标
+func TestQueryCursorErrors(t *testing.T) {
+	node := FromBytes([]byte(`[{"id":1},{"id":2},{"id":3}]`))
+	page, err := node.Query().SortBy("id", "asc").Cursor(nil, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]error{
+		"page size":    errOf(node.Query().Cursor(nil, 0)),
+		"cursor type":  errOf(node.Query().Cursor(42, 1)),
+		"garbage":      errOf(node.Query().SortBy("id", "asc").Cursor("not a cursor!", 1)),
+		"sort order":   errOf(node.Query().SortBy("id", "desc").Cursor(page.Next, 1)),
+		"not an array": errOf(node.Index(0).Query().Cursor(nil, 1)),
+	}
+	for name, err := range cases {
+		if err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+// TestQueryCursorNestedJSONKeys 测试内容为 JSON 的字符串排序键在游标中保持为字符串
+func TestQueryCursorNestedJSONKeys(t *testing.T) {
+	node, err := ParseBytes([]byte(`[{"k":"a"},{"k":"[3]"},{"k":"[1]"},{"k":"[2]"}]`), ParseOptions{LazyExpansion: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	after := ""
+	for pages := 0; pages < 10; pages++ {
+		page, err := node.Query().SortBy("k", "asc").Cursor(after, 1)
+		if err != nil {
+			t.Fatalf("Cursor failed: %v", err)
+		}
+		for _, item := range page.Items {
+			keys = append(keys, item.Get("k").StringOr(""))
+		}
+		if page.Next == "" {
+			break
+		}
+		after = page.Next
+	}
+	if got := strings.Join(keys, " "); got != "[1] [2] [3] a" {
+		t.Errorf("paged keys = %s", got)
+	}
+}
+
+// TestQueryCursorCollation 测试排序规则不同的查询不能共用游标
+func TestQueryCursorCollation(t *testing.T) {
+	node := FromBytes([]byte(`[{"k":"b"},{"k":"A"},{"k":"a"}]`))
+	page, err := node.Query().SortBy("k", "asc").Cursor(nil, 1)
+	if err != nil || page.Next == "" {
+		t.Fatalf("first page = %v, %v", page, err)
+	}
+	folded := node.Query().SortWith(SortField{Field: "k", Order: "asc", CaseInsensitive: true})
+	if _, err := folded.Cursor(page.Next, 1); err == nil {
+		t.Error("cursor of a binary sort should not be accepted by a case-insensitive sort")
+	}
+}
@@ -0,0 +1,43 @@
+package fxjson
+
+// PathCache 单个只读文档的路径解析缓存，按需启用
+//
+// 同一文档上反复解析相同路径（如每次渲染都对同一份数据求值几十个模板占位符）时，
+// 首次解析后缓存结果（包括不存在的路径），之后的查找只是一次 map 读取。
+// 不使用 PathCache 时 GetPath 保持零分配。PathCache 不是并发安全的。
+type PathCache struct {
+	root  Node
+	nodes map[string]Node
+}
+
+// NewPathCache 为 root 创建路径缓存
+//
+// Deprecated: 新代码请使用 cache.NewPath，该函数仅为兼容保留
+func NewPathCache(root Node) *PathCache {
+	return &PathCache{root: root, nodes: make(map[string]Node)}
+}
+
+// Root 返回缓存所属的根节点
+func (c *PathCache) Root() Node {
+	return c.root
+}
+
+// GetPath 与 Node.GetPath 相同，结果按路径缓存
+func (c *PathCache) GetPath(path string) Node {
+	if n, ok := c.nodes[path]; ok {
+		return n
+	}
+	n := c.root.GetPath(path)
+	c.nodes[path] = n
+	return n
+}
+
+// Len 返回已缓存的路径数量
+func (c *PathCache) Len() int {
+	return len(c.nodes)
+}
+
+// Reset 清空缓存
+func (c *PathCache) Reset() {
+	clear(c.nodes)
+}
@@ -0,0 +1,40 @@
+package fxjson
+
+import "testing"
+
+// TestPathCache 测试路径缓存与缓存模板渲染
+func TestPathCache(t *testing.T) {
+	node := FromString(`{"user":{"name":"Ann","tags":["a","b"]},"total":12.5}`)
+	cache := NewPathCache(node)
+
+	for i := 0; i < 3; i++ {
+		if s, _ := cache.GetPath("user.name").String(); s != "Ann" {
+			t.Errorf("GetPath(user.name) = %q", s)
+		}
+		if s, _ := cache.GetPath("user.tags[1]").String(); s != "b" {
+			t.Errorf("GetPath(user.tags[1]) = %q", s)
+		}
+		if cache.GetPath("user.missing").Exists() {
+			t.Error("GetPath(user.missing) should not exist")
+		}
+	}
+	if cache.Len() != 3 {
+		t.Errorf("Len() = %d, want 3", cache.Len())
+	}
+
+	out, err := RenderTemplateCached(`{{user.name}}: {{total | format "%.0f"}} {{user.age | default "?"}}`, cache)
+	if err != nil || out != "Ann: 12 ?" {
+		t.Errorf("RenderTemplateCached = %q, %v", out, err)
+	}
+	if want, _ := RenderTemplate(`{{user.name}}: {{total | format "%.0f"}} {{user.age | default "?"}}`, node); out != want {
+		t.Errorf("RenderTemplateCached = %q, RenderTemplate = %q", out, want)
+	}
+	if cache.Len() != 5 {
+		t.Errorf("Len() after render = %d, want 5", cache.Len())
+	}
+
+	cache.Reset()
+	if cache.Len() != 0 {
+		t.Errorf("Len() after Reset = %d", cache.Len())
+	}
+}
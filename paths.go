@@ -0,0 +1,64 @@
+package fxjson
+
+// LeafEntry 叶子节点：标量值、空对象或空数组
+type LeafEntry struct {
+	Path string   // 路径，格式同 Walk（如 "a.b[0]"），根节点为 ""
+	Type NodeType // 节点类型
+	Raw  []byte   // 原始 JSON 字面量，引用文档数据
+}
+
+// Leaves 按深度优先顺序返回全部叶子节点（标量值以及空对象、空数组）
+func (n Node) Leaves() []LeafEntry {
+	if !n.Exists() {
+		return nil
+	}
+	leaves := make([]LeafEntry, 0, estimateValueCount(n.Raw()))
+	n.Walk(func(path string, node Node) bool {
+		if (node.typ == 'o' || node.typ == 'a') && !isEmptyContainer(node) {
+			return true
+		}
+		leaves = append(leaves, LeafEntry{Path: path, Type: node.Kind(), Raw: node.Raw()})
+		return true
+	})
+	return leaves
+}
+
+// Paths 按深度优先顺序返回全部可寻址路径（包括容器与叶子，不含根节点），可直接用于 GetPath
+func (n Node) Paths() []string {
+	if !n.Exists() {
+		return nil
+	}
+	paths := make([]string, 0, estimateValueCount(n.Raw()))
+	n.Walk(func(path string, node Node) bool {
+		if path != "" {
+			paths = append(paths, path)
+		}
+		return true
+	})
+	return paths
+}
+
+// isEmptyContainer 对象或数组的括号之间是否只有空白
+func isEmptyContainer(n Node) bool {
+	data := n.getWorkingData()
+	for i := n.start + 1; i < n.end-1; i++ {
+		if data[i] > ' ' {
+			return false
+		}
+	}
+	return true
+}
+
+// estimateValueCount 通过统计字符串外的 '{'、'[' 与 ',' 估算文档中值的数量，用于预分配结果容量
+func estimateValueCount(data []byte) int {
+	count := 1
+	for i := 0; i < len(data); i++ {
+		switch data[i] {
+		case '"':
+			i = skipStringSimple(data, i, len(data)) - 1
+		case '{', '[', ',':
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,44 @@
+package fxjson
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestLeavesAndPaths 测试叶子节点与路径提取
+func TestLeavesAndPaths(t *testing.T) {
+	node := FromString(`{"a":{"b":1,"c":[true,null,"x"]},"d":[],"e":{},"f":"s,{["}`)
+
+	wantPaths := []string{"a", "a.b", "a.c", "a.c[0]", "a.c[1]", "a.c[2]", "d", "e", "f"}
+	if got := node.Paths(); !reflect.DeepEqual(got, wantPaths) {
+		t.Errorf("Paths() = %v, want %v", got, wantPaths)
+	}
+	for _, p := range node.Paths() {
+		if !node.GetPath(p).Exists() {
+			t.Errorf("path %q is not addressable", p)
+		}
+	}
+
+	want := []LeafEntry{
+		{"a.b", TypeNumber, []byte("1")},
+		{"a.c[0]", TypeBool, []byte("true")},
+		{"a.c[1]", TypeNull, []byte("null")},
+		{"a.c[2]", TypeString, []byte(`"x"`)},
+		{"d", TypeArray, []byte("[]")},
+		{"e", TypeObject, []byte("{}")},
+		{"f", TypeString, []byte(`"s,{["`)},
+	}
+	if got := node.Leaves(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Leaves() = %v, want %v", got, want)
+	}
+
+	if leaves := FromString(`42`).Leaves(); len(leaves) != 1 || leaves[0].Path != "" || string(leaves[0].Raw) != "42" {
+		t.Errorf("scalar root Leaves() = %v", leaves)
+	}
+	if paths := FromString(`42`).Paths(); len(paths) != 0 {
+		t.Errorf("scalar root Paths() = %v", paths)
+	}
+	if (Node{}).Leaves() != nil || (Node{}).Paths() != nil {
+		t.Error("missing node should return nil")
+	}
+}
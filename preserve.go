@@ -0,0 +1,230 @@
+package fxjson
+
+import (
+	"bytes"
+	"math/big"
+	"strings"
+)
+
+// OriginalBytes 返回节点在输入数据中的原始字节：与输入逐字节一致（空白、键顺序、数字写法与转义均保持原样），
+// 与输入共享内存，不会复制或重新序列化；对 Document 的节点即文档当前内容中的对应范围
+//
+// 节点来自展开的嵌套 JSON 字符串（FromBytes 等默认解析的立即展开、Expand 的结果）时，
+// 输入中不存在与之对应的字节，返回 nil，此时 Raw 返回展开后的数据
+func (n Node) OriginalBytes() []byte {
+	if len(n.expanded) > 0 || (n.lazy != nil && n.lazy.depth > 0) {
+		return nil
+	}
+	if n.start >= 0 && n.end <= len(n.raw) && n.start < n.end {
+		return n.raw[n.start:n.end]
+	}
+	return nil
+}
+
+// SetPreserveFormatting 开启或关闭保留格式模式，默认关闭
+//
+// 开启后 Set 替换已有的值时只改写实际变化的部分：语义相同的标量（如 1.0 与 1.00）保持原样，
+// 对象按字段比较，只替换、删除或追加变化的字段并保持原有键顺序，长度相同的数组按元素比较；
+// 写入的对象与数组按所在位置的缩进格式化，单行的容器中保持紧凑。
+// 因此修改后文档中未变化的区域与修改前逐字节一致，适用于需要最小差异的场景，如修改签名的配置文件
+func (d *Document) SetPreserveFormatting(on bool) {
+	d.preserve = on
+}
+
+// PreserveFormatting 返回是否开启了保留格式模式
+func (d *Document) PreserveFormatting() bool {
+	return d.preserve
+}
+
+// parentNode 返回路径所在的容器，根路径返回空节点
+func (d *Document) parentNode(path string) Node {
+	parentPath, _, _, err := splitDocumentPath(path)
+	if err != nil {
+		return Node{}
+	}
+	ps, ok := d.resolve(parentPath)
+	if !ok {
+		return Node{}
+	}
+	return d.node(ps)
+}
+
+// patchValue 将 [start, end) 处的值改写为 v，只替换实际变化的部分；parent 为值所在的容器，根节点为空
+func (d *Document) patchValue(parent Node, start, end int, v []byte) {
+	old := d.node(docSpan{start: start, end: end, typ: detectType(d.data[start])})
+	value := parseRootNode(v)
+	if old.typ == 'a' && value.typ == 'a' {
+		if olds, news := arrayBounds(old), arrayBounds(value); len(olds) == len(news) {
+			d.patchArray(old, olds, value, news)
+			return
+		}
+	}
+
+	switch {
+	case old.typ == 'o' && value.typ == 'o':
+		d.patchObject(old, value)
+	case sameScalar(old, value):
+	default:
+		like := old
+		if old.typ != 'o' && old.typ != 'a' {
+			like = parent
+		}
+		d.splice(start, end, d.layoutValue(like, lineIndent(d.data, start), v))
+	}
+}
+
+// patchObject 按字段改写对象：先从后向前替换或删除已有字段（保持前面字段的位置不变），再追加新字段
+func (d *Document) patchObject(obj, value Node) {
+	type member struct {
+		key  string
+		span fieldSpan
+	}
+	var members []member
+	obj.forEachFieldSpan(func(key string, span fieldSpan) bool {
+		members = append(members, member{key: strings.Clone(key), span: span})
+		return true
+	})
+
+	values := make(map[string][]byte)
+	var keys []string
+	value.forEachFieldSpan(func(key string, span fieldSpan) bool {
+		if _, dup := values[key]; !dup {
+			keys = append(keys, key)
+		}
+		values[key] = value.raw[span.valueStart:span.valueEnd]
+		return true
+	})
+
+	start, end := obj.start, obj.end
+	existing := make(map[string]bool, len(members))
+	for i := len(members) - 1; i >= 0; i-- {
+		m := members[i]
+		existing[m.key] = true
+		container := d.node(docSpan{start: start, end: end, typ: 'o'})
+		before := len(d.data)
+		if v, ok := values[m.key]; ok {
+			d.patchValue(container, m.span.valueStart, m.span.valueEnd, v)
+		} else {
+			cutStart, cutEnd := removalRange(d.data, container, m.span.keyStart, m.span.valueEnd)
+			d.splice(cutStart, cutEnd, nil)
+		}
+		end += len(d.data) - before
+	}
+
+	for _, key := range keys {
+		if existing[key] {
+			continue
+		}
+		container := d.node(docSpan{start: start, end: end, typ: 'o'})
+		v := d.layoutValue(container, d.memberIndent(container), values[key])
+		pos, insert := fieldInsertion(d.data, container, key, v)
+		d.splice(pos, pos, insert)
+		end += len(insert)
+	}
+}
+
+// patchArray 逐个改写长度相同的数组的元素（olds、news 为两者的元素范围），从后向前处理
+func (d *Document) patchArray(arr Node, olds [][2]int, value Node, news [][2]int) {
+	start, end := arr.start, arr.end
+	for i := len(olds) - 1; i >= 0; i-- {
+		container := d.node(docSpan{start: start, end: end, typ: 'a'})
+		before := len(d.data)
+		d.patchValue(container, olds[i][0], olds[i][1], value.raw[news[i][0]:news[i][1]])
+		end += len(d.data) - before
+	}
+}
+
+// arrayBounds 返回数组各元素的 [start, end) 范围
+func arrayBounds(arr Node) [][2]int {
+	var bounds [][2]int
+	arr.arrayScan(func(_ int, _ []byte, start, end int) bool {
+		bounds = append(bounds, [2]int{start, end})
+		return true
+	})
+	return bounds
+}
+
+// sameScalar 两个标量是否语义相同：数字按精确数值比较，字符串按解转义后的内容比较
+func sameScalar(a, b Node) bool {
+	if a.typ != b.typ {
+		return false
+	}
+	switch a.typ {
+	case 'n':
+		af, _, aErr := big.ParseFloat(string(a.Raw()), 10, exactNumberPrec, big.ToNearestEven)
+		bf, _, bErr := big.ParseFloat(string(b.Raw()), 10, exactNumberPrec, big.ToNearestEven)
+		return aErr == nil && bErr == nil && af.Cmp(bf) == 0
+	case 's':
+		ar, br := a.Raw(), b.Raw()
+		return bytes.Equal(appendUnescaped(nil, ar[1:len(ar)-1]), appendUnescaped(nil, br[1:len(br)-1]))
+	case 'b', 'l':
+		return bytes.Equal(a.Raw(), b.Raw())
+	}
+	return false
+}
+
+// layoutValue 按参照节点 like 的风格格式化要写入的对象或数组：like 跨多行时按文档的缩进单位展开，
+// 并在每个换行后加上 indent，否则压缩为单行；like 为空时参照整个文档，标量原样返回
+func (d *Document) layoutValue(like Node, indent []byte, v []byte) []byte {
+	if len(v) == 0 || (v[0] != '{' && v[0] != '[') {
+		return v
+	}
+	if !like.Exists() {
+		like = d.Root()
+	}
+	unit := indentUnit(d.data)
+	if unit == "" || bytes.IndexByte(like.Raw(), '\n') < 0 {
+		return CompactJSON(v)
+	}
+	pretty := PrettyJSONWithIndent(v, unit)
+	return bytes.ReplaceAll(pretty, []byte{'\n'}, append([]byte{'\n'}, indent...))
+}
+
+// memberIndent 返回容器中最后一个成员所在行的缩进，容器为空时返回容器所在行的缩进加一个缩进单位
+func (d *Document) memberIndent(container Node) []byte {
+	last := -1
+	switch container.typ {
+	case 'o':
+		container.forEachFieldSpan(func(_ string, span fieldSpan) bool {
+			last = span.keyStart
+			return true
+		})
+	case 'a':
+		if bounds := arrayBounds(container); len(bounds) > 0 {
+			last = bounds[len(bounds)-1][0]
+		}
+	}
+	if last >= 0 {
+		return lineIndent(d.data, last)
+	}
+	return append(append([]byte(nil), lineIndent(d.data, container.start)...), indentUnit(d.data)...)
+}
+
+// lineIndent 返回 pos 所在行开头的空白
+func lineIndent(data []byte, pos int) []byte {
+	lineStart := bytes.LastIndexByte(data[:pos], '\n') + 1
+	i := lineStart
+	for i < pos && (data[i] == ' ' || data[i] == '\t') {
+		i++
+	}
+	return data[lineStart:i]
+}
+
+// indentUnit 返回文档的缩进单位，即第一个有缩进的行开头的空白；文档没有缩进时返回空
+func indentUnit(data []byte) string {
+	for i := bytes.IndexByte(data, '\n'); i >= 0; {
+		j := i + 1
+		for j < len(data) && (data[j] == ' ' || data[j] == '\t') {
+			j++
+		}
+		if j > i+1 && j < len(data) && data[j] != '\n' && data[j] != '\r' {
+			return string(data[i+1 : j])
+		}
+		next := bytes.IndexByte(data[j:], '\n')
+		if next < 0 {
+			break
+		}
+		i = j + next
+	}
+	return ""
+}
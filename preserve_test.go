@@ -0,0 +1,139 @@
+package fxjson
+
+import (
+	"strings"
+	"testing"
+)
+
+const preserveTestDoc = `{
+    "version": 1.50,
+    "name":    "svc",   "tags": ["a", "b"],
+    "limits": {
+        "cpu": 2e3,
+        "mem": "1Gi"
+    },
+    "replicas": [ {"zone": "a", "n": 1}, {"zone": "b", "n": 2} ]
+}
+`
+
+// TestPreserveFormattingMinimalDiff 测试保留格式模式只改写变化的部分
+func TestPreserveFormattingMinimalDiff(t *testing.T) {
+	doc, err := NewDocument([]byte(preserveTestDoc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc.SetPreserveFormatting(true)
+
+	// 语义相同的值不产生修改
+	unchanged := map[string]string{
+		"version":  `1.5`,
+		"limits":   `{"mem":"1Gi","cpu":2000}`,
+		"name":     `"\u0073vc"`,
+		"replicas": `[{"n":1,"zone":"a"},{"zone":"b","n":2.0}]`,
+		"":         `{"tags":["a","b"],"version":15e-1,"name":"svc","limits":{"cpu":2000,"mem":"1Gi"},"replicas":[{"zone":"a","n":1},{"zone":"b","n":2}]}`,
+	}
+	for path, value := range unchanged {
+		if err := doc.Set(path, []byte(value)); err != nil {
+			t.Fatalf("Set(%q) failed: %v", path, err)
+		}
+		if got := string(doc.Bytes()); got != preserveTestDoc {
+			t.Fatalf("Set(%q, %s) changed the document:\n%s", path, value, got)
+		}
+	}
+	if len(doc.DirtyRanges()) != 0 {
+		t.Errorf("no-op edits should not be dirty: %v", doc.DirtyRanges())
+	}
+
+	// 只替换变化的字段，删除缺少的字段，按缩进追加新字段
+	if err := doc.Set("limits", []byte(`{"cpu":2e3,"gpu":{"count":1}}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.Set("replicas", []byte(`[{"zone":"a","n":1},{"zone":"c","n":2}]`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.Set("version", []byte(`1.6`)); err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+    "version": 1.6,
+    "name":    "svc",   "tags": ["a", "b"],
+    "limits": {
+        "cpu": 2e3,
+        "gpu": {
+            "count": 1
+        }
+    },
+    "replicas": [ {"zone": "a", "n": 1}, {"zone": "c", "n": 2} ]
+}
+`
+	got := string(doc.Bytes())
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+	if !ValidateJSON(doc.Bytes()) || doc.Get("limits.mem").Exists() || doc.Get("replicas[1].zone").StringOr("") != "c" {
+		t.Errorf("unexpected document state:\n%s", got)
+	}
+}
+
+// TestPreserveFormattingLayout 测试写入的容器按所在位置的风格格式化
+func TestPreserveFormattingLayout(t *testing.T) {
+	doc, err := NewDocument([]byte("{\n\t\"a\": {\n\t\t\"x\": 1\n\t},\n\t\"b\": [1, 2],\n\t\"c\": 0\n}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc.SetPreserveFormatting(true)
+
+	steps := []struct{ path, value string }{
+		{"b", `[1, 2, {"k": true}]`}, // 单行数组保持紧凑
+		{"c", `{"d":[1]}`},           // 标量替换为容器时参照父对象
+		{"a.y", `{"z":null}`},        // 新字段沿用成员缩进
+		{"e", `[]`},
+	}
+	for _, s := range steps {
+		if err := doc.Set(s.path, []byte(s.value)); err != nil {
+			t.Fatalf("Set(%q) failed: %v", s.path, err)
+		}
+	}
+
+	want := "{\n\t\"a\": {\n\t\t\"x\": 1,\n\t\t\"y\": {\n\t\t\t\"z\": null\n\t\t}\n\t},\n" +
+		"\t\"b\": [1,2,{\"k\":true}],\n\t\"c\": {\n\t\t\"d\": [\n\t\t\t1\n\t\t]\n\t},\n\t\"e\": []\n}"
+	if got := string(doc.Bytes()); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	// 关闭后恢复整体替换
+	doc.SetPreserveFormatting(false)
+	if err := doc.Set("a", []byte(`{"x":1}`)); err != nil || !strings.Contains(string(doc.Bytes()), `"a": {"x":1},`) {
+		t.Errorf("Set without preserve = %s, %v", doc.Bytes(), err)
+	}
+}
+
+// TestOriginalBytes 测试原始字节与输入一致，展开的节点返回 nil
+func TestOriginalBytes(t *testing.T) {
+	input := []byte(`{ "n": 1.50e+2, "s": "\u00e9", "o": {"b":1,  "a":2} }`)
+	node := FromBytes(input)
+	for path, want := range map[string]string{"n": `1.50e+2`, "s": `"\u00e9"`, "o": `{"b":1,  "a":2}`, "": string(input)} {
+		v := node
+		if path != "" {
+			v = node.Get(path)
+		}
+		if got := string(v.OriginalBytes()); got != want {
+			t.Errorf("OriginalBytes(%q) = %s, want %s", path, got, want)
+		}
+	}
+	if node.Get("missing").OriginalBytes() != nil {
+		t.Error("missing node should have no original bytes")
+	}
+
+	nested := FromBytes([]byte(`{"payload":"{\"a\":1}"}`))
+	if nested.OriginalBytes() != nil || nested.Get("payload.a").OriginalBytes() != nil {
+		t.Error("nodes of expanded documents should have no original bytes")
+	}
+	lazy, err := ParseBytes([]byte(`{"payload":"{\"a\":1}"}`), ParseOptions{LazyExpansion: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(lazy.Get("payload").OriginalBytes()) != `"{\"a\":1}"` || lazy.Get("payload").Expand().OriginalBytes() != nil {
+		t.Error("lazy nodes should keep original bytes until expanded")
+	}
+}
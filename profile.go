@@ -0,0 +1,64 @@
+package fxjson
+
+// ValueCounts 统计对象数组中 path 处各取值的出现次数，缺失该路径的元素不计入
+// 不同类型的值分别计数（字符串 "1" 与数字 1 不同），字符串按解码后的内容计数，数字按数值计数（1 与 1.0 相同），
+// 其余值按 JSON 字面量计数；结果的键为字符串的内容或其他值的字面量，字符串与其他值的字面量相同时，
+// 该字符串以带引号的 JSON 形式作为键（如 "\"1\""）。path 为空时统计元素本身
+func (n Node) ValueCounts(path string) map[string]int {
+	keys, counts := n.countValues(path)
+	if keys == nil {
+		return nil
+	}
+	literals := make(map[string]bool)
+	for key := range keys {
+		if key[0] != 's' {
+			literals[key[1:]] = true
+		}
+	}
+	result := make(map[string]int, len(counts))
+	for key, i := range keys {
+		name := key[1:]
+		if key[0] == 's' && literals[name] {
+			name = string(quoteKey(name))
+		}
+		result[name] = counts[i]
+	}
+	return result
+}
+
+// Cardinality 返回对象数组中 path 处不同取值的数量，规则同 ValueCounts
+func (n Node) Cardinality(path string) int {
+	_, counts := n.countValues(path)
+	return len(counts)
+}
+
+// countValues 一次遍历完成计数：带类型前缀的规范化键（appendCanonicalValueKey）映射到 counts 下标，只有新值才分配键字符串
+func (n Node) countValues(path string) (map[string]int, []int) {
+	if n.typ != 'a' {
+		return nil, nil
+	}
+	keys := make(map[string]int)
+	var counts []int
+	var scratch []byte
+	n.ArrayForEach(func(_ int, item Node) bool {
+		v := item
+		if path != "" {
+			v = item.GetPath(path)
+		}
+		if !v.Exists() {
+			return true
+		}
+		var ok bool
+		if scratch, ok = appendCanonicalValueKey(scratch[:0], v); !ok {
+			return true
+		}
+		if i, ok := keys[string(scratch)]; ok {
+			counts[i]++
+		} else {
+			keys[string(scratch)] = len(counts)
+			counts = append(counts, 1)
+		}
+		return true
+	})
+	return keys, counts
+}
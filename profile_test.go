@@ -0,0 +1,53 @@
+package fxjson
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestValueCounts 测试取值频次与基数统计
+func TestValueCounts(t *testing.T) {
+	node := FromString(`[
+		{"city":"北京","tier":1,"tags":{"vip":true}},
+		{"city":"上海","tier":1,"tags":{"vip":false}},
+		{"city":"北京","tier":2},
+		{"city":"\u5317京","tier":null},
+		{"tier":3}
+	]`)
+
+	want := map[string]int{"北京": 3, "上海": 1}
+	if got := node.ValueCounts("city"); !reflect.DeepEqual(got, want) {
+		t.Errorf("ValueCounts(city) = %v, want %v", got, want)
+	}
+	if got := node.Cardinality("city"); got != 2 {
+		t.Errorf("Cardinality(city) = %d, want 2", got)
+	}
+
+	want = map[string]int{"1": 2, "2": 1, "null": 1, "3": 1}
+	if got := node.ValueCounts("tier"); !reflect.DeepEqual(got, want) {
+		t.Errorf("ValueCounts(tier) = %v, want %v", got, want)
+	}
+	if got := node.Cardinality("tags.vip"); got != 2 {
+		t.Errorf("Cardinality(tags.vip) = %d, want 2", got)
+	}
+
+	if got := FromString(`["a","b","a"]`).ValueCounts(""); !reflect.DeepEqual(got, map[string]int{"a": 2, "b": 1}) {
+		t.Errorf("ValueCounts(\"\") = %v", got)
+	}
+	// 不同类型分别计数，数字按数值计数
+	mixed := FromString(`["1",1,1.0,"true",true,"x"]`)
+	want = map[string]int{`"1"`: 1, "1": 2, `"true"`: 1, "true": 1, "x": 1}
+	if got := mixed.ValueCounts(""); !reflect.DeepEqual(got, want) {
+		t.Errorf("ValueCounts(mixed) = %v, want %v", got, want)
+	}
+	if got := mixed.Cardinality(""); got != 5 {
+		t.Errorf("Cardinality(mixed) = %d, want 5", got)
+	}
+
+	if got := FromString(`{"a":1}`).ValueCounts("a"); got != nil {
+		t.Errorf("ValueCounts on object = %v, want nil", got)
+	}
+	if got := FromString(`[]`).Cardinality("a"); got != 0 {
+		t.Errorf("Cardinality on empty array = %d", got)
+	}
+}
@@ -0,0 +1,49 @@
+// Package query groups fxjson's array query, aggregation and jq APIs.
+//
+// The types are aliases of the ones in the root package, so values built
+// here can be passed to existing code and vice versa. The root package keeps
+// the implementation (the builders operate on fxjson.Node, which the root
+// package cannot import back). Node.Query and Node.Aggregate stay as they
+// are; the root CompileJQ is marked deprecated in favour of CompileJQ here.
+// This package is the entry point for new code.
+// Its exported surface is pinned by internal/apicheck.
+package query
+
+import (
+	"github.com/icloudza/fxjson"
+)
+
+type (
+	Builder         = fxjson.QueryBuilder    // 数组查询构建器
+	Condition       = fxjson.Condition       // 查询条件
+	SortField       = fxjson.SortField       // 排序字段
+	Collator        = fxjson.Collator        // 排序使用的区域相关字符串比较规则
+	ResultLimits    = fxjson.ResultLimits    // 结果规模上限
+	Page            = fxjson.QueryPage       // Builder.Cursor 返回的一页结果
+	Aggregator      = fxjson.Aggregator      // 聚合器
+	AggOperation    = fxjson.AggOperation    // 聚合操作
+	HistogramBucket = fxjson.HistogramBucket // 直方图分桶
+	ExecutionPlan   = fxjson.ExecutionPlan   // 查询与聚合的执行计划
+	FieldAccess     = fxjson.FieldAccess     // 执行计划中的字段访问
+	JQ              = fxjson.JQProgram       // 编译后的 jq 程序
+)
+
+// From 创建对数组节点的查询，同 n.Query()
+func From(n fxjson.Node) *Builder {
+	return n.Query()
+}
+
+// Where 构造查询条件，可用于 Aggregator.SumIf、CountIf 以及 any/all 运算符的元素条件（同 fxjson.SubCond）
+func Where(field, operator string, value interface{}) Condition {
+	return Condition{Field: field, Operator: operator, Value: value}
+}
+
+// Aggregate 创建聚合器，同 n.Aggregate()
+func Aggregate(n fxjson.Node) *Aggregator {
+	return n.Aggregate()
+}
+
+// CompileJQ 编译 jq 程序，同 fxjson.CompileJQ
+func CompileJQ(prog string) (*JQ, error) {
+	return fxjson.CompileJQ(prog)
+}
@@ -0,0 +1,31 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/icloudza/fxjson"
+)
+
+// TestFacade 测试子包入口与根包 API 互通
+func TestFacade(t *testing.T) {
+	users := fxjson.FromString(`[{"name":"a","age":30},{"name":"b","age":20},{"name":"c","age":40}]`)
+
+	var b *fxjson.QueryBuilder = From(users).Where("age", ">", 25).SortBy("age", "desc")
+	got, err := b.ToSlice()
+	if err != nil || len(got) != 2 || got[0].Get("name").StringOr("") != "c" {
+		t.Fatalf("From = %v, %v", got, err)
+	}
+
+	stats, err := Aggregate(users).CountIf(Where("age", "<", 35), "young").Execute(users)
+	if err != nil || stats["young"] != 2 {
+		t.Errorf("Aggregate = %v, %v", stats, err)
+	}
+
+	prog, err := CompileJQ(`.[0].name`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out, err := prog.Run(users); err != nil || len(out) != 1 || string(out[0].Raw()) != `"a"` {
+		t.Errorf("jq = %v, %v", out, err)
+	}
+}
@@ -0,0 +1,67 @@
+package fxjson
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// panicRecoveryDisabled 为 true 时导出接口不再拦截内部 panic
+var panicRecoveryDisabled atomic.Bool
+
+// SetPanicRecovery 设置返回 error 的导出接口（解析、解码、序列化、查询等）是否将内部 panic
+// （切片越界、unsafe 误用等）转换为 ErrorTypeInternal 错误，默认开启。
+// 排查或模糊测试解析器时可关闭，以保留原始 panic 与调用栈
+func SetPanicRecovery(enabled bool) {
+	panicRecoveryDisabled.Store(!enabled)
+}
+
+// recoverPanic 在导出接口中通过 defer 调用，将 panic 转换为 *FxJSONError 写入 *errp
+// op 为接口名称，n 为正在处理的节点（不涉及节点时传 Node{}），其字节范围会写入错误上下文
+func recoverPanic(errp *error, op string, n Node) {
+	if panicRecoveryDisabled.Load() {
+		return
+	}
+	r := recover()
+	if r == nil {
+		return
+	}
+	*errp = panicError(r, op, n)
+}
+
+// panicError 构造描述 panic 的错误，越界类运行时错误的下标作为 Pos
+func panicError(r any, op string, n Node) *FxJSONError {
+	e := &FxJSONError{
+		Type:    ErrorTypeInternal,
+		Message: fmt.Sprintf("internal panic in %s: %v", op, r),
+	}
+	if cause, ok := r.(error); ok {
+		e.Cause = cause
+		if pos, ok := panicOffset(cause.Error()); ok {
+			e.Pos = pos
+		}
+	}
+	if n.Exists() {
+		e.Context = fmt.Sprintf("node range [%d:%d]", n.start, n.end)
+	}
+	return e
+}
+
+// panicOffset 从越界错误信息（如 "index out of range [12] with length 10"、
+// "slice bounds out of range [:12] with capacity 10"）中提取出错的字节下标
+func panicOffset(msg string) (int, bool) {
+	if !strings.Contains(msg, "out of range [") {
+		return 0, false
+	}
+	i := strings.Index(msg, "out of range [") + len("out of range [")
+	for i < len(msg) && msg[i] == ':' {
+		i++
+	}
+	j := i
+	for j < len(msg) && msg[j] >= '0' && msg[j] <= '9' {
+		j++
+	}
+	pos, err := strconv.Atoi(msg[i:j])
+	return pos, err == nil
+}
@@ -0,0 +1,72 @@
+package fxjson
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// registeredTypes 多态解码的类型注册表
+var registeredTypes sync.Map // map[string]reflect.Type
+
+// RegisterType 以名称全局注册具体类型，用于多态解码：
+// DecodeOptions.TypeField 非空时，解码到接口类型的对象按该字段的值查找注册的类型，
+// 创建 t（或 *t，取实现了目标接口的一方）的值并解码后赋给接口。t 为 nil 时取消注册
+//
+//	fxjson.RegisterType("video", reflect.TypeOf(VideoNote{}))
+func RegisterType(name string, t reflect.Type) {
+	if t == nil {
+		registeredTypes.Delete(name)
+		return
+	}
+	registeredTypes.Store(name, t)
+}
+
+// lookupRegisteredType 按名称查找注册的类型
+func lookupRegisteredType(name string) (reflect.Type, bool) {
+	t, ok := registeredTypes.Load(name)
+	if !ok {
+		return nil, false
+	}
+	return t.(reflect.Type), true
+}
+
+// decodeRegisteredType 按判别字段将对象解码为注册的具体类型并赋给接口 rv，返回是否已处理
+// 目标为 any 时判别字段缺失或类型未注册不视为错误，交由默认的 map 解码处理
+func (n Node) decodeRegisteredType(rv reflect.Value, opts *DecodeOptions) (bool, error) {
+	iface := rv.Type()
+	strict := iface.NumMethod() > 0
+
+	disc := n.Get(opts.TypeField)
+	if !disc.Exists() {
+		if strict {
+			return true, fmt.Errorf("missing type field %q for %s", opts.TypeField, iface)
+		}
+		return false, nil
+	}
+	name, err := disc.String()
+	if err != nil {
+		return true, fmt.Errorf("type field %q must be a string, got %s", opts.TypeField, disc.Kind())
+	}
+	t, ok := lookupRegisteredType(name)
+	if !ok {
+		if strict {
+			return true, fmt.Errorf("unknown type %q for %s", name, iface)
+		}
+		return false, nil
+	}
+
+	v := reflect.New(t)
+	if err := n.decodeValueFast(v.Elem(), opts); err != nil {
+		return true, err
+	}
+	switch {
+	case t.Implements(iface):
+		rv.Set(v.Elem())
+	case v.Type().Implements(iface):
+		rv.Set(v)
+	default:
+		return true, fmt.Errorf("registered type %s for %q does not implement %s", t, name, iface)
+	}
+	return true, nil
+}
@@ -0,0 +1,77 @@
+package fxjson
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testNote interface{ Title() string }
+
+type testNormalNote struct {
+	Type string `json:"type"`
+	Name string `json:"title"`
+	Text string `json:"text"`
+}
+
+func (n testNormalNote) Title() string { return n.Name }
+
+type testVideoNote struct {
+	Name     string `json:"title"`
+	Duration int    `json:"duration"`
+}
+
+func (n *testVideoNote) Title() string { return n.Name }
+
+// TestRegisteredTypeDecode 测试按判别字段解码到接口切片
+func TestRegisteredTypeDecode(t *testing.T) {
+	RegisterType("normal", reflect.TypeOf(testNormalNote{}))
+	RegisterType("video", reflect.TypeOf(testVideoNote{}))
+	defer RegisterType("normal", nil)
+	defer RegisterType("video", nil)
+
+	data := `{"notes":[{"type":"normal","title":"a","text":"hi"},{"type":"video","title":"b","duration":30}],"extra":{"type":"video","title":"c"}}`
+	var v struct {
+		Notes []testNote `json:"notes"`
+		Extra any        `json:"extra"`
+	}
+	opts := DefaultDecodeOptions
+	opts.TypeField = "type"
+	if err := FromString(data).DecodeWithOptions(&v, opts); err != nil {
+		t.Fatalf("DecodeWithOptions error: %v", err)
+	}
+	if len(v.Notes) != 2 {
+		t.Fatalf("Notes = %v", v.Notes)
+	}
+	if n, ok := v.Notes[0].(testNormalNote); !ok || n.Text != "hi" || n.Title() != "a" {
+		t.Errorf("Notes[0] = %#v", v.Notes[0])
+	}
+	if n, ok := v.Notes[1].(*testVideoNote); !ok || n.Duration != 30 || n.Title() != "b" {
+		t.Errorf("Notes[1] = %#v", v.Notes[1])
+	}
+	if n, ok := v.Extra.(testVideoNote); !ok || n.Name != "c" {
+		t.Errorf("Extra = %#v", v.Extra)
+	}
+
+	// any 目标的未注册类型回退到 map
+	var plain any
+	if err := FromString(`{"type":"audio","x":1}`).DecodeWithOptions(&plain, opts); err != nil {
+		t.Errorf("unregistered any error: %v", err)
+	} else if m, ok := plain.(map[string]any); !ok || m["type"] != "audio" {
+		t.Errorf("unregistered any = %#v", plain)
+	}
+
+	var notes []testNote
+	for input, want := range map[string]string{
+		`[{"type":"audio"}]`: `unknown type "audio"`,
+		`[{"title":"x"}]`:    `missing type field "type"`,
+		`[{"type":1}]`:       `must be a string`,
+	} {
+		if err := FromString(input).DecodeWithOptions(&notes, opts); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: err = %v, want %q", input, err, want)
+		}
+	}
+	if err := FromString(`[{"type":"video"}]`).Decode(&notes); err == nil || !strings.Contains(err.Error(), "TypeField") {
+		t.Errorf("without TypeField: err = %v", err)
+	}
+}
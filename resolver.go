@@ -0,0 +1,296 @@
+package fxjson
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// PathResolver 路径语法前端：把某种语法的路径表达式解析为字面段，
+// 查找统一由 GetSegments 完成（对象按键匹配，数组按纯数字段取下标），
+// 便于从其他库迁移的代码沿用熟悉的路径写法
+type PathResolver interface {
+	Segments(expr string) ([]string, error)
+}
+
+// PathResolverFunc 函数形式的 PathResolver
+type PathResolverFunc func(expr string) ([]string, error)
+
+// Segments 实现 PathResolver
+func (f PathResolverFunc) Segments(expr string) ([]string, error) {
+	return f(expr)
+}
+
+// indexedResolver 自定义数组下标写法的路径语法，ResolveWith 使用 parseIndex 代替 GetSegments 的纯数字规则
+type indexedResolver interface {
+	PathResolver
+	parseIndex(seg string) (int, bool)
+}
+
+// dottedResolver GetPath 写法的路径语法，数组下标可以为负数
+type dottedResolver struct{}
+
+// Segments 实现 PathResolver
+func (dottedResolver) Segments(expr string) ([]string, error) {
+	return dottedSegments(expr)
+}
+
+// parseIndex 实现 indexedResolver
+func (dottedResolver) parseIndex(seg string) (int, bool) {
+	return parseSignedIndex(seg)
+}
+
+// pointerResolver RFC 6901 JSON Pointer 语法，数组下标必须为不带前导零的十进制数
+type pointerResolver struct{}
+
+// Segments 实现 PathResolver
+func (pointerResolver) Segments(expr string) ([]string, error) {
+	return pointerSegments(expr)
+}
+
+// parseIndex 实现 indexedResolver：RFC 6901 的 array-index 为 "0" 或不以 0 开头的数字，
+// 其他写法（如 "01"、"-"）在数组中不匹配任何元素，在对象中仍按字面键查找
+func (pointerResolver) parseIndex(seg string) (int, bool) {
+	if len(seg) > 1 && seg[0] == '0' {
+		return 0, false
+	}
+	return parseSegmentIndex(seg)
+}
+
+// 内置的路径语法
+var (
+	// DottedResolver 与 GetPath 相同的写法：a.b[0].c，负数下标从末尾计数，如 a.b[-1]
+	DottedResolver PathResolver = dottedResolver{}
+	// PointerResolver RFC 6901 JSON Pointer：/a/b/0/c，~1 表示 '/'，~0 表示 '~'，"" 表示根节点
+	PointerResolver PathResolver = pointerResolver{}
+	// JSONPathResolver JSONPath 的确定路径子集：$.a['b.c'][0]，不支持通配符、过滤器与递归下降
+	JSONPathResolver PathResolver = PathResolverFunc(jsonPathSegments)
+	// GJSONResolver gjson 的基本路径：a.b.0.c，'\' 转义特殊字符，不支持通配符、# 与修饰符
+	GJSONResolver PathResolver = PathResolverFunc(gjsonSegments)
+)
+
+// pathResolvers 按名称注册的路径语法
+var pathResolvers sync.Map // map[string]PathResolver
+
+func init() {
+	RegisterPathResolver("dotted", DottedResolver)
+	RegisterPathResolver("pointer", PointerResolver)
+	RegisterPathResolver("jsonpath", JSONPathResolver)
+	RegisterPathResolver("gjson", GJSONResolver)
+}
+
+// RegisterPathResolver 以名称全局注册路径语法，r 为 nil 时取消注册
+// 内置语法注册为 "dotted"、"pointer"、"jsonpath" 与 "gjson"
+func RegisterPathResolver(name string, r PathResolver) {
+	if r == nil {
+		pathResolvers.Delete(name)
+		return
+	}
+	pathResolvers.Store(name, r)
+}
+
+// LookupPathResolver 按名称查找注册的路径语法
+func LookupPathResolver(name string) (PathResolver, bool) {
+	r, ok := pathResolvers.Load(name)
+	if !ok {
+		return nil, false
+	}
+	return r.(PathResolver), true
+}
+
+// GetWith 使用指定的路径语法查找节点，表达式无法解析或路径不存在时返回不存在的节点
+//
+//	node.GetWith(fxjson.PointerResolver, "/users/0/name")
+//	node.GetWith(fxjson.JSONPathResolver, "$.users[0].name")
+func (n Node) GetWith(r PathResolver, expr string) Node {
+	node, _ := n.ResolveWith(r, expr)
+	return node
+}
+
+// ResolveWith 同 GetWith，但返回表达式的解析错误
+func (n Node) ResolveWith(r PathResolver, expr string) (Node, error) {
+	if r == nil {
+		return Node{}, fmt.Errorf("nil path resolver")
+	}
+	segments, err := r.Segments(expr)
+	if err != nil {
+		return Node{}, err
+	}
+	if ir, ok := r.(indexedResolver); ok {
+		return n.getSegments(ir.parseIndex, segments), nil
+	}
+	return n.GetSegments(segments...), nil
+}
+
+// dottedSegments 解析 GetPath 写法的路径
+func dottedSegments(expr string) ([]string, error) {
+	if expr == "" {
+		return nil, fmt.Errorf("empty path")
+	}
+	var segments []string
+	for i := 0; i < len(expr); {
+		start := i
+		for i < len(expr) && expr[i] != '.' && expr[i] != '[' {
+			i++
+		}
+		if i > start {
+			segments = append(segments, expr[start:i])
+		}
+		for i < len(expr) && expr[i] == '[' {
+			closeIdx := strings.IndexByte(expr[i:], ']')
+			if closeIdx < 0 {
+				return nil, fmt.Errorf("unclosed '[' at offset %d", i)
+			}
+			idx := expr[i+1 : i+closeIdx]
+			if _, ok := parseSignedIndex(idx); !ok {
+				return nil, fmt.Errorf("invalid array index %q at offset %d", idx, i)
+			}
+			segments = append(segments, idx)
+			i += closeIdx + 1
+		}
+		if i < len(expr) {
+			if expr[i] != '.' {
+				return nil, fmt.Errorf("unexpected %q at offset %d", expr[i], i)
+			}
+			i++
+			if i == len(expr) {
+				return nil, fmt.Errorf("trailing '.'")
+			}
+		}
+	}
+	return segments, nil
+}
+
+// pointerSegments 解析 RFC 6901 JSON Pointer
+func pointerSegments(expr string) ([]string, error) {
+	if expr == "" {
+		return nil, nil
+	}
+	if expr[0] != '/' {
+		return nil, fmt.Errorf("json pointer must start with '/'")
+	}
+	segments := strings.Split(expr[1:], "/")
+	for i, seg := range segments {
+		if strings.IndexByte(seg, '~') < 0 {
+			continue
+		}
+		var sb strings.Builder
+		for j := 0; j < len(seg); j++ {
+			if seg[j] != '~' {
+				sb.WriteByte(seg[j])
+				continue
+			}
+			if j+1 == len(seg) || (seg[j+1] != '0' && seg[j+1] != '1') {
+				return nil, fmt.Errorf("invalid escape in json pointer token %q", seg)
+			}
+			if seg[j+1] == '0' {
+				sb.WriteByte('~')
+			} else {
+				sb.WriteByte('/')
+			}
+			j++
+		}
+		segments[i] = sb.String()
+	}
+	return segments, nil
+}
+
+// jsonPathSegments 解析 JSONPath 的确定路径：$ 开头，后接 .name、['name']、["name"] 或 [n]
+func jsonPathSegments(expr string) ([]string, error) {
+	if expr == "" || expr[0] != '$' {
+		return nil, fmt.Errorf("jsonpath must start with '$'")
+	}
+	var segments []string
+	for i := 1; i < len(expr); {
+		switch expr[i] {
+		case '.':
+			i++
+			start := i
+			for i < len(expr) && expr[i] != '.' && expr[i] != '[' {
+				i++
+			}
+			name := expr[start:i]
+			switch name {
+			case "":
+				return nil, fmt.Errorf("recursive descent at offset %d is not supported", start-1)
+			case "*":
+				return nil, fmt.Errorf("wildcard at offset %d is not supported", start)
+			}
+			segments = append(segments, name)
+		case '[':
+			seg, next, err := jsonPathBracket(expr, i)
+			if err != nil {
+				return nil, err
+			}
+			segments = append(segments, seg)
+			i = next
+		default:
+			return nil, fmt.Errorf("unexpected %q at offset %d", expr[i], i)
+		}
+	}
+	return segments, nil
+}
+
+// jsonPathBracket 解析从 pos（'['）开始的下标或带引号的名称，返回段与 ']' 之后的位置
+func jsonPathBracket(expr string, pos int) (string, int, error) {
+	i := pos + 1
+	if i < len(expr) && (expr[i] == '\'' || expr[i] == '"') {
+		quote := expr[i]
+		var sb strings.Builder
+		for i++; i < len(expr) && expr[i] != quote; i++ {
+			if expr[i] == '\\' && i+1 < len(expr) {
+				i++
+			}
+			sb.WriteByte(expr[i])
+		}
+		if i+1 >= len(expr) || expr[i+1] != ']' {
+			return "", 0, fmt.Errorf("unclosed name at offset %d", pos)
+		}
+		return sb.String(), i + 2, nil
+	}
+
+	closeIdx := strings.IndexByte(expr[pos:], ']')
+	if closeIdx < 0 {
+		return "", 0, fmt.Errorf("unclosed '[' at offset %d", pos)
+	}
+	idx := strings.TrimSpace(expr[i : pos+closeIdx])
+	if _, ok := parseSegmentIndex(idx); !ok {
+		return "", 0, fmt.Errorf("unsupported selector [%s] at offset %d", idx, pos)
+	}
+	return idx, pos + closeIdx + 1, nil
+}
+
+// gjsonSegments 解析 gjson 的基本路径
+func gjsonSegments(expr string) ([]string, error) {
+	if expr == "" {
+		return nil, fmt.Errorf("empty path")
+	}
+	var segments []string
+	var sb strings.Builder
+	for i := 0; i < len(expr); i++ {
+		c := expr[i]
+		switch c {
+		case '\\':
+			if i+1 == len(expr) {
+				return nil, fmt.Errorf("trailing '\\'")
+			}
+			i++
+			sb.WriteByte(expr[i])
+		case '.':
+			segments = append(segments, sb.String())
+			sb.Reset()
+		case '*', '?':
+			return nil, fmt.Errorf("wildcard %q at offset %d is not supported", c, i)
+		case '|':
+			return nil, fmt.Errorf("pipe at offset %d is not supported", i)
+		case '#', '@':
+			if sb.Len() == 0 { // 段首的 # 为数组查询，@ 为修饰符
+				return nil, fmt.Errorf("%q at offset %d is not supported", c, i)
+			}
+			sb.WriteByte(c)
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return append(segments, sb.String()), nil
+}
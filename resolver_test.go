@@ -0,0 +1,135 @@
+package fxjson
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestGetWith 测试各路径语法查找到相同的节点
+func TestGetWith(t *testing.T) {
+	node := FromBytes([]byte(`{"users":[{"name":"Alice","a.b":1,"x/y":2,"m~n":3}],"0":"zero"}`))
+
+	cases := []struct {
+		resolver PathResolver
+		expr     string
+		want     string
+	}{
+		{DottedResolver, "users[0].name", `"Alice"`},
+		{PointerResolver, "/users/0/name", `"Alice"`},
+		{JSONPathResolver, "$.users[0].name", `"Alice"`},
+		{JSONPathResolver, `$['users'][0]["name"]`, `"Alice"`},
+		{GJSONResolver, "users.0.name", `"Alice"`},
+
+		{PointerResolver, "/users/0/a.b", `1`},
+		{JSONPathResolver, "$.users[0]['a.b']", `1`},
+		{GJSONResolver, `users.0.a\.b`, `1`},
+		{PointerResolver, "/users/0/x~1y", `2`},
+		{PointerResolver, "/users/0/m~0n", `3`},
+		{PointerResolver, "/0", `"zero"`},
+		{GJSONResolver, "0", `"zero"`},
+	}
+	for _, c := range cases {
+		if got := node.GetWith(c.resolver, c.expr); string(got.Raw()) != c.want {
+			t.Errorf("GetWith(%q) = %s, want %s", c.expr, got.Raw(), c.want)
+		}
+	}
+
+	if root := node.GetWith(PointerResolver, ""); string(root.Raw()) != string(node.Raw()) {
+		t.Errorf("empty pointer should resolve to the root")
+	}
+	if node.GetWith(PointerResolver, "/users/5/name").Exists() {
+		t.Errorf("out-of-range index should not exist")
+	}
+
+	// 负数下标与 GetPath 一致
+	list := FromBytes([]byte(`{"arr":[1,2,3]}`))
+	for _, expr := range []string{"arr[-1]", "arr[-3]", "arr[-4]", "arr[0]"} {
+		if got, want := list.GetWith(DottedResolver, expr), list.GetPath(expr); string(got.Raw()) != string(want.Raw()) || got.Exists() != want.Exists() {
+			t.Errorf("GetWith(%q) = %s, GetPath = %s", expr, got.Raw(), want.Raw())
+		}
+	}
+	// 负数下标只属于 GetPath 写法，字面段与其他语法不支持
+	if list.GetSegments("arr", "-1").Exists() || list.GetWith(PointerResolver, "/arr/-1").Exists() || list.GetWith(GJSONResolver, "arr.-1").Exists() {
+		t.Error("negative indices should only resolve with DottedResolver")
+	}
+	if r, _ := LookupPathResolver("dotted"); !list.GetWith(r, "arr[-1]").Exists() {
+		t.Error("registered dotted resolver should accept negative indices")
+	}
+}
+
+// TestResolveWithErrors 测试不支持或格式错误的表达式
+func TestResolveWithErrors(t *testing.T) {
+	node := FromBytes([]byte(`{"a":[1]}`))
+	cases := []struct {
+		resolver PathResolver
+		expr     string
+		want     string
+	}{
+		{PointerResolver, "a/0", "must start with '/'"},
+		{PointerResolver, "/a~2", "invalid escape"},
+		{JSONPathResolver, "a[0]", "must start with '$'"},
+		{JSONPathResolver, "$..a", "recursive descent"},
+		{JSONPathResolver, "$.a[*]", "unsupported selector"},
+		{JSONPathResolver, "$.a[?(@.x)]", "unsupported selector"},
+		{JSONPathResolver, "$['a'", "unclosed name"},
+		{GJSONResolver, "a.*", "wildcard"},
+		{GJSONResolver, "a.#", "not supported"},
+		{GJSONResolver, "a|@reverse", "pipe"},
+		{DottedResolver, "a[x]", "invalid array index"},
+		{DottedResolver, "a[0", "unclosed '['"},
+		{DottedResolver, "a[-0]", "invalid array index"},
+		{DottedResolver, "a[-]", "invalid array index"},
+		{nil, "a", "nil path resolver"},
+	}
+	for _, c := range cases {
+		_, err := node.ResolveWith(c.resolver, c.expr)
+		if err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Errorf("ResolveWith(%q) error = %v, want %q", c.expr, err, c.want)
+		}
+		if node.GetWith(c.resolver, c.expr).Exists() {
+			t.Errorf("GetWith(%q) should not exist", c.expr)
+		}
+	}
+
+	// 语法正确但不匹配：JSON Pointer 的数组下标不能有前导零或符号，同样的字面键在对象中仍可查找
+	pointer := FromBytes([]byte(`{"arr":[10,20],"obj":{"01":"one","-1":"neg"}}`))
+	for _, expr := range []string{"/arr/01", "/arr/00", "/arr/-1", "/arr/-", "/arr/+1"} {
+		if got, err := pointer.ResolveWith(PointerResolver, expr); err != nil || got.Exists() {
+			t.Errorf("ResolveWith(%q) = %s, %v; want no match", expr, got.Raw(), err)
+		}
+	}
+	for expr, want := range map[string]string{"/arr/0": "10", "/arr/1": "20", "/obj/01": `"one"`, "/obj/-1": `"neg"`} {
+		if got := pointer.GetWith(PointerResolver, expr); string(got.Raw()) != want {
+			t.Errorf("GetWith(%q) = %s, want %s", expr, got.Raw(), want)
+		}
+	}
+}
+
+// TestRegisterPathResolver 测试按名称注册与查找路径语法
+func TestRegisterPathResolver(t *testing.T) {
+	for _, name := range []string{"dotted", "pointer", "jsonpath", "gjson"} {
+		if _, ok := LookupPathResolver(name); !ok {
+			t.Errorf("built-in resolver %q not registered", name)
+		}
+	}
+
+	// 以 ':' 分隔的自定义语法
+	RegisterPathResolver("colon", PathResolverFunc(func(expr string) ([]string, error) {
+		return strings.Split(expr, ":"), nil
+	}))
+	defer RegisterPathResolver("colon", nil)
+
+	r, ok := LookupPathResolver("colon")
+	if !ok {
+		t.Fatal("custom resolver not registered")
+	}
+	node := FromBytes([]byte(`{"a":{"b":[10,20]}}`))
+	if v, _ := node.GetWith(r, "a:b:1").Int(); v != 20 {
+		t.Errorf("custom resolver got %d, want 20", v)
+	}
+
+	RegisterPathResolver("colon", nil)
+	if _, ok := LookupPathResolver("colon"); ok {
+		t.Error("resolver should be unregistered")
+	}
+}
@@ -0,0 +1,320 @@
+package fxjson
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"sync"
+)
+
+// patternCache ValidationRule.Pattern 编译结果缓存
+var patternCache sync.Map // map[string]*regexp.Regexp
+
+// compilePattern 编译并缓存正则表达式
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if cached, ok := patternCache.Load(pattern); ok {
+		return cached.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	patternCache.Store(pattern, re)
+	return re, nil
+}
+
+// validationRuleTypes ValidationRule.Type 允许的取值
+var validationRuleTypes = []string{"", "string", "number", "boolean", "array", "object"}
+
+// LoadValidator 从 JSON 配置创建 DataValidator，格式与 DataValidator 的 json 标签一致：
+//
+//	{"rules": {"email": {"required": true, "type": "string", "pattern": "^[^@]+@[^@]+$"}}}
+//
+// 加载时检查未知字段（并给出拼写建议）、字段类型、规则类型、长度与数值范围、默认值类型，
+// 并预编译 pattern，错误信息指出出错的规则与字段
+//
+// Deprecated: 新代码请使用 validate.Load，该函数仅为兼容保留
+func LoadValidator(spec []byte) (*DataValidator, error) {
+	root, err := loadRuleSpec(spec, []string{"rules"})
+	if err != nil {
+		return nil, err
+	}
+
+	validator := &DataValidator{Rules: make(map[string]ValidationRule)}
+	rules := root.Get("rules")
+	if rules.Exists() && rules.typ != 'o' {
+		return nil, fmt.Errorf("validator spec: \"rules\" must be an object, got %s", rules.Kind())
+	}
+
+	allowed := jsonFieldNames(reflect.TypeOf(ValidationRule{}))
+	var loadErr error
+	rules.ForEach(func(name string, spec Node) bool {
+		var rule ValidationRule
+		if err := loadValidationRule(spec, allowed, &rule); err != nil {
+			loadErr = fmt.Errorf("rule %q: %w", name, err)
+			return false
+		}
+		if err := checkValidationRule(rule); err != nil {
+			loadErr = fmt.Errorf("rule %q: %w", name, err)
+			return false
+		}
+		validator.Rules[name] = rule
+		return true
+	})
+	if loadErr != nil {
+		return nil, loadErr
+	}
+	return validator, nil
+}
+
+// loadValidationRule 解码单条规则，嵌套的 items 规则同样检查未知字段
+func loadValidationRule(spec Node, allowed []string, rule *ValidationRule) error {
+	if spec.typ != 'o' {
+		return fmt.Errorf("must be an object, got %s", spec.Kind())
+	}
+	if err := checkSpecKeys(spec, allowed); err != nil {
+		return err
+	}
+	// items 为嵌套规则，单独递归加载
+	raw := spec.Raw()
+	if obj := parseRootNode(raw); obj.Exists() {
+		if span, found := obj.findFieldSpan("items"); found {
+			raw = removeField(raw, obj, span)
+		}
+	}
+	if err := DecodeStruct(raw, rule); err != nil {
+		return err
+	}
+	if i, ok := rule.Default.(int64); ok && rule.Type == "number" {
+		// 整数字面量解码为 int64，与 Validate 返回的数值类型保持一致
+		rule.Default = float64(i)
+	}
+	rule.HasMin = spec.Get("min").Exists()
+	rule.HasMax = spec.Get("max").Exists()
+
+	if items := spec.Get("items"); items.Exists() && items.typ != 'l' {
+		rule.Items = &ValidationRule{}
+		if err := loadValidationRule(items, allowed, rule.Items); err != nil {
+			return fmt.Errorf("field \"items\": %w", err)
+		}
+	}
+	return nil
+}
+
+// checkValidationRule 检查单条验证规则的取值
+func checkValidationRule(rule ValidationRule) error {
+	if !containsString(validationRuleTypes, rule.Type) {
+		return fmt.Errorf("field \"type\": unknown type %q, expected one of string, number, boolean, array, object", rule.Type)
+	}
+	if rule.MinLength < 0 || rule.MaxLength < 0 {
+		return fmt.Errorf("min_length and max_length must not be negative")
+	}
+	if rule.MaxLength > 0 && rule.MinLength > rule.MaxLength {
+		return fmt.Errorf("min_length %d is greater than max_length %d", rule.MinLength, rule.MaxLength)
+	}
+	if rule.hasMin() && rule.hasMax() {
+		if rule.Min > rule.Max {
+			return fmt.Errorf("min %v is greater than max %v", rule.Min, rule.Max)
+		}
+		if rule.Min == rule.Max && (rule.ExclusiveMin || rule.ExclusiveMax) {
+			return fmt.Errorf("exclusive bounds min %v and max %v leave no valid value", rule.Min, rule.Max)
+		}
+	}
+	if rule.ExclusiveMin && !rule.hasMin() {
+		return fmt.Errorf("exclusive_min requires min")
+	}
+	if rule.ExclusiveMax && !rule.hasMax() {
+		return fmt.Errorf("exclusive_max requires max")
+	}
+	if rule.MultipleOf < 0 || math.IsNaN(rule.MultipleOf) || math.IsInf(rule.MultipleOf, 0) {
+		return fmt.Errorf("multiple_of must be a positive number, got %v", rule.MultipleOf)
+	}
+	if (rule.MinLength > 0 || rule.MaxLength > 0 || rule.Pattern != "") && rule.Type != "string" {
+		return fmt.Errorf("min_length, max_length and pattern require type \"string\", got %q", rule.Type)
+	}
+	if (rule.hasMin() || rule.hasMax() || rule.MultipleOf > 0 || rule.Integer) && rule.Type != "number" {
+		return fmt.Errorf("min, max, multiple_of and integer require type \"number\", got %q", rule.Type)
+	}
+	if rule.MinItems < 0 || rule.MaxItems < 0 {
+		return fmt.Errorf("min_items and max_items must not be negative")
+	}
+	if rule.MaxItems > 0 && rule.MinItems > rule.MaxItems {
+		return fmt.Errorf("min_items %d is greater than max_items %d", rule.MinItems, rule.MaxItems)
+	}
+	if (rule.MinItems > 0 || rule.MaxItems > 0 || rule.UniqueItems || rule.Items != nil) && rule.Type != "array" {
+		return fmt.Errorf("min_items, max_items, unique_items and items require type \"array\", got %q", rule.Type)
+	}
+	if rule.Items != nil {
+		if err := checkValidationRule(*rule.Items); err != nil {
+			return fmt.Errorf("field \"items\": %w", err)
+		}
+	}
+	for i, v := range rule.Enum {
+		if err := checkRuleValueType(v, rule.Type); err != nil {
+			return fmt.Errorf("field \"enum\": item %d: %w", i, err)
+		}
+	}
+	if rule.Const != nil {
+		if err := checkRuleValueType(rule.Const, rule.Type); err != nil {
+			return fmt.Errorf("field \"const\": %w", err)
+		}
+	}
+	if rule.Pattern != "" {
+		if _, err := compilePattern(rule.Pattern); err != nil {
+			return fmt.Errorf("field \"pattern\": %w", err)
+		}
+	}
+	if rule.Default != nil {
+		ok := true
+		switch rule.Type {
+		case "string":
+			_, ok = rule.Default.(string)
+		case "number":
+			var f float64
+			if f, ok = rule.Default.(float64); ok {
+				if err := checkNumberRule(f, rule); err != nil {
+					return fmt.Errorf("field \"default\": %w", err)
+				}
+			}
+		case "boolean":
+			_, ok = rule.Default.(bool)
+		}
+		if !ok {
+			return fmt.Errorf("field \"default\": %v does not match type %q", rule.Default, rule.Type)
+		}
+		if len(rule.Enum) > 0 || rule.Const != nil {
+			text, err := Marshal(rule.Default)
+			if err != nil {
+				return fmt.Errorf("field \"default\": %w", err)
+			}
+			if err := checkEnumRule(parseRootNode(text), rule); err != nil {
+				return fmt.Errorf("field \"default\": %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// checkRuleValueType 检查 enum/const 中的值与规则类型一致，未指定类型时不限制
+func checkRuleValueType(v interface{}, typ string) error {
+	text, err := Marshal(v)
+	if err != nil {
+		return err
+	}
+	kind := parseRootNode(text).Kind().String()
+	if kind == "bool" {
+		kind = "boolean"
+	}
+	if typ != "" && kind != typ {
+		return fmt.Errorf("%s does not match type %q", text, typ)
+	}
+	return nil
+}
+
+// LoadMapper 从 JSON 配置创建 FieldMapper，格式与 FieldMapper 的 json 标签一致：
+//
+//	{"rules": {"user.name": "name"}, "default_values": {"source": "api"}, "type_cast": {"name": "int"}}
+//
+// 加载时检查未知字段、规则值类型、目标路径冲突，以及 type_cast 的取值（int、float）与目标字段是否存在
+func LoadMapper(spec []byte) (FieldMapper, error) {
+	root, err := loadRuleSpec(spec, jsonFieldNames(reflect.TypeOf(FieldMapper{})))
+	if err != nil {
+		return FieldMapper{}, err
+	}
+
+	var mapper FieldMapper
+	for _, key := range []string{"rules", "type_cast"} {
+		var badErr error
+		root.Get(key).ForEach(func(k string, v Node) bool {
+			if v.typ != 's' {
+				badErr = fmt.Errorf("mapper spec: %s[%q] must be a string, got %s", key, k, v.Kind())
+				return false
+			}
+			return true
+		})
+		if badErr != nil {
+			return FieldMapper{}, badErr
+		}
+	}
+	if err := DecodeStruct(root.Raw(), &mapper); err != nil {
+		return FieldMapper{}, fmt.Errorf("mapper spec: %w", err)
+	}
+
+	// 目标路径冲突在加载时暴露，而不是在第一次变换时
+	tree := newPathTree()
+	targets := make(map[string]bool, len(mapper.Rules))
+	sources := make([]string, 0, len(mapper.Rules))
+	for source := range mapper.Rules {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+	for _, source := range sources {
+		target := mapper.Rules[source]
+		if err := tree.insert(target, nil); err != nil {
+			return FieldMapper{}, fmt.Errorf("mapper spec: rule %q -> %q: %w", source, target, err)
+		}
+		targets[target] = true
+	}
+	for target, cast := range mapper.TypeCast {
+		if cast != "int" && cast != "float" {
+			return FieldMapper{}, fmt.Errorf("mapper spec: type_cast %q: unknown cast %q, expected int or float", target, cast)
+		}
+		if !targets[target] {
+			return FieldMapper{}, fmt.Errorf("mapper spec: type_cast %q does not match any rule target", target)
+		}
+	}
+	return mapper, nil
+}
+
+// loadRuleSpec 解析配置并检查根对象的键
+func loadRuleSpec(spec []byte, allowed []string) (Node, error) {
+	root, err := ParseBytes(spec, DefaultParseOptions)
+	if err != nil {
+		return Node{}, fmt.Errorf("invalid spec: %w", err)
+	}
+	if root.typ != 'o' {
+		return Node{}, fmt.Errorf("spec must be an object, got %s", root.Kind())
+	}
+	if err := checkSpecKeys(root, allowed); err != nil {
+		return Node{}, fmt.Errorf("spec: %w", err)
+	}
+	return root, nil
+}
+
+// checkSpecKeys 检查对象中没有 allowed 以外的键，拼写相近时给出建议
+func checkSpecKeys(obj Node, allowed []string) error {
+	var err error
+	obj.ForEach(func(key string, _ Node) bool {
+		if containsString(allowed, key) {
+			return true
+		}
+		best, bestDist := "", min(max(len(key)/3, 1), 3)+1
+		for _, name := range allowed {
+			if d := editDistance(key, name); d < bestDist {
+				best, bestDist = name, d
+			}
+		}
+		if best != "" {
+			err = fmt.Errorf("unknown field %q, did you mean %q?", key, best)
+		} else {
+			err = fmt.Errorf("unknown field %q", key)
+		}
+		return false
+	})
+	return err
+}
+
+// jsonFieldNames 返回结构体可通过 JSON 设置的字段名
+func jsonFieldNames(t reflect.Type) []string {
+	fields := getStructFieldMapFast(t, &DefaultDecodeOptions)
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		if name != "-" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,228 @@
+package fxjson
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestLoadValidator 测试从 JSON 配置加载验证规则
+func TestLoadValidator(t *testing.T) {
+	v, err := LoadValidator([]byte(`{"rules":{
+		"email":{"required":true,"type":"string","pattern":"^[^@]+@[^@]+$","max_length":50},
+		"age":{"type":"number","min":18,"max":130},
+		"plan":{"type":"string","default":"free"}
+	}}`))
+	if err != nil {
+		t.Fatalf("LoadValidator error: %v", err)
+	}
+	result, errs := FromString(`{"email":"a@b","age":30}`).Validate(v)
+	if len(errs) != 0 || result["plan"] != "free" || result["age"] != float64(30) {
+		t.Errorf("Validate = %v, %v", result, errs)
+	}
+	if _, errs := FromString(`{"email":"nope","age":12}`).Validate(v); len(errs) != 2 {
+		t.Errorf("expected pattern and min errors, got %v", errs)
+	}
+
+	bad := map[string]string{
+		`{"rules":{"a":{"type":"string","max_lenght":3}}}`: `unknown field "max_lenght", did you mean "max_length"?`,
+		`{"rule":{}}`:                                        `did you mean "rules"?`,
+		`{"rules":{"a":{"type":"text"}}}`:                    `unknown type "text"`,
+		`{"rules":{"a":{"type":"string","pattern":"(a"}}}`:   `rule "a": field "pattern"`,
+		`{"rules":{"a":{"type":"number","min":5,"max":1}}}`:  `min 5 is greater than max 1`,
+		`{"rules":{"a":{"type":"number","pattern":"x"}}}`:    `require type "string"`,
+		`{"rules":{"a":{"type":"boolean","default":"yes"}}}`: `does not match type "boolean"`,
+		`{"rules":{"a":{"type":"string","min_length":"3"}}}`: `rule "a"`,
+		`{"rules":{"a":1}}`:                                  `must be an object`,
+		`{"rules":`:                                          `invalid spec`,
+	}
+	for spec, want := range bad {
+		if _, err := LoadValidator([]byte(spec)); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: err = %v, want %q", spec, err, want)
+		}
+	}
+}
+
+// TestLoadMapper 测试从 JSON 配置加载字段映射
+func TestLoadMapper(t *testing.T) {
+	m, err := LoadMapper([]byte(`{"rules":{"user.name":"profile.name","user.age":"profile.age"},
+		"default_values":{"source":"api"},"type_cast":{"profile.age":"int"}}`))
+	if err != nil {
+		t.Fatalf("LoadMapper error: %v", err)
+	}
+	out, err := FromString(`{"user":{"name":"Ann","age":30.0}}`).TransformToJSON(m)
+	if err != nil || string(out) != `{"source":"api","profile":{"age":30,"name":"Ann"}}` {
+		t.Errorf("TransformToJSON = %s, %v", out, err)
+	}
+
+	bad := map[string]string{
+		`{"rules":{"a":"x","b":"x.y"}}`:                   `rule "b" -> "x.y"`,
+		`{"rules":{"a":"x"},"type_cast":{"x":"decimal"}}`: `unknown cast "decimal"`,
+		`{"rules":{"a":"x"},"type_cast":{"y":"int"}}`:     `does not match any rule target`,
+		`{"rules":{"a":1}}`:                               `rules["a"] must be a string`,
+		`{"rules":{},"typecast":{}}`:                      `did you mean "type_cast"?`,
+	}
+	for spec, want := range bad {
+		if _, err := LoadMapper([]byte(spec)); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: err = %v, want %q", spec, err, want)
+		}
+	}
+}
+
+// TestNumberRuleBounds 测试开区间边界、零边界、整数与倍数约束
+func TestNumberRuleBounds(t *testing.T) {
+	rule := ValidationRule{Type: "number", HasMin: true, ExclusiveMin: true, Max: 1, MultipleOf: 0.1}
+	cases := map[string]string{
+		`0.3`:  "",
+		`1`:    "",
+		`0`:    "must be greater than 0",
+		`-0.5`: "must be greater than 0",
+		`1.1`:  "maximum is 1",
+		`0.25`: "not a multiple of 0.1",
+	}
+	for value, want := range cases {
+		_, err := validateAndConvertField(FromString(value), rule)
+		if want == "" && err != nil || want != "" && (err == nil || !strings.Contains(err.Error(), want)) {
+			t.Errorf("%s: err = %v, want %q", value, err, want)
+		}
+	}
+
+	integer := ValidationRule{Type: "number", Integer: true, HasMax: true, ExclusiveMax: true}
+	for value, ok := range map[string]bool{`-3`: true, `-2.0`: true, `-1.5`: false, `0`: false} {
+		if _, err := validateAndConvertField(FromString(value), integer); (err == nil) != ok {
+			t.Errorf("integer rule %s: err = %v", value, err)
+		}
+	}
+
+	// 配置中出现 "min": 0 时 0 作为边界生效
+	v, err := LoadValidator([]byte(`{"rules":{"qty":{"type":"number","min":0,"integer":true},"rate":{"type":"number","min":0,"max":1,"exclusive_max":true}}}`))
+	if err != nil {
+		t.Fatalf("LoadValidator error: %v", err)
+	}
+	if !v.Rules["qty"].HasMin || v.Rules["qty"].HasMax {
+		t.Errorf("HasMin/HasMax = %v/%v", v.Rules["qty"].HasMin, v.Rules["qty"].HasMax)
+	}
+	if _, errs := FromString(`{"qty":-1,"rate":1}`).Validate(v); len(errs) != 2 {
+		t.Errorf("expected min and exclusive max errors, got %v", errs)
+	}
+	if _, errs := FromString(`{"qty":0,"rate":0.99}`).Validate(v); len(errs) != 0 {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+	if v, err := LoadValidator([]byte(`{"rules":{"n":{"type":"number","default":5}}}`)); err != nil || v.Rules["n"].Default != float64(5) {
+		t.Errorf("integer default = %#v, %v", v, err)
+	}
+
+	bad := map[string]string{
+		`{"rules":{"a":{"type":"number","exclusive_min":true}}}`:                     `exclusive_min requires min`,
+		`{"rules":{"a":{"type":"number","min":1,"max":1,"exclusive_max":true}}}`:     `leave no valid value`,
+		`{"rules":{"a":{"type":"number","multiple_of":-2}}}`:                         `multiple_of must be a positive number`,
+		`{"rules":{"a":{"type":"string","integer":true}}}`:                           `require type "number"`,
+		`{"rules":{"a":{"type":"number","min":0,"exclusive_min":true,"default":0}}}`: `field "default": number too small`,
+		`{"rules":{"a":{"type":"number","integer":true,"default":1.5}}}`:             `not an integer`,
+	}
+	for spec, want := range bad {
+		if _, err := LoadValidator([]byte(spec)); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: err = %v, want %q", spec, err, want)
+		}
+	}
+}
+
+// TestArrayRules 测试数组元素数量、唯一性与元素规则
+func TestArrayRules(t *testing.T) {
+	v, err := LoadValidator([]byte(`{"rules":{
+		"tags":{"required":true,"type":"array","min_items":1,"unique_items":true,"items":{"type":"string","max_length":5}},
+		"matrix":{"type":"array","max_items":2,"items":{"type":"array","items":{"type":"number","min":0}}},
+		"points":{"type":"array","unique_items":true}
+	}}`))
+	if err != nil {
+		t.Fatalf("LoadValidator error: %v", err)
+	}
+
+	result, errs := FromString(`{"tags":["go","json"],"matrix":[[0,1],[2]],"points":[{"x":1,"y":2},{"x":2,"y":1}]}`).Validate(v)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if string(result["tags"].([]byte)) != `["go","json"]` {
+		t.Errorf("tags = %s", result["tags"])
+	}
+
+	cases := map[string]string{
+		`{"tags":[]}`:                                             `field 'tags': array too short, minimum items is 1`,
+		`{"tags":"go"}`:                                           `expected array, got string`,
+		`{"tags":["go","toolong"]}`:                               `item 1: string too long`,
+		`{"tags":["go",1]}`:                                       `item 1: node is not a string`,
+		`{"tags":["a","b","a"]}`:                                  `item 2 duplicates item 0`,
+		`{"tags":["x"],"matrix":[[1],[2],[3]]}`:                   `array too long, maximum items is 2`,
+		`{"tags":["x"],"matrix":[[1],[2,-1]]}`:                    `item 1: item 1: number too small`,
+		`{"tags":["x"],"points":[{"x":1,"y":2},{"y":2,"x":1.0}]}`: `item 1 duplicates item 0`,
+	}
+	for doc, want := range cases {
+		_, errs := FromString(doc).Validate(v)
+		if len(errs) != 1 || !strings.Contains(errs[0].Error(), want) {
+			t.Errorf("%s: errs = %v, want %q", doc, errs, want)
+		}
+	}
+
+	bad := map[string]string{
+		`{"rules":{"a":{"type":"array","min_items":3,"max_items":2}}}`:               `min_items 3 is greater than max_items 2`,
+		`{"rules":{"a":{"type":"string","unique_items":true}}}`:                      `require type "array"`,
+		`{"rules":{"a":{"type":"array","items":{"type":"number","max_len":1}}}}`:     `field "items": unknown field "max_len"`,
+		`{"rules":{"a":{"type":"array","items":{"type":"number","min":5,"max":1}}}}`: `field "items": min 5 is greater than max 1`,
+	}
+	for spec, want := range bad {
+		if _, err := LoadValidator([]byte(spec)); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: err = %v, want %q", spec, err, want)
+		}
+	}
+}
+
+// TestEnumRules 测试 enum/const 规则与 OneOf 辅助方法
+func TestEnumRules(t *testing.T) {
+	v, err := LoadValidator([]byte(`{"rules":{
+		"status":{"type":"string","enum":["active","disabled"],"default":"active"},
+		"level":{"type":"number","enum":[1,2,3]},
+		"version":{"const":2},
+		"shape":{"enum":[{"x":1,"y":2},null]}
+	}}`))
+	if err != nil {
+		t.Fatalf("LoadValidator error: %v", err)
+	}
+	if _, errs := FromString(`{"status":"disabled","level":2.0,"version":2,"shape":{"y":2,"x":1}}`).Validate(v); len(errs) != 0 {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+	if _, errs := FromString(`{"shape":null}`).Validate(v); len(errs) != 0 {
+		t.Errorf("null enum: unexpected errors: %v", errs)
+	}
+
+	cases := map[string]string{
+		`{"status":"deleted"}`: `value "deleted" is not one of ["active", "disabled"]`,
+		`{"level":4}`:          `value 4 is not one of [1, 2, 3]`,
+		`{"version":"2"}`:      `value "2" does not equal const 2`,
+		`{"shape":{"x":1}}`:    `is not one of`,
+	}
+	for doc, want := range cases {
+		_, errs := FromString(doc).Validate(v)
+		if len(errs) != 1 || !strings.Contains(errs[0].Error(), want) {
+			t.Errorf("%s: errs = %v, want %q", doc, errs, want)
+		}
+	}
+
+	bad := map[string]string{
+		`{"rules":{"a":{"type":"string","enum":["a",1]}}}`:                 `field "enum": item 1: 1 does not match type "string"`,
+		`{"rules":{"a":{"type":"boolean","const":"yes"}}}`:                 `field "const"`,
+		`{"rules":{"a":{"type":"string","enum":["a"],"default":"b"}}}`:     `field "default": value "b" is not one of ["a"]`,
+		`{"rules":{"a":{"type":"boolean","enum":[true],"default":false}}}`: `field "default"`,
+	}
+	for spec, want := range bad {
+		if _, err := LoadValidator([]byte(spec)); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: err = %v, want %q", spec, err, want)
+		}
+	}
+
+	node := FromString(`{"s":"\u0061bc","plain":"open","n":7,"f":7.5}`)
+	if !node.Get("s").OneOf("x", "abc") || !node.Get("plain").OneOf("open", "closed") || node.Get("plain").OneOf("Open") {
+		t.Error("OneOf mismatch")
+	}
+	if node.Get("n").OneOf("7") || !node.Get("n").OneOfInt(1, 7) || node.Get("f").OneOfInt(7) || node.Get("missing").OneOfInt(0) {
+		t.Error("OneOfInt mismatch")
+	}
+}
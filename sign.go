@@ -0,0 +1,141 @@
+package fxjson
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	_ "crypto/sha256" // 注册默认摘要算法 SHA-256
+	"encoding/base64"
+	"fmt"
+	"strconv"
+)
+
+// SignOptions 签名与验证选项，签名方与验证方需使用相同的选项
+type SignOptions struct {
+	Hash crypto.Hash // 摘要算法，默认 SHA-256；Ed25519 密钥直接对规范化字节签名，忽略此项
+
+	// Field 非空时使用内嵌签名：签名内容为根对象去掉该字段后的规范化序列化，
+	// 签名以 base64url（无填充）字符串写入该字段；为空时使用分离签名
+	Field string
+}
+
+// hash 返回摘要算法，未设置时为 SHA-256
+func (o SignOptions) hash() crypto.Hash {
+	if o.Hash == 0 {
+		return crypto.SHA256
+	}
+	return o.Hash
+}
+
+// Sign 对节点的规范化序列化（见 Canonical）签名，节点不能是展开嵌套 JSON 得到的（见 ParseOptions.LazyExpansion）
+// 分离签名返回原始签名字节；内嵌签名（opts.Field 非空）返回写入签名字段后的规范化文档。
+// 支持 Ed25519、ECDSA（ASN.1 编码）和 RSA（PKCS #1 v1.5）签名器
+func Sign(node Node, key crypto.Signer, opts SignOptions) (_ []byte, err error) {
+	defer recoverPanic(&err, "Sign", node)
+
+	payload, err := signingPayload(node, opts)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := signPayload(key, payload, opts)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Field == "" {
+		return sig, nil
+	}
+
+	encoded := strconv.Quote(base64.RawURLEncoding.EncodeToString(sig))
+	return node.appendCanonicalObject(nil, opts.Field, canonicalMember{key: opts.Field, value: FromString(encoded)})
+}
+
+// Verify 验证节点规范化序列化的签名，签名无效时返回错误
+// 内嵌签名时 signature 为 nil 则从 opts.Field 字段读取签名。
+// pub 支持 ed25519.PublicKey、*ecdsa.PublicKey 和 *rsa.PublicKey
+func Verify(node Node, signature []byte, pub crypto.PublicKey, opts SignOptions) (err error) {
+	defer recoverPanic(&err, "Verify", node)
+
+	if signature == nil && opts.Field != "" {
+		field := node.Get(opts.Field)
+		if !field.Exists() {
+			return fmt.Errorf("signature field %q not found", opts.Field)
+		}
+		encoded, err := field.String()
+		if err != nil {
+			return fmt.Errorf("signature field %q must be a string, got %s", opts.Field, field.Kind())
+		}
+		if signature, err = base64.RawURLEncoding.DecodeString(encoded); err != nil {
+			return fmt.Errorf("signature field %q is not valid base64url: %w", opts.Field, err)
+		}
+	}
+
+	payload, err := signingPayload(node, opts)
+	if err != nil {
+		return err
+	}
+
+	var ok bool
+	switch pub := pub.(type) {
+	case ed25519.PublicKey:
+		ok = ed25519.Verify(pub, payload, signature)
+	case *ecdsa.PublicKey:
+		digest, err := signDigest(payload, opts)
+		if err != nil {
+			return err
+		}
+		ok = ecdsa.VerifyASN1(pub, digest, signature)
+	case *rsa.PublicKey:
+		digest, err := signDigest(payload, opts)
+		if err != nil {
+			return err
+		}
+		ok = rsa.VerifyPKCS1v15(pub, opts.hash(), digest, signature) == nil
+	default:
+		return fmt.Errorf("unsupported public key type %T", pub)
+	}
+	if !ok {
+		return fmt.Errorf("signature verification failed")
+	}
+	return nil
+}
+
+// signingPayload 返回被签名的字节：分离签名为整个节点的规范化序列化，内嵌签名排除签名字段
+// 节点必须保留原始数据：展开嵌套 JSON 后内容为 JSON 的字符串与对应的对象规范化结果相同，
+// 签名就可以在两者之间移用，因此拒绝展开过的节点（应使用 LazyExpansion 解析）
+func signingPayload(node Node, opts SignOptions) ([]byte, error) {
+	if node.Exists() && node.OriginalBytes() == nil {
+		return nil, fmt.Errorf("cannot sign or verify a node with expanded nested JSON; parse with LazyExpansion")
+	}
+	if opts.Field == "" {
+		return node.Canonical()
+	}
+	if node.typ != 'o' {
+		return nil, fmt.Errorf("embedded signature requires an object, got %s", node.Kind())
+	}
+	return node.appendCanonicalObject(nil, opts.Field, canonicalMember{})
+}
+
+// signPayload 使用签名器对载荷签名
+func signPayload(key crypto.Signer, payload []byte, opts SignOptions) ([]byte, error) {
+	if _, ok := key.Public().(ed25519.PublicKey); ok {
+		return key.Sign(rand.Reader, payload, crypto.Hash(0))
+	}
+	digest, err := signDigest(payload, opts)
+	if err != nil {
+		return nil, err
+	}
+	return key.Sign(rand.Reader, digest, opts.hash())
+}
+
+// signDigest 计算载荷摘要
+func signDigest(payload []byte, opts SignOptions) ([]byte, error) {
+	h := opts.hash()
+	if !h.Available() {
+		return nil, fmt.Errorf("hash function %v is not available", h)
+	}
+	hasher := h.New()
+	hasher.Write(payload)
+	return hasher.Sum(nil), nil
+}
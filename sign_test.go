@@ -0,0 +1,88 @@
+package fxjson
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"strings"
+	"testing"
+)
+
+// TestSignVerify 测试分离签名与内嵌签名
+func TestSignVerify(t *testing.T) {
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+
+	payload := FromString(`{"event":"paid","amount":10.0,"id":"e1"}`)
+	reordered := FromString(`{ "id":"e1", "amount":10, "event":"paid" }`)
+	tampered := FromString(`{"event":"paid","amount":11,"id":"e1"}`)
+
+	for name, key := range map[string]crypto.Signer{"ed25519": edKey, "ecdsa": ecKey, "rsa": rsaKey} {
+		sig, err := Sign(payload, key, SignOptions{})
+		if err != nil {
+			t.Fatalf("%s: Sign error: %v", name, err)
+		}
+		if err := Verify(reordered, sig, key.Public(), SignOptions{}); err != nil {
+			t.Errorf("%s: Verify of equivalent document: %v", name, err)
+		}
+		if err := Verify(tampered, sig, key.Public(), SignOptions{}); err == nil {
+			t.Errorf("%s: Verify of tampered document should fail", name)
+		}
+
+		opts := SignOptions{Field: "signature"}
+		signed, err := Sign(payload, key, opts)
+		if err != nil {
+			t.Fatalf("%s: embedded Sign error: %v", name, err)
+		}
+		doc := FromBytes(signed)
+		if !doc.Get("signature").Exists() || !strings.HasPrefix(string(signed), `{"amount":10,"event":"paid","id":"e1","signature":"`) {
+			t.Errorf("%s: embedded document = %s", name, signed)
+		}
+		if err := Verify(doc, nil, key.Public(), opts); err != nil {
+			t.Errorf("%s: embedded Verify: %v", name, err)
+		}
+		forged := strings.Replace(string(signed), `"paid"`, `"refunded"`, 1)
+		if err := Verify(FromString(forged), nil, key.Public(), opts); err == nil {
+			t.Errorf("%s: embedded Verify of forged document should fail", name)
+		}
+	}
+
+	if err := Verify(payload, nil, ecKey.Public(), SignOptions{Field: "signature"}); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("missing signature field: err = %v", err)
+	}
+	if _, err := Sign(FromString(`[1]`), edKey, SignOptions{Field: "signature"}); err == nil {
+		t.Error("embedded signature on an array should fail")
+	}
+}
+
+// TestSignNestedJSONString 测试内容为 JSON 的字符串与对应对象的签名不能互换
+func TestSignNestedJSONString(t *testing.T) {
+	_, key, _ := ed25519.GenerateKey(rand.Reader)
+	asString, err := ParseBytes([]byte(`{"amount":"{\"v\":1}"}`), ParseOptions{LazyExpansion: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := Sign(asString, key, SignOptions{})
+	if err != nil {
+		t.Fatalf("Sign error: %v", err)
+	}
+	if err := Verify(asString, sig, key.Public(), SignOptions{}); err != nil {
+		t.Errorf("Verify of signed document: %v", err)
+	}
+	if err := Verify(FromString(`{"amount":{"v":1}}`), sig, key.Public(), SignOptions{}); err == nil {
+		t.Error("signature over a string must not verify the object it encodes")
+	}
+
+	// 展开过的节点已丢失原始字符串，拒绝签名与验证
+	expanded := FromString(`{"amount":"{\"v\":1}"}`)
+	if _, err := Sign(expanded, key, SignOptions{}); err == nil || !strings.Contains(err.Error(), "LazyExpansion") {
+		t.Errorf("Sign of expanded node: err = %v", err)
+	}
+	if err := Verify(expanded, sig, key.Public(), SignOptions{}); err == nil {
+		t.Error("Verify of expanded node should fail")
+	}
+}
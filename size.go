@@ -0,0 +1,85 @@
+package fxjson
+
+// 解码为 any 时各类值的估算堆开销（64 位平台）
+const (
+	decodedMapSize      = 48 // map 头部
+	decodedMapEntrySize = 48 // map 条目：键的字符串头、值的接口以及槽位开销
+	decodedSliceSize    = 24 // 切片头
+	decodedElemSize     = 16 // 切片中的接口元素
+	decodedStringSize   = 16 // 装箱的字符串头
+	decodedNumberSize   = 8  // 装箱的 float64
+)
+
+// SizeBytes 返回节点原始 JSON 的字节数（包括内部空白），节点不存在时返回 0
+func (n Node) SizeBytes() int {
+	if !n.Exists() {
+		return 0
+	}
+	return n.end - n.start
+}
+
+// DeepCount 统计节点子树（包括节点自身）中对象、数组、字符串值与数字的数量，对象键不计入字符串
+// 只扫描一遍原始字节，不构建子节点
+func (n Node) DeepCount() (objects, arrays, strings, numbers int) {
+	if !n.Exists() {
+		return
+	}
+	data := n.Raw()
+	for i := 0; i < len(data); {
+		switch c := data[i]; {
+		case c == '{':
+			objects++
+			i++
+		case c == '[':
+			arrays++
+			i++
+		case c == '"':
+			i = skipStringSimple(data, i, len(data))
+			j := i
+			for j < len(data) && data[j] <= ' ' {
+				j++
+			}
+			if j >= len(data) || data[j] != ':' {
+				strings++
+			}
+		case c == '-' || (c >= '0' && c <= '9'):
+			numbers++
+			for i++; i < len(data) && isNumberTailByte(data[i]); i++ {
+			}
+		default:
+			i++
+		}
+	}
+	return
+}
+
+// isNumberTailByte 字节是否可出现在数字字面量的首字符之后
+func isNumberTailByte(c byte) bool {
+	return (c >= '0' && c <= '9') || c == '.' || c == 'e' || c == 'E' || c == '+' || c == '-'
+}
+
+// EstimateDecodedSize 估算将节点解码为 any（map[string]any、[]any、string、float64 等）所需的堆内存字节数
+// 结果为近似值，可用于在处理前拒绝或分流过大的子树
+func (n Node) EstimateDecodedSize() int {
+	switch n.typ {
+	case 'o':
+		size := decodedMapSize
+		n.ForEach(func(key string, value Node) bool {
+			size += decodedMapEntrySize + len(key) + value.EstimateDecodedSize()
+			return true
+		})
+		return size
+	case 'a':
+		size := decodedSliceSize
+		n.ArrayForEach(func(_ int, value Node) bool {
+			size += decodedElemSize + value.EstimateDecodedSize()
+			return true
+		})
+		return size
+	case 's':
+		return decodedStringSize + n.SizeBytes() - 2
+	case 'n':
+		return decodedNumberSize
+	}
+	return 0 // 布尔值与 null 不分配
+}
@@ -0,0 +1,35 @@
+package fxjson
+
+import "testing"
+
+// TestSizeReporting 测试原始大小、深度计数与解码大小估算
+func TestSizeReporting(t *testing.T) {
+	node := FromString(`{"a": [1, -2.5e3, "x"], "b": {"c": "say \"hi\"", "d": null, "e": true}, "f": []}`)
+
+	if got := node.Get("a").SizeBytes(); got != len(`[1, -2.5e3, "x"]`) {
+		t.Errorf("SizeBytes = %d", got)
+	}
+	if (Node{}).SizeBytes() != 0 {
+		t.Error("missing node SizeBytes should be 0")
+	}
+
+	objects, arrays, strs, numbers := node.DeepCount()
+	if objects != 2 || arrays != 2 || strs != 2 || numbers != 2 {
+		t.Errorf("DeepCount = %d, %d, %d, %d", objects, arrays, strs, numbers)
+	}
+	if o, a, s, n := node.Get("a").Index(1).DeepCount(); o+a+s != 0 || n != 1 {
+		t.Errorf("scalar DeepCount = %d, %d, %d, %d", o, a, s, n)
+	}
+
+	small := FromString(`{"a":1}`).EstimateDecodedSize()
+	large := node.EstimateDecodedSize()
+	if small <= 0 || large <= small {
+		t.Errorf("EstimateDecodedSize small = %d, large = %d", small, large)
+	}
+	if got := FromString(`"abcd"`).EstimateDecodedSize(); got != decodedStringSize+4 {
+		t.Errorf("string EstimateDecodedSize = %d", got)
+	}
+	if got := FromString(`null`).EstimateDecodedSize(); got != 0 {
+		t.Errorf("null EstimateDecodedSize = %d", got)
+	}
+}
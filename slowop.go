@@ -0,0 +1,84 @@
+package fxjson
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// 慢操作的类型
+const (
+	OpParse     = "parse"     // ParseBytes、FromBytes 等解析入口
+	OpQuery     = "query"     // QueryBuilder.ToSlice（及 Count、First 等）
+	OpJQ        = "jq"        // JQProgram.Run
+	OpSerialize = "serialize" // Marshal、MarshalToString 等序列化入口
+)
+
+// SlowOp 超过阈值的操作描述
+type SlowOp struct {
+	Op          string        // 操作类型，见 OpParse 等常量
+	Path        string        // jq 程序源码，其他操作为空
+	Keys        []string      // 查询涉及的字段（条件与排序字段）
+	InputBytes  int           // 输入的字节数（解析的数据、被查询的节点）
+	OutputBytes int           // 输出的字节数（序列化结果、查询结果节点的总长度）
+	Duration    time.Duration // 耗时
+	Err         error         // 操作返回的错误
+}
+
+// SlowOpConfig 慢操作监控配置
+type SlowOpConfig struct {
+	MinDuration time.Duration // 耗时达到该值时回调，0 表示不按耗时判断
+	MinBytes    int           // 输入或输出字节数达到该值时回调，0 表示不按大小判断
+	// Handler 在操作所在的 goroutine 中同步调用，应尽快返回；
+	// MinDuration 与 MinBytes 都为 0 时每次操作都会回调
+	Handler func(SlowOp)
+}
+
+// slowOpConfig 当前的慢操作监控配置，nil 表示关闭
+var slowOpConfig atomic.Pointer[SlowOpConfig]
+
+// SetSlowOpConfig 设置进程级的慢操作监控，用于发现异常负载而无需在每个调用处计时
+// Handler 为 nil 时关闭监控；关闭时各入口只多一次原子读取
+//
+//	fxjson.SetSlowOpConfig(fxjson.SlowOpConfig{
+//		MinDuration: 50 * time.Millisecond,
+//		MinBytes:    10 << 20,
+//		Handler:     func(op fxjson.SlowOp) { log.Printf("slow %s: %v %d bytes", op.Op, op.Duration, op.InputBytes) },
+//	})
+//
+// Deprecated: 新代码请使用 debugx.SetSlowOpConfig，该函数仅为兼容保留
+func SetSlowOpConfig(cfg SlowOpConfig) {
+	if cfg.Handler == nil {
+		slowOpConfig.Store(nil)
+		return
+	}
+	slowOpConfig.Store(&cfg)
+}
+
+// slowOpStart 在操作开始时调用，监控关闭时返回 nil；返回的函数在操作结束时检查阈值
+func slowOpStart() func(SlowOp) {
+	c := slowOpConfig.Load()
+	if c == nil {
+		return nil
+	}
+	start := time.Now()
+	return func(op SlowOp) { c.observe(op, start) }
+}
+
+// observe 操作结束时检查阈值并回调
+func (c *SlowOpConfig) observe(op SlowOp, start time.Time) {
+	op.Duration = time.Since(start)
+	size := max(op.InputBytes, op.OutputBytes)
+	all := c.MinDuration <= 0 && c.MinBytes <= 0
+	if all || (c.MinDuration > 0 && op.Duration >= c.MinDuration) || (c.MinBytes > 0 && size >= c.MinBytes) {
+		c.Handler(op)
+	}
+}
+
+// nodesBytes 返回节点原始数据的总长度
+func nodesBytes(nodes []Node) int {
+	total := 0
+	for _, n := range nodes {
+		total += n.end - n.start
+	}
+	return total
+}
@@ -0,0 +1,77 @@
+package fxjson
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestSlowOpConfig 测试慢操作阈值回调
+func TestSlowOpConfig(t *testing.T) {
+	var mu sync.Mutex
+	var ops []SlowOp
+	SetSlowOpConfig(SlowOpConfig{
+		MinBytes: 1 << 16,
+		Handler: func(op SlowOp) {
+			mu.Lock()
+			ops = append(ops, op)
+			mu.Unlock()
+		},
+	})
+	defer SetSlowOpConfig(SlowOpConfig{})
+
+	big := `[{"name":"` + strings.Repeat("x", 1<<16) + `","age":30},{"name":"b","age":20}]`
+	node, err := ParseBytes([]byte(big), DefaultParseOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := node.Query().Where("age", ">", 25).SortBy("name", "asc").ToSlice(); err != nil {
+		t.Fatal(err)
+	}
+	prog, _ := CompileJQ(".[0]")
+	if _, err := prog.Run(node); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Marshal(map[string]string{"k": strings.Repeat("y", 1<<16)}); err != nil {
+		t.Fatal(err)
+	}
+	FromString(`{"small":true}`) // 低于阈值，不回调
+
+	mu.Lock()
+	got := append([]SlowOp(nil), ops...)
+	mu.Unlock()
+	kinds := make([]string, len(got))
+	for i, op := range got {
+		kinds[i] = op.Op
+	}
+	if strings.Join(kinds, ",") != "parse,query,jq,serialize" {
+		t.Fatalf("ops = %v", kinds)
+	}
+	if got[0].InputBytes != len(big) || got[0].Duration <= 0 {
+		t.Errorf("parse op = %+v", got[0])
+	}
+	if strings.Join(got[1].Keys, ",") != "age,name" || got[1].OutputBytes < 1<<16 {
+		t.Errorf("query op keys = %v, output = %d", got[1].Keys, got[1].OutputBytes)
+	}
+	if got[2].Path != ".[0]" {
+		t.Errorf("jq op path = %q", got[2].Path)
+	}
+	if got[3].OutputBytes < 1<<16 {
+		t.Errorf("serialize op output = %d", got[3].OutputBytes)
+	}
+
+	// 关闭后不再回调，按耗时阈值回调时带上错误
+	SetSlowOpConfig(SlowOpConfig{})
+	ParseBytes([]byte(big), DefaultParseOptions)
+	var parseErr error
+	SetSlowOpConfig(SlowOpConfig{MinDuration: time.Nanosecond, Handler: func(op SlowOp) {
+		if op.Op == OpParse {
+			parseErr = op.Err
+		}
+	}})
+	ParseBytes([]byte(`{"a":`), DefaultParseOptions)
+	if len(ops) != 4 || parseErr == nil {
+		t.Errorf("ops after disable = %d, parse error = %v", len(ops), parseErr)
+	}
+}
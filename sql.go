@@ -0,0 +1,728 @@
+package fxjson
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Select 在数组上执行简化的 SQL 查询，返回以行对象组成的数组节点（列按 SELECT 顺序排列）
+//
+// 支持的语法（关键字不区分大小写）：
+//
+//	SELECT * | col [AS alias], ... FROM $[.path] | path
+//	  [WHERE cond [AND cond]...]
+//	  [GROUP BY field, ...]
+//	  [ORDER BY col [ASC|DESC] [NULLS FIRST|LAST], ...]
+//	  [LIMIT n [OFFSET m]]
+//
+// 列可以是字段路径或聚合函数 COUNT(*)、COUNT(field)、SUM、AVG、MIN、MAX；
+// 条件支持 = != <> > < >= <=、[NOT] IN (...)、LIKE '%text%' 与 IS [NOT] NULL，值为 '字符串'、数字、true/false/null；
+// ORDER BY 的列可带 NULLS FIRST 或 NULLS LAST（默认）。
+// 查询被转换为 QueryBuilder 与 Aggregator 执行：无聚合时 ORDER BY 作用于源字段，
+// 有聚合时作用于结果列。字段列的默认列名为路径的最后一段，聚合列为其原始写法（如 SUM(revenue)）。
+func Select(query string, node Node) (_ Node, err error) {
+	defer recoverPanic(&err, "Select", node)
+
+	stmt, err := parseSelect(query)
+	if err != nil {
+		return Node{}, err
+	}
+	return stmt.execute(node)
+}
+
+// selectColumn SELECT 中的一列
+type selectColumn struct {
+	name  string // 结果列名
+	field string // 字段路径；COUNT(*) 与 * 为空
+	agg   string // 聚合类型（count/sum/avg/max/min），为空表示字段列
+	star  bool   // SELECT *
+}
+
+// selectStmt 解析后的查询
+type selectStmt struct {
+	columns []selectColumn
+	from    string
+	where   []Condition
+	groupBy []string
+	orderBy []SortField
+	limit   int
+	offset  int
+}
+
+// hasAggregates 是否包含聚合列
+func (s *selectStmt) hasAggregates() bool {
+	for _, c := range s.columns {
+		if c.agg != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// execute 执行查询
+func (s *selectStmt) execute(node Node) (Node, error) {
+	source := node
+	if s.from != "$" {
+		source = node.GetPath(strings.TrimPrefix(s.from, "$."))
+	}
+	if source.Type() != 'a' {
+		return Node{}, fmt.Errorf("select source %q is not an array", s.from)
+	}
+
+	qb := source.Query()
+	for _, cond := range s.where {
+		qb.Where(cond.Field, cond.Operator, cond.Value)
+	}
+
+	if !s.hasAggregates() && len(s.groupBy) == 0 {
+		for _, sf := range s.orderBy {
+			qb.SortByNulls(sf.Field, sf.Order, sf.Nulls)
+		}
+		qb.Offset(s.offset)
+		if s.limit > 0 {
+			qb.Limit(s.limit)
+		}
+		items, err := qb.ToSlice()
+		if err != nil {
+			return Node{}, err
+		}
+		rows := make([][]byte, len(items))
+		for i, item := range items {
+			if rows[i], err = s.projectRow(item, nil); err != nil {
+				return Node{}, err
+			}
+		}
+		return FromBytes(joinRows(rows)), nil
+	}
+
+	items, err := qb.ToSlice()
+	if err != nil {
+		return Node{}, err
+	}
+	rows, err := s.aggregateRows(items)
+	if err != nil {
+		return Node{}, err
+	}
+
+	result := FromBytes(joinRows(rows))
+	if len(s.orderBy) == 0 && s.limit <= 0 && s.offset == 0 {
+		return result, nil
+	}
+	rq := result.Query()
+	for _, sf := range s.orderBy {
+		rq.SortByNulls(sf.Field, sf.Order, sf.Nulls)
+	}
+	rq.Offset(s.offset)
+	if s.limit > 0 {
+		rq.Limit(s.limit)
+	}
+	return rq.ToNode()
+}
+
+// aggregateRows 按 GROUP BY 分组（保持分组首次出现的顺序）并计算聚合列
+func (s *selectStmt) aggregateRows(items []Node) ([][]byte, error) {
+	for _, c := range s.columns {
+		if c.star {
+			return nil, fmt.Errorf("SELECT * cannot be combined with aggregates or GROUP BY")
+		}
+		if c.agg == "" && !containsString(s.groupBy, c.field) {
+			return nil, fmt.Errorf("column %q must appear in GROUP BY or be used in an aggregate", c.field)
+		}
+	}
+
+	grouper := &Aggregator{groupBy: s.groupBy}
+	var order []string
+	groups := make(map[string][]Node)
+	for _, item := range items {
+		key := grouper.buildGroupKey(item)
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], item)
+	}
+	// 无分组的聚合即使没有匹配的元素也返回一行
+	if len(s.groupBy) == 0 && len(order) == 0 {
+		order = append(order, "")
+	}
+
+	rows := make([][]byte, 0, len(order))
+	for _, key := range order {
+		groupItems := groups[key]
+		values := make(map[string]interface{})
+		for _, c := range s.columns {
+			if c.agg == "" {
+				continue
+			}
+			opItems := groupItems
+			if c.agg == "count" && c.field != "" {
+				// COUNT(field) 只统计字段存在且不为 null 的元素
+				opItems = nil
+				for _, item := range groupItems {
+					if v := item.Get(c.field); v.Exists() && !v.IsNull() {
+						opItems = append(opItems, item)
+					}
+				}
+			}
+			value, err := grouper.executeOperation(AggOperation{Type: c.agg, Field: c.field, Alias: c.name}, opItems)
+			if err != nil {
+				return nil, err
+			}
+			values[c.name] = value
+		}
+
+		var first Node
+		if len(groupItems) > 0 {
+			first = groupItems[0]
+		}
+		row, err := s.projectRow(first, values)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// projectRow 按列生成一行：字段列复用原始字节，聚合列使用 aggValues 中的结果
+func (s *selectStmt) projectRow(item Node, aggValues map[string]interface{}) ([]byte, error) {
+	if len(s.columns) == 1 && s.columns[0].star {
+		return append([]byte(nil), item.Raw()...), nil
+	}
+
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	buf.WriteByte('{')
+	for i, c := range s.columns {
+		if c.star {
+			return nil, fmt.Errorf("SELECT * cannot be combined with other columns")
+		}
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		writeString(buf, c.name, false)
+		buf.WriteByte(':')
+
+		if c.agg != "" {
+			data, err := Marshal(aggValues[c.name])
+			if err != nil {
+				return nil, err
+			}
+			buf.Write(data)
+			continue
+		}
+		if v := item.GetPath(c.field); v.Exists() {
+			buf.Write(v.Raw())
+		} else {
+			buf.WriteString("null")
+		}
+	}
+	buf.WriteByte('}')
+
+	return append([]byte(nil), buf.buf...), nil
+}
+
+// joinRows 将行拼接为 JSON 数组
+func joinRows(rows [][]byte) []byte {
+	out := []byte{'['}
+	for i, row := range rows {
+		if i > 0 {
+			out = append(out, ',')
+		}
+		out = append(out, row...)
+	}
+	return append(out, ']')
+}
+
+// containsString 切片中是否包含 s
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// ===== 解析 =====
+
+// sqlTokenKind 词法单元类型
+type sqlTokenKind int
+
+const (
+	sqlTokEOF sqlTokenKind = iota
+	sqlTokIdent
+	sqlTokString
+	sqlTokNumber
+	sqlTokSymbol
+)
+
+// sqlToken 词法单元
+type sqlToken struct {
+	kind sqlTokenKind
+	text string
+	pos  int
+}
+
+// lexSQL 切分查询语句
+func lexSQL(src string) ([]sqlToken, error) {
+	var tokens []sqlToken
+	i := 0
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '\'':
+			var sb strings.Builder
+			j := i + 1
+			for {
+				if j >= len(src) {
+					return nil, fmt.Errorf("sql: unterminated string at position %d", i)
+				}
+				if src[j] == '\'' {
+					if j+1 < len(src) && src[j+1] == '\'' {
+						sb.WriteByte('\'')
+						j += 2
+						continue
+					}
+					break
+				}
+				sb.WriteByte(src[j])
+				j++
+			}
+			tokens = append(tokens, sqlToken{kind: sqlTokString, text: sb.String(), pos: i})
+			i = j + 1
+		case c == '"' || c == '`':
+			end := strings.IndexByte(src[i+1:], c)
+			if end < 0 {
+				return nil, fmt.Errorf("sql: unterminated identifier at position %d", i)
+			}
+			tokens = append(tokens, sqlToken{kind: sqlTokIdent, text: src[i+1 : i+1+end], pos: i})
+			i += end + 2
+		case c >= '0' && c <= '9' || (c == '-' && i+1 < len(src) && src[i+1] >= '0' && src[i+1] <= '9'):
+			j := i + 1
+			for j < len(src) && (src[j] >= '0' && src[j] <= '9' || src[j] == '.' || src[j] == 'e' || src[j] == 'E') {
+				j++
+			}
+			tokens = append(tokens, sqlToken{kind: sqlTokNumber, text: src[i:j], pos: i})
+			i = j
+		case isSQLIdentChar(c):
+			j := i
+			for j < len(src) && isSQLIdentChar(src[j]) {
+				j++
+			}
+			tokens = append(tokens, sqlToken{kind: sqlTokIdent, text: src[i:j], pos: i})
+			i = j
+		default:
+			op := string(c)
+			if i+1 < len(src) {
+				switch two := src[i : i+2]; two {
+				case "!=", "<>", "<=", ">=":
+					op = two
+				}
+			}
+			if !strings.Contains("(),*=<>!", op[:1]) || op == "!" {
+				return nil, fmt.Errorf("sql: unexpected character %q at position %d", c, i)
+			}
+			tokens = append(tokens, sqlToken{kind: sqlTokSymbol, text: op, pos: i})
+			i += len(op)
+		}
+	}
+	return append(tokens, sqlToken{kind: sqlTokEOF, pos: len(src)}), nil
+}
+
+// isSQLIdentChar 标识符（含路径）字符
+func isSQLIdentChar(c byte) bool {
+	return c == '_' || c == '.' || c == '$' || c == '[' || c == ']' ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// sqlParser 查询语句解析器
+type sqlParser struct {
+	tokens []sqlToken
+	pos    int
+}
+
+func (p *sqlParser) peek() sqlToken { return p.tokens[p.pos] }
+
+func (p *sqlParser) next() sqlToken {
+	t := p.tokens[p.pos]
+	if t.kind != sqlTokEOF {
+		p.pos++
+	}
+	return t
+}
+
+// isKeyword 当前词法单元是否为关键字（不区分大小写）
+func (p *sqlParser) isKeyword(kw string) bool {
+	t := p.peek()
+	return t.kind == sqlTokIdent && strings.EqualFold(t.text, kw)
+}
+
+// isSymbol 当前词法单元是否为指定符号
+func (p *sqlParser) isSymbol(sym string) bool {
+	t := p.peek()
+	return t.kind == sqlTokSymbol && t.text == sym
+}
+
+// expectKeyword 消费关键字
+func (p *sqlParser) expectKeyword(kw string) error {
+	if !p.isKeyword(kw) {
+		return p.errorf("expected %s", kw)
+	}
+	p.next()
+	return nil
+}
+
+// expectSymbol 消费符号
+func (p *sqlParser) expectSymbol(sym string) error {
+	if !p.isSymbol(sym) {
+		return p.errorf("expected %q", sym)
+	}
+	p.next()
+	return nil
+}
+
+// errorf 返回带位置的解析错误
+func (p *sqlParser) errorf(format string, args ...interface{}) error {
+	t := p.peek()
+	if t.kind == sqlTokEOF {
+		return fmt.Errorf("sql: "+format+" at end of query", args...)
+	}
+	return fmt.Errorf("sql: "+format+" at position %d, got %q", append(args, t.pos, t.text)...)
+}
+
+// ident 消费一个标识符
+func (p *sqlParser) ident(what string) (string, error) {
+	if p.peek().kind != sqlTokIdent {
+		return "", p.errorf("expected %s", what)
+	}
+	return p.next().text, nil
+}
+
+// sqlAggregates 支持的聚合函数
+var sqlAggregates = map[string]string{"COUNT": "count", "SUM": "sum", "AVG": "avg", "MIN": "min", "MAX": "max"}
+
+// parseSelect 解析查询语句
+func parseSelect(query string) (*selectStmt, error) {
+	tokens, err := lexSQL(query)
+	if err != nil {
+		return nil, err
+	}
+	p := &sqlParser{tokens: tokens}
+	stmt := &selectStmt{}
+
+	if err := p.expectKeyword("SELECT"); err != nil {
+		return nil, err
+	}
+	if stmt.columns, err = p.parseColumns(); err != nil {
+		return nil, err
+	}
+	if err := p.expectKeyword("FROM"); err != nil {
+		return nil, err
+	}
+	if stmt.from, err = p.ident("source"); err != nil {
+		return nil, err
+	}
+
+	if p.isKeyword("WHERE") {
+		p.next()
+		for {
+			cond, err := p.parseCondition()
+			if err != nil {
+				return nil, err
+			}
+			stmt.where = append(stmt.where, cond)
+			if p.isKeyword("OR") {
+				return nil, p.errorf("OR is not supported")
+			}
+			if !p.isKeyword("AND") {
+				break
+			}
+			p.next()
+		}
+	}
+
+	if p.isKeyword("GROUP") {
+		p.next()
+		if err := p.expectKeyword("BY"); err != nil {
+			return nil, err
+		}
+		for {
+			field, err := p.ident("group field")
+			if err != nil {
+				return nil, err
+			}
+			stmt.groupBy = append(stmt.groupBy, field)
+			if !p.isSymbol(",") {
+				break
+			}
+			p.next()
+		}
+	}
+
+	if p.isKeyword("ORDER") {
+		p.next()
+		if err := p.expectKeyword("BY"); err != nil {
+			return nil, err
+		}
+		for {
+			field, err := p.parseOrderTarget()
+			if err != nil {
+				return nil, err
+			}
+			order := "asc"
+			if p.isKeyword("DESC") {
+				p.next()
+				order = "desc"
+			} else if p.isKeyword("ASC") {
+				p.next()
+			}
+			nulls, err := p.parseNulls()
+			if err != nil {
+				return nil, err
+			}
+			stmt.orderBy = append(stmt.orderBy, SortField{Field: field, Order: order, Nulls: nulls})
+			if !p.isSymbol(",") {
+				break
+			}
+			p.next()
+		}
+	}
+
+	if p.isKeyword("LIMIT") {
+		p.next()
+		if stmt.limit, err = p.parseCount("LIMIT"); err != nil {
+			return nil, err
+		}
+		if p.isKeyword("OFFSET") {
+			p.next()
+			if stmt.offset, err = p.parseCount("OFFSET"); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if p.peek().kind != sqlTokEOF {
+		return nil, p.errorf("unexpected token")
+	}
+	return stmt, nil
+}
+
+// parseColumns 解析 SELECT 列
+func (p *sqlParser) parseColumns() ([]selectColumn, error) {
+	var columns []selectColumn
+	for {
+		var col selectColumn
+		switch {
+		case p.isSymbol("*"):
+			p.next()
+			col = selectColumn{star: true}
+		case p.peek().kind == sqlTokIdent && p.tokens[p.pos+1].kind == sqlTokSymbol && p.tokens[p.pos+1].text == "(":
+			fn := strings.ToUpper(p.next().text)
+			agg, ok := sqlAggregates[fn]
+			if !ok {
+				return nil, fmt.Errorf("sql: unknown function %s", fn)
+			}
+			p.next() // '('
+			if p.isSymbol("*") {
+				if agg != "count" {
+					return nil, p.errorf("%s(*) is not supported", fn)
+				}
+				p.next()
+				col = selectColumn{name: "COUNT(*)", agg: agg}
+			} else {
+				field, err := p.ident("field")
+				if err != nil {
+					return nil, err
+				}
+				col = selectColumn{name: fn + "(" + field + ")", field: field, agg: agg}
+			}
+			if err := p.expectSymbol(")"); err != nil {
+				return nil, err
+			}
+		default:
+			field, err := p.ident("column")
+			if err != nil {
+				return nil, err
+			}
+			name := field
+			if i := strings.LastIndexByte(field, '.'); i >= 0 {
+				name = field[i+1:]
+			}
+			col = selectColumn{name: name, field: field}
+		}
+
+		if p.isKeyword("AS") {
+			p.next()
+			alias, err := p.ident("alias")
+			if err != nil {
+				return nil, err
+			}
+			if col.star {
+				return nil, fmt.Errorf("sql: * cannot be aliased")
+			}
+			col.name = alias
+		}
+		columns = append(columns, col)
+
+		if !p.isSymbol(",") {
+			return columns, nil
+		}
+		p.next()
+	}
+}
+
+// parseOrderTarget 解析 ORDER BY 的目标：字段路径、列别名或聚合写法（如 SUM(revenue)）
+func (p *sqlParser) parseOrderTarget() (string, error) {
+	name, err := p.ident("order field")
+	if err != nil {
+		return "", err
+	}
+	if !p.isSymbol("(") {
+		return name, nil
+	}
+	p.next()
+	arg := "*"
+	if !p.isSymbol("*") {
+		if arg, err = p.ident("field"); err != nil {
+			return "", err
+		}
+	} else {
+		p.next()
+	}
+	if err := p.expectSymbol(")"); err != nil {
+		return "", err
+	}
+	return strings.ToUpper(name) + "(" + arg + ")", nil
+}
+
+// parseCondition 解析单个 WHERE 条件
+func (p *sqlParser) parseCondition() (Condition, error) {
+	field, err := p.ident("field")
+	if err != nil {
+		return Condition{}, err
+	}
+
+	switch {
+	case p.isKeyword("IS"):
+		p.next()
+		op := "is_null"
+		if p.isKeyword("NOT") {
+			p.next()
+			op = "not_null"
+		}
+		return Condition{Field: field, Operator: op}, p.expectKeyword("NULL")
+	case p.isKeyword("NOT"):
+		p.next()
+		if err := p.expectKeyword("IN"); err != nil {
+			return Condition{}, err
+		}
+		values, err := p.parseValueList()
+		return Condition{Field: field, Operator: "not_in", Value: values}, err
+	case p.isKeyword("IN"):
+		p.next()
+		values, err := p.parseValueList()
+		return Condition{Field: field, Operator: "in", Value: values}, err
+	case p.isKeyword("LIKE"):
+		p.next()
+		t := p.next()
+		if t.kind != sqlTokString || len(t.text) < 2 || !strings.HasPrefix(t.text, "%") || !strings.HasSuffix(t.text, "%") {
+			return Condition{}, fmt.Errorf("sql: only LIKE '%%text%%' patterns are supported")
+		}
+		return Condition{Field: field, Operator: "contains", Value: t.text[1 : len(t.text)-1]}, nil
+	}
+
+	if p.peek().kind != sqlTokSymbol {
+		return Condition{}, p.errorf("expected comparison operator")
+	}
+	op := p.next().text
+	switch op {
+	case "=", "!=", "<", ">", "<=", ">=":
+	case "<>":
+		op = "!="
+	default:
+		return Condition{}, fmt.Errorf("sql: unsupported operator %q", op)
+	}
+	value, err := p.parseValue()
+	return Condition{Field: field, Operator: op, Value: value}, err
+}
+
+// parseNulls 解析可选的 NULLS FIRST|LAST
+func (p *sqlParser) parseNulls() (string, error) {
+	if !p.isKeyword("NULLS") {
+		return "", nil
+	}
+	p.next()
+	switch {
+	case p.isKeyword("FIRST"):
+		p.next()
+		return "first", nil
+	case p.isKeyword("LAST"):
+		p.next()
+		return "last", nil
+	}
+	return "", p.errorf("expected FIRST or LAST after NULLS")
+}
+
+// parseValueList 解析 (v1, v2, ...)
+func (p *sqlParser) parseValueList() ([]interface{}, error) {
+	if err := p.expectSymbol("("); err != nil {
+		return nil, err
+	}
+	var values []interface{}
+	for {
+		v, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+		if !p.isSymbol(",") {
+			break
+		}
+		p.next()
+	}
+	return values, p.expectSymbol(")")
+}
+
+// parseValue 解析字面量
+func (p *sqlParser) parseValue() (interface{}, error) {
+	t := p.peek()
+	switch {
+	case t.kind == sqlTokString:
+		p.next()
+		return t.text, nil
+	case t.kind == sqlTokNumber:
+		p.next()
+		if i, err := strconv.ParseInt(t.text, 10, 64); err == nil {
+			return i, nil
+		}
+		f, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("sql: invalid number %q", t.text)
+		}
+		return f, nil
+	case p.isKeyword("TRUE"):
+		p.next()
+		return true, nil
+	case p.isKeyword("FALSE"):
+		p.next()
+		return false, nil
+	case p.isKeyword("NULL"):
+		p.next()
+		return nil, nil
+	}
+	return nil, p.errorf("expected value")
+}
+
+// parseCount 解析非负整数
+func (p *sqlParser) parseCount(clause string) (int, error) {
+	t := p.next()
+	n, err := strconv.Atoi(t.text)
+	if t.kind != sqlTokNumber || err != nil || n < 0 {
+		return 0, fmt.Errorf("sql: %s requires a non-negative integer, got %q", clause, t.text)
+	}
+	return n, nil
+}
@@ -0,0 +1,112 @@
+package fxjson
+
+import "testing"
+
+const sqlTestDoc = `{"posts": [
+  {"id": 1, "category": "go", "status": "published", "revenue": 100, "title": "Generics"},
+  {"id": 2, "category": "rust", "status": "draft", "revenue": 50, "title": "Lifetimes"},
+  {"id": 3, "category": "go", "status": "published", "revenue": 25.5, "title": "Channels"},
+  {"id": 4, "category": "js", "status": "published", "revenue": 10, "title": "Promises"},
+  {"id": 5, "category": "rust", "status": "published", "revenue": 70}
+]}`
+
+// TestSelect 测试 SQL 查询的执行结果
+func TestSelect(t *testing.T) {
+	doc := FromString(sqlTestDoc)
+	posts := doc.Get("posts")
+
+	tests := []struct {
+		query string
+		node  Node
+		want  string
+	}{
+		{
+			`SELECT category, SUM(revenue) FROM $ WHERE status='published' GROUP BY category`, posts,
+			`[{"category":"go","SUM(revenue)":125.5},{"category":"js","SUM(revenue)":10},{"category":"rust","SUM(revenue)":70}]`,
+		},
+		{
+			`select category, count(*) as n, max(revenue) as top from $.posts group by category order by n desc, category limit 2`, doc,
+			`[{"category":"go","n":2,"top":100},{"category":"rust","n":2,"top":70}]`,
+		},
+		{
+			`SELECT id, title FROM posts WHERE revenue >= 25 AND category <> 'rust' ORDER BY revenue DESC`, doc,
+			`[{"id":1,"title":"Generics"},{"id":3,"title":"Channels"}]`,
+		},
+		{
+			`SELECT id FROM $ WHERE category IN ('js', 'rust') ORDER BY id LIMIT 2 OFFSET 1`, posts,
+			`[{"id":4},{"id":5}]`,
+		},
+		{
+			`SELECT id FROM $ WHERE category NOT IN ('go') AND title LIKE '%i%'`, posts,
+			`[{"id":2},{"id":4}]`,
+		},
+		{
+			`SELECT COUNT(title) AS titled, AVG(revenue) FROM $ WHERE status = 'published'`, posts,
+			`[{"titled":3,"AVG(revenue)":51.375}]`,
+		},
+		{
+			`SELECT COUNT(*) FROM $ WHERE id > 100`, posts,
+			`[{"COUNT(*)":0}]`,
+		},
+		{
+			`SELECT * FROM $ WHERE id = 4`, posts,
+			`[{"id":4,"category":"js","status":"published","revenue":10,"title":"Promises"}]`,
+		},
+		{
+			`SELECT id, title AS name FROM $ WHERE id = 5`, posts,
+			`[{"id":5,"name":null}]`,
+		},
+		{
+			`SELECT id FROM $ WHERE title IS NULL`, posts,
+			`[{"id":5}]`,
+		},
+		{
+			`SELECT id FROM $ WHERE title IS NOT NULL AND title != 'Generics'`, posts,
+			`[{"id":2},{"id":3},{"id":4}]`,
+		},
+		{
+			`SELECT id FROM $ ORDER BY title DESC NULLS FIRST LIMIT 3`, posts,
+			`[{"id":5},{"id":4},{"id":2}]`,
+		},
+	}
+
+	for _, tt := range tests {
+		result, err := Select(tt.query, tt.node)
+		if err != nil {
+			t.Errorf("Select(%q) failed: %v", tt.query, err)
+			continue
+		}
+		if got := string(CompactJSON(result.Raw())); got != tt.want {
+			t.Errorf("%s\n got: %s\nwant: %s", tt.query, got, tt.want)
+		}
+	}
+}
+
+// TestSelectErrors 测试语法与语义错误
+func TestSelectErrors(t *testing.T) {
+	doc := FromString(sqlTestDoc)
+
+	queries := []string{
+		``,
+		`SELECT FROM posts`,
+		`SELECT id posts`,
+		`SELECT id FROM posts WHERE id = 1 OR id = 2`,
+		`SELECT id FROM posts WHERE title LIKE 'Gen%'`,
+		`SELECT id FROM posts WHERE title = 'open`,
+		`SELECT MEDIAN(revenue) FROM posts`,
+		`SELECT SUM(*) FROM posts`,
+		`SELECT title, COUNT(*) FROM posts GROUP BY category`,
+		`SELECT *, id FROM posts`,
+		`SELECT * FROM posts GROUP BY category`,
+		`SELECT id FROM posts LIMIT -1`,
+		`SELECT id FROM posts WHERE title IS 'x'`,
+		`SELECT id FROM posts ORDER BY id NULLS`,
+		`SELECT id FROM posts extra`,
+		`SELECT id FROM missing`,
+	}
+	for _, q := range queries {
+		if _, err := Select(q, doc); err == nil {
+			t.Errorf("Select(%q) should fail", q)
+		}
+	}
+}
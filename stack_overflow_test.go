@@ -1,6 +1,7 @@
 package fxjson
 
 import (
+	"errors"
 	"strings"
 	"testing"
 )
@@ -97,4 +98,94 @@ func BenchmarkDeepJSON(b *testing.B) {
 		node := FromBytes(deepJSON)
 		_ = node.Get("level")
 	}
-}
\ No newline at end of file
+}
+// buildExpansionBomb 构建逐层转义嵌套的JSON字符串
+func buildExpansionBomb(depth int) []byte {
+	current := `{"value":1}`
+	for i := 0; i < depth; i++ {
+		quoted, _ := MarshalToString(current)
+		current = `{"n":` + quoted + `}`
+	}
+	return []byte(current)
+}
+
+// TestNestedExpansionBudget 测试嵌套JSON字符串展开的深度与字节预算
+func TestNestedExpansionBudget(t *testing.T) {
+	bomb := buildExpansionBomb(8)
+
+	t.Run("WithinBudget", func(t *testing.T) {
+		node, err := ParseBytes(bomb, DefaultParseOptions)
+		if err != nil {
+			t.Fatalf("ParseBytes failed: %v", err)
+		}
+		path := strings.Repeat("n.", 8) + "value"
+		if v, _ := node.GetPath(path).Int(); v != 1 {
+			t.Errorf("expected fully expanded value at %s", path)
+		}
+	})
+
+	t.Run("DepthLimit", func(t *testing.T) {
+		opts := DefaultParseOptions
+		opts.MaxExpansionDepth = 3
+
+		_, err := ParseBytes(bomb, opts)
+		var fxErr *FxJSONError
+		if !errors.As(err, &fxErr) || fxErr.Type != ErrorTypeDepthLimit {
+			t.Fatalf("expected depth limit error, got %v", err)
+		}
+		if FromBytesWithOptions(bomb, opts).Exists() {
+			t.Error("FromBytesWithOptions should reject payload exceeding expansion depth")
+		}
+	})
+
+	t.Run("ByteBudget", func(t *testing.T) {
+		// 大量重复的嵌套字符串，单个不大但累计展开量很大
+		item, _ := MarshalToString(string(buildExpansionBomb(4)))
+		var sb strings.Builder
+		sb.WriteByte('[')
+		for i := 0; i < 200; i++ {
+			if i > 0 {
+				sb.WriteByte(',')
+			}
+			sb.WriteString(item)
+		}
+		sb.WriteByte(']')
+
+		opts := DefaultParseOptions
+		opts.MaxExpandedBytes = 4096
+
+		_, err := ParseBytes([]byte(sb.String()), opts)
+		var fxErr *FxJSONError
+		if !errors.As(err, &fxErr) || fxErr.Type != ErrorTypeMemoryLimit {
+			t.Fatalf("expected memory limit error, got %v", err)
+		}
+
+		// 关闭预算后可以正常展开
+		opts.MaxExpandedBytes = 0
+		node, err := ParseBytes([]byte(sb.String()), opts)
+		if err != nil || node.Len() != 200 {
+			t.Fatalf("unlimited expansion failed: len=%d err=%v", node.Len(), err)
+		}
+	})
+
+	t.Run("UnterminatedNotExpanded", func(t *testing.T) {
+		// 字符串内容是未闭合的 JSON 片段，不应被当作嵌套 JSON 展开
+		for _, doc := range []string{`["\"",["\""]]`, `{"a":"\"\\\"","b":"[1"}`, `["{\"a\":1"]`} {
+			node, err := ParseBytes([]byte(doc), DefaultParseOptions)
+			if err != nil || string(node.Raw()) != doc {
+				t.Errorf("ParseBytes(%s) = %s, %v", doc, node.Raw(), err)
+			}
+		}
+	})
+}
+
+// TestMalformedContainerExpansion 格式错误的容器元素不应导致展开无限递归
+func TestMalformedContainerExpansion(t *testing.T) {
+	inputs := []string{`[,1]`, `[1,,2]`, `{"a":,"b":"{\"c\":1}"}`, `[tru]`}
+	for _, input := range inputs {
+		node := FromBytes([]byte(input))
+		if !node.Exists() {
+			t.Errorf("%s: root node should still be parsed", input)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package fxjson
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// RenderTemplate 渲染文本模板，{{path}} 占位符通过 GetPath 从 node 中取值
+//
+// 占位符可以用管道追加修饰符：
+//
+//	{{user.name | default "anonymous"}}  路径不存在或为 null 时使用默认文本
+//	{{price | format "%.2f"}}            按 fmt 格式化（数字、字符串、布尔分别以 float64/int64、string、bool 传入）
+//	{{user | json}}                      输出紧凑 JSON（字符串带引号并转义，适合拼接 JSON 请求体）
+//
+// 字符串输出为解码后的文本，数字保留原始字面量，对象与数组输出紧凑 JSON，路径 "." 表示 node 本身。
+// 路径不存在且没有 default 修饰符时返回错误。
+func RenderTemplate(tmpl string, node Node) (_ string, err error) {
+	defer recoverPanic(&err, "RenderTemplate", node)
+	return renderTemplate(tmpl, node, node.GetPath)
+}
+
+// RenderTemplateCached 与 RenderTemplate 相同，但通过 cache 解析路径，
+// 适用于同一文档被多次渲染或同一模板包含大量占位符的场景
+func RenderTemplateCached(tmpl string, cache *PathCache) (_ string, err error) {
+	defer recoverPanic(&err, "RenderTemplate", cache.Root())
+	return renderTemplate(tmpl, cache.Root(), cache.GetPath)
+}
+
+// renderTemplate 渲染模板，lookup 用于解析占位符路径
+func renderTemplate(tmpl string, node Node, lookup func(string) Node) (string, error) {
+	var sb strings.Builder
+	sb.Grow(len(tmpl))
+
+	rest := tmpl
+	for {
+		open := strings.Index(rest, "{{")
+		if open < 0 {
+			sb.WriteString(rest)
+			return sb.String(), nil
+		}
+		sb.WriteString(rest[:open])
+
+		close := strings.Index(rest[open+2:], "}}")
+		if close < 0 {
+			return "", fmt.Errorf("template: unclosed placeholder at position %d", len(tmpl)-len(rest)+open)
+		}
+		expr := rest[open+2 : open+2+close]
+		text, err := renderPlaceholder(expr, node, lookup)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(text)
+		rest = rest[open+2+close+2:]
+	}
+}
+
+// renderPlaceholder 渲染单个占位符表达式
+func renderPlaceholder(expr string, node Node, lookup func(string) Node) (string, error) {
+	parts, err := splitTemplatePipeline(expr)
+	if err != nil {
+		return "", err
+	}
+
+	path := strings.TrimSpace(parts[0])
+	if path == "" {
+		return "", fmt.Errorf("template: empty placeholder {{%s}}", expr)
+	}
+	value := node
+	if path != "." {
+		value = lookup(path)
+	}
+
+	text, resolved := "", false
+	for _, part := range parts[1:] {
+		name, arg, err := parseTemplateModifier(part)
+		if err != nil {
+			return "", err
+		}
+		switch name {
+		case "default":
+			if arg == nil {
+				return "", fmt.Errorf("template: default requires an argument in {{%s}}", expr)
+			}
+			if !resolved && (!value.Exists() || value.IsNull()) {
+				text, resolved = *arg, true
+			}
+		case "format":
+			if arg == nil {
+				return "", fmt.Errorf("template: format requires an argument in {{%s}}", expr)
+			}
+			if resolved {
+				continue
+			}
+			if !value.Exists() {
+				return "", fmt.Errorf("template: path %q not found", path)
+			}
+			text, resolved = formatTemplateValue(*arg, value), true
+		case "json":
+			if arg != nil {
+				return "", fmt.Errorf("template: json takes no argument in {{%s}}", expr)
+			}
+			if resolved {
+				continue
+			}
+			if !value.Exists() {
+				return "", fmt.Errorf("template: path %q not found", path)
+			}
+			text, resolved = string(CompactJSON(value.Raw())), true
+		default:
+			return "", fmt.Errorf("template: unknown modifier %q in {{%s}}", name, expr)
+		}
+	}
+	if resolved {
+		return text, nil
+	}
+
+	if !value.Exists() {
+		return "", fmt.Errorf("template: path %q not found", path)
+	}
+	return templateText(value), nil
+}
+
+// templateText 返回节点的文本形式
+func templateText(n Node) string {
+	switch n.typ {
+	case 's':
+		s, _ := n.String()
+		return s
+	case 'o', 'a':
+		return string(CompactJSON(n.Raw()))
+	default:
+		return string(n.Raw())
+	}
+}
+
+// formatTemplateValue 按 fmt 格式化节点的值；整数动词（%d %x 等）使用 int64，其余数字使用 float64
+func formatTemplateValue(format string, n Node) string {
+	switch n.typ {
+	case 's':
+		s, _ := n.String()
+		return fmt.Sprintf(format, s)
+	case 'n':
+		if isIntegerVerb(format) {
+			if i, err := n.Int(); err == nil {
+				return fmt.Sprintf(format, i)
+			}
+		}
+		f, _ := n.Float()
+		return fmt.Sprintf(format, f)
+	case 'b':
+		b, _ := n.Bool()
+		return fmt.Sprintf(format, b)
+	case 'l':
+		return fmt.Sprintf(format, nil)
+	default:
+		return fmt.Sprintf(format, string(CompactJSON(n.Raw())))
+	}
+}
+
+// isIntegerVerb 格式串中第一个动词是否为整数动词
+func isIntegerVerb(format string) bool {
+	for i := strings.IndexByte(format, '%'); i >= 0 && i+1 < len(format); {
+		if format[i+1] == '%' {
+			next := strings.IndexByte(format[i+2:], '%')
+			if next < 0 {
+				return false
+			}
+			i += 2 + next
+			continue
+		}
+		verb := strings.IndexFunc(format[i+1:], func(r rune) bool {
+			return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+		})
+		if verb < 0 {
+			return false
+		}
+		return strings.IndexByte("dxXoObc", format[i+1+verb]) >= 0
+	}
+	return false
+}
+
+// splitTemplatePipeline 按 '|' 拆分占位符表达式，忽略双引号字符串内的 '|'
+func splitTemplatePipeline(expr string) ([]string, error) {
+	var parts []string
+	start, inString := 0, false
+	for i := 0; i < len(expr); i++ {
+		switch expr[i] {
+		case '\\':
+			if inString {
+				i++
+			}
+		case '"':
+			inString = !inString
+		case '|':
+			if !inString {
+				parts = append(parts, expr[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if inString {
+		return nil, fmt.Errorf("template: unterminated string in {{%s}}", expr)
+	}
+	return append(parts, expr[start:]), nil
+}
+
+// parseTemplateModifier 解析修饰符名称与可选的双引号字符串参数
+func parseTemplateModifier(part string) (string, *string, error) {
+	part = strings.TrimSpace(part)
+	name, argText, _ := strings.Cut(part, " ")
+	if name == "" {
+		return "", nil, fmt.Errorf("template: empty modifier")
+	}
+	argText = strings.TrimSpace(argText)
+	if argText == "" {
+		return name, nil, nil
+	}
+	arg, err := strconv.Unquote(argText)
+	if err != nil || argText[0] != '"' {
+		return "", nil, fmt.Errorf("template: invalid argument %s for %s", argText, name)
+	}
+	return name, &arg, nil
+}
@@ -0,0 +1,65 @@
+package fxjson
+
+import "testing"
+
+// TestRenderTemplate 测试模板渲染
+func TestRenderTemplate(t *testing.T) {
+	node := FromString(`{
+		"alert": {"name": "disk \"full\"", "severity": 2, "host": null},
+		"usage": 0.91234,
+		"count": 1500,
+		"ok": false,
+		"tags": ["prod", "eu"],
+		"items": [{"id": 7}]
+	}`)
+
+	tests := []struct {
+		tmpl string
+		want string
+	}{
+		{`plain text`, `plain text`},
+		{`[{{alert.severity}}] {{alert.name}}`, `[2] disk "full"`},
+		{`{{ usage | format "%.1f" }} load`, `0.9 load`},
+		{`{{count | format "%05d"}}`, `01500`},
+		{`{{count | format "%x"}}`, `5dc`},
+		{`{{alert.name | format "%-12s|"}}`, `disk "full" |`},
+		{`host={{alert.host | default "unknown"}}`, `host=unknown`},
+		{`{{missing.path | default "n/a"}}`, `n/a`},
+		{`{{alert.severity | default "0"}}`, `2`},
+		{`{{ok}} {{tags}} {{items[0].id}}`, `false ["prod","eu"] 7`},
+		{`{"text": {{alert.name | json}}}`, `{"text": "disk \"full\""}`},
+		{`{{missing | default "a|b"}}`, `a|b`},
+		{`{{alert.host}}`, `null`},
+		{`{{missing | default "-" | format "%d"}}`, `-`},
+	}
+	for _, tt := range tests {
+		got, err := RenderTemplate(tt.tmpl, node)
+		if err != nil {
+			t.Errorf("RenderTemplate(%q) failed: %v", tt.tmpl, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("RenderTemplate(%q) = %q, want %q", tt.tmpl, got, tt.want)
+		}
+	}
+
+	if got, err := RenderTemplate(`{{.}}`, FromString(`"root"`)); err != nil || got != "root" {
+		t.Errorf("RenderTemplate({{.}}) = %q, %v", got, err)
+	}
+
+	errorTemplates := []string{
+		`{{missing}}`,
+		`{{alert.name`,
+		`{{}}`,
+		`{{alert.name | upper}}`,
+		`{{alert.name | default}}`,
+		`{{alert.name | format 5}}`,
+		`{{alert.name | default "open}}`,
+		`{{missing | json}}`,
+	}
+	for _, tmpl := range errorTemplates {
+		if _, err := RenderTemplate(tmpl, node); err == nil {
+			t.Errorf("RenderTemplate(%q) should fail", tmpl)
+		}
+	}
+}
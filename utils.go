@@ -1,6 +1,7 @@
 package fxjson
 
 import (
+	"bytes"
 	"net/url"
 	"regexp"
 	"strconv"
@@ -60,6 +61,15 @@ func (n Node) GetMultiple(paths ...string) []Node {
 	return results
 }
 
+// GetMultipleSep 使用自定义路径分隔符同时获取多个路径的值
+func (n Node) GetMultipleSep(sep byte, paths ...string) []Node {
+	results := make([]Node, len(paths))
+	for i, path := range paths {
+		results[i] = n.GetPathSep(path, sep)
+	}
+	return results
+}
+
 // HasAnyPath 检查是否存在任意一个路径
 func (n Node) HasAnyPath(paths ...string) bool {
 	for _, path := range paths {
@@ -522,6 +532,40 @@ func (n Node) Equals(other Node) bool {
 	return true
 }
 
+// OneOf 检查字符串节点的值是否为 values 之一，不含转义的字符串直接按字节比较，不分配内存
+func (n Node) OneOf(values ...string) bool {
+	if n.typ != 's' || n.end-n.start < 2 {
+		return false
+	}
+	raw := n.getWorkingData()[n.start+1 : n.end-1]
+	var s string
+	if bytes.IndexByte(raw, '\\') < 0 {
+		s = bytesToString(raw)
+	} else {
+		s = string(appendUnescaped(nil, raw))
+	}
+	for _, v := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+// OneOfInt 检查整数节点的值是否为 values 之一，非整数返回 false
+func (n Node) OneOfInt(values ...int64) bool {
+	i, err := n.Int()
+	if err != nil {
+		return false
+	}
+	for _, v := range values {
+		if i == v {
+			return true
+		}
+	}
+	return false
+}
+
 // IsEmpty 检查节点是否为空（空字符串、空数组、空对象、null）
 func (n Node) IsEmpty() bool {
 	switch n.typ {
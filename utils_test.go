@@ -1,6 +1,9 @@
 package fxjson
 
 import (
+	"math"
+	"math/rand"
+	"strings"
 	"testing"
 	"time"
 )
@@ -430,3 +433,450 @@ func BenchmarkDefaultValueFunctions(b *testing.B) {
 		}
 	})
 }
+
+// TestArrayMembership 测试数组成员检查
+func TestArrayMembership(t *testing.T) {
+	node := FromBytes([]byte(`{"tags":["go", "json","a\"b", "caf\u00e9"],"nums":[1, 2.5, 3e2, -4, 18446744073709551615],"mixed":[null,true,{"k":1},[1]]}`))
+	tags := node.Get("tags")
+	nums := node.Get("nums")
+	mixed := node.Get("mixed")
+
+	tests := []struct {
+		name string
+		arr  Node
+		v    any
+		want int
+	}{
+		{"string", tags, "json", 1},
+		{"escaped string", tags, `a"b`, 2},
+		{"unicode escape", tags, "café", 3},
+		{"missing string", tags, "rust", -1},
+		{"int", nums, 1, 0},
+		{"float", nums, 2.5, 1},
+		{"exponent", nums, 300, 2},
+		{"negative int8", nums, int8(-4), 3},
+		{"uint64 max", nums, uint64(18446744073709551615), 4},
+		{"int as float", nums, 1.0, 0},
+		{"type mismatch", nums, "1", -1},
+		{"null", mixed, nil, 0},
+		{"bool", mixed, true, 1},
+		{"node", mixed, FromString(`{"k":1}`), 2},
+		{"unsupported", mixed, []int{1}, -1},
+		{"not array", node, "go", -1},
+	}
+
+	for _, tt := range tests {
+		if got := tt.arr.IndexOf(tt.v); got != tt.want {
+			t.Errorf("%s: IndexOf(%v) = %d, want %d", tt.name, tt.v, got, tt.want)
+		}
+		if got := tt.arr.ContainsValue(tt.v); got != (tt.want >= 0) {
+			t.Errorf("%s: ContainsValue(%v) = %v", tt.name, tt.v, got)
+		}
+	}
+
+	if !nums.ContainsWhere(func(v Node) bool { return v.FloatOr(0) > 100 }) {
+		t.Error("ContainsWhere should find element > 100")
+	}
+	if tags.ContainsWhere(func(v Node) bool { return v.IsNumber() }) {
+		t.Error("ContainsWhere should not find numbers in tags")
+	}
+}
+
+// TestArraySetOperations 测试数组集合运算
+func TestArraySetOperations(t *testing.T) {
+	a := FromString(`["read", "write", "admin", "read", 1, 2.0, "café"]`)
+	b := FromString(`["write","delete",2,"café", 3]`)
+
+	tests := []struct {
+		name string
+		fn   func(a, b Node) ([]byte, error)
+		want string
+	}{
+		{"Intersect", Intersect, `["write",2.0,"café"]`},
+		{"Union", Union, `["read","write","admin",1,2.0,"café","delete",3]`},
+		{"Difference", Difference, `["read","admin",1]`},
+	}
+	for _, tt := range tests {
+		got, err := tt.fn(a, b)
+		if err != nil {
+			t.Fatalf("%s failed: %v", tt.name, err)
+		}
+		if string(got) != tt.want {
+			t.Errorf("%s = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+
+	users := FromString(`[{"id":1,"name":"a"},{"id":2,"name":"b"},{"name":"anon"}]`)
+	others := FromString(`[{"id":2,"name":"B"},{"id":3,"name":"c"}]`)
+
+	keyed := []struct {
+		name string
+		fn   func(a, b Node, key string) ([]byte, error)
+		want string
+	}{
+		{"IntersectBy", IntersectBy, `[{"id":2,"name":"b"}]`},
+		{"UnionBy", UnionBy, `[{"id":1,"name":"a"},{"id":2,"name":"b"},{"name":"anon"},{"id":3,"name":"c"}]`},
+		{"DifferenceBy", DifferenceBy, `[{"id":1,"name":"a"},{"name":"anon"}]`},
+	}
+	for _, tt := range keyed {
+		got, err := tt.fn(users, others, "id")
+		if err != nil {
+			t.Fatalf("%s failed: %v", tt.name, err)
+		}
+		if string(got) != tt.want {
+			t.Errorf("%s = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+
+	if _, err := Intersect(a, FromString(`{"x":1}`)); err == nil {
+		t.Error("expected error for non-array input")
+	}
+}
+
+// TestArrayChunks 测试数组分块遍历
+func TestArrayChunks(t *testing.T) {
+	arr := FromString(`[1, 2, {"a":3}, [4], "five"]`)
+
+	var sizes []int
+	var firsts []string
+	arr.Chunks(2, func(chunk []Node) bool {
+		sizes = append(sizes, len(chunk))
+		firsts = append(firsts, string(chunk[0].Raw()))
+		return true
+	})
+	if len(sizes) != 3 || sizes[0] != 2 || sizes[2] != 1 {
+		t.Errorf("Chunks sizes = %v", sizes)
+	}
+	if firsts[1] != `{"a":3}` || firsts[2] != `"five"` {
+		t.Errorf("Chunks first elements = %v", firsts)
+	}
+
+	var raws []string
+	arr.ChunksRaw(2, func(raw []byte) bool {
+		if !FromBytes(raw).IsArray() {
+			t.Errorf("chunk is not a valid array: %s", raw)
+		}
+		raws = append(raws, string(raw))
+		return true
+	})
+	want := []string{`[1, 2]`, `[{"a":3}, [4]]`, `["five"]`}
+	if len(raws) != len(want) {
+		t.Fatalf("ChunksRaw = %q, want %q", raws, want)
+	}
+	for i := range want {
+		if raws[i] != want[i] {
+			t.Errorf("ChunksRaw[%d] = %s, want %s", i, raws[i], want[i])
+		}
+	}
+
+	calls := 0
+	arr.ChunksRaw(1, func([]byte) bool {
+		calls++
+		return calls < 2
+	})
+	if calls != 2 {
+		t.Errorf("ChunksRaw should stop early, got %d calls", calls)
+	}
+
+	arr.Chunks(0, func([]Node) bool {
+		t.Error("size 0 should not invoke callback")
+		return true
+	})
+
+	// 巨大的 size 不按 size 预分配，整个数组作为最后一组
+	calls = 0
+	arr.Chunks(math.MaxInt, func(chunk []Node) bool {
+		calls++
+		if len(chunk) != arr.Len() || cap(chunk) > maxPreallocNodes {
+			t.Errorf("huge chunk len = %d, cap = %d", len(chunk), cap(chunk))
+		}
+		return true
+	})
+	if calls != 1 {
+		t.Errorf("huge size chunks = %d calls", calls)
+	}
+}
+
+// TestSplitArray 测试按元素数量拆分文档中的数组
+func TestSplitArray(t *testing.T) {
+	doc := []byte(`{"batch":"b1","data":{"items":[1, {"a":2}, "3", [4], 5]},"n":5}`)
+
+	parts, err := SplitArray(doc, "data.items", 2)
+	if err != nil {
+		t.Fatalf("SplitArray error: %v", err)
+	}
+	want := []string{
+		`{"batch":"b1","data":{"items":[1, {"a":2}]},"n":5}`,
+		`{"batch":"b1","data":{"items":["3", [4]]},"n":5}`,
+		`{"batch":"b1","data":{"items":[5]},"n":5}`,
+	}
+	if len(parts) != len(want) {
+		t.Fatalf("SplitArray = %q", parts)
+	}
+	for i := range want {
+		if string(parts[i]) != want[i] {
+			t.Errorf("part %d = %s, want %s", i, parts[i], want[i])
+		}
+	}
+
+	if parts, err := SplitArray([]byte(`[]`), "", 3); err != nil || len(parts) != 1 || string(parts[0]) != `[]` {
+		t.Errorf("empty array: %q, %v", parts, err)
+	}
+	if _, err := SplitArray(doc, "batch", 2); err == nil {
+		t.Error("non-array path should fail")
+	}
+	if _, err := SplitArray(doc, "data.items", 0); err == nil {
+		t.Error("maxItems 0 should fail")
+	}
+}
+
+// TestConcatArrays 测试合并多份文档中的数组
+func TestConcatArrays(t *testing.T) {
+	page1 := []byte(`{"page":1,"data":{"items":[1, {"a":2}]}}`)
+	page2 := []byte(`{"page":2,"data":{"items":[]}}`)
+	page3 := []byte(`{"page":3,"data":{"items":["3",[4]],"more":false}}`)
+
+	got, err := ConcatArrays("data.items", page1, page2, page3)
+	if err != nil {
+		t.Fatalf("ConcatArrays error: %v", err)
+	}
+	if want := `{"page":1,"data":{"items":[1, {"a":2},"3",[4]]}}`; string(got) != want {
+		t.Errorf("ConcatArrays = %s, want %s", got, want)
+	}
+	if got, err := ConcatArrays("", []byte(`[1]`), []byte(`[ 2 , 3 ]`)); err != nil || string(got) != `[1,2 , 3]` {
+		t.Errorf("root arrays = %s, %v", got, err)
+	}
+	if _, err := ConcatArrays("data.items", page1, []byte(`{"data":{}}`)); err == nil || !strings.Contains(err.Error(), "document 1") {
+		t.Errorf("missing path: err = %v", err)
+	}
+	if _, err := ConcatArrays("x"); err == nil {
+		t.Error("no documents should fail")
+	}
+}
+
+// TestDeleteWhere 测试按条件删除数组元素与删除 null 元素
+func TestDeleteWhere(t *testing.T) {
+	doc := []byte("{\"v\":1,\"items\":[\n  {\"id\":1,\"deleted\":true},\n  {\"id\":2,\"deleted\":false},\n  {\"id\":3,\"deleted\":true}\n]}")
+
+	got, n, err := DeleteWhere(doc, "items", Condition{Field: "deleted", Operator: "=", Value: true})
+	if err != nil || n != 2 {
+		t.Fatalf("DeleteWhere: n=%d err=%v", n, err)
+	}
+	if want := "{\"v\":1,\"items\":[\n  {\"id\":2,\"deleted\":false}\n]}"; string(got) != want {
+		t.Errorf("DeleteWhere = %q, want %q", got, want)
+	}
+
+	got, n, _ = DeleteWhere(doc, "items", Condition{Field: "id", Operator: "in", Value: []any{1, 2, 3}})
+	if n != 3 || string(got) != `{"v":1,"items":[]}` {
+		t.Errorf("delete all: n=%d got=%s", n, got)
+	}
+	got, n, _ = DeleteWhere(doc, "items", Condition{Field: "id", Operator: ">", Value: 5})
+	if n != 0 || string(got) != string(doc) {
+		t.Errorf("no match: n=%d got=%s", n, got)
+	}
+	if _, _, err := DeleteWhere(doc, "items", Condition{Field: "id", Operator: "~"}); err == nil {
+		t.Error("unknown operator should fail")
+	}
+	if _, _, err := DeleteWhere(doc, "v", Condition{Field: "id", Operator: "="}); err == nil {
+		t.Error("non-array path should fail")
+	}
+
+	got, n, err = CompactNulls([]byte(`{"a":[null, 1,null, null ,"x", null]}`), "a")
+	if err != nil || n != 4 || string(got) != `{"a":[1 ,"x"]}` {
+		t.Errorf("CompactNulls = %s, %d, %v", got, n, err)
+	}
+	if got, n, _ := CompactNulls([]byte(`[null]`), ""); n != 1 || string(got) != `[]` {
+		t.Errorf("CompactNulls root = %s, %d", got, n)
+	}
+}
+
+// TestArrayPage 测试数组分页
+func TestArrayPage(t *testing.T) {
+	arr := FromString(`[1,2,3,4,5,6,7]`)
+
+	items, meta, err := arr.Page(2, 3)
+	if err != nil {
+		t.Fatalf("Page failed: %v", err)
+	}
+	if string(items) != `[4,5,6]` {
+		t.Errorf("items = %s", items)
+	}
+	if meta != (PageMeta{Page: 2, PerPage: 3, Total: 7, Pages: 3, HasNext: true, HasPrev: true}) {
+		t.Errorf("meta = %+v", meta)
+	}
+
+	items, meta, _ = arr.Page(3, 3)
+	if string(items) != `[7]` || meta.HasNext {
+		t.Errorf("last page: items=%s meta=%+v", items, meta)
+	}
+
+	items, meta, _ = arr.Page(9, 3)
+	if string(items) != `[]` || meta.HasNext || meta.Total != 7 {
+		t.Errorf("out of range: items=%s meta=%+v", items, meta)
+	}
+
+	metaJSON, _ := Marshal(meta)
+	if !FromBytes(metaJSON).Get("has_next").Exists() {
+		t.Errorf("meta JSON should use snake_case keys: %s", metaJSON)
+	}
+
+	if _, _, err := arr.Page(0, 3); err == nil {
+		t.Error("expected error for page 0")
+	}
+	if _, _, err := FromString(`{}`).Page(1, 3); err == nil {
+		t.Error("expected error for non-array")
+	}
+}
+
+// TestArraySample 测试蓄水池抽样与随机下标
+func TestArraySample(t *testing.T) {
+	arr := FromString(`[0,1,2,3,4,5,6,7,8,9]`)
+	rng := rand.New(rand.NewSource(42))
+
+	sample := arr.Sample(3, rng)
+	if len(sample) != 3 {
+		t.Fatalf("Sample len = %d, want 3", len(sample))
+	}
+	seen := map[int64]bool{}
+	for _, v := range sample {
+		i, err := v.Int()
+		if err != nil || i < 0 || i > 9 || seen[i] {
+			t.Errorf("invalid or duplicate sample element %s", v.Raw())
+		}
+		seen[i] = true
+	}
+
+	// 相同种子结果可复现
+	again := arr.Sample(3, rand.New(rand.NewSource(42)))
+	for i := range sample {
+		if !sample[i].Equals(again[i]) {
+			t.Errorf("Sample not deterministic for same seed")
+		}
+	}
+
+	if got := arr.Sample(20, rng); len(got) != 10 {
+		t.Errorf("Sample larger than array should return all elements, got %d", len(got))
+	}
+	// 巨大的 k 不按 k 预分配
+	if got := arr.Sample(math.MaxInt, rng); len(got) != 10 || cap(got) > maxPreallocNodes {
+		t.Errorf("Sample(MaxInt) len = %d, cap = %d", len(got), cap(got))
+	}
+	if arr.Sample(0, rng) != nil || FromString(`{}`).Sample(1, rng) != nil {
+		t.Error("Sample should return nil for k<=0 or non-array")
+	}
+
+	for i := 0; i < 20; i++ {
+		if idx := arr.RandomIndex(); idx < 0 || idx > 9 {
+			t.Fatalf("RandomIndex out of range: %d", idx)
+		}
+	}
+	if FromString(`[]`).RandomIndex() != -1 {
+		t.Error("RandomIndex of empty array should be -1")
+	}
+}
+
+// TestNormalize 测试一次遍历完成的文档清理
+func TestNormalize(t *testing.T) {
+	data := []byte(`{"Name":"  Ada  ","Age":"42","Score":" 1.5e3 ","Zip":"007","Note":null,
+		"Tags":[null," x ",{}],"Meta":{"Empty":{},"Gone":null},"List":[],"Flag":true}`)
+	node := FromBytes(data)
+
+	out, err := node.Normalize(NormalizeOptions{
+		DropNulls:            true,
+		TrimStrings:          true,
+		LowercaseKeys:        true,
+		CoerceNumericStrings: true,
+		RemoveEmpty:          true,
+	})
+	if err != nil {
+		t.Fatalf("Normalize failed: %v", err)
+	}
+	want := `{"name":"Ada","age":42,"score":1.5e3,"zip":"007","tags":["x"],"flag":true}`
+	if string(out) != want {
+		t.Errorf("got %s, want %s", out, want)
+	}
+
+	// 零值选项保持内容不变（仅去除格式空白）
+	out, err = node.Normalize(NormalizeOptions{})
+	if err != nil {
+		t.Fatalf("Normalize failed: %v", err)
+	}
+	if want := `{"Name":"  Ada  ","Age":"42","Score":" 1.5e3 ","Zip":"007","Note":null,"Tags":[null," x ",{}],"Meta":{"Empty":{},"Gone":null},"List":[],"Flag":true}`; string(out) != want {
+		t.Errorf("got %s, want %s", out, want)
+	}
+
+	out, err = FromBytes([]byte(`{"a":{"b":null}}`)).Normalize(NormalizeOptions{DropNulls: true, RemoveEmpty: true})
+	if err != nil || string(out) != `{}` {
+		t.Errorf("root container should be kept, got %s, err %v", out, err)
+	}
+
+	if _, err := FromBytes([]byte(`{"A":1,"a":2}`)).Normalize(NormalizeOptions{LowercaseKeys: true}); err == nil {
+		t.Error("expected error for duplicate lowercased keys")
+	}
+	// 被删除的字段不参与重复键检查
+	out, err = FromBytes([]byte(`{"A":null,"a":1,"B":{},"b":2}`)).Normalize(NormalizeOptions{LowercaseKeys: true, DropNulls: true, RemoveEmpty: true})
+	if err != nil || string(out) != `{"a":1,"b":2}` {
+		t.Errorf("dropped duplicates = %s, %v", out, err)
+	}
+
+	// 未开启 TrimStrings 时带空白的数字字符串保持原样
+	out, err = FromBytes([]byte(`{"a":" 42 ","b":"42"}`)).Normalize(NormalizeOptions{CoerceNumericStrings: true})
+	if err != nil || string(out) != `{"a":" 42 ","b":42}` {
+		t.Errorf("coerce without trim = %s, %v", out, err)
+	}
+}
+
+// TestEachTyped 测试标量类型化遍历
+func TestEachTyped(t *testing.T) {
+	cfg := FromBytes([]byte(`{"host":"db.local","path":"a\/b","env":"prod","ports":{"http":80,"https":443},"ratios":[0.5,1,2.25]}`))
+
+	var keys, values []string
+	if err := FromBytes([]byte(`{"host":"db.local","path":"a\/b"}`)).EachString(func(k, s string) bool {
+		keys = append(keys, k)
+		values = append(values, s)
+		return true
+	}); err != nil {
+		t.Fatalf("EachString failed: %v", err)
+	}
+	if strings.Join(keys, ",") != "host,path" || strings.Join(values, ",") != "db.local,a/b" {
+		t.Errorf("got keys %v values %v", keys, values)
+	}
+
+	sum := int64(0)
+	if err := cfg.Get("ports").EachInt(func(_ string, v int64) bool {
+		sum += v
+		return true
+	}); err != nil || sum != 523 {
+		t.Errorf("EachInt sum=%d err=%v", sum, err)
+	}
+
+	var idx []string
+	total := 0.0
+	if err := cfg.Get("ratios").EachFloat(func(k string, v float64) bool {
+		idx = append(idx, k)
+		total += v
+		return true
+	}); err != nil || total != 3.75 || strings.Join(idx, ",") != "0,1,2" {
+		t.Errorf("EachFloat total=%v idx=%v err=%v", total, idx, err)
+	}
+
+	// 提前结束
+	count := 0
+	_ = cfg.Get("ratios").EachFloat(func(string, float64) bool {
+		count++
+		return false
+	})
+	if count != 1 {
+		t.Errorf("expected early stop, got %d calls", count)
+	}
+
+	if err := cfg.EachString(func(string, string) bool { return true }); err == nil {
+		t.Error("expected error for mixed value types")
+	}
+	if err := cfg.Get("ratios").EachInt(func(string, int64) bool { return true }); err == nil {
+		t.Error("expected error for non-integer value")
+	}
+	if err := cfg.Get("host").EachString(func(string, string) bool { return true }); err == nil {
+		t.Error("expected error for scalar node")
+	}
+}
@@ -0,0 +1,398 @@
+package fxjson
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// structRuleCache 结构体 validate 标签解析结果缓存
+var structRuleCache = sync.Map{} // map[reflect.Type]*structRules
+
+// validateRule validate 标签中的单条规则，如 max=100
+type validateRule struct {
+	name  string
+	param string
+	num   float64 // 数值参数，param 不是数字时为 NaN
+}
+
+// fieldRules 单个字段的校验计划
+type fieldRules struct {
+	name      string // JSON 键名
+	typ       reflect.Type
+	required  bool
+	omitempty bool
+	rules     []validateRule
+	dive      []validateRule // dive 之后的规则，作用于数组的每个元素
+}
+
+// structRules 结构体的校验计划；标签写法错误时 err 非空
+type structRules struct {
+	fields []fieldRules
+	err    error
+}
+
+// ValidateStruct 按 prototype 结构体类型（值或指针）的 go-playground 风格 validate 标签校验对象节点，无需先解码
+//
+// 支持的规则：required、omitempty、min、max、len、gt、gte、lt、lte、eq、ne、oneof、
+// email、url、uuid、alpha、alphanum、numeric，以及作用于数组元素的 dive。
+// 字符串按字符数、数组按元素数、对象按键数、数字按数值比较；required 要求字段存在、非 null 且字符串非空。
+// 同时检查 JSON 类型能否解码到字段类型，并递归校验嵌套结构体与结构体切片。
+// 返回的错误为 *ValidationError，Field 为字段路径（如 "items[0].name"）
+//
+// Deprecated: 新代码请使用 validate.Struct，该函数仅为兼容保留
+func ValidateStruct(n Node, prototype any) []error {
+	t := reflect.TypeOf(prototype)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return []error{fmt.Errorf("prototype must be a struct or pointer to struct, got %T", prototype)}
+	}
+	if n.typ != 'o' {
+		return []error{newStructValidationError("", n, "type", fmt.Sprintf("expected object, got %s", n.Kind()))}
+	}
+	var errs []error
+	validateStructNode(n, t, "", &errs)
+	return errs
+}
+
+// validateStructNode 按结构体类型 t 校验对象节点
+func validateStructNode(n Node, t reflect.Type, prefix string, errs *[]error) {
+	plan := getStructRules(t)
+	if plan.err != nil {
+		*errs = append(*errs, plan.err)
+		return
+	}
+	for i := range plan.fields {
+		f := &plan.fields[i]
+		path := f.name
+		if prefix != "" {
+			path = prefix + "." + f.name
+		}
+		validateField(n.Get(f.name), f, path, errs)
+	}
+}
+
+// validateField 校验单个字段
+func validateField(v Node, f *fieldRules, path string, errs *[]error) {
+	if !v.Exists() || v.typ == 'l' || (v.typ == 's' && v.end-v.start == 2) {
+		if f.required {
+			*errs = append(*errs, newStructValidationError(path, v, "required", "field is required"))
+			return
+		}
+		if f.omitempty || !v.Exists() || v.typ == 'l' {
+			return
+		}
+	}
+
+	if msg := checkDecodableType(v, f.typ); msg != "" {
+		*errs = append(*errs, newStructValidationError(path, v, "type", msg))
+		return
+	}
+	for _, r := range f.rules {
+		if msg := r.check(v); msg != "" {
+			*errs = append(*errs, newStructValidationError(path, v, r.name, msg))
+		}
+	}
+
+	elemType := derefType(f.typ)
+	switch {
+	case v.typ == 'o' && elemType.Kind() == reflect.Struct && !isTextType(elemType):
+		validateStructNode(v, elemType, path, errs)
+	case v.typ == 'a' && (elemType.Kind() == reflect.Slice || elemType.Kind() == reflect.Array):
+		item := derefType(elemType.Elem())
+		v.ArrayForEach(func(i int, elem Node) bool {
+			elemPath := path + "[" + strconv.Itoa(i) + "]"
+			for _, r := range f.dive {
+				if msg := r.check(elem); msg != "" {
+					*errs = append(*errs, newStructValidationError(elemPath, elem, r.name, msg))
+				}
+			}
+			if elem.typ == 'o' && item.Kind() == reflect.Struct && !isTextType(item) {
+				validateStructNode(elem, item, elemPath, errs)
+			}
+			return true
+		})
+	}
+}
+
+// getStructRules 解析并缓存结构体的 validate 标签
+func getStructRules(t reflect.Type) *structRules {
+	if cached, ok := structRuleCache.Load(t); ok {
+		return cached.(*structRules)
+	}
+
+	plan := &structRules{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() || isRemainField(field) {
+			continue
+		}
+		name := getJSONFieldNameFast(field)
+		if name == "-" {
+			continue
+		}
+
+		f := fieldRules{name: name, typ: field.Type}
+		target := &f.rules
+		tag := field.Tag.Get("validate")
+		for _, part := range strings.Split(tag, ",") {
+			part = strings.TrimSpace(part)
+			ruleName, param, _ := strings.Cut(part, "=")
+			switch ruleName {
+			case "", "-":
+			case "required":
+				f.required = true
+			case "omitempty":
+				f.omitempty = true
+			case "dive":
+				target = &f.dive
+			default:
+				r, err := newValidateRule(ruleName, param)
+				if err != nil {
+					if plan.err == nil {
+						plan.err = fmt.Errorf("field %s.%s: %w", t.Name(), field.Name, err)
+					}
+					continue
+				}
+				*target = append(*target, r)
+			}
+		}
+		plan.fields = append(plan.fields, f)
+	}
+
+	structRuleCache.Store(t, plan)
+	return plan
+}
+
+// newValidateRule 创建并检查规则
+func newValidateRule(name, param string) (validateRule, error) {
+	r := validateRule{name: name, param: param, num: parseNumOrNaN(param)}
+	switch name {
+	case "min", "max", "len", "gt", "gte", "lt", "lte":
+		if math.IsNaN(r.num) {
+			return r, fmt.Errorf("validation rule %q requires a numeric parameter, got %q", name, param)
+		}
+	case "eq", "ne", "oneof":
+		if param == "" {
+			return r, fmt.Errorf("validation rule %q requires a parameter", name)
+		}
+	case "email", "url", "uuid", "alpha", "alphanum", "numeric":
+	default:
+		return r, fmt.Errorf("unknown validation rule %q", name)
+	}
+	return r, nil
+}
+
+// check 校验节点，通过时返回空字符串
+func (r validateRule) check(v Node) string {
+	switch r.name {
+	case "min", "max", "len", "gt", "gte", "lt", "lte":
+		size, unit, ok := validateSize(v)
+		if !ok {
+			return fmt.Sprintf("%s cannot be applied to %s", r.name, v.Kind())
+		}
+		var pass bool
+		switch r.name {
+		case "min", "gte":
+			pass = size >= r.num
+		case "max", "lte":
+			pass = size <= r.num
+		case "len":
+			pass = size == r.num
+		case "gt":
+			pass = size > r.num
+		case "lt":
+			pass = size < r.num
+		}
+		if !pass {
+			return fmt.Sprintf("%s %s %s, got %s", unit, ruleDescription[r.name], r.param, strconv.FormatFloat(size, 'f', -1, 64))
+		}
+	case "eq", "ne", "oneof":
+		s := validateScalarText(v)
+		var pass bool
+		switch r.name {
+		case "eq":
+			pass = validateScalarEqual(v, s, r)
+		case "ne":
+			pass = !validateScalarEqual(v, s, r)
+		default:
+			for _, option := range strings.Fields(r.param) {
+				if pass = validateScalarEqual(v, s, validateRule{param: option, num: parseNumOrNaN(option)}); pass {
+					break
+				}
+			}
+		}
+		if !pass {
+			return fmt.Sprintf("value %s does not satisfy %s=%s", v.Raw(), r.name, r.param)
+		}
+	default:
+		if v.typ != 's' {
+			return fmt.Sprintf("%s requires a string, got %s", r.name, v.Kind())
+		}
+		s := validateScalarText(v)
+		if !validateStringFormat(r.name, s) {
+			return fmt.Sprintf("value %q is not a valid %s", s, r.name)
+		}
+	}
+	return ""
+}
+
+// ruleDescription 长度与数值规则的描述
+var ruleDescription = map[string]string{
+	"min": "must be at least", "max": "must be at most", "len": "must equal",
+	"gt": "must be greater than", "gte": "must be at least", "lt": "must be less than", "lte": "must be at most",
+}
+
+// validateSize 返回用于长度与数值规则比较的量：字符串字符数、数组元素数、对象键数或数值
+func validateSize(v Node) (float64, string, bool) {
+	switch v.typ {
+	case 's':
+		return float64(utf8.RuneCountInString(validateScalarText(v))), "length", true
+	case 'a':
+		return float64(v.Len()), "length", true
+	case 'o':
+		count := 0
+		v.ForEach(func(string, Node) bool {
+			count++
+			return true
+		})
+		return float64(count), "key count", true
+	case 'n':
+		f, err := v.Float()
+		return f, "value", err == nil
+	}
+	return 0, "", false
+}
+
+// validateScalarText 返回字符串的解转义内容，其他类型返回原始字面量
+func validateScalarText(v Node) string {
+	raw := v.Raw()
+	if v.typ == 's' {
+		return string(appendUnescaped(nil, raw[1:len(raw)-1]))
+	}
+	return string(raw)
+}
+
+// validateScalarEqual 数字按数值比较，其余按文本比较
+func validateScalarEqual(v Node, text string, r validateRule) bool {
+	if v.typ == 'n' && !math.IsNaN(r.num) {
+		f, err := v.Float()
+		return err == nil && f == r.num
+	}
+	return text == r.param
+}
+
+// parseNumOrNaN 将参数解析为数字，失败时返回 NaN
+func parseNumOrNaN(s string) float64 {
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f
+	}
+	return math.NaN()
+}
+
+// validateStringFormat 校验字符串格式规则
+func validateStringFormat(rule, s string) bool {
+	switch rule {
+	case "email":
+		return emailRegex.MatchString(s)
+	case "url":
+		u, err := url.ParseRequestURI(s)
+		return err == nil && u.Scheme != "" && u.Host != ""
+	case "uuid":
+		return uuidRegex.MatchString(s)
+	case "alpha", "alphanum":
+		for i := 0; i < len(s); i++ {
+			c := s[i]
+			letter := (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+			if !letter && (rule == "alpha" || c < '0' || c > '9') {
+				return false
+			}
+		}
+		return s != ""
+	case "numeric":
+		_, err := strconv.ParseFloat(s, 64)
+		return err == nil
+	}
+	return false
+}
+
+// checkDecodableType 检查 JSON 值能否解码到类型 t，可以时返回空字符串
+func checkDecodableType(v Node, t reflect.Type) string {
+	t = derefType(t)
+	if v.typ == 'l' || isTextType(t) {
+		return ""
+	}
+	if _, ok := lookupDecoder(t); ok {
+		return ""
+	}
+
+	want := byte(0)
+	switch t.Kind() {
+	case reflect.String:
+		want = 's'
+	case reflect.Bool:
+		want = 'b'
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if v.typ == 'n' {
+			f, err := v.Float()
+			if err != nil || f != math.Trunc(f) {
+				return fmt.Sprintf("expected integer for %s, got %s", t, v.Raw())
+			}
+			if t.Kind() >= reflect.Uint && f < 0 {
+				return fmt.Sprintf("expected non-negative integer for %s, got %s", t, v.Raw())
+			}
+			return ""
+		}
+		want = 'n'
+	case reflect.Float32, reflect.Float64:
+		want = 'n'
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 && v.typ == 's' {
+			return ""
+		}
+		want = 'a'
+	case reflect.Map, reflect.Struct:
+		want = 'o'
+	}
+	if want != 0 && v.typ != want {
+		return fmt.Sprintf("expected %s for %s, got %s", NodeType(want), t, v.Kind())
+	}
+	return ""
+}
+
+// derefType 去掉指针层
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// isTextType 类型是否以 JSON 字符串表示（time.Time 与 encoding.TextUnmarshaler）
+func isTextType(t reflect.Type) bool {
+	return t == reflect.TypeOf(time.Time{}) || reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+// newStructValidationError 创建结构体校验错误
+func newStructValidationError(path string, v Node, rule, msg string) *ValidationError {
+	value := ""
+	if v.Exists() {
+		value = truncateUTF8(string(v.Raw()), 64)
+	}
+	return &ValidationError{
+		Field:     path,
+		Value:     value,
+		Rule:      rule,
+		Message:   msg,
+		Timestamp: time.Now(),
+	}
+}
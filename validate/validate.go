@@ -0,0 +1,39 @@
+// Package validate groups fxjson's rule-based and struct-tag validation APIs.
+//
+// The types are aliases of the ones in the root package, so validators built
+// here work with Node.Validate and vice versa. The root package keeps the
+// implementation, since Node.Validate needs it; the root LoadValidator and
+// ValidateStruct are marked deprecated in favour of Load and Struct. This
+// package is the entry point for new code.
+// Its exported surface is pinned by internal/apicheck.
+package validate
+
+import (
+	"github.com/icloudza/fxjson"
+)
+
+type (
+	Rule      = fxjson.ValidationRule  // 单个字段的验证规则
+	Validator = fxjson.DataValidator   // 按字段组织的验证规则集合
+	Error     = fxjson.ValidationError // Struct 返回的字段错误
+)
+
+// New 由规则创建验证器
+func New(rules map[string]Rule) *Validator {
+	return &Validator{Rules: rules}
+}
+
+// Load 从 JSON 配置加载验证器，同 fxjson.LoadValidator
+func Load(spec []byte) (*Validator, error) {
+	return fxjson.LoadValidator(spec)
+}
+
+// Check 按验证器校验对象节点，返回转换后的字段值与全部错误，同 n.Validate(v)
+func Check(n fxjson.Node, v *Validator) (map[string]interface{}, []error) {
+	return n.Validate(v)
+}
+
+// Struct 按 prototype 结构体的 validate 标签校验对象节点，同 fxjson.ValidateStruct
+func Struct(n fxjson.Node, prototype any) []error {
+	return fxjson.ValidateStruct(n, prototype)
+}
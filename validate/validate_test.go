@@ -0,0 +1,34 @@
+package validate
+
+import (
+	"testing"
+
+	"github.com/icloudza/fxjson"
+)
+
+// TestFacade 测试子包入口与根包 API 互通
+func TestFacade(t *testing.T) {
+	v := New(map[string]Rule{"name": {Required: true, Type: "string", MinLength: 2}})
+	if _, errs := Check(fxjson.FromString(`{"name":"x"}`), v); len(errs) != 1 {
+		t.Errorf("Check errors = %v", errs)
+	}
+
+	loaded, err := Load([]byte(`{"rules":{"age":{"type":"number","min":0}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, errs := fxjson.FromString(`{"age":-1}`).Validate(loaded); len(errs) != 1 {
+		t.Errorf("Validate errors = %v", errs)
+	}
+
+	type User struct {
+		Email string `json:"email" validate:"required,email"`
+	}
+	errs := Struct(fxjson.FromString(`{"email":"nope"}`), User{})
+	if len(errs) != 1 {
+		t.Fatalf("Struct errors = %v", errs)
+	}
+	if _, ok := errs[0].(*Error); !ok {
+		t.Errorf("Struct error type = %T", errs[0])
+	}
+}
@@ -0,0 +1,71 @@
+package fxjson
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type testValidateItem struct {
+	SKU string `json:"sku" validate:"required,len=6,alphanum"`
+	Qty int    `json:"qty" validate:"gte=1,lte=99"`
+}
+
+type testValidateOrder struct {
+	ID     string             `json:"id" validate:"required,uuid"`
+	Email  string             `json:"email" validate:"required,email"`
+	Note   string             `json:"note" validate:"omitempty,max=10"`
+	Status string             `json:"status" validate:"oneof=new paid shipped"`
+	Site   string             `json:"site" validate:"omitempty,url"`
+	Tags   []string           `json:"tags" validate:"max=3,dive,min=2"`
+	Items  []testValidateItem `json:"items" validate:"required,min=1"`
+	Total  float64            `json:"total" validate:"gt=0"`
+}
+
+// TestValidateStruct 测试按 validate 标签校验节点
+func TestValidateStruct(t *testing.T) {
+	valid := FromString(`{"id":"123e4567-e89b-12d3-a456-426614174000","email":"a@b.co","status":"paid",
+		"site":"https://x.io/a","tags":["ab","cd"],"items":[{"sku":"AB12CD","qty":2}],"total":9.5}`)
+	if errs := ValidateStruct(valid, &testValidateOrder{}); len(errs) != 0 {
+		t.Fatalf("valid order: %v", errs)
+	}
+
+	invalid := FromString(`{"id":"nope","email":"","note":"abcdefghijkl","status":"lost","site":"x",
+		"tags":["a","bc","d","e"],"items":[{"sku":"AB12","qty":0},{"sku":"ABCDEF","qty":1.5}],"total":0}`)
+	got := map[string]string{}
+	for _, err := range ValidateStruct(invalid, testValidateOrder{}) {
+		var ve *ValidationError
+		if !errors.As(err, &ve) {
+			t.Fatalf("unexpected error type %T: %v", err, err)
+		}
+		got[ve.Field] += ve.Rule + " "
+	}
+	want := map[string]string{
+		"id": "uuid ", "email": "required ", "note": "max ", "status": "oneof ", "site": "url ",
+		"tags": "max ", "tags[0]": "min ", "tags[2]": "min ", "tags[3]": "min ",
+		"items[0].sku": "len ", "items[0].qty": "gte ", "items[1].qty": "type ", "total": "gt ",
+	}
+	if len(got) != len(want) {
+		t.Errorf("errors = %v", got)
+	}
+	for field, rule := range want {
+		if got[field] != rule {
+			t.Errorf("%s: rules %q, want %q", field, got[field], rule)
+		}
+	}
+
+	errs := ValidateStruct(FromString(`{"total":"9"}`), testValidateOrder{})
+	if len(errs) != 4 || !strings.Contains(errs[len(errs)-1].Error(), "expected number") {
+		t.Errorf("missing fields: %v", errs)
+	}
+
+	type badTag struct {
+		A string `validate:"bogus"`
+	}
+	if errs := ValidateStruct(FromString(`{}`), badTag{}); len(errs) != 1 || !strings.Contains(errs[0].Error(), `unknown validation rule "bogus"`) {
+		t.Errorf("bad tag: %v", errs)
+	}
+	if errs := ValidateStruct(FromString(`{}`), 1); len(errs) != 1 {
+		t.Errorf("non-struct prototype: %v", errs)
+	}
+}
@@ -0,0 +1,147 @@
+package fxjson
+
+import "strconv"
+
+// Visitor 文档遍历回调，由 Node.Visit 按深度优先顺序调用
+// EnterObject/EnterArray 返回 false 时跳过该容器的子节点，且不会调用对应的 Leave 回调
+type Visitor interface {
+	EnterObject(path string, node Node) bool
+	LeaveObject(path string, node Node)
+	EnterArray(path string, node Node) bool
+	LeaveArray(path string, node Node)
+	Value(path string, node Node) // 字符串、数字、布尔与 null
+}
+
+// BaseVisitor 所有回调均为空操作的 Visitor，可嵌入后只实现需要的方法
+type BaseVisitor struct{}
+
+// EnterObject 进入对象，默认遍历子节点
+func (BaseVisitor) EnterObject(string, Node) bool { return true }
+
+// LeaveObject 离开对象
+func (BaseVisitor) LeaveObject(string, Node) {}
+
+// EnterArray 进入数组，默认遍历子节点
+func (BaseVisitor) EnterArray(string, Node) bool { return true }
+
+// LeaveArray 离开数组
+func (BaseVisitor) LeaveArray(string, Node) {}
+
+// Value 访问标量值
+func (BaseVisitor) Value(string, Node) {}
+
+// visitItem 遍历栈中的一项
+type visitItem struct {
+	node  Node
+	path  string
+	leave bool // 子节点已处理完，待调用 Leave 回调
+}
+
+// Visit 深度优先遍历节点，按进入/离开顺序调用 v 的回调，路径格式同 Walk（如 "a.b[0]"）
+func (n Node) Visit(v Visitor) {
+	if v == nil || !n.Exists() {
+		return
+	}
+
+	stack := make([]visitItem, 0, 64)
+	stack = append(stack, visitItem{node: n})
+	var children []visitItem
+
+	for len(stack) > 0 {
+		item := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		switch item.node.typ {
+		case 'o':
+			if item.leave {
+				v.LeaveObject(item.path, item.node)
+				continue
+			}
+			if !v.EnterObject(item.path, item.node) {
+				continue
+			}
+			stack = append(stack, visitItem{node: item.node, path: item.path, leave: true})
+
+			children = children[:0]
+			data := item.node.getWorkingData()
+			item.node.forEachFieldSpan(func(key string, span fieldSpan) bool {
+				children = append(children, visitItem{
+					node: item.node.nodeAt(data, span.valueStart, span.valueEnd),
+					path: joinVisitPath(item.path, key),
+				})
+				return true
+			})
+			for i := len(children) - 1; i >= 0; i-- {
+				stack = append(stack, children[i])
+			}
+		case 'a':
+			if item.leave {
+				v.LeaveArray(item.path, item.node)
+				continue
+			}
+			if !v.EnterArray(item.path, item.node) {
+				continue
+			}
+			stack = append(stack, visitItem{node: item.node, path: item.path, leave: true})
+
+			children = children[:0]
+			item.node.arrayScan(func(idx int, data []byte, start, end int) bool {
+				children = append(children, visitItem{
+					node: item.node.nodeAt(data, start, end),
+					path: item.path + "[" + strconv.Itoa(idx) + "]",
+				})
+				return true
+			})
+			for i := len(children) - 1; i >= 0; i-- {
+				stack = append(stack, children[i])
+			}
+		default:
+			v.Value(item.path, item.node)
+		}
+	}
+}
+
+// joinVisitPath 拼接对象字段路径
+func joinVisitPath(parent, key string) string {
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
+
+// nodeKey 节点在文档数据中的字节范围
+type nodeKey struct {
+	start, end int
+}
+
+// NodeTable 以节点在文档中的字节范围为键的用户数据表，用于在遍历中为节点附加数据（如先收集后改写的两阶段处理）
+// 键只包含偏移量，同一个表只应存放来自同一文档的节点
+type NodeTable[T any] struct {
+	m map[nodeKey]T
+}
+
+// NewNodeTable 创建节点数据表
+func NewNodeTable[T any]() *NodeTable[T] {
+	return &NodeTable[T]{m: make(map[nodeKey]T)}
+}
+
+// Set 为节点设置数据
+func (t *NodeTable[T]) Set(n Node, value T) {
+	t.m[nodeKey{n.start, n.end}] = value
+}
+
+// Get 获取节点的数据
+func (t *NodeTable[T]) Get(n Node) (T, bool) {
+	v, ok := t.m[nodeKey{n.start, n.end}]
+	return v, ok
+}
+
+// Delete 删除节点的数据
+func (t *NodeTable[T]) Delete(n Node) {
+	delete(t.m, nodeKey{n.start, n.end})
+}
+
+// Len 返回已附加数据的节点数量
+func (t *NodeTable[T]) Len() int {
+	return len(t.m)
+}
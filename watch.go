@@ -0,0 +1,97 @@
+package fxjson
+
+import (
+	"bytes"
+	"strings"
+)
+
+// docWatcher Watch 注册的单个监听
+type docWatcher struct {
+	path   string
+	fn     func(old, new Node)
+	last   []byte // 上次 Commit 时的值
+	exists bool   // 上次 Commit 时路径是否存在
+}
+
+// Watch 监听路径（同 Get）处的值，Commit 时若该值自上次 Commit 以来发生变化则调用 fn(old, new)
+//
+// 只有与修改记录中的路径相关（相同、祖先或后代；修改数组元素视为修改整个数组）的监听才会重新取值，
+// 并与上次的值按字节比较，因此不需要对整个文档做比较。路径不存在时对应参数的 Exists() 为 false；
+// new 在下一次修改前有效。返回的函数用于取消监听
+func (d *Document) Watch(path string, fn func(old, new Node)) (cancel func()) {
+	w := &docWatcher{path: path, fn: fn}
+	if v := d.Get(path); v.Exists() {
+		w.last, w.exists = append([]byte(nil), v.Raw()...), true
+	}
+	d.watchers = append(d.watchers, w)
+
+	return func() {
+		for i, other := range d.watchers {
+			if other == w {
+				d.watchers = append(d.watchers[:i:i], d.watchers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Commit 结束一批修改，按注册顺序触发值发生变化的监听；回调中进行的修改在下一次 Commit 时处理
+func (d *Document) Commit() {
+	edited := d.edited
+	d.edited = nil
+	if len(edited) == 0 {
+		return
+	}
+
+	for _, w := range append([]*docWatcher(nil), d.watchers...) {
+		if !watchAffected(w.path, edited) {
+			continue
+		}
+		cur := d.Get(w.path)
+		exists := cur.Exists()
+		var raw []byte
+		if exists {
+			raw = cur.Raw()
+		}
+		if exists == w.exists && bytes.Equal(raw, w.last) {
+			continue
+		}
+
+		var old Node
+		if w.exists {
+			old = parseRootNode(w.last)
+		}
+		w.last, w.exists = append([]byte(nil), raw...), exists
+		w.fn(old, cur)
+	}
+}
+
+// logEdit 记录修改过的路径；路径中含数组下标时记录第一个数组，
+// 因为删除与填充元素会使同一数组中其他下标的值发生变化
+func (d *Document) logEdit(path string) {
+	if i := strings.IndexByte(path, '['); i >= 0 {
+		path = path[:i]
+	}
+	if !containsString(d.edited, path) {
+		d.edited = append(d.edited, path)
+	}
+}
+
+// watchAffected 监听路径是否与任一修改路径相同、为其祖先或后代
+func watchAffected(path string, edited []string) bool {
+	for _, e := range edited {
+		if e == path || isPathWithin(path, e) || isPathWithin(e, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// isPathWithin path 是否位于 prefix 之下（prefix 为空表示根节点）
+func isPathWithin(path, prefix string) bool {
+	if prefix == "" {
+		return true
+	}
+	return len(path) > len(prefix) && strings.HasPrefix(path, prefix) &&
+		(path[len(prefix)] == '.' || path[len(prefix)] == '[')
+}
@@ -0,0 +1,69 @@
+package fxjson
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestDocumentWatch 测试 Commit 时按修改记录触发监听
+func TestDocumentWatch(t *testing.T) {
+	doc, err := NewDocument([]byte(`{"db":{"host":"a","port":5432},"list":[1,2,3],"name":"x"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var events []string
+	watch := func(path string) func() {
+		return doc.Watch(path, func(old, new Node) {
+			o, n := "<none>", "<none>"
+			if old.Exists() {
+				o = string(old.Raw())
+			}
+			if new.Exists() {
+				n = string(new.Raw())
+			}
+			events = append(events, path+": "+o+" -> "+n)
+		})
+	}
+	watch("db.host")
+	watch("db")
+	watch("list[1]")
+	watch("name")
+	cancelMissing := watch("extra.flag")
+
+	commit := func() string {
+		events = nil
+		doc.Commit()
+		return strings.Join(events, "; ")
+	}
+
+	// 没有修改时不触发
+	if got := commit(); got != "" {
+		t.Errorf("empty commit fired: %s", got)
+	}
+
+	doc.SetString("db.host", "b")
+	doc.Set("db.port", []byte(`5432`)) // 值未变化
+	if got, want := commit(), `db.host: "a" -> "b"; db: {"host":"a","port":5432} -> {"host":"b","port":5432}`; got != want {
+		t.Errorf("commit =\n%s\nwant\n%s", got, want)
+	}
+
+	// 删除数组元素使后续下标的值变化
+	doc.Delete("list[0]")
+	if got, want := commit(), `list[1]: 2 -> 3`; got != want {
+		t.Errorf("commit = %s, want %s", got, want)
+	}
+
+	// 替换父节点、创建与删除路径
+	doc.Set("", []byte(`{"db":{"host":"b","port":5432},"list":[3],"extra":{"flag":true}}`))
+	if got, want := commit(), `list[1]: 3 -> <none>; name: "x" -> <none>; extra.flag: <none> -> true`; got != want {
+		t.Errorf("commit =\n%s\nwant\n%s", got, want)
+	}
+
+	// 取消监听后不再触发
+	cancelMissing()
+	doc.Delete("extra")
+	if got := commit(); got != "" {
+		t.Errorf("cancelled watcher fired: %s", got)
+	}
+}
@@ -0,0 +1,139 @@
+package fxjson
+
+import (
+	"fmt"
+	"strings"
+)
+
+// workspaceSep 引用中文档名与路径的分隔符
+const workspaceSep = "::"
+
+// Workspace 一组具名的只读文档，按 "文档名::路径" 的引用跨文档取值，
+// 例如 "accounts::data.users[0]"；路径为空（"accounts" 或 "accounts::"）表示根节点
+//
+// 每个文档带有 PathCache，重复解析同一路径只做一次 map 读取。
+// Workspace 不是并发安全的。
+type Workspace struct {
+	docs  map[string]*PathCache
+	names []string // 按添加顺序
+}
+
+// WorkspaceDiff 一对引用的比较结果
+type WorkspaceDiff struct {
+	From    string       `json:"from"`
+	To      string       `json:"to"`
+	Results []DiffResult `json:"results"`
+}
+
+// NewWorkspace 创建空的工作区
+func NewWorkspace() *Workspace {
+	return &Workspace{docs: make(map[string]*PathCache)}
+}
+
+// Add 解析 data 并以 name 加入工作区，同名文档会被替换
+func (w *Workspace) Add(name string, data []byte) error {
+	node, err := ParseBytes(data, DefaultParseOptions)
+	if err != nil {
+		return fmt.Errorf("document %q: %w", name, err)
+	}
+	return w.AddNode(name, node)
+}
+
+// AddNode 以 name 加入已解析的节点，同名文档会被替换
+func (w *Workspace) AddNode(name string, root Node) error {
+	if name == "" || strings.Contains(name, workspaceSep) {
+		return fmt.Errorf("invalid document name %q", name)
+	}
+	if !root.Exists() {
+		return fmt.Errorf("document %q does not exist", name)
+	}
+	if _, ok := w.docs[name]; !ok {
+		w.names = append(w.names, name)
+	}
+	w.docs[name] = NewPathCache(root)
+	return nil
+}
+
+// Remove 从工作区移除文档
+func (w *Workspace) Remove(name string) {
+	if _, ok := w.docs[name]; !ok {
+		return
+	}
+	delete(w.docs, name)
+	for i, n := range w.names {
+		if n == name {
+			w.names = append(w.names[:i], w.names[i+1:]...)
+			break
+		}
+	}
+}
+
+// Names 按添加顺序返回文档名
+func (w *Workspace) Names() []string {
+	return append([]string(nil), w.names...)
+}
+
+// Doc 返回文档的根节点
+func (w *Workspace) Doc(name string) (Node, bool) {
+	cache, ok := w.docs[name]
+	if !ok {
+		return Node{}, false
+	}
+	return cache.Root(), true
+}
+
+// Get 按引用取值，文档或路径不存在时返回不存在的节点
+func (w *Workspace) Get(ref string) Node {
+	node, _ := w.Resolve(ref)
+	return node
+}
+
+// Resolve 按引用取值，文档不存在时返回错误；路径不存在时返回不存在的节点与 nil
+func (w *Workspace) Resolve(ref string) (Node, error) {
+	name, path, _ := strings.Cut(ref, workspaceSep)
+	cache, ok := w.docs[name]
+	if !ok {
+		return Node{}, fmt.Errorf("unknown document %q in reference %q", name, ref)
+	}
+	if path == "" {
+		return cache.Root(), nil
+	}
+	return cache.GetPath(path), nil
+}
+
+// ValidateAll 用同一个验证器验证全部文档，返回有错误的文档名到错误列表的映射
+func (w *Workspace) ValidateAll(validator *DataValidator) map[string][]error {
+	failures := make(map[string][]error)
+	for _, name := range w.names {
+		if _, errs := w.docs[name].Root().Validate(validator); len(errs) > 0 {
+			failures[name] = errs
+		}
+	}
+	return failures
+}
+
+// Diff 比较两个引用处的值，引用可以指向不同文档的子树
+func (w *Workspace) Diff(from, to string) ([]DiffResult, error) {
+	a, err := w.Resolve(from)
+	if err != nil {
+		return nil, err
+	}
+	b, err := w.Resolve(to)
+	if err != nil {
+		return nil, err
+	}
+	return a.Diff(b), nil
+}
+
+// DiffPairs 依次比较多对引用，每对为 {from, to}，任一引用的文档不存在时返回错误
+func (w *Workspace) DiffPairs(pairs ...[2]string) ([]WorkspaceDiff, error) {
+	diffs := make([]WorkspaceDiff, 0, len(pairs))
+	for _, pair := range pairs {
+		results, err := w.Diff(pair[0], pair[1])
+		if err != nil {
+			return nil, err
+		}
+		diffs = append(diffs, WorkspaceDiff{From: pair[0], To: pair[1], Results: results})
+	}
+	return diffs, nil
+}
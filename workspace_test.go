@@ -0,0 +1,98 @@
+package fxjson
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestWorkspace 测试跨文档引用与文档管理
+func TestWorkspace(t *testing.T) {
+	ws := NewWorkspace()
+	if err := ws.Add("accounts", []byte(`{"data":{"users":[{"id":1,"name":"Alice"}]}}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := ws.Add("ledger", []byte(`{"entries":[{"user":1,"amount":10}]}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	if name, _ := ws.Get("accounts::data.users[0].name").String(); name != "Alice" {
+		t.Errorf("cross-document get = %q, want Alice", name)
+	}
+	if v, _ := ws.Get("ledger::entries[0].amount").Int(); v != 10 {
+		t.Errorf("ledger amount = %d, want 10", v)
+	}
+	for _, ref := range []string{"ledger", "ledger::"} {
+		if root := ws.Get(ref); root.Kind() != TypeObject {
+			t.Errorf("Get(%q) should return the root object", ref)
+		}
+	}
+
+	if ws.Get("accounts::data.missing").Exists() {
+		t.Error("missing path should not exist")
+	}
+	if _, err := ws.Resolve("accounts::data.missing"); err != nil {
+		t.Errorf("missing path should not be an error: %v", err)
+	}
+	if _, err := ws.Resolve("other::data"); err == nil || !strings.Contains(err.Error(), `unknown document "other"`) {
+		t.Errorf("expected unknown document error, got %v", err)
+	}
+
+	if err := ws.Add("bad::name", []byte(`{}`)); err == nil {
+		t.Error("expected error for name containing '::'")
+	}
+	if err := ws.Add("broken", []byte(`{"a":`)); err == nil || !strings.Contains(err.Error(), `document "broken"`) {
+		t.Errorf("expected parse error naming the document, got %v", err)
+	}
+
+	// 替换保持原有顺序，移除后不可访问
+	if err := ws.Add("accounts", []byte(`{"data":{"users":[]}}`)); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(ws.Names(), ","); got != "accounts,ledger" {
+		t.Errorf("Names = %s", got)
+	}
+	if ws.Get("accounts::data.users[0]").Exists() {
+		t.Error("replaced document should not serve cached paths")
+	}
+	ws.Remove("accounts")
+	if _, ok := ws.Doc("accounts"); ok {
+		t.Error("removed document still present")
+	}
+	if got := strings.Join(ws.Names(), ","); got != "ledger" {
+		t.Errorf("Names after Remove = %s", got)
+	}
+}
+
+// TestWorkspaceBatch 测试批量验证与成对比较
+func TestWorkspaceBatch(t *testing.T) {
+	ws := NewWorkspace()
+	ws.Add("desired", []byte(`{"name":"svc","replicas":3}`))
+	ws.Add("actual", []byte(`{"name":"svc","replicas":2}`))
+	ws.Add("empty", []byte(`{"replicas":1}`))
+
+	validator := &DataValidator{Rules: map[string]ValidationRule{
+		"name": {Required: true, Type: "string"},
+	}}
+	failures := ws.ValidateAll(validator)
+	if len(failures) != 1 || len(failures["empty"]) != 1 {
+		t.Errorf("ValidateAll = %v, want a single failure for empty", failures)
+	}
+
+	diffs, err := ws.DiffPairs([2]string{"desired", "actual"}, [2]string{"desired::name", "actual::name"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diffs) != 2 {
+		t.Fatalf("expected 2 diffs, got %d", len(diffs))
+	}
+	if len(diffs[0].Results) != 1 || diffs[0].Results[0].Path != "replicas" {
+		t.Errorf("desired vs actual = %+v", diffs[0].Results)
+	}
+	if len(diffs[1].Results) != 0 {
+		t.Errorf("name subtrees should be equal, got %+v", diffs[1].Results)
+	}
+
+	if _, err := ws.DiffPairs([2]string{"desired", "nope"}); err == nil {
+		t.Error("expected error for unknown document")
+	}
+}
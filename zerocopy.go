@@ -0,0 +1,25 @@
+//go:build !fxjson_safe
+
+package fxjson
+
+import "unsafe"
+
+// zeroCopy 当前构建是否使用零拷贝的字节与字符串转换；fxjson_safe 构建标签下为 false，
+// 此时键名等字符串需要复制，零分配保证不成立
+const zeroCopy = true
+
+// bytesToString 零拷贝地将字节切片视为字符串，返回值与 b 共享底层内存
+func bytesToString(b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+	return unsafe.String(unsafe.SliceData(b), len(b))
+}
+
+// dataPtr 返回切片底层数据的地址，用于数组偏移缓存的键
+func dataPtr(b []byte) uintptr {
+	if len(b) == 0 {
+		return 0
+	}
+	return uintptr(unsafe.Pointer(unsafe.SliceData(b)))
+}
@@ -0,0 +1,21 @@
+//go:build fxjson_safe
+
+package fxjson
+
+import "reflect"
+
+// zeroCopy 当前构建是否使用零拷贝的字节与字符串转换
+const zeroCopy = false
+
+// bytesToString 复制字节切片生成字符串（fxjson_safe 构建标签下不使用 unsafe）
+func bytesToString(b []byte) string {
+	return string(b)
+}
+
+// dataPtr 返回切片底层数据的地址，用于数组偏移缓存的键
+func dataPtr(b []byte) uintptr {
+	if len(b) == 0 {
+		return 0
+	}
+	return reflect.ValueOf(b).Pointer()
+}
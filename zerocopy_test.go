@@ -0,0 +1,69 @@
+package fxjson
+
+import (
+	"go/build/constraint"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestUnsafeImportsConstrained 测试导入 unsafe 的文件都带有 !fxjson_safe 构建约束，
+// 保证 fxjson_safe 构建标签下整个模块不使用 unsafe
+func TestUnsafeImportsConstrained(t *testing.T) {
+	err := filepath.WalkDir(".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if name := d.Name(); path != "." && (name == "testdata" || strings.HasPrefix(name, ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+
+		file, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.ImportsOnly|parser.ParseComments)
+		if err != nil {
+			return err
+		}
+		importsUnsafe := false
+		for _, spec := range file.Imports {
+			if p, _ := strconv.Unquote(spec.Path.Value); p == "unsafe" {
+				importsUnsafe = true
+			}
+		}
+		if !importsUnsafe {
+			return nil
+		}
+
+		excluded := false
+		for _, group := range file.Comments {
+			if group.Pos() >= file.Package {
+				break
+			}
+			for _, c := range group.List {
+				if !constraint.IsGoBuild(c.Text) {
+					continue
+				}
+				expr, err := constraint.Parse(c.Text)
+				if err != nil {
+					return err
+				}
+				excluded = !expr.Eval(func(tag string) bool { return tag == "fxjson_safe" })
+			}
+		}
+		if !excluded {
+			t.Errorf("%s imports unsafe but is built with the fxjson_safe tag", path)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}